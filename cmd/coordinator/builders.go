@@ -8,6 +8,7 @@ package main
 
 import (
 	"bytes"
+	"cmp"
 	_ "embed"
 	"encoding/json"
 	"fmt"
@@ -19,11 +20,66 @@ import (
 	"golang.org/x/build/dashboard"
 )
 
+// builderExplain is the form on the builders page that explains
+// the decisions of a builder, and its result.
+type builderExplain struct {
+	Builder, Repo, Branch, GoBranch, DistTest string
+
+	Explanation *dashboard.Explanation
+	Error       string
+}
+
+// explainBuilder returns the builder explanation requested by r, if any.
+func explainBuilder(r *http.Request) *builderExplain {
+	e := &builderExplain{
+		Builder:  r.FormValue("builder"),
+		Repo:     cmp.Or(r.FormValue("repo"), "go"),
+		Branch:   cmp.Or(r.FormValue("branch"), "master"),
+		GoBranch: r.FormValue("gobranch"),
+		DistTest: r.FormValue("test"),
+	}
+	if e.Builder == "" {
+		return e
+	}
+	if e.GoBranch == "" && e.Repo != "go" {
+		e.GoBranch = "master"
+	}
+	bc, ok := dashboard.Builders[e.Builder]
+	if !ok {
+		e.Error = fmt.Sprintf("unknown builder %q", e.Builder)
+		return e
+	}
+	x, err := bc.Explain(e.Repo, e.Branch, e.GoBranch, e.DistTest)
+	if err != nil {
+		e.Error = err.Error()
+		return e
+	}
+	e.Explanation = x
+	return e
+}
+
 func handleBuilders(w http.ResponseWriter, r *http.Request) {
+	explain := explainBuilder(r)
+	if r.FormValue("mode") == "json" && explain.Builder != "" {
+		if explain.Error != "" {
+			http.Error(w, explain.Error, http.StatusBadRequest)
+			return
+		}
+		j, err := json.MarshalIndent(explain.Explanation, "", "\t")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(j)
+		return
+	}
+
 	data := struct {
 		Builders map[string]*dashboard.BuildConfig
 		Hosts    map[string]*dashboard.HostConfig
-	}{dashboard.Builders, dashboard.Hosts}
+		Explain  *builderExplain `json:"-"`
+	}{dashboard.Builders, dashboard.Hosts, explain}
 	if r.FormValue("mode") == "json" {
 		j, err := json.MarshalIndent(data, "", "\t")
 		if err != nil {
@@ -47,6 +103,17 @@ func handleBuilders(w http.ResponseWriter, r *http.Request) {
 var buildersTmplStr string
 
 var buildersTmpl = template.Must(baseTmpl.New("builders").Funcs(template.FuncMap{
+	// decisionRow returns the data for a row of the
+	// builder-decision template, for a Decision or *Decision.
+	"decisionRow": func(what string, d any) any {
+		if p, ok := d.(*dashboard.Decision); ok {
+			d = *p
+		}
+		return struct {
+			What     string
+			Decision dashboard.Decision
+		}{what, d.(dashboard.Decision)}
+	},
 	"builderOwners": func(bc *dashboard.BuildConfig) template.HTML {
 		owners := bc.HostConfig().Owners
 		if len(owners) == 0 {
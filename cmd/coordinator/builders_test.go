@@ -7,8 +7,12 @@
 package main
 
 import (
+	"encoding/json"
 	"net/http/httptest"
+	"strings"
 	"testing"
+
+	"golang.org/x/build/dashboard"
 )
 
 func TestHandleBuilders(t *testing.T) {
@@ -20,3 +24,36 @@ func TestHandleBuilders(t *testing.T) {
 	}
 	t.Logf("Got: %s", rec.Body.Bytes())
 }
+
+func TestHandleBuildersExplain(t *testing.T) {
+	rec := httptest.NewRecorder()
+	handleBuilders(rec, httptest.NewRequest("GET", "/builders?builder=wasip1-wasm-wasmtime&repo=go&branch=master&test=reboot", nil))
+	if rec.Code != 200 {
+		t.Fatalf("Want 200 OK. Got status: %v, %s", rec.Code, rec.Body.Bytes())
+	}
+	for _, want := range []string{
+		"wasip1-wasm-wasmtime building go on master with go on master",
+		"run reboot in normal trybot builds",
+		"deny (distTestAdjust): dist test policy `wasip1FasterTrybots` changes the decision",
+	} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("builders page doesn't contain %q:\n%s", want, rec.Body.Bytes())
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	handleBuilders(rec, httptest.NewRequest("GET", "/builders?mode=json&builder=wasip1-wasm-wasmtime&repo=net&branch=master", nil))
+	var e dashboard.Explanation
+	if err := json.Unmarshal(rec.Body.Bytes(), &e); err != nil {
+		t.Fatalf("decoding JSON explanation: %v\n%s", err, rec.Body.Bytes())
+	}
+	if e.Builder != "wasip1-wasm-wasmtime" || e.Repo != "net" || e.GoBranch != "master" || len(e.PostSubmit.Steps) == 0 {
+		t.Errorf("got explanation %+v, want one for net on master", e)
+	}
+
+	rec = httptest.NewRecorder()
+	handleBuilders(rec, httptest.NewRequest("GET", "/builders?mode=json&builder=nope", nil))
+	if rec.Code != 400 {
+		t.Errorf("explaining unknown builder: got status %v, want 400", rec.Code)
+	}
+}
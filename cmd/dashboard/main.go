@@ -0,0 +1,141 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Dashboard inspects the builder and host configurations
+// of the golang.org/x/build/dashboard package.
+//
+// Usage:
+//
+//	dashboard lint [-config file]
+//	dashboard explain [-json] [-test name] builder repo branch [gobranch]
+//
+// The lint command checks the builders and hosts defined in a
+// configuration file, by default the one built into the dashboard
+// package (dashboard/builders.yaml), along with the builders defined
+// in Go in dashboard/builders.go. It reports problems that the
+// dashboard package accepts but that are likely mistakes, such as
+// builders that don't build any repo on any branch, or that do the
+// same builds as another builder. It exits with status 1 if there
+// are any.
+//
+// The explain command explains whether the builder does post-submit and
+// trybot builds of the repo ("go", "net", etc.) on the branch, with Go
+// on gobranch, by printing the checks that allowed or denied each build
+// in the order they were made. The gobranch may be omitted for the go
+// repo. With -test, it also explains whether the builder runs the named
+// cmd/dist test. With -json, it prints the explanation as JSON.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"golang.org/x/build/dashboard"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: dashboard lint [-config file]\n")
+	fmt.Fprintf(os.Stderr, "       dashboard explain [-json] [-test name] builder repo branch [gobranch]\n")
+	os.Exit(2)
+}
+
+func main() {
+	log.SetPrefix("dashboard: ")
+	log.SetFlags(0)
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() < 1 {
+		usage()
+	}
+	switch cmd, args := flag.Arg(0), flag.Args()[1:]; cmd {
+	case "lint":
+		os.Exit(lint(args))
+	case "explain":
+		os.Exit(explain(os.Stdout, args))
+	default:
+		log.Printf("unknown command %q", cmd)
+		usage()
+	}
+}
+
+// lint runs the lint command with args,
+// and returns the exit status.
+func lint(args []string) int {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	fs.Usage = usage
+	config := fs.String("config", "", "configuration `file` to check (default: the built-in configuration)")
+	fs.Parse(args)
+	if fs.NArg() != 0 {
+		usage()
+	}
+	c, err := dashboard.LoadConfig(*config)
+	if err != nil {
+		log.Print(err)
+		return 1
+	}
+	problems := dashboard.Lint(c)
+	for _, p := range problems {
+		fmt.Println(p)
+	}
+	if len(problems) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// explain runs the explain command with args, writing to w,
+// and returns the exit status.
+func explain(w io.Writer, args []string) int {
+	fs := flag.NewFlagSet("explain", flag.ExitOnError)
+	fs.Usage = usage
+	asJSON := fs.Bool("json", false, "print the explanation as JSON")
+	test := fs.String("test", "", "also explain whether the builder runs the cmd/dist test `name`")
+	fs.Parse(args)
+	if fs.NArg() != 3 && fs.NArg() != 4 {
+		usage()
+	}
+	bc, ok := dashboard.Builders[fs.Arg(0)]
+	if !ok {
+		log.Printf("unknown builder %q", fs.Arg(0))
+		return 1
+	}
+	e, err := bc.Explain(fs.Arg(1), fs.Arg(2), fs.Arg(3), *test)
+	if err != nil {
+		log.Print(err)
+		return 1
+	}
+	if *asJSON {
+		b, err := json.MarshalIndent(e, "", "\t")
+		if err != nil {
+			log.Print(err)
+			return 1
+		}
+		fmt.Fprintf(w, "%s\n", b)
+		return 0
+	}
+
+	fmt.Fprintf(w, "%s building %s on %s with go on %s:\n", e.Builder, e.Repo, e.Branch, e.GoBranch)
+	writeDecision(w, "post-submit build", e.PostSubmit)
+	writeDecision(w, "trybot build", e.TryBot)
+	if e.DistTest != "" {
+		writeDecision(w, "run "+e.DistTest+" in normal trybot builds", *e.DistTestNormalTry)
+		writeDecision(w, "run "+e.DistTest+" in other builds", *e.DistTestOther)
+	}
+	return 0
+}
+
+func writeDecision(w io.Writer, what string, d dashboard.Decision) {
+	result := "no"
+	if d.Result {
+		result = "yes"
+	}
+	fmt.Fprintf(w, "\n%s: %s\n", what, result)
+	for _, s := range d.Steps {
+		fmt.Fprintf(w, "\t%s\n", s)
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"golang.org/x/build/dashboard"
+)
+
+func TestLint(t *testing.T) {
+	if status := lint(nil); status != 0 {
+		t.Errorf("lint of the built-in configuration exited with status %d, want 0", status)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	var buf bytes.Buffer
+	if status := explain(&buf, []string{"-test=reboot", "wasip1-wasm-wasmtime", "go", "master"}); status != 0 {
+		t.Fatalf("explain exited with status %d", status)
+	}
+	for _, want := range []string{
+		"wasip1-wasm-wasmtime building go on master with go on master:\n",
+		"\ntrybot build: no\n\ttryBot: allow: trybot policy `repo == \"go\"` allows go\n",
+		"\nrun reboot in other builds: yes\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("explain output doesn't contain %q:\n%s", want, &buf)
+		}
+	}
+
+	buf.Reset()
+	if status := explain(&buf, []string{"-json", "wasip1-wasm-wasmtime", "net", "master", "release-branch.go1.20"}); status != 0 {
+		t.Fatalf("explain -json exited with status %d", status)
+	}
+	var e dashboard.Explanation
+	if err := json.Unmarshal(buf.Bytes(), &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Repo != "net" || e.GoBranch != "release-branch.go1.20" || e.PostSubmit.Result || e.DistTestOther != nil {
+		t.Errorf("explain -json = %+v, want net with Go on release-branch.go1.20 not built", e)
+	}
+
+	if status := explain(&buf, []string{"nope", "go", "master"}); status != 1 {
+		t.Errorf("explain of unknown builder exited with status %d, want 1", status)
+	}
+}
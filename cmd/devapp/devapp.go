@@ -12,6 +12,7 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strings"
 
 	"golang.org/x/build/internal/https"
 )
@@ -20,6 +21,7 @@ var (
 	staticDir   = flag.String("static-dir", "./static/", "location of static directory relative to binary location")
 	templateDir = flag.String("template-dir", "./templates/", "location of templates directory relative to binary location")
 	reload      = flag.Bool("reload", false, "reload content on each page load")
+	ownersRepos = flag.String("owners-repos", "", "comma-separated Gerrit projects to load OWNERS files from, in place of the owners built into devapp")
 )
 
 func init() {
@@ -34,6 +36,9 @@ func main() {
 	flag.Parse()
 
 	s := newServer(http.NewServeMux(), *staticDir, *templateDir, *reload)
+	if *ownersRepos != "" {
+		s.ownersRepos = strings.Split(*ownersRepos, ",")
+	}
 	ctx := context.Background()
 	if err := s.initCorpus(ctx); err != nil {
 		log.Fatalf("Could not init corpus: %v", err)
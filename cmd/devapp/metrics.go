@@ -0,0 +1,112 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/build/devapp/metrics"
+	"golang.org/x/build/maintner"
+)
+
+// updateMetrics brings the review metrics history up to date
+// with the corpus, recomputing the facts about the CLs that
+// changed since it was last called.
+func (s *server) updateMetrics() {
+	s.cMu.RLock()
+	defer s.cMu.RUnlock()
+	if s.corpus == nil {
+		return
+	}
+	var changed int
+	s.corpus.Gerrit().ForeachProjectUnsorted(filterProjects(func(p *maintner.GerritProject) error {
+		return p.ForeachCLUnsorted(withoutDeletedCLs(p, func(cl *maintner.GerritCL) error {
+			if !cl.Private && s.history.Observe(p, cl) {
+				changed++
+			}
+			return nil
+		}))
+	}))
+	log.Printf("Updated metrics of %d CLs (%d total).", changed, s.history.Len())
+}
+
+// handleMetrics serves the JSON endpoints under dev.golang.org/metrics/:
+//
+//   - /metrics/cls lists the facts about CLs that metrics are computed from.
+//   - /metrics/latency reports the time to first review, the time to submit,
+//     and the number of review rounds. With interval=week, it reports them
+//     for each week.
+//   - /metrics/reviewers reports the load of each reviewer.
+//   - /metrics/dirs reports the throughput of each directory. With depth=N,
+//     directories are truncated to N path elements below the repo root.
+//
+// All of them take the query parameters described by metrics.ParseQuery,
+// which select the CLs and the time window to compute metrics over.
+func (s *server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != "GET" && r.Method != "HEAD" {
+		writeMetricsError(w, http.StatusMethodNotAllowed, http.StatusText(http.StatusMethodNotAllowed))
+		return
+	}
+	v := r.URL.Query()
+	q, err := metrics.ParseQuery(v, time.Now())
+	if err != nil {
+		writeMetricsError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	cls := s.history.CLs(q)
+
+	var resp any
+	switch strings.TrimPrefix(r.URL.Path, "/metrics/") {
+	case "cls":
+		resp = cls
+	case "latency":
+		switch v.Get("interval") {
+		case "":
+			resp = metrics.LatencyOf(cls, q)
+		case "week":
+			resp = metrics.WeeklyLatency(cls, q)
+		default:
+			writeMetricsError(w, http.StatusBadRequest, "interval must be week, if set")
+			return
+		}
+	case "reviewers":
+		resp = metrics.ReviewerLoads(cls, q)
+	case "dirs":
+		depth := 0
+		if d := v.Get("depth"); d != "" {
+			depth, err = strconv.Atoi(d)
+			if err != nil || depth < 0 {
+				writeMetricsError(w, http.StatusBadRequest, "depth must be a non-negative integer")
+				return
+			}
+		}
+		resp = metrics.DirThroughputs(cls, q, depth)
+	default:
+		writeMetricsError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+		return
+	}
+	data, err := json.Marshal(resp)
+	if err != nil {
+		log.Printf("unable to encode metrics response: %v", err)
+		writeMetricsError(w, http.StatusInternalServerError, "unable to encode response")
+		return
+	}
+	w.Write(data)
+}
+
+func writeMetricsError(w http.ResponseWriter, code int, text string) {
+	w.WriteHeader(code)
+	data, _ := json.Marshal(struct {
+		Error string `json:"error"`
+	}{text})
+	w.Write(data)
+}
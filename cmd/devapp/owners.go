@@ -0,0 +1,245 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"path"
+	"time"
+
+	"golang.org/x/build/devapp/owners"
+	"golang.org/x/build/internal/gitfs"
+	"golang.org/x/build/maintner"
+)
+
+type ownersData struct {
+	// history is the review history of the CLs updated
+	// in the last reviewHistoryAge.
+	history []owners.Review
+
+	// dirty is set if this data needs to be updated due to a corpus change.
+	dirty bool
+}
+
+// reviewHistoryAge is how far back the review history that
+// reviewer suggestions are based on goes.
+const reviewHistoryAge = 90 * 24 * time.Hour
+
+// handleOwnersSuggest serves dev.golang.org/owners/suggest, which
+// responds to a POST of an owners.SuggestRequest with an
+// owners.SuggestResponse.
+func (s *server) handleOwnersSuggest(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method == "OPTIONS" {
+		return
+	}
+	if r.Method != "POST" {
+		writeSuggestResponse(w, http.StatusMethodNotAllowed, owners.SuggestResponse{Error: http.StatusText(http.StatusMethodNotAllowed)})
+		return
+	}
+	var req owners.SuggestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("unable to decode owners suggest request: %v", err)
+		writeSuggestResponse(w, http.StatusBadRequest, owners.SuggestResponse{Error: "unable to decode request"})
+		return
+	}
+
+	s.cMu.RLock()
+	dirty := s.data.owners.dirty
+	s.cMu.RUnlock()
+	if dirty {
+		if err := s.updateOwnersData(); err != nil {
+			log.Println("updateOwnersData:", err)
+			writeSuggestResponse(w, http.StatusInternalServerError, owners.SuggestResponse{Error: "unable to load review history"})
+			return
+		}
+	}
+
+	s.cMu.RLock()
+	history := s.data.owners.history
+	s.cMu.RUnlock()
+	resp := owners.SuggestResponse{
+		Suggestions: owners.Suggest(req.Paths, req.Author, history, time.Now()),
+	}
+	writeSuggestResponse(w, http.StatusOK, resp)
+}
+
+func writeSuggestResponse(w http.ResponseWriter, code int, resp owners.SuggestResponse) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		log.Printf("unable to encode owners suggest response: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(code)
+	w.Write(data)
+}
+
+func (s *server) updateOwnersData() error {
+	log.Println("Updating owners data ...")
+	s.cMu.Lock()
+	defer s.cMu.Unlock()
+	history, err := owners.ReviewHistory(s.corpus, time.Now().Add(-reviewHistoryAge))
+	if err != nil {
+		return err
+	}
+	s.data.owners.history = history
+	s.data.owners.dirty = false
+	return nil
+}
+
+// ownersFiles holds the OWNERS files loaded from the master branches
+// of the repos in ownersRepos. It's only used by corpusUpdateLoop.
+type ownersFiles struct {
+	table  *owners.Table               // installed with owners.SetTable
+	heads  map[string]maintner.GitHash // repo -> commit the repo's OWNERS files are from
+	failed map[string]ownersFailure    // repo -> last failure to load the repo's OWNERS files
+}
+
+// An ownersFailure records a failure to load the OWNERS files of a repo.
+type ownersFailure struct {
+	head maintner.GitHash // commit the files failed to load from
+	at   time.Time
+}
+
+// ownersRetryInterval is how long to wait before loading OWNERS files
+// that failed to load again, unless they change in the meantime.
+const ownersRetryInterval = time.Hour
+
+// failing reports whether loading the OWNERS files of repo at head
+// would likely fail again, since they failed to load recently and
+// haven't changed since.
+//
+// The caller must hold the corpus lock for reading.
+func (f *ownersFiles) failing(p *maintner.GerritProject, repo string, head maintner.GitHash, now time.Time) bool {
+	last, ok := f.failed[repo]
+	return ok && now.Sub(last.at) < ownersRetryInterval &&
+		(head == last.head || !ownersFilesChanged(p, last.head, head))
+}
+
+// updateOwnersFiles reloads the OWNERS files of each repo in ownersRepos
+// whose master branch has changed them since they were last loaded,
+// and installs them with owners.SetTable.
+func (s *server) updateOwnersFiles() {
+	if len(s.ownersRepos) == 0 {
+		return
+	}
+	heads := make(map[string]maintner.GitHash)
+	s.cMu.RLock()
+	for _, repo := range s.ownersRepos {
+		p := s.corpus.Gerrit().Project("go.googlesource.com", repo)
+		if p == nil {
+			log.Printf("Loading OWNERS files: no Gerrit project %q in the corpus", repo)
+			continue
+		}
+		head := p.Ref("refs/heads/master")
+		old, loaded := s.ownersFiles.heads[repo]
+		if head == "" || head == old || s.ownersFiles.failing(p, repo, head, time.Now()) {
+			continue
+		}
+		if loaded && !ownersFilesChanged(p, old, head) {
+			s.ownersFiles.heads[repo] = head
+			continue
+		}
+		heads[repo] = head
+	}
+	s.cMu.RUnlock()
+	if err := s.loadOwnersFiles(heads); err != nil {
+		log.Printf("Loading OWNERS files: %v", err)
+	}
+}
+
+// loadOwnersFiles loads the OWNERS files of each repo in heads from the
+// commit it maps the repo to, and installs them with owners.SetTable.
+// If loading a repo fails, its previously loaded OWNERS files, if any,
+// stay installed.
+func (s *server) loadOwnersFiles(heads map[string]maintner.GitHash) error {
+	if len(heads) == 0 {
+		return nil
+	}
+	t := owners.NewTable()
+	if s.ownersFiles.table != nil {
+		t = s.ownersFiles.table.Clone()
+	}
+	if s.ownersFiles.heads == nil {
+		s.ownersFiles.heads = make(map[string]maintner.GitHash)
+		s.ownersFiles.failed = make(map[string]ownersFailure)
+	}
+	var errs []error
+	for repo, head := range heads {
+		fsys, err := s.cloneRepo(repo, head)
+		if err == nil {
+			err = t.Load(repo, fsys)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s at %v: %v", repo, head, err))
+			s.ownersFiles.failed[repo] = ownersFailure{head, time.Now()}
+			continue
+		}
+		s.ownersFiles.heads[repo] = head
+		delete(s.ownersFiles.failed, repo)
+		log.Printf("Loaded OWNERS files of %s at %v.", repo, head)
+	}
+	s.ownersFiles.table = t
+	owners.SetTable(t)
+	return errors.Join(errs...)
+}
+
+// cloneGerritRepo returns the file tree of the Gerrit project
+// on go.googlesource.com named repo, at the given commit.
+func cloneGerritRepo(repo string, commit maintner.GitHash) (fs.FS, error) {
+	r, err := gitfs.NewRepo("https://go.googlesource.com/" + repo)
+	if err != nil {
+		return nil, err
+	}
+	_, fsys, err := r.Clone(commit.String())
+	return fsys, err
+}
+
+// ownersFilesChanged reports whether any commit in p that's an ancestor
+// of to (or to itself) but not of from changes an OWNERS file. It also
+// reports true if it can't tell, such as when from isn't an ancestor of to.
+//
+// The caller must hold the corpus lock for reading.
+func ownersFilesChanged(p *maintner.GerritProject, from, to maintner.GitHash) bool {
+	const maxCommits = 10000
+	seen := make(map[maintner.GitHash]bool)
+	queue := []maintner.GitHash{to}
+	sawFrom := false
+	for len(queue) > 0 {
+		h := queue[0]
+		queue = queue[1:]
+		if seen[h] {
+			continue
+		}
+		seen[h] = true
+		if h == from {
+			sawFrom = true
+			continue
+		}
+		if len(seen) > maxCommits {
+			return true
+		}
+		c, err := p.GitCommit(h.String())
+		if err != nil {
+			return true
+		}
+		for _, f := range c.Files {
+			if path.Base(f.File) == owners.FileName {
+				return true
+			}
+		}
+		for _, parent := range c.Parents {
+			queue = append(queue, parent.Hash)
+		}
+	}
+	return !sawFrom
+}
@@ -9,6 +9,7 @@ import (
 	"context"
 	"fmt"
 	"html/template"
+	"io/fs"
 	"log"
 	"math/rand"
 	"net/http"
@@ -18,6 +19,7 @@ import (
 	"sync"
 	"time"
 
+	"golang.org/x/build/devapp/metrics"
 	"golang.org/x/build/devapp/owners"
 	"golang.org/x/build/maintner"
 	"golang.org/x/build/maintner/godata"
@@ -38,6 +40,18 @@ type server struct {
 	helpWantedIssues []issueData
 	data             pageData
 
+	// history is the history of CLs that review metrics are computed from.
+	// It has its own lock, and is updated as the corpus is.
+	history *metrics.History
+
+	// ownersRepos are the Gerrit projects whose OWNERS files are loaded,
+	// as the corpus updates, in place of the owners built into the
+	// devapp/owners package. cloneRepo returns the file tree of a repo
+	// at a commit.
+	ownersRepos []string
+	cloneRepo   func(repo string, commit maintner.GitHash) (fs.FS, error)
+	ownersFiles ownersFiles
+
 	// GopherCon-specific fields. Must still hold cMu when reading/writing these.
 	userMapping map[int]*maintner.GitHubUser // Gerrit Owner ID => GitHub user
 	activities  []activity                   // All contribution activities
@@ -53,6 +67,7 @@ type pageData struct {
 	release releaseData
 	reviews reviewsData
 	stats   statsData
+	owners  ownersData
 }
 
 func newServer(mux *http.ServeMux, staticDir, templateDir string, reloadTmpls bool) *server {
@@ -62,6 +77,8 @@ func newServer(mux *http.ServeMux, staticDir, templateDir string, reloadTmpls bo
 		templateDir: templateDir,
 		reloadTmpls: reloadTmpls,
 		userMapping: map[int]*maintner.GitHubUser{},
+		history:     metrics.NewHistory(),
+		cloneRepo:   cloneGerritRepo,
 	}
 	s.mux.Handle("/", http.FileServer(http.Dir(s.staticDir)))
 	s.mux.HandleFunc("/favicon.ico", s.handleFavicon)
@@ -70,6 +87,8 @@ func newServer(mux *http.ServeMux, staticDir, templateDir string, reloadTmpls bo
 	s.mux.HandleFunc("/stats", s.withTemplate("/stats.tmpl", s.handleStats))
 	s.mux.HandleFunc("/dir/", handleDirRedirect)
 	s.mux.HandleFunc("/owners", owners.Handler)
+	s.mux.HandleFunc("/owners/suggest", s.handleOwnersSuggest)
+	s.mux.HandleFunc("/metrics/", s.handleMetrics)
 	s.mux.Handle("/owners/", http.RedirectHandler("/owners", http.StatusPermanentRedirect)) // TODO: remove after clients updated to use URL without trailing slash
 	for _, p := range []string{"/imfeelinghelpful", "/imfeelinglucky"} {
 		s.mux.HandleFunc(p, s.handleRandomHelpWantedIssue)
@@ -117,10 +136,14 @@ func (s *server) corpusUpdateLoop(ctx context.Context) {
 		s.updateHelpWantedIssues()
 		log.Println("Updating activities ...")
 		s.updateActivities()
+		log.Println("Updating metrics ...")
+		s.updateMetrics()
+		s.updateOwnersFiles()
 		s.cMu.Lock()
 		s.data.release.dirty = true
 		s.data.reviews.dirty = true
 		s.data.stats.dirty = true
+		s.data.owners.dirty = true
 		s.cMu.Unlock()
 		err := s.corpus.UpdateWithLocker(ctx, &s.cMu)
 		if err != nil {
@@ -6,9 +6,20 @@ package main
 
 import (
 	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/fs"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
+
+	"golang.org/x/build/devapp/metrics"
+	"golang.org/x/build/devapp/owners"
+	"golang.org/x/build/maintner"
 )
 
 var testServer = newServer(http.DefaultServeMux, "./static/", "./templates/", false)
@@ -130,3 +141,153 @@ func TestHandleDirRedirect(t *testing.T) {
 		}
 	}
 }
+
+func TestOwnersSuggest(t *testing.T) {
+	testServer.cMu.Lock()
+	testServer.data.owners.history = []owners.Review{{Reviewer: "bradfitz@golang.org", Open: true}}
+	testServer.cMu.Unlock()
+
+	body := `{"paths": ["go/src/archive/zip/a.go"], "author": "iant@golang.org"}`
+	req := httptest.NewRequest("POST", "/owners/suggest", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	testServer.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("w.Code = %d; want %d", w.Code, http.StatusOK)
+	}
+	var resp owners.SuggestResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, s := range resp.Suggestions {
+		got = append(got, fmt.Sprintf("%s:%d", s.Owner.GerritEmail, s.Open))
+	}
+	if want := []string{"joetsai@digital-static.net:0", "bradfitz@golang.org:1"}; !slices.Equal(got, want) {
+		t.Errorf("suggestions = %q; want %q", got, want)
+	}
+
+	req = httptest.NewRequest("GET", "/owners/suggest", nil)
+	w = httptest.NewRecorder()
+	testServer.ServeHTTP(w, req)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET: w.Code = %d; want %d", w.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestLoadOwnersFiles(t *testing.T) {
+	trees := map[maintner.GitHash]fstest.MapFS{
+		"c1": {"src/net/http/OWNERS": {Data: []byte("* @neild\n")}},
+		"c2": {"src/net/http/OWNERS": {Data: []byte("* @rsc\n")}},
+		"c3": {"src/net/http/OWNERS": {Data: []byte("* rsc\n")}}, // invalid
+	}
+	s := newServer(http.NewServeMux(), "./static/", "./templates/", false)
+	s.cloneRepo = func(repo string, commit maintner.GitHash) (fs.FS, error) {
+		if repo != "go" || trees[commit] == nil {
+			return nil, fmt.Errorf("no commit %v in %s", commit, repo)
+		}
+		return trees[commit], nil
+	}
+	defer owners.SetTable(nil)
+
+	ownerOf := func(path string) string {
+		t.Helper()
+		body := `{"payload": {"paths": ["` + path + `"]}}`
+		w := httptest.NewRecorder()
+		owners.Handler(w, httptest.NewRequest("POST", "/owners", strings.NewReader(body)))
+		var resp owners.Response
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		if e := resp.Payload.Entries[path]; e != nil && len(e.Primary) > 0 {
+			return e.Primary[0].GitHubUsername
+		}
+		return ""
+	}
+	builtin := ownerOf("go/src/net/http/server.go")
+	for _, tt := range []struct {
+		commit  maintner.GitHash
+		wantErr bool
+		want    string
+	}{
+		{"c1", false, "neild"},
+		{"c2", false, "rsc"},
+		{"c3", true, "rsc"}, // the files from c2 stay installed
+	} {
+		err := s.loadOwnersFiles(map[string]maintner.GitHash{"go": tt.commit})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("loadOwnersFiles at %s: error = %v, want error: %v", tt.commit, err, tt.wantErr)
+		}
+		if got := ownerOf("go/src/net/http/server.go"); got != tt.want {
+			t.Errorf("after loading OWNERS files at %s: owner = %q, want %q", tt.commit, got, tt.want)
+		}
+	}
+	if got := s.ownersFiles.heads["go"]; got != "c2" {
+		t.Errorf("OWNERS files of go are from %q, want c2", got)
+	}
+	// The files at c3 aren't loaded again until the retry interval passes.
+	now := time.Now()
+	if !s.ownersFiles.failing(nil, "go", "c3", now) {
+		t.Errorf("OWNERS files at c3 not failing after they failed to load")
+	}
+	if s.ownersFiles.failing(nil, "go", "c3", now.Add(ownersRetryInterval)) {
+		t.Errorf("OWNERS files at c3 still failing after the retry interval")
+	}
+	if err := s.loadOwnersFiles(map[string]maintner.GitHash{"go": "c1"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.ownersFiles.failed["go"]; ok {
+		t.Errorf("failure to load OWNERS files of go still recorded after they loaded")
+	}
+	if got := ownerOf("tools/go/ssa/ssa.go"); got == "" {
+		t.Errorf("repo without OWNERS files has no owner, want the one built in")
+	}
+
+	owners.SetTable(nil)
+	if got := ownerOf("go/src/net/http/server.go"); got != builtin {
+		t.Errorf("owner after uninstalling OWNERS files = %q, want %q", got, builtin)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	created := time.Now().Add(-48 * time.Hour)
+	testServer.history.Add(&metrics.CL{
+		Project:     "go",
+		Number:      1,
+		Status:      "merged",
+		Created:     created,
+		FirstReview: created.Add(time.Hour),
+		Closed:      created.Add(24 * time.Hour),
+		Reviewers:   []string{"a@golang.org"},
+		Dirs:        []string{"src/net/http"},
+	})
+
+	req := httptest.NewRequest("GET", "/metrics/latency?project=go", nil)
+	w := httptest.NewRecorder()
+	testServer.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("w.Code = %d; want %d", w.Code, http.StatusOK)
+	}
+	var latency metrics.Latency
+	if err := json.NewDecoder(w.Body).Decode(&latency); err != nil {
+		t.Fatal(err)
+	}
+	if latency.FirstReview.Median != 1 || latency.Submit.Median != 24 {
+		t.Errorf("latency = %+v; want 1 hour to first review and 24 to submit", latency)
+	}
+
+	for path, code := range map[string]int{
+		"/metrics/dirs?depth=2":     http.StatusOK,
+		"/metrics/reviewers":        http.StatusOK,
+		"/metrics/cls?dir=go/src":   http.StatusOK,
+		"/metrics/dirs?depth=x":     http.StatusBadRequest,
+		"/metrics/latency?since=x":  http.StatusBadRequest,
+		"/metrics/latency?interval": http.StatusOK,
+		"/metrics/nope":             http.StatusNotFound,
+	} {
+		w := httptest.NewRecorder()
+		testServer.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != code {
+			t.Errorf("GET %s: w.Code = %d; want %d", path, w.Code, code)
+		}
+	}
+}
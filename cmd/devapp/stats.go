@@ -15,6 +15,7 @@ import (
 	"strings"
 	"time"
 
+	"golang.org/x/build/devapp/metrics"
 	"golang.org/x/build/maintner"
 )
 
@@ -136,6 +137,29 @@ func (s *server) updateStatsData() {
 		Columns: cols,
 		Data:    chartData[len(chartData)-7:],
 	})
+
+	q := metrics.Query{Since: windowStart, Until: time.Now()}
+	median := func(d metrics.Distribution) any {
+		if d.Count == 0 {
+			return nil // leave a gap in the chart
+		}
+		return d.Median
+	}
+	var latencyData [][]any
+	for _, p := range metrics.WeeklyLatency(s.history.CLs(q), q) {
+		latencyData = append(latencyData, []any{
+			p.Week, median(p.FirstReview), median(p.Submit),
+		})
+	}
+	charts = append(charts, &chart{
+		Title: "Median Hours to First Review and Submit (Weekly, 1 Year)",
+		Columns: []*chartColumn{
+			{Type: "date", Label: "week"},
+			{Type: "number", Label: "To first review"},
+			{Type: "number", Label: "To submit"},
+		},
+		Data: latencyData,
+	})
 	s.data.stats.Charts = charts
 }
 
@@ -0,0 +1,129 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/google/go-github/v74/github"
+	"golang.org/x/build/cmd/gerritbot/internal/rules"
+)
+
+const checkUsage = `usage: gerritbot check [flags] [commit]
+
+Check runs the rules that GerritBot checks new PRs against on a commit
+in the git repo in the current directory (by default, HEAD), reporting
+any findings. The commit's title and body are checked as they would be
+for a PR with the same title and description, and its changes are
+compared against the merge base of the commit and the base branch.
+
+Check exits with status 1 if there are findings, and 2 on error.
+
+Flags:
+`
+
+// runCheck implements the "gerritbot check" subcommand, returning the exit status.
+func runCheck(args []string) int {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), checkUsage)
+		fs.PrintDefaults()
+	}
+	repo := fs.String("repo", "", "Gerrit repo name, like \"go\" or \"tools\"; if empty, inferred from the origin remote")
+	base := fs.String("base", "origin/master", "the branch the change is for")
+	config := fs.String("config", "", "if non-empty, file to load the configuration of checks from, instead of the built-in one")
+	fs.Parse(args)
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return 2
+	}
+	commit := "HEAD"
+	if fs.NArg() == 1 {
+		commit = fs.Arg(0)
+	}
+
+	log.SetOutput(io.Discard) // Don't log the git commands being run.
+	results, err := checkCommit(*repo, *base, *config, commit)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gerritbot check: %v\n", err)
+		return 2
+	}
+	if len(results) == 0 {
+		fmt.Println("No problems found.")
+		return 0
+	}
+	for _, r := range results {
+		fmt.Printf("%s: %s\n", r.ID, r.Finding)
+		if r.URL != "" {
+			fmt.Printf("\tSee %s.\n", r.URL)
+		}
+	}
+	if _, notes := rules.FormatResults(results); notes != "" {
+		fmt.Printf("\n%s\n", notes)
+	}
+	return 1
+}
+
+// checkCommit checks commit in the git repo in the current directory,
+// as if it were a PR for the base branch of the named Gerrit repo.
+func checkCommit(repo, base, configFile, commit string) ([]rules.Result, error) {
+	cfg, err := rules.LoadConfig(configFile)
+	if err != nil {
+		return nil, err
+	}
+	if repo == "" {
+		origin, err := cmdOut(exec.Command("git", "remote", "get-url", "origin"))
+		if err != nil {
+			return nil, fmt.Errorf("finding repo name: %v; use -repo", err)
+		}
+		repo = strings.TrimSuffix(path.Base(origin), ".git")
+	}
+	sha, err := cmdOut(exec.Command("git", "rev-parse", "--verify", commit+"^{commit}"))
+	if err != nil {
+		return nil, err
+	}
+	text, err := cmdOut(exec.Command("git", "log", "-1", "--format=%B", sha))
+	if err != nil {
+		return nil, err
+	}
+	mergeBase, err := cmdOut(exec.Command("git", "merge-base", base, sha))
+	if err != nil {
+		return nil, err
+	}
+
+	// Build the commit message the way GerritBot would for a PR
+	// with the commit's title and body, so that the checks see
+	// the same footers.
+	title, body, _ := strings.Cut(text, "\n")
+	pr := &github.PullRequest{
+		Title: new(title),
+		Body:  new(strings.TrimLeft(body, "\n")),
+		Head:  &github.PullRequestBranch{SHA: new(sha)},
+		Base: &github.PullRequestBranch{Repo: &github.Repository{
+			Owner: &github.User{Login: new("golang")},
+			Name:  new(repo),
+		}},
+	}
+	cmsg, err := commitMessage(pr, nil)
+	if err != nil {
+		return nil, err
+	}
+	change, err := rules.ParseCommitMessage(repo, cmsg)
+	if err != nil {
+		return nil, err
+	}
+	change.Files, change.DiffLines, err = diffStat(".", mergeBase, sha)
+	if err != nil {
+		return nil, err
+	}
+	return rules.NewChecker(cfg).Check(change), nil
+}
@@ -4,6 +4,9 @@
 
 // The gerritbot binary converts GitHub Pull Requests to Gerrit Changes,
 // updating the PR and Gerrit Change as appropriate.
+//
+// Run as "gerritbot check", it instead checks a local commit against
+// the rules GerritBot uses for new PRs. See "gerritbot check -help".
 package main
 
 import (
@@ -45,15 +48,26 @@ var (
 	gitcookiesFile  = flag.String("gitcookies-file", "", "if non-empty, write a git http cookiefile to this location using secret manager")
 	dryRun          = flag.Bool("dry-run", false, "print out mutating actions but don’t perform any")
 	singlePR        = flag.String("single-pr", "", "process only this PR, specified in GitHub shortlink format, e.g. golang/go#1")
+	rulesConfig     = flag.String("rules-config", "", "if non-empty, file to load the configuration of PR checks from, instead of the built-in one")
 )
 
 // TODO(amedee): set to this value until the SLO numbers are published
 const secretClientTimeout = 10 * time.Second
 
 func main() {
+	// The check subcommand runs locally, without the server's flags and secrets.
+	if len(os.Args) > 1 && os.Args[1] == "check" {
+		os.Exit(runCheck(os.Args[2:]))
+	}
 	https.RegisterFlags(flag.CommandLine)
+	flag.TextVar(&relayEpoch, "relay-comments-since", time.Time{}, "relay the comments PR authors make on GitHub after this time, in RFC 3339 format, to Gerrit; if unset, comments aren't relayed")
 	flag.Parse()
 
+	rulesCfg, err := rules.LoadConfig(*rulesConfig)
+	if err != nil {
+		log.Fatalf("rules.LoadConfig(): %v", err)
+	}
+
 	var secretClient *secret.Client
 	if metadata.OnGCE() {
 		secretClient = secret.MustNewClient()
@@ -70,6 +84,7 @@ func main() {
 		log.Fatalf("gerritClient(): %v", err)
 	}
 	b := newBot(ghc, gc)
+	b.rules = rules.NewChecker(rulesCfg)
 
 	ctx := context.Background()
 	b.initCorpus(ctx)
@@ -229,6 +244,7 @@ func genProjectAllowlist() map[string]bool {
 type bot struct {
 	githubClient *github.Client
 	gerritClient *gerrit.Client
+	rules        *rules.Checker // checks run against new PRs
 
 	sync.RWMutex // Protects all fields below
 	corpus       *maintner.Corpus
@@ -242,6 +258,9 @@ type bot struct {
 
 	// Cache of Gerrit Account IDs to AccountInfo structs.
 	cachedGerritAccounts map[int]*gerrit.AccountInfo // 1234 -> Detailed Account Info
+
+	// State of PRs and their CLs when their comments were last synced.
+	synced map[string]syncState // GitHub owner/repo#n -> state
 }
 
 func newBot(githubClient *github.Client, gerritClient *gerrit.Client) *bot {
@@ -251,6 +270,7 @@ func newBot(githubClient *github.Client, gerritClient *gerrit.Client) *bot {
 		importedPRs:          map[string]*maintner.GerritCL{},
 		pendingCLs:           map[string]string{},
 		cachedGerritAccounts: map[int]*gerrit.AccountInfo{},
+		synced:               map[string]syncState{},
 	}
 }
 
@@ -446,6 +466,9 @@ func (b *bot) processPullRequest(ctx context.Context, pr *github.PullRequest) er
 	if err := b.syncGerritCommentsToGitHub(ctx, pr, cl); err != nil {
 		return fmt.Errorf("syncGerritCommentsToGitHub: %v", err)
 	}
+	// The remaining syncing is best effort:
+	// don't let it hold up importing the PR.
+	b.syncComments(ctx, pr, cl)
 
 	if cmsg == cl.Commit.Msg && pr.GetDraft() == cl.WorkInProgress() {
 		log.Printf("Change https://go-review.googlesource.com/q/%s is up to date; nothing to do.",
@@ -810,7 +833,11 @@ Please visit Gerrit at %s.
 		if err != nil {
 			return fmt.Errorf("failed to parse commit message for %s: %v", prShortLink(pr), err)
 		}
-		problems := rules.Check(change)
+		change.Files, change.DiffLines, err = diffStat(worktreeDir, mergeBaseSHA, "HEAD")
+		if err != nil {
+			return fmt.Errorf("failed to compute diff for %s: %v", prShortLink(pr), err)
+		}
+		problems := b.rules.Check(change)
 		if len(problems) > 0 {
 			findings, notes := rules.FormatResults(problems)
 			// When applicable, notes contains advice for how to edit the commit message.
@@ -931,6 +958,34 @@ func cmdOut(cmd *exec.Cmd) (string, error) {
 	return strings.TrimSpace(string(out)), nil
 }
 
+// diffStat returns the names of the files changed between the from and to
+// commits of the git repo in dir, and the number of lines added plus removed.
+// Binary files count as changing no lines.
+func diffStat(dir, from, to string) (files []string, lines int, err error) {
+	out, err := cmdOut(exec.Command("git", "-C", dir, "diff", "--numstat", "--no-renames", from, to))
+	if err != nil {
+		return nil, 0, err
+	}
+	for line := range strings.Lines(out) {
+		added, rest, _ := strings.Cut(strings.TrimSuffix(line, "\n"), "\t")
+		removed, file, ok := strings.Cut(rest, "\t")
+		if !ok {
+			return nil, 0, fmt.Errorf("unexpected git diff --numstat output line %q", line)
+		}
+		files = append(files, file)
+		if added == "-" { // Binary file.
+			continue
+		}
+		a, err1 := strconv.Atoi(added)
+		r, err2 := strconv.Atoi(removed)
+		if err1 != nil || err2 != nil {
+			return nil, 0, fmt.Errorf("unexpected git diff --numstat output line %q", line)
+		}
+		lines += a + r
+	}
+	return files, lines, nil
+}
+
 func reposRoot() string {
 	return filepath.Join(*workdir, "repos")
 }
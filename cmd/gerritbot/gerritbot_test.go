@@ -7,10 +7,12 @@ package main
 import (
 	"net/url"
 	"os/exec"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-github/v74/github"
+	"golang.org/x/build/cmd/gerritbot/internal/rules"
 	"golang.org/x/build/maintner"
 	"golang.org/x/build/repos"
 )
@@ -159,6 +161,55 @@ Cq-Include-Trybots: bot-a-123,bot-b-123
 	}
 }
 
+// TestCommitMessageFooterRules tests that the footers the author of a PR
+// writes at the end of its description are seen by the footer rules,
+// even though commitMessage puts GerritBot's footers after them.
+func TestCommitMessageFooterRules(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skipf("skipping; 'git' not in PATH")
+	}
+	cfg, err := rules.ParseConfig([]byte(`
+repos:
+  go:
+    required_footers: [Signed-off-by]
+  tools:
+    forbidden_footers: [Signed-off-by]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checker := rules.NewChecker(cfg)
+	for _, tc := range []struct {
+		repo string
+		body string
+		want []string // rule IDs of the footer rules that find problems
+	}{
+		{"go", "Body text.\n\nFixes #1234\n\nSigned-off-by: Gopher <gopher@golang.org>", nil},
+		{"go", "Body text.\n\nFixes #1234", []string{"footer-required"}},
+		{"tools", "Body text.\n\nFixes golang/go#1234\n\nSigned-off-by: Gopher <gopher@golang.org>", []string{"footer-forbidden"}},
+		{"tools", "Body text.\n\nFixes golang/go#1234", nil},
+	} {
+		pr := newPullRequest("cmd/gerritbot: title of change", tc.body)
+		cmsg, err := commitMessage(pr, nil)
+		if err != nil {
+			t.Fatalf("got unexpected error from commitMessage: %v", err)
+		}
+		change, err := rules.ParseCommitMessage(tc.repo, cmsg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, r := range checker.Check(change) {
+			if strings.HasPrefix(r.ID, "footer-") {
+				got = append(got, r.ID)
+			}
+		}
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("%s: rules for commit message %q: (-want +got)\n%s", tc.repo, cmsg, diff)
+		}
+	}
+}
+
 // Test that gerritChangeRE matches the URL to the Change within
 // the git output from Gerrit after successfully creating a new CL.
 // Whenever Gerrit changes the Change URL format in its output,
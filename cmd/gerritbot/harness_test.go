@@ -0,0 +1,303 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+// This file holds a harness for testing how GerritBot syncs a PR
+// and its CL offline. newTestBot loads a corpus from a mutation log
+// fixture in testdata and connects the bot to fake GitHub and Gerrit
+// servers that keep the comments posted to them and record the calls
+// that change something, for comparison against the calls a test expects.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+	"golang.org/x/build/gerrit"
+	"golang.org/x/build/maintner"
+	"golang.org/x/build/maintner/maintpb"
+	"golang.org/x/tools/txtar"
+	"google.golang.org/protobuf/encoding/prototext"
+)
+
+// newTestBot returns a bot whose corpus is loaded from the named
+// fixture in testdata, along with the fakes it uses for GitHub and Gerrit.
+//
+// A fixture is a txtar archive in which each file holds one
+// maintpb.Mutation in text format. The mutations are applied in order;
+// the file names only serve to describe them.
+func newTestBot(t *testing.T, fixture string) (*bot, *fakeGitHub, *fakeGerrit) {
+	t.Helper()
+	old, oldEpoch := *dryRun, relayEpoch
+	*dryRun, relayEpoch = false, time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	t.Cleanup(func() { *dryRun, relayEpoch = old, oldEpoch })
+
+	ar, err := txtar.ParseFile(filepath.Join("testdata", fixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var src fixtureSource
+	for _, f := range ar.Files {
+		m := new(maintpb.Mutation)
+		if err := prototext.Unmarshal(f.Data, m); err != nil {
+			t.Fatalf("%s: %s: %v", fixture, f.Name, err)
+		}
+		src = append(src, m)
+	}
+	corpus := new(maintner.Corpus)
+	if err := corpus.Initialize(context.Background(), src); err != nil {
+		t.Fatal(err)
+	}
+
+	gh := &fakeGitHub{nextID: 9000}
+	ghServer := httptest.NewServer(gh)
+	t.Cleanup(ghServer.Close)
+	ghClient := github.NewClient(nil)
+	ghClient.BaseURL, _ = url.Parse(ghServer.URL + "/")
+
+	gc := &fakeGerrit{comments: map[string]map[string][]gerrit.CommentInfo{}}
+	gcServer := httptest.NewServer(gc)
+	t.Cleanup(gcServer.Close)
+
+	b := newBot(ghClient, gerrit.NewClient(gcServer.URL, gerrit.NoAuth))
+	b.corpus = corpus
+	return b, gh, gc
+}
+
+// fixtureSource is a maintner.MutationSource that sends a fixed list of mutations.
+type fixtureSource []*maintpb.Mutation
+
+func (s fixtureSource) GetMutations(ctx context.Context) <-chan maintner.MutationStreamEvent {
+	ch := make(chan maintner.MutationStreamEvent, len(s)+1)
+	for _, m := range s {
+		ch <- maintner.MutationStreamEvent{Mutation: m}
+	}
+	ch <- maintner.MutationStreamEvent{End: true}
+	return ch
+}
+
+// gerritBotUser is the GitHub user GerritBot posts as in tests.
+var gerritBotUser = &github.User{ID: new(int64(1)), Login: new("gopherbot")}
+
+// fakeGitHub is a fake of the GitHub API for the review and issue
+// comments on PRs. It starts out with no comments, and records the
+// comments posted to it.
+type fakeGitHub struct {
+	mu             sync.Mutex
+	reviewComments map[int][]*github.PullRequestComment // by PR number
+	issueComments  map[int][]*github.IssueComment       // by PR number
+	reject         map[string]bool                      // paths GitHub rejects review comments on
+	nextID         int64                                // ID of the next comment posted
+	calls          []string                             // comments posted, in order
+	requests       int                                  // requests served
+}
+
+func (f *fakeGitHub) record(format string, args ...any) {
+	f.calls = append(f.calls, fmt.Sprintf(format, args...))
+}
+
+// addReviewComment adds c to the review comments on PR number.
+func (f *fakeGitHub) addReviewComment(number int, c *github.PullRequestComment) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.reviewComments == nil {
+		f.reviewComments = make(map[int][]*github.PullRequestComment)
+	}
+	f.reviewComments[number] = append(f.reviewComments[number], c)
+}
+
+// commentMarker returns the ID of the Gerrit comment that body mirrors,
+// or body itself if it doesn't mirror one.
+func commentMarker(body string) string {
+	if m := gerritCommentIDRE.FindStringSubmatch(body); m != nil {
+		return "gerrit:" + m[1]
+	}
+	return strconv.Quote(body)
+}
+
+func (f *fakeGitHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests++
+	// The comments on a PR are at /repos/<owner>/<repo>/{pulls,issues}/<number>/comments.
+	elems := strings.Split(r.URL.Path, "/")
+	if len(elems) != 7 || elems[1] != "repos" || elems[6] != "comments" {
+		http.Error(w, "unexpected request "+r.URL.Path, http.StatusNotFound)
+		return
+	}
+	kind := elems[4]
+	number, err := strconv.Atoi(elems[5])
+	if err != nil {
+		http.Error(w, "unexpected request "+r.URL.Path, http.StatusNotFound)
+		return
+	}
+	switch {
+	case kind == "pulls" && r.Method == "GET":
+		writeJSON(w, http.StatusOK, f.reviewComments[number])
+	case kind == "pulls" && r.Method == "POST":
+		var req struct {
+			Body      string `json:"body"`
+			CommitID  string `json:"commit_id"`
+			Path      string `json:"path"`
+			Line      int    `json:"line"`
+			Side      string `json:"side"`
+			InReplyTo int64  `json:"in_reply_to"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		c := &github.PullRequestComment{
+			ID:        new(f.nextID),
+			Body:      new(req.Body),
+			User:      gerritBotUser,
+			CreatedAt: &github.Timestamp{Time: time.Now()},
+		}
+		if req.InReplyTo != 0 {
+			f.record("CreateCommentInReplyTo %d %s", req.InReplyTo, commentMarker(req.Body))
+			c.InReplyTo = new(req.InReplyTo)
+		} else {
+			f.record("CreateComment %s:%d %s@%s %s", req.Path, req.Line, req.Side, req.CommitID, commentMarker(req.Body))
+			if f.reject[req.Path] {
+				writeJSON(w, http.StatusUnprocessableEntity, map[string]string{"message": "Validation Failed"})
+				return
+			}
+			c.Path = new(req.Path)
+		}
+		f.nextID++
+		if f.reviewComments == nil {
+			f.reviewComments = make(map[int][]*github.PullRequestComment)
+		}
+		f.reviewComments[number] = append(f.reviewComments[number], c)
+		writeJSON(w, http.StatusCreated, c)
+	case kind == "issues" && r.Method == "GET":
+		writeJSON(w, http.StatusOK, f.issueComments[number])
+	case kind == "issues" && r.Method == "POST":
+		c := new(github.IssueComment)
+		if err := json.NewDecoder(r.Body).Decode(c); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.record("CreateIssueComment %s", commentMarker(c.GetBody()))
+		c.ID, c.User = new(f.nextID), gerritBotUser
+		f.nextID++
+		if f.issueComments == nil {
+			f.issueComments = make(map[int][]*github.IssueComment)
+		}
+		f.issueComments[number] = append(f.issueComments[number], c)
+		writeJSON(w, http.StatusCreated, c)
+	default:
+		http.Error(w, "unexpected request "+r.Method+" "+r.URL.Path, http.StatusNotFound)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+// gerritBotAccount is GerritBot's Gerrit account,
+// which owns the CLs it imports.
+var gerritBotAccount = &gerrit.AccountInfo{NumericID: 12446, Name: "GerritBot"}
+
+// fakeGerrit is a fake of the Gerrit API for the comments on changes.
+// It keeps the comments posted through it, and records the reviews set.
+type fakeGerrit struct {
+	mu       sync.Mutex
+	comments map[string]map[string][]gerrit.CommentInfo // change ID -> path -> comments
+	messages map[string][]string                        // change ID -> messages posted
+	nextID   int                                        // number in the ID of the next comment posted
+	calls    []string                                   // reviews set, in order
+	requests int                                        // requests served
+}
+
+func (f *fakeGerrit) record(format string, args ...any) {
+	f.calls = append(f.calls, fmt.Sprintf(format, args...))
+}
+
+// addComment adds c to the comments on path in the change with the given ID.
+func (f *fakeGerrit) addComment(changeID, path string, c gerrit.CommentInfo) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.comments[changeID] == nil {
+		f.comments[changeID] = make(map[string][]gerrit.CommentInfo)
+	}
+	f.comments[changeID][path] = append(f.comments[changeID][path], c)
+}
+
+func (f *fakeGerrit) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests++
+	rest, ok := strings.CutPrefix(r.URL.Path, "/changes/")
+	if !ok {
+		http.Error(w, "unexpected request "+r.URL.Path, http.StatusNotFound)
+		return
+	}
+	changeID, rest, _ := strings.Cut(rest, "/")
+	switch {
+	case rest == "comments" && r.Method == "GET":
+		writeGerritJSON(w, f.comments[changeID])
+	case rest == "detail" && r.Method == "GET":
+		// No TryBot results.
+		writeGerritJSON(w, gerrit.ChangeInfo{})
+	case strings.HasPrefix(rest, "revisions/") && strings.HasSuffix(rest, "/review") && r.Method == "POST":
+		revision := strings.TrimSuffix(strings.TrimPrefix(rest, "revisions/"), "/review")
+		var review gerrit.ReviewInput
+		if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if review.Message != "" {
+			f.record("SetReview %s %s message=%q", changeID, revision, review.Message)
+			if f.messages == nil {
+				f.messages = make(map[string][]string)
+			}
+			f.messages[changeID] = append(f.messages[changeID], review.Message)
+		}
+		patchSet, _ := strconv.Atoi(revision)
+		for path, cs := range review.Comments {
+			for _, c := range cs {
+				f.record("SetReview %s %s %s:%d in_reply_to=%s %q", changeID, revision, path, c.Line, c.InReplyTo, c.Message)
+				f.nextID++
+				if f.comments[changeID] == nil {
+					f.comments[changeID] = make(map[string][]gerrit.CommentInfo)
+				}
+				f.comments[changeID][path] = append(f.comments[changeID][path], gerrit.CommentInfo{
+					ID:        fmt.Sprintf("posted%d", f.nextID),
+					PatchSet:  patchSet,
+					Side:      c.Side,
+					Line:      c.Line,
+					InReplyTo: c.InReplyTo,
+					Message:   c.Message,
+					Updated:   gerrit.TimeStamp(time.Now()),
+					Author:    gerritBotAccount,
+				})
+			}
+		}
+		writeGerritJSON(w, struct{}{})
+	default:
+		http.Error(w, "unexpected request "+r.Method+" "+r.URL.Path, http.StatusNotFound)
+	}
+}
+
+// writeGerritJSON writes v as a Gerrit API response,
+// with the header that defeats XSSI attacks.
+func writeGerritJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintln(w, ")]}'")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,377 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rules
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// builtinConfig is the configuration used by Check.
+//
+//go:embed config.yaml
+var builtinConfig []byte
+
+// A Config configures which rules apply to which repos.
+type Config struct {
+	// Default holds the settings for repos not listed in Repos,
+	// and the settings that a repo's entry in Repos doesn't give.
+	Default RepoConfig `yaml:"default"`
+
+	// Repos holds the settings for individual repos, keyed by Gerrit
+	// project name. Settings given here replace those in Default,
+	// except for Disable, which adds to the rules Default disables.
+	Repos map[string]RepoConfig `yaml:"repos"`
+}
+
+// A RepoConfig holds the settings for a repo.
+// Rules whose settings are unset don't report any findings.
+type RepoConfig struct {
+	// Skip turns off all rules for the repo.
+	Skip bool `yaml:"skip"`
+
+	// Disable lists the IDs of rules not to run for the repo.
+	Disable []string `yaml:"disable"`
+
+	// TitlePattern is a regular expression that commit titles must match
+	// (rule "title-format"). TitleExample is an example of a good title,
+	// to show when one doesn't match.
+	TitlePattern string `yaml:"title_pattern"`
+	TitleExample string `yaml:"title_example"`
+
+	// RequireBug requires every change to reference a bug,
+	// even if it looks trivial (rule "bug-required").
+	RequireBug *bool `yaml:"require_bug"`
+
+	// ForbiddenFiles lists files that changes must not modify
+	// (rule "files-forbidden"). A pattern ending in a slash matches
+	// everything in that directory; other patterns are matched against
+	// the whole file name with path.Match.
+	ForbiddenFiles []string `yaml:"forbidden_files"`
+
+	// MaxDiffLines is the most lines that a change may add and remove
+	// in total (rule "diff-size"). Zero means no limit.
+	MaxDiffLines int `yaml:"max_diff_lines"`
+
+	// RequiredFooters and ForbiddenFooters list commit message footer
+	// keys, like "Signed-off-by", that changes must and must not have
+	// (rules "footer-required" and "footer-forbidden").
+	RequiredFooters  []string `yaml:"required_footers"`
+	ForbiddenFooters []string `yaml:"forbidden_footers"`
+
+	titleRE *regexp.Regexp
+}
+
+// ParseConfig parses a configuration in YAML form.
+func ParseConfig(data []byte) (*Config, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	c := new(Config)
+	if err := dec.Decode(c); err != nil {
+		return nil, fmt.Errorf("rules: parsing config: %v", err)
+	}
+	known := make(map[string]bool)
+	for _, r := range NewChecker(&Config{}).Rules() {
+		known[r.ID] = true
+	}
+	var errs []error
+	check := func(name string, rc *RepoConfig) {
+		for _, id := range rc.Disable {
+			if !known[id] {
+				errs = append(errs, fmt.Errorf("%s: disable: unknown rule %q", name, id))
+			}
+		}
+		if rc.TitlePattern != "" {
+			re, err := regexp.Compile(rc.TitlePattern)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: title_pattern: %v", name, err))
+			}
+			rc.titleRE = re
+		}
+		for _, p := range rc.ForbiddenFiles {
+			if _, err := path.Match(p, ""); err != nil {
+				errs = append(errs, fmt.Errorf("%s: forbidden_files: %q: %v", name, p, err))
+			}
+		}
+		if rc.MaxDiffLines < 0 {
+			errs = append(errs, fmt.Errorf("%s: max_diff_lines: negative limit", name))
+		}
+		for _, f := range slices.Concat(rc.RequiredFooters, rc.ForbiddenFooters) {
+			if f == "" || strings.ContainsAny(f, ": \t") {
+				errs = append(errs, fmt.Errorf("%s: bad footer key %q", name, f))
+			}
+		}
+	}
+	check("default", &c.Default)
+	for _, repo := range slices.Sorted(func(yield func(string) bool) {
+		for repo := range c.Repos {
+			if !yield(repo) {
+				return
+			}
+		}
+	}) {
+		rc := c.Repos[repo]
+		check("repos: "+repo, &rc)
+		c.Repos[repo] = rc
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("rules: config: %w", err)
+	}
+	return c, nil
+}
+
+// LoadConfig reads and parses the named configuration file.
+// If file is empty, it returns the built-in configuration.
+func LoadConfig(file string) (*Config, error) {
+	if file == "" {
+		return ParseConfig(builtinConfig)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	c, err := ParseConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	return c, nil
+}
+
+// forRepo returns the settings for repo.
+func (c *Config) forRepo(repo string) RepoConfig {
+	rc := c.Default
+	r, ok := c.Repos[repo]
+	if !ok {
+		return rc
+	}
+	rc.Skip = r.Skip
+	rc.Disable = slices.Concat(rc.Disable, r.Disable)
+	if r.TitlePattern != "" {
+		rc.TitlePattern, rc.TitleExample, rc.titleRE = r.TitlePattern, r.TitleExample, r.titleRE
+	}
+	if r.RequireBug != nil {
+		rc.RequireBug = r.RequireBug
+	}
+	if r.ForbiddenFiles != nil {
+		rc.ForbiddenFiles = r.ForbiddenFiles
+	}
+	if r.MaxDiffLines != 0 {
+		rc.MaxDiffLines = r.MaxDiffLines
+	}
+	if r.RequiredFooters != nil {
+		rc.RequiredFooters = r.RequiredFooters
+	}
+	if r.ForbiddenFooters != nil {
+		rc.ForbiddenFooters = r.ForbiddenFooters
+	}
+	return rc
+}
+
+// configRules returns the groups of rules whose settings are in c.
+func configRules(c *Config) [][]Rule {
+	return [][]Rule{
+		{{
+			ID:   "title-format",
+			Name: "title: does not match repo format",
+			URL:  commitMessages,
+			Func: func(change Change) (finding string, note string) {
+				rc := c.forRepo(change.Repo)
+				if rc.titleRE == nil || rc.titleRE.MatchString(change.Title) {
+					return "", ""
+				}
+				if rc.TitleExample != "" {
+					return fmt.Sprintf("The commit title does not follow the format used in the %s repo, like \"%s\".", change.Repo, rc.TitleExample), commitMessageAdvice
+				}
+				return fmt.Sprintf("The commit title does not follow the format used in the %s repo, which must match the regular expression `%s`.", change.Repo, rc.TitlePattern), commitMessageAdvice
+			},
+		}},
+		{{
+			ID:   "bug-required",
+			Name: "body: required bug reference not found",
+			URL:  commitMessages,
+			Func: func(change Change) (finding string, note string) {
+				rc := c.forRepo(change.Repo)
+				if rc.RequireBug == nil || !*rc.RequireBug {
+					return "", ""
+				}
+				if match(`(?m)^(Fixes|Updates|For|Closes|Resolves) \S*#\d+\.?$`, change.Body) {
+					return "", ""
+				}
+				return fmt.Sprintf("Every change to the %s repo must reference a bug, even a trivial one. %s at the end of the commit message.", change.Repo, bugExamples(change.Repo)), commitMessageAdvice
+			},
+		}},
+		{{
+			ID:   "files-forbidden",
+			Name: "files: modifies forbidden files",
+			URL:  contributing,
+			Func: func(change Change) (finding string, note string) {
+				rc := c.forRepo(change.Repo)
+				var bad []string
+				for _, f := range change.Files {
+					if slices.ContainsFunc(rc.ForbiddenFiles, func(p string) bool { return matchFile(p, f) }) {
+						bad = append(bad, f)
+					}
+				}
+				switch {
+				case len(bad) == 0:
+					return "", ""
+				case len(bad) > 3:
+					bad = append(bad[:3], "...")
+				}
+				return fmt.Sprintf("This change modifies files that should not be changed directly in the %s repo: %s.", change.Repo, strings.Join(bad, ", ")), ""
+			},
+		}},
+		{{
+			ID:   "diff-size",
+			Name: "files: change is too large",
+			URL:  contributing,
+			Func: func(change Change) (finding string, note string) {
+				rc := c.forRepo(change.Repo)
+				if rc.MaxDiffLines == 0 || change.DiffLines <= rc.MaxDiffLines {
+					return "", ""
+				}
+				return fmt.Sprintf("This change adds or removes %d lines, more than the %d expected for a single change to the %s repo. Could it be split into a series of smaller changes?", change.DiffLines, rc.MaxDiffLines, change.Repo), ""
+			},
+		}},
+		{{
+			ID:   "footer-required",
+			Name: "footer: required footer missing",
+			URL:  commitMessages,
+			Func: func(change Change) (finding string, note string) {
+				rc := c.forRepo(change.Repo)
+				for _, key := range rc.RequiredFooters {
+					if !hasFooter(change, key) {
+						return fmt.Sprintf("Changes to the %s repo must have a '%s:' line at the end of the commit message.", change.Repo, key), commitMessageAdvice
+					}
+				}
+				return "", ""
+			},
+		}},
+		{{
+			ID:   "footer-forbidden",
+			Name: "footer: forbidden footer present",
+			URL:  commitMessages,
+			Func: func(change Change) (finding string, note string) {
+				rc := c.forRepo(change.Repo)
+				for _, key := range rc.ForbiddenFooters {
+					if hasFooter(change, key) {
+						return fmt.Sprintf("Please remove the '%s:' line from the end of the commit message; the %s repo does not use it.", key, change.Repo), commitMessageAdvice
+					}
+				}
+				return "", ""
+			},
+		}},
+	}
+}
+
+// matchFile reports whether the file name matches the pattern,
+// as described for [RepoConfig.ForbiddenFiles].
+func matchFile(pattern, name string) bool {
+	if strings.HasSuffix(pattern, "/") {
+		return strings.HasPrefix(name, pattern)
+	}
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
+
+// hasFooter reports whether change has a footer with the given key.
+func hasFooter(change Change, key string) bool {
+	return slices.ContainsFunc(change.Footers, func(f string) bool {
+		return strings.HasPrefix(f, key+": ")
+	})
+}
+
+// A Checker checks changes against a set of rules: the built-in rules,
+// then those configured by its Config, then those added with Add.
+type Checker struct {
+	config *Config
+	groups [][]Rule
+	ids    map[string]bool
+}
+
+// NewChecker returns a Checker using the given configuration.
+func NewChecker(config *Config) *Checker {
+	c := &Checker{config: config, ids: make(map[string]bool)}
+	for _, group := range slices.Concat(ruleGroups, configRules(config)) {
+		if err := c.Add(group...); err != nil {
+			panic(err)
+		}
+	}
+	return c
+}
+
+// Add adds a group of rules to c, to be checked after those already added.
+// At most one rule in the group reports a finding for a change: the first
+// one that finds a problem. Add returns an error if a rule lacks an ID or
+// function, or has the ID of another rule.
+//
+// Added rules run for every repo not skipped by c's Config; the Config
+// can only disable the built-in and configured rules.
+func (c *Checker) Add(group ...Rule) error {
+	for i, r := range group {
+		if r.ID == "" || r.Func == nil {
+			return fmt.Errorf("rules: rule %q needs an ID and a Func", r.Name)
+		}
+		if c.ids[r.ID] || slices.ContainsFunc(group[:i], func(r1 Rule) bool { return r1.ID == r.ID }) {
+			return fmt.Errorf("rules: duplicate rule ID %q", r.ID)
+		}
+	}
+	for _, r := range group {
+		c.ids[r.ID] = true
+	}
+	c.groups = append(c.groups, slices.Clone(group))
+	return nil
+}
+
+// Rules returns c's rules in the order they are checked.
+func (c *Checker) Rules() []Rule {
+	return slices.Concat(c.groups...)
+}
+
+// Check runs c's rules against change, skipping any disabled for its repo.
+func (c *Checker) Check(change Change) (results []Result) {
+	rc := c.config.forRepo(change.Repo)
+	if rc.Skip {
+		return nil
+	}
+	for _, group := range c.groups {
+		for _, rule := range group {
+			if slices.Contains(rc.Disable, rule.ID) {
+				continue
+			}
+			finding, advice := rule.Func(change)
+			if finding != "" {
+				results = append(results, Result{
+					ID:      rule.ID,
+					Name:    rule.Name,
+					URL:     rule.URL,
+					Finding: finding,
+					Note:    advice,
+				})
+				break // Only report the first finding per rule group.
+			}
+		}
+	}
+	return results
+}
+
+// defaultChecker returns the Checker used by Check.
+var defaultChecker = sync.OnceValue(func() *Checker {
+	c, err := ParseConfig(builtinConfig)
+	if err != nil {
+		panic(err)
+	}
+	return NewChecker(c)
+})
@@ -0,0 +1,179 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rules
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseConfigErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{"unknown field", "default:\n  disabled: [title-verb]\n", "field disabled not found"},
+		{"unknown rule", "repos:\n  tools:\n    disable: [no-such-rule]\n", `repos: tools: disable: unknown rule "no-such-rule"`},
+		{"bad title pattern", "default:\n  title_pattern: '('\n", "default: title_pattern"},
+		{"bad file pattern", "repos:\n  go:\n    forbidden_files: ['[']\n", `repos: go: forbidden_files: "["`},
+		{"negative diff size", "default:\n  max_diff_lines: -1\n", "default: max_diff_lines"},
+		{"bad footer", "default:\n  required_footers: ['Signed-off-by:']\n", `default: bad footer key "Signed-off-by:"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseConfig([]byte(tt.config))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseConfig error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuiltinConfig(t *testing.T) {
+	if _, err := ParseConfig(builtinConfig); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConfiguredRules(t *testing.T) {
+	const config = `
+default:
+  forbidden_footers: [Signed-off-by]
+  disable: [body-signed-off-by]
+repos:
+  vscode-go:
+    title_pattern: '^[a-z/.-]+: '
+    title_example: 'extension/src: fix the thing'
+    require_bug: true
+    required_footers: [Reviewed-on]
+    forbidden_footers: []
+    disable: [title-package, title-colon-space, bug-missing, bug-format, bug-at-end]
+  go:
+    forbidden_files: [src/vendor/, go.sum]
+    max_diff_lines: 100
+    disable: [body-short]
+`
+	cfg, err := ParseConfig([]byte(config))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewChecker(cfg)
+
+	tests := []struct {
+		name   string
+		change Change
+		want   []string // rule IDs
+	}{
+		{
+			name: "good",
+			change: Change{
+				Repo:      "go",
+				Title:     goodCommitTitle,
+				Body:      goodCommitBody,
+				Files:     []string{"src/net/http/server.go"},
+				DiffLines: 100,
+			},
+			want: nil,
+		},
+		{
+			name: "forbidden files and large diff",
+			change: Change{
+				Repo:      "go",
+				Title:     goodCommitTitle,
+				Body:      goodCommitBody,
+				Files:     []string{"src/vendor/golang.org/x/net/http2/frame.go", "src/go.sum"},
+				DiffLines: 101,
+			},
+			want: []string{"files-forbidden", "diff-size"},
+		},
+		{
+			name: "disabled in repo, forbidden footer by default",
+			change: Change{
+				Repo:    "go",
+				Title:   goodCommitTitle,
+				Body:    "Short.\n\nFixes #1234",
+				Footers: []string{"Signed-off-by: Gopher <gopher@golang.org>"},
+			},
+			want: []string{"footer-forbidden"},
+		},
+		{
+			name: "title format, missing bug and footer",
+			change: Change{
+				Repo:    "vscode-go",
+				Title:   "Fix the thing",
+				Body:    "A commit message body that does not trigger any rules.",
+				Footers: []string{"Signed-off-by: Gopher <gopher@golang.org>"},
+			},
+			want: []string{"title-verb", "title-format", "bug-required", "footer-required"},
+		},
+		{
+			name: "title format, bug and footer ok",
+			change: Change{
+				Repo:    "vscode-go",
+				Title:   goodCommitTitle,
+				Body:    goodCommitBody,
+				Footers: []string{"Reviewed-on: https://go-review.googlesource.com/c/vscode-go/+/1"},
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, r := range c.Check(tt.change) {
+				got = append(got, r.ID)
+				if r.URL == "" {
+					t.Errorf("rule %s has no URL", r.ID)
+				}
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Check mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSkippedRepos(t *testing.T) {
+	change := Change{Title: "Fix the thing.", Body: "Short."}
+	for _, repo := range []string{"wiki", "proposal"} {
+		change.Repo = repo
+		var got []string
+		for _, r := range Check(change) {
+			got = append(got, r.ID)
+		}
+		var want []string
+		if repo == "proposal" {
+			want = []string{"title-period", "body-short"}
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Check in %s repo mismatch (-want +got):\n%s", repo, diff)
+		}
+	}
+}
+
+func TestCheckerAdd(t *testing.T) {
+	c := NewChecker(&Config{})
+	noop := func(Change) (string, string) { return "", "" }
+	if err := c.Add(Rule{ID: "title-verb", Func: noop}); err == nil {
+		t.Errorf("Add of built-in rule ID succeeded, want error")
+	}
+	if err := c.Add(Rule{ID: "x", Func: noop}, Rule{ID: "x", Func: noop}); err == nil {
+		t.Errorf("Add of duplicate IDs in one group succeeded, want error")
+	}
+	if err := c.Add(Rule{ID: "no-func"}); err == nil {
+		t.Errorf("Add of rule without Func succeeded, want error")
+	}
+	found := func(Change) (string, string) { return "Found it.", "" }
+	if err := c.Add(Rule{ID: "custom", Name: "custom rule", Func: found}); err != nil {
+		t.Fatal(err)
+	}
+	results := c.Check(Change{Repo: "go", Title: goodCommitTitle, Body: goodCommitBody})
+	if len(results) != 1 || results[0].ID != "custom" {
+		t.Errorf("Check = %+v, want one finding from custom rule", results)
+	}
+}
@@ -11,10 +11,18 @@
 // if the repo primarily contains Go code and follows typical patterns like
 // using the main Go issue tracker. If a new repo is unusual in some way that
 // causes a notable problem, some simple options include:
-//   - adding the repo to skipAll to ignore the repo entirely
-//   - adding the repo to the skip field on individual rules
+//   - setting skip for the repo in config.yaml to ignore the repo entirely
+//   - listing individual rules to disable for the repo in config.yaml
 //   - updating the usesTracker and packageExample functions if needed
 //
+// The per-repo settings in config.yaml also turn on rules that only make
+// sense for some repos, such as requiring a bug reference or limiting the
+// size of a change. See [Config] for the settings.
+//
+// Every rule has a stable ID, used to refer to it in the configuration,
+// and a URL of a page explaining what the rule asks for. IDs must never
+// be reused for a different rule.
+//
 // A rule is primarily defined via a function that takes a Change (CL or PR) as input
 // and reports zero or 1 findings, which is just a string (usually 1-2 short sentences).
 // A rule can also optionally return a note, which might be auxiliary advice such as
@@ -35,8 +43,8 @@
 //
 //	Possible problems detected:
 //	  1. The first word in the commit title after the package should be a
-//	     lowercase English word (usually a verb).
-//	  2. The commit title should not end with a period.
+//	     lowercase English word (usually a verb). ([title-verb](...))
+//	  2. The commit title should not end with a period. ([title-period](...))
 //
 //	 To edit the commit message, see instructions [here](...). For guidance on commit
 //	 messages for the Go project, see [here](...).
@@ -62,40 +70,47 @@ import (
 	"strings"
 )
 
-// rule defines a single rule that can report a finding.
+// A Rule defines a single rule that can report a finding.
 // See the package comment for an overview.
-type rule struct {
-	// name is a short internal rule name that don't expect to tweak frequently.
+type Rule struct {
+	// ID is the stable identifier of the rule, like "title-period",
+	// used to refer to it in configuration.
+	ID string
+
+	// Name is a short internal rule name that we don't expect to tweak frequently.
 	// We don't show it to users, but do use in tests.
-	name string
+	Name string
+
+	// URL is the address of a page explaining what the rule asks for.
+	URL string
 
-	// f is the rule function that reports a single finding and optionally
+	// Func is the rule function that reports a single finding and optionally
 	// an auxiliary note, which for example could be advice on how to edit the commit message.
 	// Notes are deduplicated across different rules, and ignored for a given rule if there is no finding.
-	f func(change Change) (finding string, note string)
-
-	// skip lists repos to skip for this rule.
-	skip []string
-
-	// only lists the only repos that this rule will run against.
-	only []string
+	Func func(change Change) (finding string, note string)
 }
 
-// skipAll lists repos that we ignore entirely by skipping all rules.
-var skipAll = []string{"wiki"}
+// Pages explaining rules.
+const (
+	commitMessages = "https://go.dev/doc/contribute#commit_messages"
+	contributing   = "https://go.dev/doc/contribute"
+	gerritBotWiki  = "https://go.dev/wiki/GerritBot"
+)
 
-// ruleGroups defines our live set of rules. It is a [][]rule
+// ruleGroups defines our built-in set of rules. It is a [][]Rule
 // because the individual rules are arranged into groups of rules
 // that are mutually exclusive, where the first triggering rule wins
-// within a []rule in ruleGroups. See the package comment for details.
-var ruleGroups = [][]rule{
+// within a []Rule in ruleGroups. See the package comment for details.
+// The rules configured in config.yaml follow these.
+var ruleGroups = [][]Rule{
 	{
 		// We have two rules in this rule group, and hence we report at most one of them.
 		// The second (pickier) rule is checked only if the first doesn't trigger.
 		{
-			name: "title: no package found",
-			skip: []string{"proposal"}, // We allow the proposal repo to have an irregular title.
-			f: func(change Change) (finding string, note string) {
+			ID:   "title-package",
+			Name: "title: no package found",
+			URL:  commitMessages,
+			Func: func(change Change) (finding string, note string) {
 				component, example := packageExample(change.Repo)
 				finding = fmt.Sprintf("The commit title should start with the primary affected %s name followed by a colon, like \"%s\".", component, example)
 				start, _, ok := strings.Cut(change.Title, ":")
@@ -131,9 +146,10 @@ var ruleGroups = [][]rule{
 			},
 		},
 		{
-			name: "title: no colon then single space after package",
-			skip: []string{"proposal"}, // We allow the proposal repo to have an irregular title.
-			f: func(change Change) (finding string, note string) {
+			ID:   "title-colon-space",
+			Name: "title: no colon then single space after package",
+			URL:  commitMessages,
+			Func: func(change Change) (finding string, note string) {
 				component, example := packageExample(change.Repo)
 				finding = fmt.Sprintf("The %s in the commit title should be followed by a colon and single space, like \"%s\".", component, example)
 				if !match(`[^ ]: [^ ]`, change.Title) {
@@ -145,9 +161,10 @@ var ruleGroups = [][]rule{
 	},
 	{
 		{
-			name: "title: no lowercase word after a first colon",
-			skip: []string{"proposal"}, // We allow the proposal repo to have an irregular title.
-			f: func(change Change) (finding string, note string) {
+			ID:   "title-verb",
+			Name: "title: no lowercase word after a first colon",
+			URL:  commitMessages,
+			Func: func(change Change) (finding string, note string) {
 				component, _ := packageExample(change.Repo)
 				finding = fmt.Sprintf("The first word in the commit title after the %s should be a lowercase English word (usually a verb).", component)
 				_, after, ok := strings.Cut(change.Title, ":")
@@ -165,8 +182,10 @@ var ruleGroups = [][]rule{
 	},
 	{
 		{
-			name: "title: ends with period",
-			f: func(change Change) (finding string, note string) {
+			ID:   "title-period",
+			Name: "title: ends with period",
+			URL:  commitMessages,
+			Func: func(change Change) (finding string, note string) {
 				finding = "The commit title should not end with a period."
 				if len(change.Title) > 0 && change.Title[len(change.Title)-1] == '.' {
 					return finding, commitMessageAdvice
@@ -179,8 +198,10 @@ var ruleGroups = [][]rule{
 		// We have two rules in this group, and hence we report at most one of them.
 		// The second rule is pickier.
 		{
-			name: "body: short",
-			f: func(change Change) (finding string, note string) {
+			ID:   "body-short",
+			Name: "body: short",
+			URL:  commitMessages,
+			Func: func(change Change) (finding string, note string) {
 				finding = "The commit message body is %s. " +
 					"That can be OK if the change is trivial like correcting spelling or fixing a broken link, " +
 					"but usually the description should provide context for the change and explain what it does in complete sentences."
@@ -197,8 +218,10 @@ var ruleGroups = [][]rule{
 			},
 		},
 		{
-			name: "body: no sentence candidates found",
-			f: func(change Change) (finding string, note string) {
+			ID:   "body-sentences",
+			Name: "body: no sentence candidates found",
+			URL:  commitMessages,
+			Func: func(change Change) (finding string, note string) {
 				finding = "Are you describing the change in complete sentences with correct punctuation in the commit message body, including ending sentences with periods?"
 				if !mightBeTrivial(change) && !match("[a-zA-Z0-9\"'`)][.:?!)]( |\\n|$)", change.Body) {
 					// A complete English sentence usually ends with an alphanumeric or quote immediately followed by a terminating punctuation.
@@ -213,8 +236,10 @@ var ruleGroups = [][]rule{
 	},
 	{
 		{
-			name: "body: long lines",
-			f: func(change Change) (finding string, note string) {
+			ID:   "body-long-lines",
+			Name: "body: long lines",
+			URL:  commitMessages,
+			Func: func(change Change) (finding string, note string) {
 				finding = "You have a long %d character line in the commit message body. " +
 					"Please add line breaks to long lines that should be wrapped. " +
 					"Lines in the commit message body should be wrapped at ~76 characters unless needed for things like URLs or tables. " +
@@ -248,8 +273,10 @@ var ruleGroups = [][]rule{
 	},
 	{
 		{
-			name: "body: might use markdown",
-			f: func(change Change) (finding string, note string) {
+			ID:   "body-markdown",
+			Name: "body: might use markdown",
+			URL:  commitMessages,
+			Func: func(change Change) (finding string, note string) {
 				// In practice, this rule has had close to zero false positives, though it cannot be perfect.
 				finding = "It looks like you are using markdown in the commit message. If so, please remove it. " +
 					"Be sure to double-check the plain text shown in the Gerrit commit message above for any " +
@@ -288,8 +315,10 @@ var ruleGroups = [][]rule{
 	},
 	{
 		{
-			name: "body: still contains PR instructions",
-			f: func(change Change) (finding string, note string) {
+			ID:   "body-pr-instructions",
+			Name: "body: still contains PR instructions",
+			URL:  gerritBotWiki,
+			Func: func(change Change) (finding string, note string) {
 				finding = "Do you still have the GitHub PR instructions in your commit message text? The PR instructions should be deleted once you have applied them."
 				if strings.Contains(change.Body, "Delete these instructions once you have read and applied them") {
 					return finding, commitMessageAdvice
@@ -300,10 +329,12 @@ var ruleGroups = [][]rule{
 	},
 	{
 		{
-			name: "body: contains Signed-off-by",
-			f: func(change Change) (finding string, note string) {
+			ID:   "body-signed-off-by",
+			Name: "body: contains Signed-off-by",
+			URL:  contributing,
+			Func: func(change Change) (finding string, note string) {
 				finding = "Please do not use 'Signed-off-by'. We instead rely on contributors signing CLAs."
-				if match(`(?mi)^Signed-off-by: `, change.Body) {
+				if match(`(?mi)^Signed-off-by: `, change.Body) || match(`(?mi)^Signed-off-by: `, strings.Join(change.Footers, "\n")) {
 					return finding, commitMessageAdvice
 				}
 				return "", ""
@@ -313,12 +344,13 @@ var ruleGroups = [][]rule{
 	{
 		// We have three rules in this group, and hence we report at most one of them.
 		// The three rules get progressively pickier.
-		// We exempt the proposal repo from these rules because almost all GitHub PRs
-		// for the proposal repo are for typos or similar.
+		// config.yaml exempts the proposal repo from these rules because almost
+		// all GitHub PRs for the proposal repo are for typos or similar.
 		{
-			name: "body: no bug reference candidate found",
-			skip: []string{"proposal"},
-			f: func(change Change) (finding string, note string) {
+			ID:   "bug-missing",
+			Name: "body: no bug reference candidate found",
+			URL:  commitMessages,
+			Func: func(change Change) (finding string, note string) {
 				finding = "You usually need to reference a bug number for all but trivial or cosmetic fixes. " +
 					"%s at the end of the commit message. Should you have a bug reference?"
 				if mightBeTrivial(change) {
@@ -338,9 +370,10 @@ var ruleGroups = [][]rule{
 			},
 		},
 		{
-			name: "body: bug format looks incorrect",
-			skip: []string{"proposal"},
-			f: func(change Change) (finding string, note string) {
+			ID:   "bug-format",
+			Name: "body: bug format looks incorrect",
+			URL:  commitMessages,
+			Func: func(change Change) (finding string, note string) {
 				finding = "Do you have the right bug reference format? %s at the end of the commit message."
 				if mightBeTrivial(change) {
 					return "", ""
@@ -363,9 +396,10 @@ var ruleGroups = [][]rule{
 			},
 		},
 		{
-			name: "body: no bug reference candidate at end",
-			skip: []string{"proposal"},
-			f: func(change Change) (finding string, note string) {
+			ID:   "bug-at-end",
+			Name: "body: no bug reference candidate at end",
+			URL:  commitMessages,
+			Func: func(change Change) (finding string, note string) {
 				// If this rule is running, it means it passed the earlier bug-related rules
 				// in this group, so we know there is what looks like a well-formed bug.
 				finding = "It looks like you have a properly formated bug reference, but the convention is to " +
@@ -5,6 +5,7 @@
 package rules
 
 import (
+	"slices"
 	"strings"
 	"testing"
 
@@ -28,9 +29,9 @@ func TestFormatRuleResults(t *testing.T) {
 		{
 			title: `a bad commit message title.`,
 			body:  goodCommitBody,
-			wantFindings: `  1. The commit title should start with the primary affected package name followed by a colon, like "net/http: improve [...]".
-  2. The first word in the commit title after the package should be a lowercase English word (usually a verb).
-  3. The commit title should not end with a period.
+			wantFindings: `  1. The commit title should start with the primary affected package name followed by a colon, like "net/http: improve [...]". ([title-package](https://go.dev/doc/contribute#commit_messages))
+  2. The first word in the commit title after the package should be a lowercase English word (usually a verb). ([title-verb](https://go.dev/doc/contribute#commit_messages))
+  3. The commit title should not end with a period. ([title-period](https://go.dev/doc/contribute#commit_messages))
 `,
 			wantNotes: commitMessageAdvice,
 		},
@@ -38,9 +39,9 @@ func TestFormatRuleResults(t *testing.T) {
 			title: `A bad vscode-go commit title`, // This verifies we complain about a "component" rather than "package".
 			repo:  "vscode-go",
 			body:  "This includes a bad bug format for vscode-go repo.\nFixes #1234",
-			wantFindings: `  1. The commit title should start with the primary affected component name followed by a colon, like "src/goInstallTools: improve [...]".
-  2. The first word in the commit title after the component should be a lowercase English word (usually a verb).
-  3. Do you have the right bug reference format? For the vscode-go repo, the format is usually 'Fixes golang/vscode-go#1234' or 'Updates golang/vscode-go#1234' at the end of the commit message.
+			wantFindings: `  1. The commit title should start with the primary affected component name followed by a colon, like "src/goInstallTools: improve [...]". ([title-package](https://go.dev/doc/contribute#commit_messages))
+  2. The first word in the commit title after the component should be a lowercase English word (usually a verb). ([title-verb](https://go.dev/doc/contribute#commit_messages))
+  3. Do you have the right bug reference format? For the vscode-go repo, the format is usually 'Fixes golang/vscode-go#1234' or 'Updates golang/vscode-go#1234' at the end of the commit message. ([bug-format](https://go.dev/doc/contribute#commit_messages))
 `,
 			wantNotes: commitMessageAdvice,
 		},
@@ -54,14 +55,14 @@ func TestFormatRuleResults(t *testing.T) {
 		{
 			title:        goodCommitTitle,
 			body:         "This commit body is missing a bug reference.",
-			wantFindings: "  1. You usually need to reference a bug number for all but trivial or cosmetic fixes. For this repo, the format is usually 'Fixes #12345' or 'Updates #12345' at the end of the commit message. Should you have a bug reference?\n",
+			wantFindings: "  1. You usually need to reference a bug number for all but trivial or cosmetic fixes. For this repo, the format is usually 'Fixes #12345' or 'Updates #12345' at the end of the commit message. Should you have a bug reference? ([bug-missing](https://go.dev/doc/contribute#commit_messages))\n",
 			wantNotes:    commitMessageAdvice,
 		},
 		{
 			title: goodCommitTitle,
 			body:  "Some `backticks`\n" + strings.Repeat("long line", 20) + "\n" + goodCommitBody,
-			wantFindings: `  1. You have a long 180 character line in the commit message body. Please add line breaks to long lines that should be wrapped. Lines in the commit message body should be wrapped at ~76 characters unless needed for things like URLs or tables. (Note: GitHub might render long lines as soft-wrapped, so double-check in the Gerrit commit message shown above.)
-  2. It looks like you are using markdown in the commit message. If so, please remove it. Be sure to double-check the plain text shown in the Gerrit commit message above for any markdown backticks, markdown links, or other markdown formatting.
+			wantFindings: `  1. You have a long 180 character line in the commit message body. Please add line breaks to long lines that should be wrapped. Lines in the commit message body should be wrapped at ~76 characters unless needed for things like URLs or tables. (Note: GitHub might render long lines as soft-wrapped, so double-check in the Gerrit commit message shown above.) ([body-long-lines](https://go.dev/doc/contribute#commit_messages))
+  2. It looks like you are using markdown in the commit message. If so, please remove it. Be sure to double-check the plain text shown in the Gerrit commit message above for any markdown backticks, markdown links, or other markdown formatting. ([body-markdown](https://go.dev/doc/contribute#commit_messages))
 `,
 			wantNotes: commitMessageAdvice,
 		},
@@ -523,8 +524,19 @@ func TestParseCommitMessage(t *testing.T) {
 			name: "good",
 			text: "title\n\nBody 1\n\nFooter: 1\n",
 			want: Change{
-				Title: "title",
-				Body:  "Body 1",
+				Title:   "title",
+				Body:    "Body 1",
+				Footers: []string{"Footer: 1"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "good with several footers",
+			text: "title\n\nBody 1\n\nFooter: 1\nFooter: 2\n",
+			want: Change{
+				Title:   "title",
+				Body:    "Body 1",
+				Footers: []string{"Footer: 1", "Footer: 2"},
 			},
 			wantErr: false,
 		},
@@ -532,8 +544,9 @@ func TestParseCommitMessage(t *testing.T) {
 			name: "good with two body lines",
 			text: "title\n\nBody 1\nBody 2\n\nFooter: 1\n",
 			want: Change{
-				Title: "title",
-				Body:  "Body 1\nBody 2",
+				Title:   "title",
+				Body:    "Body 1\nBody 2",
+				Footers: []string{"Footer: 1"},
 			},
 			wantErr: false,
 		},
@@ -541,8 +554,9 @@ func TestParseCommitMessage(t *testing.T) {
 			name: "good with empty body",
 			text: "title\n\nFooter: 1\n",
 			want: Change{
-				Title: "title",
-				Body:  "",
+				Title:   "title",
+				Body:    "",
+				Footers: []string{"Footer: 1"},
 			},
 			wantErr: false,
 		},
@@ -550,8 +564,9 @@ func TestParseCommitMessage(t *testing.T) {
 			name: "good with extra blank lines after footer",
 			text: "title\n\nBody 1\n\nFooter: 1\n\n\n",
 			want: Change{
-				Title: "title",
-				Body:  "Body 1",
+				Title:   "title",
+				Body:    "Body 1",
+				Footers: []string{"Footer: 1"},
 			},
 			wantErr: false,
 		},
@@ -559,8 +574,39 @@ func TestParseCommitMessage(t *testing.T) {
 			name: "good with body line that looks like footer",
 			text: "title\n\nBody 1\nLink: example.com\n\nFooter: 1\n\n\n",
 			want: Change{
-				Title: "title",
-				Body:  "Body 1\nLink: example.com",
+				Title:   "title",
+				Body:    "Body 1\nLink: example.com",
+				Footers: []string{"Footer: 1"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "author footers before GerritBot footers",
+			text: "title\n\nBody 1\n\nFixes #1234\n\nSigned-off-by: Gopher <gopher@golang.org>\n\n" + goodCommitFooters + "\n",
+			want: Change{
+				Title:   "title",
+				Body:    "Body 1\n\nFixes #1234",
+				Footers: slices.Concat([]string{"Signed-off-by: Gopher <gopher@golang.org>"}, strings.Split(goodCommitFooters, "\n")),
+			},
+			wantErr: false,
+		},
+		{
+			name: "body paragraph before GerritBot footers",
+			text: "title\n\nBody 1\nLink: example.com\n\n" + goodCommitFooters + "\n",
+			want: Change{
+				Title:   "title",
+				Body:    "Body 1\nLink: example.com",
+				Footers: strings.Split(goodCommitFooters, "\n"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "footer-like paragraph before other footers",
+			text: "title\n\nBody 1\n\nLink: example.com\n\nFooter: 1\n",
+			want: Change{
+				Title:   "title",
+				Body:    "Body 1\n\nLink: example.com",
+				Footers: []string{"Footer: 1"},
 			},
 			wantErr: false,
 		},
@@ -568,8 +614,9 @@ func TestParseCommitMessage(t *testing.T) {
 			name: "allowed cherry pick in footer", // Example from CL 346093.
 			text: "title\n\nBody 1\n\nFooter: 1\n(cherry picked from commit ebd07b13caf35114b32e7d6783b27902af4829ce)\n",
 			want: Change{
-				Title: "title",
-				Body:  "Body 1",
+				Title:   "title",
+				Body:    "Body 1",
+				Footers: []string{"Footer: 1"},
 			},
 			wantErr: false,
 		},
@@ -21,14 +21,30 @@ type Change struct {
 	// Body is the commit message body (skipping the title on the first line and the blank second line,
 	// and without the footers).
 	Body string
+	// Footers are the commit message footer lines, like "Fixes: golang/go#1234" or
+	// "Change-Id: I1234", in the order they appear.
+	Footers []string
 
-	// TODO: could consider a Footer field, if useful, though I think GerritBot & Gerrit manage those.
-	// TODO: could be useful in future to have CL and PR fields as well, e.g., if we wanted to
-	// spot check for something that looks like test files in the changed file list.
+	// Files are the names of the files the change modifies, relative to the repo root.
+	// It is nil if the files are not known.
+	Files []string
+	// DiffLines is the number of lines the change adds plus the number it removes.
+	// It is 0 if the diff is not known.
+	DiffLines int
 }
 
+// gerritBotFooters are the keys of the footers that GerritBot adds
+// to the description of a PR to make its commit message.
+var gerritBotFooters = []string{"Change-Id", "GitHub-Last-Rev", "GitHub-Pull-Request", "Cq-Include-Trybots"}
+
 // ParseCommitMessage parses the commit message, returning an error if there
 // isn't a blank second line or if there aren't footers after the body.
+//
+// GerritBot adds its footers to the description of a PR after a blank line,
+// so any footers the author wrote at the end of the description, such as
+// Signed-off-by, end up in a block of their own. If the last block of footers
+// only has footers that GerritBot adds, ParseCommitMessage also takes the
+// block before it as footers, if it only has footer lines.
 func ParseCommitMessage(repo string, text string) (Change, error) {
 	change := Change{Repo: repo}
 	lines := splitLines(text)
@@ -49,6 +65,7 @@ func ParseCommitMessage(repo string, text string) (Change, error) {
 	for i, b := range slices.Backward(body) {
 		if match(`^[a-zA-Z][^ ]*: `, b) {
 			body = body[:i]
+			change.Footers = append(change.Footers, b)
 			sawFooter = true
 			continue
 		}
@@ -68,44 +85,60 @@ func ParseCommitMessage(repo string, text string) (Change, error) {
 	if !sawFooter {
 		return Change{}, fmt.Errorf("rules: ParseCommitMessage: did not find any footers preceded by blank line for commit message: %q", text)
 	}
+	slices.Reverse(change.Footers)
+	if !slices.ContainsFunc(change.Footers, func(f string) bool {
+		key, _, _ := strings.Cut(f, ":")
+		return !slices.Contains(gerritBotFooters, key)
+	}) {
+		var authorFooters []string
+		body, authorFooters = cutFooterBlock(body)
+		change.Footers = append(authorFooters, change.Footers...)
+	}
 	change.Body = strings.Join(body, "\n")
 
 	return change, nil
 }
 
+// cutFooterBlock returns lines without its last block of lines, and that
+// block, if the block only has footer lines. Otherwise it returns lines
+// and nil. Blank lines at the end of lines, and before the block, are
+// dropped.
+func cutFooterBlock(lines []string) (rest, footers []string) {
+	end := len(lines)
+	for end > 0 && lines[end-1] == "" {
+		end--
+	}
+	start := end
+	for start > 0 && match(`^[a-zA-Z][^ ]*: `, lines[start-1]) {
+		start--
+	}
+	if start == end || start > 0 && lines[start-1] != "" {
+		return lines, nil
+	}
+	rest = lines[:start]
+	for len(rest) > 0 && rest[len(rest)-1] == "" {
+		rest = rest[:len(rest)-1]
+	}
+	return rest, lines[start:end]
+}
+
 // Result contains the result of a single rule check against a Change.
 type Result struct {
+	ID      string // stable ID of the rule, as used in config.yaml
 	Name    string
+	URL     string // explanation of the rule
 	Finding string
 	Note    string
 }
 
-// Check runs the defined rules against one Change.
+// Check runs the built-in rules, configured by config.yaml, against one Change.
 func Check(change Change) (results []Result) {
-	if slices.Contains(skipAll, change.Repo) {
-		return nil
-	}
-	for _, group := range ruleGroups {
-		for _, rule := range group {
-			if slices.Contains(rule.skip, change.Repo) || len(rule.only) > 0 && !slices.Contains(rule.only, change.Repo) {
-				continue
-			}
-			finding, advice := rule.f(change)
-			if finding != "" {
-				results = append(results, Result{
-					Name:    rule.name,
-					Finding: finding,
-					Note:    advice,
-				})
-				break // Only report the first finding per rule group.
-			}
-		}
-	}
-	return results
+	return defaultChecker().Check(change)
 }
 
 // FormatResults returns the findings and notes ready to be placed in a CL comment,
-// formatted as simple markdown.
+// formatted as simple markdown. Each finding is followed by the ID of its rule,
+// linked to the page explaining the rule if there is one.
 func FormatResults(results []Result) (findings string, notes string) {
 	if len(results) == 0 {
 		return "", ""
@@ -113,7 +146,11 @@ func FormatResults(results []Result) (findings string, notes string) {
 	var b strings.Builder
 	cnt := 1
 	for _, r := range results {
-		fmt.Fprintf(&b, "  %d. %s\n", cnt, r.Finding)
+		if r.URL != "" {
+			fmt.Fprintf(&b, "  %d. %s ([%s](%s))\n", cnt, r.Finding, r.ID, r.URL)
+		} else {
+			fmt.Fprintf(&b, "  %d. %s (%s)\n", cnt, r.Finding, r.ID)
+		}
 		cnt++
 	}
 	advice := formatAdvice(results)
@@ -125,7 +162,7 @@ func formatAdvice(results []Result) string {
 	var s []string
 	seen := make(map[string]bool)
 	for _, r := range results {
-		if !seen[r.Note] {
+		if r.Note != "" && !seen[r.Note] {
 			s = append(s, r.Note)
 		}
 		seen[r.Note] = true
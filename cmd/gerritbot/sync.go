@@ -0,0 +1,427 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+	"golang.org/x/build/gerrit"
+	"golang.org/x/build/maintner"
+)
+
+// tryBotLabels are the Gerrit labels holding TryBot results,
+// which are reported to GitHub as commit statuses.
+var tryBotLabels = []string{"LUCI-TryBot-Result", "TryBot-Result"}
+
+// relayEpoch is the time after which comments by PR authors on GitHub
+// are relayed to Gerrit, set by the -relay-comments-since flag to when
+// relaying was deployed. Earlier comments were made before GerritBot
+// relayed comments, and may well have been answered already.
+// If it's zero, comments aren't relayed.
+var relayEpoch time.Time
+
+// relayed reports whether a comment made on GitHub at time t
+// is relayed to Gerrit.
+func relayed(t time.Time) bool {
+	return !relayEpoch.IsZero() && !t.Before(relayEpoch)
+}
+
+// gerritCommentIDRE matches the marker that GerritBot adds to the GitHub
+// comments it mirrors from Gerrit comments, which holds the Gerrit comment ID.
+var gerritCommentIDRE = regexp.MustCompile(`<!-- gerrit-comment-id: (\S+) -->`)
+
+// gerritChangeID returns the ID of cl for use in Gerrit API calls.
+func gerritChangeID(cl *maintner.GerritCL) string {
+	return fmt.Sprintf("%s~%d", url.PathEscape(cl.Project.Project()), cl.Number)
+}
+
+// gerritCommentURL returns the URL of the Gerrit comment with the given ID on cl.
+func gerritCommentURL(cl *maintner.GerritCL, id string) string {
+	return fmt.Sprintf("https://go-review.googlesource.com/c/%s/+/%d/comment/%s/", cl.Project.Project(), cl.Number, id)
+}
+
+// importedRev returns the SHA of the GitHub commit that was imported
+// as the given patch set of cl, or the empty string if it is unknown.
+func importedRev(cl *maintner.GerritCL, patchSet int) string {
+	c := cl.CommitAtVersion(int32(patchSet))
+	if c == nil {
+		return ""
+	}
+	for _, line := range strings.Split(c.Msg, "\n") {
+		if rev, ok := strings.CutPrefix(line, prefixGitFooterLastRev); ok {
+			return strings.TrimSpace(rev)
+		}
+	}
+	return ""
+}
+
+// A reviewLocation is the place on a PR's diff where a
+// Gerrit comment belongs as a GitHub review comment.
+type reviewLocation struct {
+	Path string
+	Line int    // 0 for a comment on the whole file
+	Side string // "LEFT" (the base) or "RIGHT" (the PR head)
+}
+
+// reviewLocationFor returns the location on a PR's diff of a Gerrit
+// comment on path, and whether it has one. Comments on the commit
+// message, and patch set level comments, have no location.
+//
+// The location is exact because GerritBot imports a PR by squashing its
+// head commit onto the merge base with its base branch: the files of the
+// patch set are those of the head commit, and those of the patch set's
+// parent are those of the base side of the PR's diff.
+func reviewLocationFor(path string, c *gerrit.CommentInfo) (reviewLocation, bool) {
+	if strings.HasPrefix(path, "/") { // "/COMMIT_MSG", "/PATCHSET_LEVEL", and so on
+		return reviewLocation{}, false
+	}
+	loc := reviewLocation{Path: path, Line: c.Line, Side: "RIGHT"}
+	if c.Side == "PARENT" {
+		loc.Side = "LEFT"
+	}
+	return loc, true
+}
+
+// gerritCommentBody returns the body of the GitHub comment mirroring
+// the Gerrit comment c on path in cl. If where is true, the body says
+// where in the change the comment was made, for comments that can't
+// be posted in place.
+func gerritCommentBody(cl *maintner.GerritCL, path string, c *gerrit.CommentInfo, where bool) string {
+	name := "Unknown"
+	if c.Author != nil && c.Author.Name != "" {
+		name = c.Author.Name
+	}
+	var loc string
+	switch {
+	case !where || path == "/PATCHSET_LEVEL":
+	case path == "/COMMIT_MSG":
+		loc = fmt.Sprintf(" on line %d of the commit message", c.Line)
+	case c.Line == 0:
+		loc = fmt.Sprintf(" on `%s`", path)
+	default:
+		loc = fmt.Sprintf(" on `%s` line %d", path, c.Line)
+	}
+	return fmt.Sprintf("[Comment from %s](%s)%s (patch set %d):\n\n%s\n\n<!-- gerrit-comment-id: %s -->",
+		name, gerritCommentURL(cl, c.ID), loc, c.PatchSet, c.Message, c.ID)
+}
+
+// A gerritComment is a Gerrit comment together with the path it is on.
+type gerritComment struct {
+	path string
+	*gerrit.CommentInfo
+}
+
+// sortedComments returns the comments returned by ListChangeComments
+// in the order they were made.
+func sortedComments(comments map[string][]gerrit.CommentInfo) []gerritComment {
+	var all []gerritComment
+	for _, path := range slices.Sorted(maps.Keys(comments)) {
+		for i := range comments[path] {
+			all = append(all, gerritComment{path, &comments[path][i]})
+		}
+	}
+	slices.SortStableFunc(all, func(a, b gerritComment) int {
+		return a.Updated.Time().Compare(b.Updated.Time())
+	})
+	return all
+}
+
+// A syncState is the state of a PR and its CL
+// when the comments on them were last synced.
+type syncState struct {
+	meta    maintner.GitHash // CL's meta commit
+	updated time.Time        // PR's last update
+}
+
+// syncComments mirrors the comments and TryBot results on cl to pr,
+// and relays the PR author's comments back to cl, unless neither has
+// changed since they were last synced. It's best effort: errors are
+// logged, and syncing is retried the next time.
+// b.RWMutex must be Lock'ed.
+func (b *bot) syncComments(ctx context.Context, pr *github.PullRequest, cl *maintner.GerritCL) {
+	shortLink := prShortLink(pr)
+	state := syncState{cl.Meta.Commit.Hash, pr.GetUpdatedAt().Time}
+	if b.synced[shortLink] == state {
+		return
+	}
+	ok := true
+	if err := b.syncReviewComments(ctx, pr, cl); err != nil {
+		log.Printf("syncReviewComments(%s): %v", shortLink, err)
+		ok = false
+	}
+	if err := b.syncGitHubCommentsToGerrit(ctx, pr, cl); err != nil {
+		log.Printf("syncGitHubCommentsToGerrit(%s): %v", shortLink, err)
+		ok = false
+	}
+	if err := b.syncTryBotStatuses(ctx, pr, cl); err != nil {
+		log.Printf("syncTryBotStatuses(%s): %v", shortLink, err)
+		ok = false
+	}
+	if ok {
+		b.synced[shortLink] = state
+	}
+}
+
+// syncReviewComments mirrors the comments on files in cl to pr as review
+// comments, and relays the PR author's replies to them back to Gerrit.
+// b.RWMutex must be Lock'ed.
+func (b *bot) syncReviewComments(ctx context.Context, pr *github.PullRequest, cl *maintner.GerritCL) error {
+	comments, err := b.gerritClient.ListChangeComments(ctx, gerritChangeID(cl))
+	if err != nil {
+		return fmt.Errorf("b.gerritClient.ListChangeComments: %v", err)
+	}
+	repo := pr.GetBase().GetRepo()
+	owner, name := repo.GetOwner().GetLogin(), repo.GetName()
+	var prComments []*github.PullRequestComment
+	opt := &github.PullRequestListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		cs, resp, err := b.githubClient.PullRequests.ListComments(ctx, owner, name, pr.GetNumber(), opt)
+		if err != nil {
+			return fmt.Errorf("b.githubClient.PullRequests.ListComments: %v", err)
+		}
+		logGitHubRateLimits(resp)
+		prComments = append(prComments, cs...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	// Map between mirrored Gerrit comments and their GitHub copies.
+	mirrors := make(map[string]int64)         // Gerrit comment ID -> GitHub comment ID
+	mirrored := make(map[int64]gerritComment) // GitHub comment ID -> Gerrit comment
+	for _, c := range prComments {
+		if m := gerritCommentIDRE.FindStringSubmatch(c.GetBody()); m != nil {
+			mirrors[m[1]] = c.GetID()
+		}
+	}
+	all := sortedComments(comments)
+	for _, c := range all {
+		if id, ok := mirrors[c.ID]; ok {
+			mirrored[id] = c
+		}
+	}
+
+	for _, c := range all {
+		if _, ok := mirrors[c.ID]; ok {
+			continue
+		}
+		if c.Author == nil || c.Author.NumericID == int64(cl.OwnerID()) {
+			// Comments by GerritBot, including those relayed from GitHub.
+			continue
+		}
+		id, err := b.postReviewComment(ctx, pr, cl, c, mirrors[c.InReplyTo])
+		if err != nil {
+			return err
+		}
+		if id != 0 {
+			mirrors[c.ID] = id
+		}
+	}
+
+	for _, rc := range prComments {
+		if rc.GetUser().GetID() != pr.GetUser().GetID() || !relayed(rc.GetCreatedAt().Time) {
+			continue
+		}
+		parent, ok := mirrored[rc.GetInReplyTo()]
+		if !ok {
+			// Not a reply to a comment from Gerrit,
+			// so there's no thread to add it to.
+			continue
+		}
+		if slices.ContainsFunc(all, func(c gerritComment) bool { return strings.Contains(c.Message, rc.GetHTMLURL()) }) {
+			continue // already relayed
+		}
+		msg := fmt.Sprintf("Reply from %s on GitHub (%s):\n\n%s", rc.GetUser().GetLogin(), rc.GetHTMLURL(), rc.GetBody())
+		if *dryRun {
+			log.Printf("[dry run] would relay %s to https://go.dev/cl/%d", rc.GetHTMLURL(), cl.Number)
+			continue
+		}
+		review := gerrit.ReviewInput{Comments: map[string][]gerrit.CommentInput{
+			parent.path: {{Side: parent.Side, Line: parent.Line, InReplyTo: parent.ID, Message: msg}},
+		}}
+		if err := b.gerritClient.SetReview(ctx, gerritChangeID(cl), strconv.Itoa(parent.PatchSet), review); err != nil {
+			return fmt.Errorf("relaying %s: %v", rc.GetHTMLURL(), err)
+		}
+	}
+	return nil
+}
+
+// postReviewComment mirrors the Gerrit comment c on cl to pr, as a reply to
+// the GitHub review comment with ID inReplyTo if that is non-zero, and returns
+// the ID of the new review comment. Comments that can't be posted as review
+// comments are posted as issue comments, for which postReviewComment returns 0.
+func (b *bot) postReviewComment(ctx context.Context, pr *github.PullRequest, cl *maintner.GerritCL, c gerritComment, inReplyTo int64) (int64, error) {
+	repo := pr.GetBase().GetRepo()
+	owner, name := repo.GetOwner().GetLogin(), repo.GetName()
+	loc, ok := reviewLocationFor(c.path, c.CommentInfo)
+	rev := importedRev(cl, c.PatchSet)
+	if ok && rev != "" {
+		body := gerritCommentBody(cl, c.path, c.CommentInfo, false)
+		if *dryRun {
+			log.Printf("[dry run] would post review comment on %s line %d to %s", loc.Path, loc.Line, prShortLink(pr))
+			return 0, nil
+		}
+		var rc *github.PullRequestComment
+		var resp *github.Response
+		var err error
+		if inReplyTo != 0 {
+			rc, resp, err = b.githubClient.PullRequests.CreateCommentInReplyTo(ctx, owner, name, pr.GetNumber(), body, inReplyTo)
+		} else {
+			comment := &github.PullRequestComment{
+				Body:     new(body),
+				CommitID: new(rev),
+				Path:     new(loc.Path),
+			}
+			if loc.Line == 0 {
+				comment.SubjectType = new("file")
+			} else {
+				comment.Line = new(loc.Line)
+				comment.Side = new(loc.Side)
+			}
+			rc, resp, err = b.githubClient.PullRequests.CreateComment(ctx, owner, name, pr.GetNumber(), comment)
+		}
+		if err == nil {
+			logGitHubRateLimits(resp)
+			return rc.GetID(), nil
+		}
+		if resp == nil || resp.StatusCode != http.StatusUnprocessableEntity {
+			return 0, fmt.Errorf("posting review comment for %s: %v", gerritCommentURL(cl, c.ID), err)
+		}
+		// GitHub only accepts comments near the changed lines,
+		// and on commits that are still part of the PR.
+		log.Printf("GitHub rejected review comment for %s: %v; posting it as an issue comment", gerritCommentURL(cl, c.ID), err)
+	}
+	body := gerritCommentBody(cl, c.path, c.CommentInfo, true)
+	if err := b.postGitHubMessageNoDup(ctx, owner, name, pr.GetNumber(), "", body, nil); err != nil {
+		return 0, fmt.Errorf("postGitHubMessageNoDup: %v", err)
+	}
+	return 0, nil
+}
+
+// syncGitHubCommentsToGerrit relays the comments on pr by its author to cl.
+// b.RWMutex must be Lock'ed.
+func (b *bot) syncGitHubCommentsToGerrit(ctx context.Context, pr *github.PullRequest, cl *maintner.GerritCL) error {
+	repo := pr.GetBase().GetRepo()
+	gr := b.corpus.GitHub().Repo(repo.GetOwner().GetLogin(), repo.GetName())
+	if gr == nil {
+		return fmt.Errorf("unknown github repo %s/%s", repo.GetOwner().GetLogin(), repo.GetName())
+	}
+	gi := gr.Issue(int32(pr.GetNumber()))
+	if gi == nil {
+		return nil
+	}
+	return gi.ForeachComment(func(c *maintner.GitHubComment) error {
+		if c.User == nil || c.User.ID != pr.GetUser().GetID() || !relayed(c.Created) ||
+			strings.HasPrefix(c.Body, "/comments ") {
+			return nil
+		}
+		commentURL := fmt.Sprintf("%s#issuecomment-%d", pr.GetHTMLURL(), c.ID)
+		for _, m := range cl.Messages {
+			if strings.Contains(m.Message, commentURL) {
+				return nil // already relayed
+			}
+		}
+		if *dryRun {
+			log.Printf("[dry run] would relay %s to https://go.dev/cl/%d", commentURL, cl.Number)
+			return nil
+		}
+		msg := fmt.Sprintf("Message from %s on GitHub (%s):\n\n%s", c.User.Login, commentURL, c.Body)
+		if err := b.gerritClient.SetReview(ctx, gerritChangeID(cl), "current", gerrit.ReviewInput{Message: msg}); err != nil {
+			return fmt.Errorf("relaying %s: %v", commentURL, err)
+		}
+		return nil
+	})
+}
+
+// tryBotStatuses returns the GitHub commit statuses that report the
+// TryBot results in labels, for the change at changeURL.
+func tryBotStatuses(labels map[string]gerrit.LabelInfo, changeURL string) []*github.RepoStatus {
+	running := slices.ContainsFunc(labels["Commit-Queue"].All, func(a gerrit.ApprovalInfo) bool { return a.Value > 0 })
+	var statuses []*github.RepoStatus
+	for _, name := range tryBotLabels {
+		l, ok := labels[name]
+		if !ok {
+			continue
+		}
+		var lo, hi int
+		for _, a := range l.All {
+			lo, hi = min(lo, a.Value), max(hi, a.Value)
+		}
+		var state, desc string
+		switch {
+		case running:
+			state, desc = "pending", "TryBots are running"
+		case lo < 0:
+			state, desc = "failure", "TryBots failed"
+		case hi > 0:
+			state, desc = "success", "TryBots passed"
+		default:
+			continue
+		}
+		statuses = append(statuses, &github.RepoStatus{
+			State:       new(state),
+			TargetURL:   new(changeURL),
+			Description: new(desc),
+			Context:     new("gerrit/" + name),
+		})
+	}
+	return statuses
+}
+
+// syncTryBotStatuses reports the TryBot results for cl as statuses
+// on the GitHub commit imported as its current patch set.
+// b.RWMutex must be Lock'ed.
+func (b *bot) syncTryBotStatuses(ctx context.Context, pr *github.PullRequest, cl *maintner.GerritCL) error {
+	rev := cl.Footer(prefixGitFooterLastRev)
+	if rev == "" {
+		return nil
+	}
+	ch, err := b.gerritClient.GetChangeDetail(ctx, gerritChangeID(cl))
+	if err != nil {
+		return fmt.Errorf("b.gerritClient.GetChangeDetail: %v", err)
+	}
+	changeURL := fmt.Sprintf("https://go-review.googlesource.com/c/%s/+/%d", cl.Project.Project(), cl.Number)
+	statuses := tryBotStatuses(ch.Labels, changeURL)
+	if len(statuses) == 0 {
+		return nil
+	}
+	repo := pr.GetBase().GetRepo()
+	owner, name := repo.GetOwner().GetLogin(), repo.GetName()
+	combined, resp, err := b.githubClient.Repositories.GetCombinedStatus(ctx, owner, name, rev, nil)
+	if err != nil {
+		return fmt.Errorf("b.githubClient.Repositories.GetCombinedStatus: %v", err)
+	}
+	logGitHubRateLimits(resp)
+	for _, s := range statuses {
+		if slices.ContainsFunc(combined.Statuses, func(old *github.RepoStatus) bool {
+			return old.GetContext() == s.GetContext() && old.GetState() == s.GetState() &&
+				old.GetDescription() == s.GetDescription()
+		}) {
+			continue
+		}
+		if *dryRun {
+			log.Printf("[dry run] would set status %s=%s on %s/%s@%s", s.GetContext(), s.GetState(), owner, name, rev)
+			continue
+		}
+		_, resp, err := b.githubClient.Repositories.CreateStatus(ctx, owner, name, rev, s)
+		if err != nil {
+			return fmt.Errorf("b.githubClient.Repositories.CreateStatus: %v", err)
+		}
+		logGitHubRateLimits(resp)
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v74/github"
+	"golang.org/x/build/gerrit"
+	"golang.org/x/build/maintner"
+)
+
+func TestReviewLocationFor(t *testing.T) {
+	testCases := []struct {
+		path string
+		c    gerrit.CommentInfo
+		want reviewLocation
+		ok   bool
+	}{
+		{"src/net/http/server.go", gerrit.CommentInfo{Line: 12}, reviewLocation{"src/net/http/server.go", 12, "RIGHT"}, true},
+		{"src/net/http/server.go", gerrit.CommentInfo{Line: 7, Side: "PARENT"}, reviewLocation{"src/net/http/server.go", 7, "LEFT"}, true},
+		{"README.md", gerrit.CommentInfo{}, reviewLocation{"README.md", 0, "RIGHT"}, true},
+		{"/COMMIT_MSG", gerrit.CommentInfo{Line: 3}, reviewLocation{}, false},
+		{"/PATCHSET_LEVEL", gerrit.CommentInfo{}, reviewLocation{}, false},
+	}
+	for _, tc := range testCases {
+		got, ok := reviewLocationFor(tc.path, &tc.c)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("reviewLocationFor(%q, %+v) = %+v, %v; want %+v, %v", tc.path, tc.c, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestGerritCommentBody(t *testing.T) {
+	cl := &maintner.GerritCL{Number: 1234, Project: &maintner.GerritProject{}}
+	c := &gerrit.CommentInfo{
+		ID:       "abc_123",
+		PatchSet: 2,
+		Line:     5,
+		Message:  "Please use errors.Is.",
+		Author:   &gerrit.AccountInfo{Name: "Gopher"},
+	}
+	for _, where := range []bool{false, true} {
+		body := gerritCommentBody(cl, "foo.go", c, where)
+		m := gerritCommentIDRE.FindStringSubmatch(body)
+		if m == nil || m[1] != c.ID {
+			t.Errorf("gerritCommentBody(where=%v) = %q; marker does not hold ID %q", where, body, c.ID)
+		}
+		if !strings.Contains(body, c.Message) {
+			t.Errorf("gerritCommentBody(where=%v) = %q; missing message", where, body)
+		}
+		if got := strings.Contains(body, "`foo.go` line 5"); got != where {
+			t.Errorf("gerritCommentBody(where=%v) = %q; mentions location = %v", where, body, got)
+		}
+	}
+}
+
+func TestSortedComments(t *testing.T) {
+	t0 := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) gerrit.TimeStamp { return gerrit.TimeStamp(t0.Add(d)) }
+	comments := map[string][]gerrit.CommentInfo{
+		"b.go":            {{ID: "reply", Updated: at(3 * time.Hour), InReplyTo: "first"}},
+		"a.go":            {{ID: "second", Updated: at(2 * time.Hour)}, {ID: "first", Updated: at(time.Hour)}},
+		"/PATCHSET_LEVEL": {{ID: "last", Updated: at(4 * time.Hour)}},
+	}
+	var got []string
+	for _, c := range sortedComments(comments) {
+		got = append(got, c.path+":"+c.ID)
+	}
+	want := []string{"a.go:first", "a.go:second", "b.go:reply", "/PATCHSET_LEVEL:last"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("sortedComments mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestTryBotStatuses(t *testing.T) {
+	votes := func(vs ...int) gerrit.LabelInfo {
+		var l gerrit.LabelInfo
+		for _, v := range vs {
+			l.All = append(l.All, gerrit.ApprovalInfo{Value: v})
+		}
+		return l
+	}
+	testCases := []struct {
+		desc   string
+		labels map[string]gerrit.LabelInfo
+		want   []string // context=state
+	}{
+		{"no labels", nil, nil},
+		{"not run", map[string]gerrit.LabelInfo{"LUCI-TryBot-Result": votes(0)}, nil},
+		{"running", map[string]gerrit.LabelInfo{
+			"Commit-Queue":       votes(1),
+			"LUCI-TryBot-Result": votes(),
+		}, []string{"gerrit/LUCI-TryBot-Result=pending"}},
+		{"passed", map[string]gerrit.LabelInfo{
+			"Commit-Queue":       votes(0),
+			"LUCI-TryBot-Result": votes(0, 1),
+		}, []string{"gerrit/LUCI-TryBot-Result=success"}},
+		{"failed", map[string]gerrit.LabelInfo{
+			"LUCI-TryBot-Result": votes(1, -1),
+			"TryBot-Result":      votes(1),
+		}, []string{"gerrit/LUCI-TryBot-Result=failure", "gerrit/TryBot-Result=success"}},
+	}
+	for _, tc := range testCases {
+		var got []string
+		for _, s := range tryBotStatuses(tc.labels, "https://go.dev/cl/1") {
+			if s.GetTargetURL() != "https://go.dev/cl/1" {
+				t.Errorf("%s: status %s has target URL %q", tc.desc, s.GetContext(), s.GetTargetURL())
+			}
+			got = append(got, s.GetContext()+"="+s.GetState())
+		}
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("%s: tryBotStatuses mismatch (-want +got):\n%s", tc.desc, diff)
+		}
+	}
+}
+
+// syncTestPR returns the PR and CL in testdata/sync.txtar.
+func syncTestPR(t *testing.T, b *bot) (*github.PullRequest, *maintner.GerritCL) {
+	t.Helper()
+	pr := newPullRequest("net/http: fix a bug", "")
+	pr.User = &github.User{ID: new(int64(7)), Login: new("contributor")}
+	pr.HTMLURL = new("https://github.com/golang/go/pull/42")
+	cl := b.corpus.Gerrit().Project("go.googlesource.com", "go").CL(1001)
+	if cl == nil {
+		t.Fatal("CL 1001 not in corpus")
+	}
+	return pr, cl
+}
+
+func TestSyncReviewComments(t *testing.T) {
+	b, gh, gc := newTestBot(t, "sync.txtar")
+	pr, cl := syncTestPR(t, b)
+	ctx := context.Background()
+
+	t0 := relayEpoch.Add(time.Hour)
+	at := func(d time.Duration) gerrit.TimeStamp { return gerrit.TimeStamp(t0.Add(d)) }
+	reviewer := &gerrit.AccountInfo{NumericID: 100, Name: "Reviewer"}
+	const changeID = "go~1001"
+	for _, c := range []struct {
+		path string
+		gerrit.CommentInfo
+	}{
+		// Mirrored as review comment 8000 before.
+		{"foo.go", gerrit.CommentInfo{ID: "c3", PatchSet: 1, Line: 7, Message: "Typo.", Updated: at(5), Author: reviewer}},
+		// Relayed from review comment 8002 before.
+		{"foo.go", gerrit.CommentInfo{ID: "c4", PatchSet: 1, Line: 7, InReplyTo: "c3", Updated: at(6), Author: gerritBotAccount,
+			Message: "Reply from contributor on GitHub (https://github.com/golang/go/pull/42#discussion_r8002):\n\nOops."}},
+		{"foo.go", gerrit.CommentInfo{ID: "c1", PatchSet: 1, Line: 3, Message: "Check the error.", Updated: at(10), Author: reviewer}},
+		{"foo.go", gerrit.CommentInfo{ID: "c2", PatchSet: 1, Line: 3, InReplyTo: "c1", Message: "Use errors.Is.", Updated: at(20), Author: reviewer}},
+		// GitHub rejects comments on bar.go, as if line 50 were far from the diff.
+		{"bar.go", gerrit.CommentInfo{ID: "c5", PatchSet: 1, Line: 50, Message: "Also here.", Updated: at(30), Author: reviewer}},
+	} {
+		gc.addComment(changeID, c.path, c.CommentInfo)
+	}
+	gh.reject = map[string]bool{"bar.go": true}
+
+	contributor := pr.GetUser()
+	reply := func(id int64, created time.Time, body string) *github.PullRequestComment {
+		return &github.PullRequestComment{
+			ID:        new(id),
+			InReplyTo: new(int64(8000)),
+			User:      contributor,
+			Body:      new(body),
+			CreatedAt: &github.Timestamp{Time: created},
+			HTMLURL:   new("https://github.com/golang/go/pull/42#discussion_r" + strconv.FormatInt(id, 10)),
+		}
+	}
+	gh.addReviewComment(42, &github.PullRequestComment{
+		ID:   new(int64(8000)),
+		User: gerritBotUser,
+		Body: new(gerritCommentBody(cl, "foo.go", &gerrit.CommentInfo{ID: "c3", PatchSet: 1, Line: 7, Message: "Typo."}, false)),
+	})
+	gh.addReviewComment(42, reply(8001, t0, "Fixed."))
+	gh.addReviewComment(42, reply(8002, t0, "Oops."))                           // relayed already
+	gh.addReviewComment(42, reply(8003, relayEpoch.Add(-time.Hour), "Thanks.")) // made before relaying began
+
+	if err := b.syncReviewComments(ctx, pr, cl); err != nil {
+		t.Fatal(err)
+	}
+	const rev = "0123456789abcdef0123456789abcdef01234567"
+	wantGitHub := []string{
+		"CreateComment foo.go:3 RIGHT@" + rev + " gerrit:c1",
+		"CreateCommentInReplyTo 9000 gerrit:c2",
+		"CreateComment bar.go:50 RIGHT@" + rev + " gerrit:c5",
+		"CreateIssueComment gerrit:c5", // the 422 fallback
+	}
+	if diff := cmp.Diff(wantGitHub, gh.calls); diff != "" {
+		t.Errorf("GitHub calls mismatch (-want +got):\n%s", diff)
+	}
+	wantGerrit := []string{
+		`SetReview go~1001 1 foo.go:7 in_reply_to=c3 "Reply from contributor on GitHub (https://github.com/golang/go/pull/42#discussion_r8001):\n\nFixed."`,
+	}
+	if diff := cmp.Diff(wantGerrit, gc.calls); diff != "" {
+		t.Errorf("Gerrit calls mismatch (-want +got):\n%s", diff)
+	}
+
+	// Syncing again must find the mirrored comments by their markers,
+	// and the relayed reply by its URL, and post nothing new. GitHub
+	// still rejects the review comment for c5, but its issue comment
+	// is already there.
+	gh.calls, gc.calls = nil, nil
+	if err := b.syncReviewComments(ctx, pr, cl); err != nil {
+		t.Fatal(err)
+	}
+	wantGitHub = []string{"CreateComment bar.go:50 RIGHT@" + rev + " gerrit:c5"}
+	if diff := cmp.Diff(wantGitHub, gh.calls); diff != "" {
+		t.Errorf("second sync: GitHub calls mismatch (-want +got):\n%s", diff)
+	}
+	if len(gc.calls) > 0 {
+		t.Errorf("second sync: unexpected Gerrit calls: %q", gc.calls)
+	}
+}
+
+func TestSyncGitHubCommentsToGerrit(t *testing.T) {
+	b, gh, gc := newTestBot(t, "sync.txtar")
+	pr, cl := syncTestPR(t, b)
+	if err := b.syncGitHubCommentsToGerrit(context.Background(), pr, cl); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`SetReview go~1001 current message="Message from contributor on GitHub (https://github.com/golang/go/pull/42#issuecomment-600):\n\nI've addressed the comments."`,
+	}
+	if diff := cmp.Diff(want, gc.calls); diff != "" {
+		t.Errorf("Gerrit calls mismatch (-want +got):\n%s", diff)
+	}
+	if len(gh.calls) > 0 {
+		t.Errorf("unexpected GitHub calls: %q", gh.calls)
+	}
+}
+
+func TestSyncGitHubCommentsToGerritDisabled(t *testing.T) {
+	b, _, gc := newTestBot(t, "sync.txtar")
+	pr, cl := syncTestPR(t, b)
+	relayEpoch = time.Time{}
+	if err := b.syncGitHubCommentsToGerrit(context.Background(), pr, cl); err != nil {
+		t.Fatal(err)
+	}
+	if len(gc.calls) > 0 {
+		t.Errorf("relayed comments without -relay-comments-since: %q", gc.calls)
+	}
+}
+
+func TestSyncCommentsUnchanged(t *testing.T) {
+	b, gh, gc := newTestBot(t, "sync.txtar")
+	pr, cl := syncTestPR(t, b)
+	ctx := context.Background()
+	pr.UpdatedAt = &github.Timestamp{Time: relayEpoch.Add(time.Hour)}
+
+	b.syncComments(ctx, pr, cl)
+	if gh.requests == 0 || gc.requests == 0 {
+		t.Fatalf("first sync made %d GitHub and %d Gerrit requests, want some of each", gh.requests, gc.requests)
+	}
+	gh.requests, gc.requests = 0, 0
+	b.syncComments(ctx, pr, cl)
+	if gh.requests != 0 || gc.requests != 0 {
+		t.Errorf("sync of an unchanged PR and CL made %d GitHub and %d Gerrit requests, want none", gh.requests, gc.requests)
+	}
+	pr.UpdatedAt = &github.Timestamp{Time: relayEpoch.Add(2 * time.Hour)}
+	b.syncComments(ctx, pr, cl)
+	if gh.requests == 0 {
+		t.Errorf("sync of an updated PR made no GitHub requests")
+	}
+}
@@ -0,0 +1,316 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	repospkg "golang.org/x/build/repos"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultMirrorConfig is the mirroring configuration used
+// when the -mirror-config flag is not set.
+//
+//go:embed mirrors.yaml
+var defaultMirrorConfig []byte
+
+// A mirrorConfig configures where repos are mirrored to.
+type mirrorConfig struct {
+	Destinations []destConfig `yaml:"destinations"`
+}
+
+// A destConfig configures a destination that repos are mirrored to.
+type destConfig struct {
+	// Name is the name of the git remote for the destination.
+	Name string `yaml:"name"`
+
+	// Kind is one of:
+	//   - "github": repos with MirrorToGitHub set, to their GitHubRepo
+	//   - "csr": repos with MirrorToCSRProject set, to Cloud Source Repositories
+	//   - "dir": all repos, to bare repos named <repo>.git in Dir,
+	//     which are created as needed; meant for testing
+	Kind string `yaml:"kind"`
+	Dir  string `yaml:"dir"`
+
+	// Repos, if non-empty, limits the destination to the listed
+	// Gerrit projects.
+	Repos []string `yaml:"repos"`
+
+	// Include and Exclude are ref patterns selecting the refs to push.
+	// As in git refspecs, a pattern may contain one "*", which matches
+	// any sequence of characters, including slashes. If Include is empty,
+	// branches and tags are pushed, but not internal Gerrit refs such as
+	// refs/changes/* and refs/users/*, which aren't helpful on other hosts.
+	Include []string `yaml:"include"`
+	Exclude []string `yaml:"exclude"`
+
+	// Rename maps the names of refs to the names they are pushed as,
+	// such as refs/heads/master to refs/heads/main.
+	Rename map[string]string `yaml:"rename"`
+
+	// Force is whether to force-push refs and delete refs that no longer
+	// exist in Gerrit. It defaults to true, making the destination an
+	// exact mirror. If false, pushes that aren't fast-forwards fail.
+	Force *bool `yaml:"force"`
+
+	// PushOption is an optional extra push option (--push-option).
+	PushOption string `yaml:"push_option"`
+}
+
+var defaultInclude = []string{"refs/heads/*", "refs/tags/*"}
+
+// parseMirrorConfig parses a mirroring configuration in YAML form.
+func parseMirrorConfig(data []byte) (*mirrorConfig, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	c := new(mirrorConfig)
+	if err := dec.Decode(c); err != nil {
+		return nil, fmt.Errorf("parsing mirror config: %v", err)
+	}
+	seen := make(map[string]bool)
+	for _, d := range c.Destinations {
+		if d.Name == "" || strings.ContainsAny(d.Name, "/ \t") {
+			return nil, fmt.Errorf("mirror config: bad destination name %q", d.Name)
+		}
+		if seen[d.Name] {
+			return nil, fmt.Errorf("mirror config: duplicate destination %q", d.Name)
+		}
+		seen[d.Name] = true
+		switch d.Kind {
+		case "github", "csr":
+		case "dir":
+			if d.Dir == "" {
+				return nil, fmt.Errorf("mirror config: destination %q: dir is required for kind dir", d.Name)
+			}
+		default:
+			return nil, fmt.Errorf("mirror config: destination %q: unknown kind %q", d.Name, d.Kind)
+		}
+		for _, p := range slices.Concat(d.Include, d.Exclude) {
+			if !strings.HasPrefix(p, "refs/") || strings.Count(p, "*") > 1 {
+				return nil, fmt.Errorf("mirror config: destination %q: bad ref pattern %q", d.Name, p)
+			}
+		}
+		for from, to := range d.Rename {
+			if !strings.HasPrefix(from, "refs/") || !strings.HasPrefix(to, "refs/") || strings.Contains(from+to, "*") {
+				return nil, fmt.Errorf("mirror config: destination %q: bad rename %q: %q", d.Name, from, to)
+			}
+		}
+	}
+	return c, nil
+}
+
+// loadMirrorConfig reads and parses the named mirroring configuration file.
+// If file is empty, it returns the default configuration.
+func loadMirrorConfig(file string) (*mirrorConfig, error) {
+	data := defaultMirrorConfig
+	if file != "" {
+		var err error
+		data, err = os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return parseMirrorConfig(data)
+}
+
+// url returns the URL of the destination for the repo,
+// or "" if the repo is not mirrored to the destination.
+func (d *destConfig) url(name string, meta *repospkg.Repo) string {
+	if len(d.Repos) > 0 && !slices.Contains(d.Repos, name) {
+		return ""
+	}
+	switch d.Kind {
+	case "github":
+		if meta.MirrorToGitHub {
+			return "git@github.com:" + meta.GitHubRepo + ".git"
+		}
+	case "csr":
+		if meta.MirrorToCSRProject != "" {
+			return "https://source.developers.google.com/p/" + meta.MirrorToCSRProject + "/r/" + name
+		}
+	case "dir":
+		return filepath.Join(d.Dir, name+".git")
+	}
+	return ""
+}
+
+func (d *destConfig) force() bool {
+	return d.Force == nil || *d.Force
+}
+
+// wants reports whether d mirrors the local ref.
+func (d *destConfig) wants(ref string) bool {
+	include := d.Include
+	if len(include) == 0 {
+		include = defaultInclude
+	}
+	match := func(p string) bool { return matchRef(p, ref) }
+	return slices.ContainsFunc(include, match) && !slices.ContainsFunc(d.Exclude, match)
+}
+
+// manages reports whether the destination's ref is one that d
+// mirrors to, and so may be deleted if it doesn't exist locally.
+func (d *destConfig) manages(dst string) bool {
+	for from, to := range d.Rename {
+		if to == dst {
+			return d.wants(from)
+		}
+	}
+	_, renamed := d.Rename[dst]
+	return !renamed && d.wants(dst)
+}
+
+// refspecs returns the refspecs to push to the destination, which has
+// the refs in have, so that its refs match the local ones, as selected
+// and renamed by d. Both maps are from ref name to object ID.
+func (d *destConfig) refspecs(local, have map[string]string) []string {
+	want := make(map[string]string)
+	for ref, id := range local {
+		if !d.wants(ref) {
+			continue
+		}
+		if to, ok := d.Rename[ref]; ok {
+			ref = to
+		}
+		want[ref] = id
+	}
+	prefix := ""
+	if d.force() {
+		prefix = "+"
+	}
+	var specs []string
+	for ref, id := range want {
+		if have[ref] != id {
+			specs = append(specs, prefix+id+":"+ref)
+		}
+	}
+	if d.force() {
+		for ref := range have {
+			if _, ok := want[ref]; !ok && d.manages(ref) {
+				specs = append(specs, ":"+ref)
+			}
+		}
+	}
+	// Sort by destination ref, for stable output.
+	slices.SortFunc(specs, func(a, b string) int {
+		_, a, _ = strings.Cut(a, ":")
+		_, b, _ = strings.Cut(b, ":")
+		return strings.Compare(a, b)
+	})
+	return specs
+}
+
+// matchRef reports whether ref matches the pattern, in which
+// a "*" matches any sequence of characters, as in git refspecs.
+func matchRef(pattern, ref string) bool {
+	prefix, suffix, ok := strings.Cut(pattern, "*")
+	if !ok {
+		return pattern == ref
+	}
+	return len(ref) >= len(prefix)+len(suffix) && strings.HasPrefix(ref, prefix) && strings.HasSuffix(ref, suffix)
+}
+
+// parseRefs parses the output of "git for-each-ref --format='%(objectname) %(refname)'"
+// or "git ls-remote" into a map from ref name to object ID. It leaves out
+// symbolic refs such as HEAD and the peeled values of annotated tags.
+func parseRefs(out []byte) (map[string]string, error) {
+	refs := make(map[string]string)
+	s := bufio.NewScanner(bytes.NewReader(out))
+	for s.Scan() {
+		id, ref, ok := strings.Cut(s.Text(), "\t")
+		if !ok {
+			id, ref, ok = strings.Cut(s.Text(), " ")
+		}
+		if !ok {
+			return nil, fmt.Errorf("unexpected line in git refs output: %q", s.Text())
+		}
+		if !strings.HasPrefix(ref, "refs/") || strings.HasSuffix(ref, "^{}") {
+			continue
+		}
+		refs[ref] = id
+	}
+	return refs, s.Err()
+}
+
+// A remote is a destination that a repo is mirrored to.
+type remote struct {
+	name string // name as configured in the repo.
+	cfg  *destConfig
+
+	mu       sync.Mutex
+	behind   int           // refs out of date when last checked, before pushing
+	since    time.Time     // start of the fetch that first found the destination out of date; zero if in sync
+	lastLag  time.Duration // lag as of the last push attempt
+	lastSync time.Time     // last time the destination was seen in sync
+	err      error         // error from the last push attempt
+}
+
+// lag returns how long the destination has been behind Gerrit,
+// or 0 if it's in sync.
+func (d *remote) lag() time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.since.IsZero() {
+		return 0
+	}
+	return time.Since(d.since)
+}
+
+// observe records that the destination had behind refs out of date
+// after the fetch that started at the given time.
+func (d *remote) observe(behind int, fetched time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.behind = behind
+	if behind > 0 && d.since.IsZero() {
+		d.since = fetched
+	}
+}
+
+// update records the result of a push attempt following the fetch that
+// started at the given time. The lag of the attempt is the time from the
+// fetch that first found the destination out of date to the end of the
+// attempt, which is when the destination caught up, if it succeeded.
+func (d *remote) update(fetched time.Time, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := time.Now()
+	d.err = err
+	if err != nil && d.since.IsZero() {
+		// The push may have failed before the destination was checked.
+		d.since = fetched
+	}
+	d.lastLag = 0
+	if !d.since.IsZero() {
+		d.lastLag = now.Sub(d.since)
+	}
+	if err == nil {
+		d.since, d.lastSync = time.Time{}, now
+	}
+}
+
+func (d *remote) statusLine() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	switch {
+	case d.err != nil && d.behind > 0:
+		return fmt.Sprintf("broken; behind by %d refs for %v", d.behind, time.Since(d.since).Round(time.Second))
+	case d.err != nil:
+		return fmt.Sprintf("broken for %v", time.Since(d.since).Round(time.Second))
+	case d.lastSync.IsZero():
+		return "not pushed yet"
+	}
+	return fmt.Sprintf("in sync as of %v ago", time.Since(d.lastSync).Round(time.Second))
+}
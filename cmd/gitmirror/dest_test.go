@@ -0,0 +1,158 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseMirrorConfig(t *testing.T) {
+	if _, err := parseMirrorConfig(defaultMirrorConfig); err != nil {
+		t.Fatalf("default config: %v", err)
+	}
+	for _, bad := range []string{
+		"destinations:\n- name: x\n  kind: ftp\n",
+		"destinations:\n- name: x\n  kind: dir\n",
+		"destinations:\n- name: x\n  kind: github\n- name: x\n  kind: csr\n",
+		"destinations:\n- name: x\n  kind: github\n  include: ['refs/*/*']\n",
+		"destinations:\n- name: x\n  kind: github\n  rename: {master: main}\n",
+		"destinations:\n- name: x\n  kind: github\n  forcepush: true\n",
+	} {
+		if _, err := parseMirrorConfig([]byte(bad)); err == nil {
+			t.Errorf("parseMirrorConfig(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestRefspecs(t *testing.T) {
+	local := map[string]string{
+		"refs/heads/master":             "a1",
+		"refs/heads/dev.go2go":          "a2",
+		"refs/heads/release-branch.go1": "a3",
+		"refs/tags/go1":                 "a4",
+		"refs/changes/01/1/1":           "a5",
+	}
+	have := map[string]string{
+		"refs/heads/main":    "b1",
+		"refs/heads/master":  "b2",
+		"refs/heads/gone":    "b3",
+		"refs/heads/dev.old": "b4",
+		"refs/tags/go1":      "a4",
+		"refs/meta/config":   "b5",
+	}
+	noForce := false
+	tests := []struct {
+		name string
+		d    destConfig
+		want []string
+	}{
+		{
+			name: "default",
+			want: []string{
+				"+a2:refs/heads/dev.go2go",
+				":refs/heads/dev.old",
+				":refs/heads/gone",
+				":refs/heads/main",
+				"+a1:refs/heads/master",
+				"+a3:refs/heads/release-branch.go1",
+			},
+		},
+		{
+			name: "filtered and renamed",
+			d: destConfig{
+				Include: []string{"refs/heads/*"},
+				Exclude: []string{"refs/heads/dev.*"},
+				Rename:  map[string]string{"refs/heads/master": "refs/heads/main"},
+			},
+			want: []string{
+				":refs/heads/gone",
+				"+a1:refs/heads/main",
+				"+a3:refs/heads/release-branch.go1",
+			},
+		},
+		{
+			name: "no force",
+			d:    destConfig{Force: &noForce, Include: []string{"refs/heads/release-branch.*"}},
+			want: []string{"a3:refs/heads/release-branch.go1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.refspecs(local, have); !slices.Equal(got, tt.want) {
+				t.Errorf("refspecs =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMirrorFiltered(t *testing.T) {
+	tm := newTestMirror(t, destConfig{
+		Name:    "local",
+		Kind:    "dir",
+		Dir:     t.TempDir(),
+		Exclude: []string{"refs/heads/dev.*"},
+		Rename:  map[string]string{"refs/heads/master": "refs/heads/main"},
+	})
+	tm.commit("hello world")
+	tm.git(tm.gerrit, "branch", "-M", "master")
+	tm.git(tm.gerrit, "branch", "dev.test")
+	tm.git(tm.gerrit, "tag", "v1.0.0")
+	rev := tm.git(tm.gerrit, "rev-parse", "HEAD")
+	tm.loopOnce()
+
+	local := tm.buildRepo.dests[2]
+	dir := local.cfg.url("build", tm.buildRepo.meta)
+	dest := explicitRepo{dir, dir}
+	refs := tm.git(dest, "for-each-ref", "--format=%(refname)")
+	if want := "refs/heads/main\nrefs/tags/v1.0.0\n"; refs != want {
+		t.Errorf("local destination refs = %q, want %q", refs, want)
+	}
+	if got := tm.git(dest, "rev-parse", "refs/heads/main"); got != rev {
+		t.Errorf("local destination main is %v, want %v", got, rev)
+	}
+	if lag := local.lag(); lag != 0 {
+		t.Errorf("lag after successful push = %v, want 0", lag)
+	}
+	if local.lastLag <= 0 {
+		t.Errorf("lag as of push = %v, want the time from the fetch to the end of the push", local.lastLag)
+	}
+	if local.behind != 2 {
+		t.Errorf("refs behind before push = %d, want 2", local.behind)
+	}
+
+	// Nothing is out of date after a fetch that brings in nothing new.
+	tm.loopOnce()
+	if local.behind != 0 || local.lastLag != 0 {
+		t.Errorf("after a push of nothing, refs behind = %d and lag = %v, want 0 and 0", local.behind, local.lastLag)
+	}
+	if body := tm.get("/"); !strings.Contains(body, "to local - in sync") {
+		t.Errorf("home page does not show local destination in sync: %q", body)
+	}
+}
+
+func TestRemoteLag(t *testing.T) {
+	d := &remote{name: "github"}
+	fetched := time.Now().Add(-time.Minute)
+	d.observe(3, fetched)
+	d.update(fetched, errors.New("push failed"))
+	if d.behind != 3 || d.lastLag < time.Minute {
+		t.Errorf("after failed push, refs behind = %d and lag = %v, want 3 and at least 1m", d.behind, d.lastLag)
+	}
+
+	// The lag is still measured from the fetch that first
+	// found the destination out of date, until it catches up.
+	d.observe(4, time.Now())
+	d.update(time.Now(), nil)
+	if d.behind != 4 || d.lastLag < time.Minute {
+		t.Errorf("after successful push, refs behind = %d and lag = %v, want 4 and at least 1m", d.behind, d.lastLag)
+	}
+	if lag := d.lag(); lag != 0 {
+		t.Errorf("lag after successful push = %v, want 0", lag)
+	}
+}
@@ -5,7 +5,9 @@
 // The gitmirror binary watches the specified Gerrit repositories for
 // new commits and syncs them to mirror repositories.
 //
-// It also serves tarballs over HTTP for the build system.
+// It also serves tarballs over HTTP for the build system, and serves
+// its copies of the repositories read-only over the git smart HTTP
+// protocol at /<repo>.git, for builders to clone from.
 package main
 
 import (
@@ -27,9 +29,11 @@ import (
 	"sync"
 	"time"
 
+	"cloud.google.com/go/compute/metadata"
 	"golang.org/x/build/gerrit"
 	"golang.org/x/build/internal/envutil"
 	"golang.org/x/build/internal/gitauth"
+	"golang.org/x/build/internal/metrics"
 	"golang.org/x/build/internal/secret"
 	"golang.org/x/build/maintner"
 	"golang.org/x/build/maintner/godata"
@@ -45,6 +49,7 @@ var (
 	flagMirrorGitHub = flag.Bool("mirror-github", true, "whether to mirror to GitHub when mirroring is enabled")
 	flagMirrorCSR    = flag.Bool("mirror-csr", true, "whether to mirror to Cloud Source Repositories when mirroring is enabled")
 	flagSecretsDir   = flag.String("secretsdir", "", "directory to load secrets from instead of GCP")
+	flagMirrorConfig = flag.String("mirror-config", "", "if non-empty, file to load the mirror destinations from, instead of the built-in mirrors.yaml")
 )
 
 func main() {
@@ -59,10 +64,38 @@ func main() {
 	http.HandleFunc("/debug/env", handleDebugEnv)
 	http.HandleFunc("/debug/goroutines", handleDebugGoroutines)
 
+	var gr *metrics.MonitoredResource
+	if metadata.OnGCE() {
+		var err error
+		gr, err = metrics.GKEResource("gitmirror")
+		if err != nil {
+			log.Println("metrics.GKEResource:", err)
+		}
+	}
+	ms, err := metrics.NewService(gr, views)
+	if err != nil {
+		log.Println("failed to initialize metrics:", err)
+	} else {
+		defer ms.Stop()
+		http.Handle("/metrics", ms)
+	}
+
 	if err := gitauth.Init(); err != nil {
 		log.Fatalf("gitauth: %v", err)
 	}
 
+	mirrorCfg, err := loadMirrorConfig(*flagMirrorConfig)
+	if err != nil {
+		log.Fatalf("loading mirror config: %v", err)
+	}
+	var dests []destConfig
+	for _, d := range mirrorCfg.Destinations {
+		if d.Kind == "github" && !*flagMirrorGitHub || d.Kind == "csr" && !*flagMirrorCSR {
+			continue
+		}
+		dests = append(dests, d)
+	}
+
 	cacheDir, err := createCacheDir()
 	if err != nil {
 		log.Fatalf("creating cache dir: %v", err)
@@ -80,8 +113,7 @@ func main() {
 		homeDir:      credsDir,
 		goBase:       "https://go.googlesource.com/",
 		gerritClient: gerrit.NewClient("https://go-review.googlesource.com", gerrit.NoAuth),
-		mirrorGitHub: *flagMirrorGitHub,
-		mirrorCSR:    *flagMirrorCSR,
+		dests:        dests,
 		timeoutScale: 1,
 	}
 
@@ -199,11 +231,11 @@ type gitMirror struct {
 	repos    map[string]*repo
 	cacheDir string
 	// homeDir is used as $HOME for all commands, allowing easy configuration overrides.
-	homeDir                 string
-	goBase                  string // Base URL/path for Go upstream repos.
-	gerritClient            *gerrit.Client
-	mirrorGitHub, mirrorCSR bool
-	timeoutScale            int
+	homeDir      string
+	goBase       string // Base URL/path for Go upstream repos.
+	gerritClient *gerrit.Client
+	dests        []destConfig // destinations to mirror to
+	timeoutScale int
 }
 
 func (m *gitMirror) addRepo(meta *repospkg.Repo) *repo {
@@ -217,6 +249,7 @@ func (m *gitMirror) addRepo(meta *repospkg.Repo) *repo {
 		mirror:  m,
 	}
 	m.mux.Handle("/"+name+".tar.gz", r)
+	m.mux.HandleFunc("/"+name+".git/", r.serveGit)
 	m.mux.Handle("/debug/watcher/"+r.name, r)
 	m.repos[name] = r
 	return r
@@ -225,16 +258,21 @@ func (m *gitMirror) addRepo(meta *repospkg.Repo) *repo {
 // addMirrors sets up mirroring for repositories that need it.
 func (m *gitMirror) addMirrors() error {
 	for _, repo := range m.repos {
-		if m.mirrorGitHub && repo.meta.MirrorToGitHub {
-			if err := repo.addRemote("github", "git@github.com:"+repo.meta.GitHubRepo+".git", ""); err != nil {
-				return fmt.Errorf("adding GitHub remote: %v", err)
+		for i := range m.dests {
+			d := &m.dests[i]
+			url := d.url(repo.name, repo.meta)
+			if url == "" {
+				continue
 			}
-		}
-		if m.mirrorCSR && repo.meta.MirrorToCSRProject != "" {
-			// Option "nokeycheck" skips Cloud Source Repositories' private
-			// key checking. We have dummy keys checked in as test data.
-			if err := repo.addRemote("csr", "https://source.developers.google.com/p/"+repo.meta.MirrorToCSRProject+"/r/"+repo.name, "nokeycheck"); err != nil {
-				return fmt.Errorf("adding CSR remote: %v", err)
+			if d.Kind == "dir" {
+				if _, err := os.Stat(url); os.IsNotExist(err) {
+					if _, _, err := repo.runGitLogged("init", "--bare", url); err != nil {
+						return fmt.Errorf("creating %s: %v", url, err)
+					}
+				}
+			}
+			if err := repo.addRemote(d, url); err != nil {
+				return fmt.Errorf("adding %s remote: %v", d.Name, err)
 			}
 		}
 	}
@@ -257,7 +295,11 @@ func (m *gitMirror) handleRoot(w http.ResponseWriter, r *http.Request) {
 	}
 	sort.Strings(names)
 	for _, name := range names {
-		fmt.Fprintf(w, "<a href='/debug/watcher/%s'>%s</a> - %s\n", name, name, m.repos[name].statusLine())
+		r := m.repos[name]
+		fmt.Fprintf(w, "<a href='/debug/watcher/%s'>%s</a> - %s\n", name, name, r.statusLine())
+		for _, dest := range r.dests {
+			fmt.Fprintf(w, "    to %s - %s\n", dest.name, dest.statusLine())
+		}
 	}
 	fmt.Fprint(w, "</pre></body></html>")
 }
@@ -320,11 +362,6 @@ func (r *statusRing) foreachDesc(fn func(statusEntry)) {
 	}
 }
 
-type remote struct {
-	name       string // name as configured in the repo.
-	pushOption string // optional extra push option (--push-option).
-}
-
 // repo represents a repository to be watched.
 type repo struct {
 	name    string
@@ -333,7 +370,7 @@ type repo struct {
 	meta    *repospkg.Repo
 	changed chan bool // sent to when a change comes in
 	status  statusRing
-	dests   []remote // destination remotes to mirror to
+	dests   []*remote // destination remotes to mirror to
 	mirror  *gitMirror
 
 	mu        sync.Mutex
@@ -390,8 +427,9 @@ func (r *repo) runGitQuiet(args ...string) ([]byte, []byte, error) {
 
 	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
 	cmd := exec.Command("git", args...)
-	if args[0] == "clone" {
-		// Small hack: if we're cloning, the root doesn't exist yet.
+	if args[0] == "clone" || args[0] == "init" {
+		// Small hack: if we're cloning, the root doesn't exist yet,
+		// and if we're creating a destination, it's not the root.
 		envutil.SetDir(cmd, "/")
 	} else {
 		envutil.SetDir(cmd, r.root)
@@ -453,22 +491,14 @@ func (r *repo) setStatus(status string) {
 	r.status.add(status)
 }
 
-func (r *repo) addRemote(name, url, pushOption string) error {
-	r.dests = append(r.dests, remote{
-		name:       name,
-		pushOption: pushOption,
-	})
+// addRemote adds the destination d, with the given URL, to mirror r to.
+func (r *repo) addRemote(d *destConfig, url string) error {
+	r.dests = append(r.dests, &remote{name: d.Name, cfg: d})
 	if err := os.MkdirAll(filepath.Join(r.root, "remotes"), 0777); err != nil {
 		return err
 	}
-	// We want to include only the refs/heads/* and refs/tags/* namespaces
-	// in the mirrors. They correspond to published branches and tags.
-	// Leave out internal Gerrit namespaces such as refs/changes/*,
-	// refs/users/*, etc., because they're not helpful on other hosts.
-	remote := "URL: " + url + "\n" +
-		"Push: +refs/heads/*:refs/heads/*\n" +
-		"Push: +refs/tags/*:refs/tags/*\n"
-	return os.WriteFile(filepath.Join(r.root, "remotes", name), []byte(remote), 0777)
+	// The refs to push are chosen by push, according to d.
+	return os.WriteFile(filepath.Join(r.root, "remotes", d.Name), []byte("URL: "+url+"\n"), 0777)
 }
 
 // loop continuously runs "git fetch" in the repo, checks for new
@@ -495,13 +525,14 @@ func (r *repo) loop() {
 }
 
 func (r *repo) loopOnce() error {
+	fetched := time.Now()
 	if err := r.fetch(); err != nil {
 		r.logf("fetch failed: %v", err)
 		r.setErr(err)
 		return err
 	}
 	for _, dest := range r.dests {
-		if err := r.push(dest); err != nil {
+		if err := r.push(dest, fetched); err != nil {
 			r.logf("push failed: %v", err)
 			r.setErr(err)
 			return err
@@ -519,6 +550,7 @@ func (r *repo) logf(format string, args ...any) {
 // fetch runs "git fetch" in the repository root.
 // It tries three times, just in case it failed because of a transient error.
 func (r *repo) fetch() error {
+	start := time.Now()
 	err := r.try(3, func(attempt int) error {
 		r.setStatus(fmt.Sprintf("running git fetch origin, attempt %d", attempt))
 		if _, stderr, err := r.runGitLogged("fetch", "--prune", "origin"); err != nil {
@@ -530,25 +562,53 @@ func (r *repo) fetch() error {
 		r.setStatus("git fetch failed")
 	} else {
 		r.setStatus("ran git fetch")
+		recordFetch(r.name, time.Since(start))
 	}
 	return err
 }
 
-// push runs "git push -f --mirror dest" in the repository root.
+// push pushes the refs selected by dest's configuration to dest, deleting
+// refs that no longer exist if dest is force-pushed to. fetched is the start
+// of the fetch that brought the refs in, which dest's lag is measured from.
 // It tries three times, just in case it failed because of a transient error.
-func (r *repo) push(dest remote) error {
+func (r *repo) push(dest *remote, fetched time.Time) error {
+	start := time.Now()
 	err := r.try(3, func(attempt int) error {
-		r.setStatus(fmt.Sprintf("syncing to %v, attempt %d", dest, attempt))
-		args := []string{"push", "-f", "--mirror"}
-		if dest.pushOption != "" {
-			args = append(args, "--push-option", dest.pushOption)
+		r.setStatus(fmt.Sprintf("syncing to %v, attempt %d", dest.name, attempt))
+		localOut, stderr, err := r.runGitQuiet("for-each-ref", "--format=%(objectname) %(refname)")
+		if err != nil {
+			return fmt.Errorf("listing refs: %v\n\n%s", err, stderr)
+		}
+		remoteOut, stderr, err := r.runGitLogged("ls-remote", dest.name)
+		if err != nil {
+			return fmt.Errorf("listing %s refs: %v\n\n%s", dest.name, err, stderr)
+		}
+		local, err := parseRefs(localOut)
+		if err != nil {
+			return err
+		}
+		have, err := parseRefs(remoteOut)
+		if err != nil {
+			return err
+		}
+		specs := dest.cfg.refspecs(local, have)
+		dest.observe(len(specs), fetched)
+		if len(specs) == 0 {
+			return nil
+		}
+		args := []string{"push"}
+		if dest.cfg.PushOption != "" {
+			args = append(args, "--push-option", dest.cfg.PushOption)
 		}
 		args = append(args, dest.name)
+		args = append(args, specs...)
 		if _, stderr, err := r.runGitLogged(args...); err != nil {
 			return fmt.Errorf("%v\n\n%s", err, stderr)
 		}
 		return nil
 	})
+	dest.update(fetched, err)
+	recordPush(r.name, dest, time.Since(start))
 	if err != nil {
 		r.setStatus("sync to " + dest.name + " failed")
 	} else {
@@ -83,8 +83,11 @@ func TestMirror(t *testing.T) {
 // The repository still has to exist.
 func TestMirrorInitiallyEmpty(t *testing.T) {
 	tm := newTestMirror(t)
-	if err := tm.m.repos["build"].loopOnce(); err == nil {
-		t.Error("expected error mirroring empty repository, got none")
+	// There's nothing to push from an empty repository, so unlike
+	// "git push --mirror", which fails when there are no refs,
+	// mirroring it succeeds without pushing.
+	if err := tm.m.repos["build"].loopOnce(); err != nil {
+		t.Errorf("mirroring empty repository: %v", err)
 	}
 	tm.commit("first commit")
 	tm.loopOnce()
@@ -114,10 +117,10 @@ type explicitRepo struct {
 func (r explicitRepo) bare() bool { return r.gitDir == r.dir }
 
 // newTestMirror returns a mirror configured to watch the "build" repository
-// and mirror it to GitHub and CSR. All repositories are faked out with local
-// versions created hermetically. The mirror is idle and must be pumped with
-// loopOnce.
-func newTestMirror(t *testing.T) *testMirror {
+// and mirror it to GitHub and CSR, and to any additional dests. All
+// repositories are faked out with local versions created hermetically.
+// The mirror is idle and must be pumped with loopOnce.
+func newTestMirror(t *testing.T, dests ...destConfig) *testMirror {
 	if _, err := exec.LookPath("git"); err != nil {
 		t.Skip("skipping; git not in PATH")
 	}
@@ -140,8 +143,6 @@ func newTestMirror(t *testing.T) *testMirror {
 			// make sure concatenation is OK.
 			goBase:       goBase + "/",
 			repos:        map[string]*repo{},
-			mirrorGitHub: true,
-			mirrorCSR:    true,
 			timeoutScale: 0,
 		},
 		t: t,
@@ -169,11 +170,8 @@ func newTestMirror(t *testing.T) *testMirror {
 		}
 		return explicitRepo{gitDir, dir}
 	}
-	// The origin is non-bare so we can commit to it; the destinations are
-	// bare so we can push to them.
+	// The origin is non-bare so we can commit to it.
 	tm.gerrit = initRepo(gerrit, false)
-	tm.github = initRepo(t.TempDir(), true)
-	tm.csr = initRepo(t.TempDir(), true)
 
 	tm.buildRepo = tm.m.addRepo(&repospkg.Repo{
 		GoGerritProject:    "build",
@@ -186,11 +184,17 @@ func newTestMirror(t *testing.T) *testMirror {
 		t.Fatal(err)
 	}
 
-	// Manually add mirror repos. We can't use tm.m.addMirrors, as they
-	// hard-codes the real remotes, but we need to use local test
-	// directories.
-	tm.buildRepo.addRemote("github", tm.github.gitDir, "")
-	tm.buildRepo.addRemote("csr", tm.csr.gitDir, "")
+	// Mirror to local bare repos standing in for GitHub and CSR.
+	githubDir, csrDir := t.TempDir(), t.TempDir()
+	tm.m.dests = append([]destConfig{
+		{Name: "github", Kind: "dir", Dir: githubDir},
+		{Name: "csr", Kind: "dir", Dir: csrDir},
+	}, dests...)
+	if err := tm.m.addMirrors(); err != nil {
+		t.Fatal(err)
+	}
+	tm.github = explicitRepo{filepath.Join(githubDir, "build.git"), filepath.Join(githubDir, "build.git")}
+	tm.csr = explicitRepo{filepath.Join(csrDir, "build.git"), filepath.Join(csrDir, "build.git")}
 
 	return tm
 }
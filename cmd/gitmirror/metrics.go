@@ -0,0 +1,79 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"time"
+
+	"go.opencensus.io/plugin/ochttp"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+var (
+	kRepo = tag.MustNewKey("go-build/gitmirror/keys/repo")
+	kDest = tag.MustNewKey("go-build/gitmirror/keys/dest")
+
+	mFetchLatency = stats.Float64("go-build/gitmirror/fetch/latency", "git fetch latency by repo", stats.UnitMilliseconds)
+	mPushLatency  = stats.Float64("go-build/gitmirror/push/latency", "git push latency by repo and destination", stats.UnitMilliseconds)
+	mDestLag      = stats.Float64("go-build/gitmirror/dest/lag", "time from the fetch of new refs to the end of the push of them to a destination", stats.UnitSeconds)
+	mDestBehind   = stats.Int64("go-build/gitmirror/dest/refs_behind", "number of refs out of date in a destination before a push", stats.UnitDimensionless)
+)
+
+// views should contain all measurements. All *view.View added to this
+// slice will be registered and exported to the metric service.
+var views = []*view.View{
+	{
+		Name:        "go-build/gitmirror/fetch/latency",
+		Description: "Latency distribution of git fetches from Gerrit",
+		Measure:     mFetchLatency,
+		TagKeys:     []tag.Key{kRepo},
+		Aggregation: ochttp.DefaultLatencyDistribution,
+	},
+	{
+		Name:        "go-build/gitmirror/push/latency",
+		Description: "Latency distribution of git pushes to mirror destinations",
+		Measure:     mPushLatency,
+		TagKeys:     []tag.Key{kRepo, kDest},
+		Aggregation: ochttp.DefaultLatencyDistribution,
+	},
+	{
+		Name:        "go-build/gitmirror/dest/lag",
+		Description: "Time from the fetch that found a mirror destination behind Gerrit to the end of the latest push to it",
+		Measure:     mDestLag,
+		TagKeys:     []tag.Key{kRepo, kDest},
+		Aggregation: view.LastValue(),
+	},
+	{
+		Name:        "go-build/gitmirror/dest/refs_behind",
+		Description: "Number of refs out of date in a mirror destination before the latest push to it",
+		Measure:     mDestBehind,
+		TagKeys:     []tag.Key{kRepo, kDest},
+		Aggregation: view.LastValue(),
+	},
+}
+
+// recordFetch records the latency of a fetch of the repo.
+func recordFetch(repo string, d time.Duration) {
+	stats.RecordWithTags(context.Background(),
+		[]tag.Mutator{tag.Upsert(kRepo, repo)},
+		mFetchLatency.M(float64(d)/float64(time.Millisecond)))
+}
+
+// recordPush records the latency of a push of the repo to dest,
+// how far dest was behind before the push, and its lag as of
+// the end of the push.
+func recordPush(repo string, dest *remote, d time.Duration) {
+	dest.mu.Lock()
+	behind, lag := dest.behind, dest.lastLag
+	dest.mu.Unlock()
+	stats.RecordWithTags(context.Background(),
+		[]tag.Mutator{tag.Upsert(kRepo, repo), tag.Upsert(kDest, dest.name)},
+		mPushLatency.M(float64(d)/float64(time.Millisecond)),
+		mDestLag.M(lag.Seconds()),
+		mDestBehind.M(int64(behind)))
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/build/internal/envutil"
+)
+
+// gitProtocolRE matches the Git-Protocol header values passed on to git,
+// such as "version=2".
+var gitProtocolRE = regexp.MustCompile(`^[a-zA-Z0-9=:._-]+$`)
+
+// serveGit serves the read-only parts of the git smart HTTP protocol
+// from r's local clone, so that it can be cloned or fetched from with
+// git or internal/gitfs at /<name>.git. The heavy lifting is done by
+// "git upload-pack", which supports protocol v2 ls-refs and fetch.
+// See https://git-scm.com/docs/http-protocol.
+//
+// GET  /<name>.git/info/refs?service=git-upload-pack
+// POST /<name>.git/git-upload-pack
+func (r *repo) serveGit(w http.ResponseWriter, req *http.Request) {
+	path := strings.TrimPrefix(req.URL.Path, "/"+r.name+".git")
+	switch {
+	case path == "/info/refs" && req.Method == "GET":
+		if service := req.FormValue("service"); service != "git-upload-pack" {
+			// Dumb HTTP, and pushing with git-receive-pack, are not supported.
+			http.Error(w, "only the smart HTTP git-upload-pack service is supported", http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-git-upload-pack-advertisement")
+		w.Header().Set("Cache-Control", "no-cache")
+		protocol := req.Header.Get("Git-Protocol")
+		if !strings.Contains(protocol, "version=2") {
+			// Protocol v0 and v1 responses start with the service name.
+			// See https://git-scm.com/docs/http-protocol#_smart_clients.
+			io.WriteString(w, "001e# service=git-upload-pack\n0000")
+		}
+		r.runUploadPack(w, req, protocol, nil, "--advertise-refs")
+	case path == "/git-upload-pack" && req.Method == "POST":
+		if ct := req.Header.Get("Content-Type"); ct != "application/x-git-upload-pack-request" {
+			http.Error(w, "bad Content-Type "+ct, http.StatusBadRequest)
+			return
+		}
+		body := req.Body
+		if req.Header.Get("Content-Encoding") == "gzip" {
+			zr, err := gzip.NewReader(req.Body)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			body = zr
+		}
+		w.Header().Set("Content-Type", "application/x-git-upload-pack-result")
+		w.Header().Set("Cache-Control", "no-cache")
+		r.runUploadPack(w, req, req.Header.Get("Git-Protocol"), body)
+	case path == "/git-receive-pack" || req.URL.Query().Get("service") == "git-receive-pack":
+		http.Error(w, "this mirror is read-only", http.StatusForbidden)
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+// runUploadPack runs "git upload-pack --stateless-rpc" on r's clone,
+// copying stdin to it and its output to w.
+func (r *repo) runUploadPack(w http.ResponseWriter, req *http.Request, protocol string, stdin io.Reader, args ...string) {
+	ctx, cancel := context.WithTimeout(req.Context(), 10*time.Minute)
+	defer cancel()
+
+	start := time.Now()
+	args = append([]string{"upload-pack", "--stateless-rpc"}, args...)
+	cmd := exec.Command("git", append(args, r.root)...)
+	envutil.SetDir(cmd, r.root)
+	envutil.SetEnv(cmd, "HOME="+r.mirror.homeDir)
+	if gitProtocolRE.MatchString(protocol) {
+		envutil.SetEnv(cmd, "GIT_PROTOCOL="+protocol)
+	}
+	var stderr strings.Builder
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = stdin, w, &stderr
+	// Once upload-pack starts writing, the response status can no longer
+	// be changed, so errors are only logged.
+	if err := runCmdContext(ctx, cmd); err != nil {
+		r.logf("git %s failed after %v: %v\nstderr: %v", args, time.Since(start), err, stderr.String())
+	}
+}
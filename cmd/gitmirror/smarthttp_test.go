@@ -0,0 +1,84 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io/fs"
+	"net/http"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/build/internal/gitfs"
+)
+
+func TestSmartHTTPClone(t *testing.T) {
+	tm := newTestMirror(t)
+	tm.commit("hello world")
+	rev := strings.TrimSpace(tm.git(tm.gerrit, "rev-parse", "HEAD"))
+	tm.loopOnce()
+
+	for _, version := range []string{"0", "2"} {
+		t.Run("protocol.version="+version, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "build")
+			cmd := exec.Command("git", "-c", "protocol.version="+version, "clone", tm.server.URL+"/build.git", dir)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("git clone: %v\n%s", err, out)
+			}
+			clone := explicitRepo{filepath.Join(dir, ".git"), dir}
+			if got := strings.TrimSpace(tm.git(clone, "rev-parse", "HEAD")); got != rev {
+				t.Errorf("cloned HEAD is %v, want %v", got, rev)
+			}
+		})
+	}
+}
+
+func TestSmartHTTPGitFS(t *testing.T) {
+	tm := newTestMirror(t)
+	tm.commit("hello gitfs")
+	tm.loopOnce()
+
+	repo, err := gitfs.NewRepo(tm.server.URL + "/build.git")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, fsys, err := repo.Clone("HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := fs.ReadFile(fsys, "README")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "hello gitfs" {
+		t.Errorf("README = %q, want %q", data, "hello gitfs")
+	}
+}
+
+func TestSmartHTTPReadOnly(t *testing.T) {
+	tm := newTestMirror(t)
+	for _, path := range []string{
+		"/build.git/info/refs?service=git-receive-pack",
+		"/build.git/info/refs",
+	} {
+		resp, err := http.Get(tm.server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("GET %s: status %v, want %v", path, resp.StatusCode, http.StatusForbidden)
+		}
+	}
+	resp, err := http.Post(tm.server.URL+"/build.git/git-receive-pack", "application/x-git-receive-pack-request", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("POST git-receive-pack: status %v, want %v", resp.StatusCode, http.StatusForbidden)
+	}
+}
@@ -6,8 +6,8 @@
 // GitHub and Gerrit.
 //
 // General documentation is at https://go.dev/wiki/gopherbot.
-// Consult the tasks slice in gopherbot.go for an up-to-date
-// list of all gopherbot tasks.
+// Consult the tasks slice in gopherbot.go and the rules in rules.yaml
+// for an up-to-date list of all gopherbot tasks.
 package main
 
 import (
@@ -61,18 +61,28 @@ var (
 	gerritTokenFile = flag.String("gerrit-token-file", filepath.Join(os.Getenv("HOME"), "keys", "gerrit-gobot"), `File to load Gerrit token from. File should be of form <git-email>:<token>`)
 
 	onlyRun = flag.String("only-run", "", "if non-empty, the name of a task to run. Mostly for debugging, but tasks (like 'kicktrain') may choose to only run in explicit mode")
+
+	rulesFile = flag.String("rules", "", "if non-empty, a YAML file of gardening rules to apply instead of the built-in rules.yaml")
+	planFile  = flag.String("plan", "", "if non-empty, implies -dry-run and writes the planned actions to this file, as JSON if it ends in .json and otherwise as text")
 )
 
 func init() {
 	flag.Usage = func() {
 		output := flag.CommandLine.Output()
 		fmt.Fprintf(output, "gopherbot runs Go's gopherbot role account on GitHub and Gerrit.\n\n")
+		fmt.Fprintf(output, "usage: gopherbot [flags]\n")
+		fmt.Fprintf(output, "       gopherbot [flags] apply plan.json\n\n")
 		flag.PrintDefaults()
 		fmt.Fprintln(output, "")
 		fmt.Fprintln(output, "Tasks (can be used for the --only-run flag):")
 		for _, t := range tasks {
 			fmt.Fprintf(output, "  %q\n", t.name)
 		}
+		if rules, err := parseRules(builtinRules); err == nil {
+			for _, r := range rules {
+				fmt.Fprintf(output, "  %q (rule)\n", r.Name)
+			}
+		}
 	}
 }
 
@@ -103,12 +113,9 @@ const (
 
 // GitHub Milestone numbers for the golang/go repo.
 var (
-	proposal      = milestone{30, "Proposal"}
-	unreleased    = milestone{22, "Unreleased"}
-	unplanned     = milestone{6, "Unplanned"}
-	gccgo         = milestone{23, "Gccgo"}
-	vgo           = milestone{71, "vgo"}
-	vulnUnplanned = milestone{288, "vuln/unplanned"}
+	proposal   = milestone{30, "Proposal"}
+	unreleased = milestone{22, "Unreleased"}
+	unplanned  = milestone{6, "Unplanned"}
 )
 
 // GitHub Milestone numbers for the golang/vscode-go repo.
@@ -231,6 +238,24 @@ type githubIssue struct {
 
 func main() {
 	flag.Parse()
+	var applyFile string
+	switch {
+	case flag.NArg() == 0:
+	case flag.NArg() == 2 && flag.Arg(0) == "apply":
+		applyFile = flag.Arg(1)
+		if *planFile != "" || *daemon {
+			log.Fatal("apply cannot be used with -plan or -daemon")
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *planFile != "" {
+		if *daemon {
+			log.Fatal("-plan cannot be used with -daemon")
+		}
+		*dryRun = true
+	}
 
 	var sc *secret.Client
 	if metadata.OnGCE() {
@@ -238,6 +263,10 @@ func main() {
 	}
 	ctx := context.Background()
 
+	rules, err := loadRules(*rulesFile)
+	if err != nil {
+		log.Fatal(err)
+	}
 	ghV3, ghV4, err := getGitHubClients(ctx, sc)
 	if err != nil {
 		log.Fatal(err)
@@ -254,11 +283,11 @@ func main() {
 	var goRepo = maintner.GitHubRepoID{Owner: "golang", Repo: "go"}
 	var vscode = maintner.GitHubRepoID{Owner: "golang", Repo: "vscode-go"}
 	bot := &gopherbot{
-		ghc:    ghV3,
 		ghV4:   ghV4,
 		gerrit: gerrit,
 		mc:     mc,
 		is:     ghV3.Issues,
+		rules:  rules,
 		deletedChanges: map[gerritChange]bool{
 			{"crypto", 35958}:  true,
 			{"scratch", 71730}: true,
@@ -386,6 +415,23 @@ func main() {
 	}
 	bot.initCorpus()
 
+	if applyFile != "" {
+		plan, err := readPlan(applyFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if errs := bot.applyPlan(ctx, plan); len(errs) > 0 {
+			for _, err := range errs {
+				log.Print(err)
+			}
+			os.Exit(1)
+		}
+		return
+	}
+	if *planFile != "" {
+		bot.plan = &actionPlan{Created: time.Now().UTC()}
+	}
+
 	for {
 		t0 := time.Now()
 		taskErrors := bot.doTasks(ctx)
@@ -394,6 +440,12 @@ func main() {
 		}
 		botDur := time.Since(t0)
 		log.Printf("gopherbot ran in %v", botDur)
+		if bot.plan != nil {
+			if err := bot.plan.write(*planFile); err != nil {
+				log.Fatal(err)
+			}
+			log.Printf("wrote %d planned actions to %s", len(bot.plan.Actions), *planFile)
+		}
 		if !*daemon {
 			if len(taskErrors) > 0 {
 				os.Exit(1)
@@ -425,13 +477,17 @@ func main() {
 }
 
 type gopherbot struct {
-	ghc    *github.Client
 	ghV4   *githubv4.Client
-	gerrit *gerrit.Client
+	gerrit gerritService
 	mc     apipb.MaintnerServiceClient
 	corpus *maintner.Corpus
 	gorepo *maintner.GitHubRepo
 	is     issuesService
+	rules  []*rule // gardening rules, run after tasks
+
+	task      string      // name of the task or rule being run
+	plan      *actionPlan // if non-nil, the actions planned in dry-run mode
+	newIssues int         // number of issues created in dry-run mode
 
 	knownContributors map[string]bool
 
@@ -454,15 +510,7 @@ var tasks = []struct {
 }{
 	// Tasks that are specific to the golang/go repo.
 	{"kicktrain", (*gopherbot).getOffKickTrain},
-	{"label access issues", (*gopherbot).labelAccessIssues},
-	{"label build issues", (*gopherbot).labelBuildIssues},
 	{"label compiler/runtime issues", (*gopherbot).labelCompilerRuntimeIssues},
-	{"label mobile issues", (*gopherbot).labelMobileIssues},
-	{"label tools issues", (*gopherbot).labelToolsIssues},
-	{"label website issues", (*gopherbot).labelWebsiteIssues},
-	{"label pkgsite issues", (*gopherbot).labelPkgsiteIssues},
-	{"label proxy.golang.org issues", (*gopherbot).labelProxyIssues},
-	{"label vulncheck or vulndb issues", (*gopherbot).labelVulnIssues},
 	{"label proposals", (*gopherbot).labelProposals},
 	{"handle gopls issues", (*gopherbot).handleGoplsIssues},
 	{"handle telemetry issues", (*gopherbot).handleTelemetryIssues},
@@ -470,7 +518,6 @@ var tasks = []struct {
 	{"close cherry pick issues", (*gopherbot).closeCherryPickIssues},
 	{"close luci-config issues", (*gopherbot).closeLUCIConfigIssues},
 	{"set subrepo milestones", (*gopherbot).setSubrepoMilestones},
-	{"set misc milestones", (*gopherbot).setMiscMilestones},
 	{"apply minor release milestones", (*gopherbot).setMinorMilestones},
 	{"update needs", (*gopherbot).updateNeeds},
 
@@ -533,21 +580,59 @@ func (b *gopherbot) doTasks(ctx context.Context) []error {
 		if *onlyRun != "" && task.name != *onlyRun {
 			continue
 		}
+		b.task = task.name
 		err := task.fn(b, ctx)
 		if err != nil {
 			errs = append(errs, fmt.Errorf("%s: %v", task.name, err))
 		}
 	}
+	for _, r := range b.rules {
+		if *onlyRun != "" && r.Name != *onlyRun {
+			continue
+		}
+		b.task = r.Name
+		if err := b.applyRule(ctx, r); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", r.Name, err))
+		}
+	}
 	return errs
 }
 
-// issuesService represents portions of github.IssuesService that we want to override in tests.
+// issuesService represents the portions of github.IssuesService that gopherbot uses,
+// so that tests can override them.
 type issuesService interface {
+	Get(ctx context.Context, owner, repo string, number int) (*github.Issue, *github.Response, error)
+	ListByRepo(ctx context.Context, owner, repo string, opts *github.IssueListByRepoOptions) ([]*github.Issue, *github.Response, error)
+	Create(ctx context.Context, owner, repo string, issue *github.IssueRequest) (*github.Issue, *github.Response, error)
+	Edit(ctx context.Context, owner, repo string, number int, issue *github.IssueRequest) (*github.Issue, *github.Response, error)
+	Lock(ctx context.Context, owner, repo string, number int, opts *github.LockIssueOptions) (*github.Response, error)
+	ListComments(ctx context.Context, owner, repo string, number int, opts *github.IssueListCommentsOptions) ([]*github.IssueComment, *github.Response, error)
+	CreateComment(ctx context.Context, owner, repo string, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error)
 	ListLabelsByIssue(ctx context.Context, owner, repo string, number int, opt *github.ListOptions) ([]*github.Label, *github.Response, error)
 	AddLabelsToIssue(ctx context.Context, owner, repo string, number int, labels []string) ([]*github.Label, *github.Response, error)
 	RemoveLabelForIssue(ctx context.Context, owner, repo string, number int, label string) (*github.Response, error)
 }
 
+// gerritService represents the portions of gerrit.Client that gopherbot uses,
+// so that tests can override them.
+type gerritService interface {
+	QueryChanges(ctx context.Context, q string, opts ...gerrit.QueryChangesOpt) ([]*gerrit.ChangeInfo, error)
+	GetChange(ctx context.Context, changeID string, opts ...gerrit.QueryChangesOpt) (*gerrit.ChangeInfo, error)
+	GetChangeDetail(ctx context.Context, changeID string, opts ...gerrit.QueryChangesOpt) (*gerrit.ChangeInfo, error)
+	ListFiles(ctx context.Context, changeID, revision string) (map[string]*gerrit.FileInfo, error)
+	ListReviewers(ctx context.Context, changeID string) ([]gerrit.ReviewerInfo, error)
+	GetMergeable(ctx context.Context, changeID, revision string) (gerrit.MergeableInfo, error)
+	GetRevisionActions(ctx context.Context, changeID, revision string) (map[string]*gerrit.ActionInfo, error)
+	GetRelatedChanges(ctx context.Context, changeID, revision string) (*gerrit.RelatedChangesInfo, error)
+	GetGroupMembers(ctx context.Context, groupID string) ([]gerrit.AccountInfo, error)
+	SetReview(ctx context.Context, changeID, revision string, review gerrit.ReviewInput) error
+	AddHashtags(ctx context.Context, changeID string, tags ...string) ([]string, error)
+	RemoveHashtags(ctx context.Context, changeID string, tags ...string) ([]string, error)
+	DeleteTopic(ctx context.Context, changeID string) error
+	AbandonChange(ctx context.Context, changeID string, message ...string) error
+	SubmitChange(ctx context.Context, changeID string) (gerrit.ChangeInfo, error)
+}
+
 func (b *gopherbot) addLabel(ctx context.Context, repoID maintner.GitHubRepoID, gi *maintner.GitHubIssue, label string) error {
 	return b.addLabels(ctx, repoID, gi, []string{label})
 }
@@ -563,7 +648,11 @@ func (b *gopherbot) addLabels(ctx context.Context, repoID maintner.GitHubRepoID,
 		toAdd = append(toAdd, label)
 	}
 
-	if *dryRun || len(toAdd) == 0 {
+	if len(toAdd) == 0 {
+		return nil
+	}
+	if *dryRun {
+		b.planIssue(ctx, repoID, gi.Number, "add-labels", toAdd...)
 		return nil
 	}
 
@@ -584,17 +673,21 @@ func (b *gopherbot) removeLabel(ctx context.Context, repoID maintner.GitHubRepoI
 }
 
 func (b *gopherbot) removeLabels(ctx context.Context, repoID maintner.GitHubRepoID, gi *maintner.GitHubIssue, labels []string) error {
-	var removeLabels bool
+	var present []string
 	for _, l := range labels {
 		if !gi.HasLabel(l) {
 			log.Printf("Issue %d (in maintner) does not have label %q; no need to send request to remove it", gi.Number, l)
 			continue
 		}
 		printIssue("label-"+l, repoID, gi)
-		removeLabels = true
+		present = append(present, l)
 	}
 
-	if *dryRun || !removeLabels {
+	if len(present) == 0 {
+		return nil
+	}
+	if *dryRun {
+		b.planIssue(ctx, repoID, gi.Number, "remove-labels", present...)
 		return nil
 	}
 
@@ -645,9 +738,10 @@ func removeLabelFromIssue(ctx context.Context, repoID maintner.GitHubRepoID, iss
 func (b *gopherbot) setMilestone(ctx context.Context, repoID maintner.GitHubRepoID, gi *maintner.GitHubIssue, m milestone) error {
 	printIssue("milestone-"+m.Name, repoID, gi)
 	if *dryRun {
+		b.planIssue(ctx, repoID, gi.Number, "set-milestone", strconv.Itoa(m.Number), m.Name)
 		return nil
 	}
-	_, resp, err := b.ghc.Issues.Edit(ctx, repoID.Owner, repoID.Repo, int(gi.Number), &github.IssueRequest{
+	_, resp, err := b.is.Edit(ctx, repoID.Owner, repoID.Repo, int(gi.Number), &github.IssueRequest{
 		Milestone: new(m.Number),
 	})
 	if err != nil && resp != nil && (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone) {
@@ -680,6 +774,7 @@ func (b *gopherbot) addGitHubComment(ctx context.Context, repo *maintner.GitHubR
 	}
 	if *dryRun {
 		log.Printf("[dry-run] would add comment to github.com/%s/issues/%d: %v", repo.ID(), issueNum, msg)
+		b.planIssue(ctx, repo.ID(), issueNum, "comment", msg)
 		return nil
 	}
 	// See if there is a dup comment from when gopherbot last got
@@ -688,7 +783,7 @@ func (b *gopherbot) addGitHubComment(ctx context.Context, repo *maintner.GitHubR
 	if !since.IsZero() {
 		opt.Since = &since
 	}
-	ics, resp, err := b.ghc.Issues.ListComments(ctx, repo.ID().Owner, repo.ID().Repo, int(issueNum), opt)
+	ics, resp, err := b.is.ListComments(ctx, repo.ID().Owner, repo.ID().Repo, int(issueNum), opt)
 	if err != nil {
 		// TODO(golang/go#40640) - This issue was transferred or otherwise is gone. We should permanently skip it. This
 		// is a temporary fix to keep gopherbot working.
@@ -705,7 +800,7 @@ func (b *gopherbot) addGitHubComment(ctx context.Context, repo *maintner.GitHubR
 			return nil
 		}
 	}
-	_, resp, createError := b.ghc.Issues.CreateComment(ctx, repo.ID().Owner, repo.ID().Repo, int(issueNum), &github.IssueComment{
+	_, resp, createError := b.is.CreateComment(ctx, repo.ID().Owner, repo.ID().Repo, int(issueNum), &github.IssueComment{
 		Body: new(msg),
 	})
 	if createError != nil && resp != nil && resp.StatusCode == http.StatusUnprocessableEntity {
@@ -714,7 +809,7 @@ func (b *gopherbot) addGitHubComment(ctx context.Context, repo *maintner.GitHubR
 		// empty list, so the error check from ListComments doesn't catch it. (The deleted
 		// issue 55403 is an example of such a case.) So check again with the Get endpoint,
 		// which seems to return 404 more reliably in such cases at least as of 2022-10-11.
-		if _, resp, err := b.ghc.Issues.Get(ctx, repo.ID().Owner, repo.ID().Repo, int(issueNum)); err != nil &&
+		if _, resp, err := b.is.Get(ctx, repo.ID().Owner, repo.ID().Repo, int(issueNum)); err != nil &&
 			resp != nil && resp.StatusCode == http.StatusNotFound {
 			log.Printf("addGitHubComment: Issue %v#%v returned a 404 after posting comment failed with 422. Skipping. See go.dev/issue/30184.", repo.ID(), issueNum)
 			b.deletedIssues[githubIssue{repo.ID(), issueNum}] = true
@@ -724,25 +819,19 @@ func (b *gopherbot) addGitHubComment(ctx context.Context, repo *maintner.GitHubR
 	return createError
 }
 
-// createGitHubIssue returns the number of the created issue, or 4242 in dry-run mode.
+// createGitHubIssue returns the number of the created issue.
+// In dry-run mode, it returns -n for the n'th issue it would have
+// created, which issueMention turns into a placeholder that
+// "gopherbot apply" replaces with the number of the issue once
+// it creates it.
 // baseEvent is the timestamp of the event causing this action, and is used for de-duplication.
 func (b *gopherbot) createGitHubIssue(ctx context.Context, title, msg string, labels []string, baseEvent time.Time) (int, error) {
-	var dup int
-	b.gorepo.ForeachIssue(func(gi *maintner.GitHubIssue) error {
-		// TODO: check for gopherbot as author? check for exact match?
-		// This seems fine for now.
-		if gi.Title == title {
-			dup = int(gi.Number)
-			return errStopIteration
-		}
-		return nil
-	})
-	if dup != 0 {
+	if dup := b.issueWithTitle(title); dup != 0 {
 		// Issue's already been posted. Nothing to do.
-		return dup, nil
+		return int(dup), nil
 	}
 	// See if there is a dup issue from when gopherbot last got its data from maintner.
-	is, _, err := b.ghc.Issues.ListByRepo(ctx, "golang", "go", &github.IssueListByRepoOptions{
+	is, _, err := b.is.ListByRepo(ctx, "golang", "go", &github.IssueListByRepoOptions{
 		State:       "all",
 		ListOptions: github.ListOptions{PerPage: 100},
 		Since:       baseEvent,
@@ -758,9 +847,16 @@ func (b *gopherbot) createGitHubIssue(ctx context.Context, title, msg string, la
 	}
 	if *dryRun {
 		log.Printf("[dry-run] would create issue with title %s and labels %v\n%s", title, labels, msg)
-		return 4242, nil
+		b.newIssues++
+		b.planAction(ctx, &plannedAction{
+			Issue:  &issueRef{Repo: b.gorepo.ID().String()},
+			Action: "create-issue",
+			Ref:    newIssuePlaceholder(b.newIssues),
+			Args:   append([]string{title, msg}, labels...),
+		})
+		return -b.newIssues, nil
 	}
-	i, _, err := b.ghc.Issues.Create(ctx, "golang", "go", &github.IssueRequest{
+	i, _, err := b.is.Create(ctx, "golang", "go", &github.IssueRequest{
 		Title:  new(title),
 		Body:   new(msg),
 		Labels: &labels,
@@ -768,6 +864,33 @@ func (b *gopherbot) createGitHubIssue(ctx context.Context, title, msg string, la
 	return i.GetNumber(), err
 }
 
+// issueWithTitle returns the number of the golang/go issue
+// with the given title in the corpus, or 0 if there is none.
+func (b *gopherbot) issueWithTitle(title string) int32 {
+	var num int32
+	b.gorepo.ForeachIssue(func(gi *maintner.GitHubIssue) error {
+		// TODO: check for gopherbot as author? check for exact match?
+		// This seems fine for now.
+		if gi.Title == title {
+			num = gi.Number
+			return errStopIteration
+		}
+		return nil
+	})
+	return num
+}
+
+// issueMention returns the text that refers to the golang/go issue
+// numbered num in a GitHub comment, such as "#123". If num is a
+// placeholder returned by createGitHubIssue in dry-run mode,
+// issueMention returns the placeholder in the form "#{new-issue-1}".
+func issueMention(num int) string {
+	if num < 0 {
+		return "#" + newIssuePlaceholder(-num)
+	}
+	return fmt.Sprintf("#%d", num)
+}
+
 // issueCloseReason is a reason given when closing an issue on GitHub.
 // See https://docs.github.com/en/issues/tracking-your-work-with-issues/closing-an-issue.
 type issueCloseReason *string
@@ -786,9 +909,14 @@ func (b *gopherbot) closeGitHubIssue(ctx context.Context, repoID maintner.GitHub
 			suffix = " as " + *reason
 		}
 		log.Printf("[dry-run] would close go.dev/issue/%v%s", number, suffix)
+		if reason != nil {
+			b.planIssue(ctx, repoID, number, "close", *reason)
+		} else {
+			b.planIssue(ctx, repoID, number, "close")
+		}
 		return nil
 	}
-	_, _, err := b.ghc.Issues.Edit(ctx, repoID.Owner, repoID.Repo, int(number), &github.IssueRequest{
+	_, _, err := b.is.Edit(ctx, repoID.Owner, repoID.Repo, int(number), &github.IssueRequest{
 		State:       new("closed"),
 		StateReason: reason,
 	})
@@ -814,6 +942,11 @@ func (b *gopherbot) addGerritComment(ctx context.Context, changeID, comment stri
 	}
 	if *dryRun {
 		log.Printf("[dry-run] would add comment to golang.org/cl/%s: %v", changeID, comment)
+		var oldPhrases []string
+		if opts != nil {
+			oldPhrases = opts.OldPhrases
+		}
+		b.planCL(ctx, clRef{ChangeID: changeID}, "comment", append([]string{comment}, oldPhrases...)...)
 		return nil
 	}
 	if opts == nil {
@@ -914,10 +1047,8 @@ func (b *gopherbot) getOffKickTrain(ctx context.Context) error {
 	fmt.Printf("%d issues:\n", len(matches))
 	for _, m := range matches {
 		fmt.Printf("%-30s - %s\n", m.url, m.title)
-		if !*dryRun {
-			if err := b.setMilestone(ctx, b.gorepo.ID(), m.gi, unplanned); err != nil {
-				return err
-			}
+		if err := b.setMilestone(ctx, b.gorepo.ID(), m.gi, unplanned); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -944,22 +1075,32 @@ func (b *gopherbot) freezeOldIssues(ctx context.Context) error {
 				return nil
 			}
 			printIssue("freeze", repo.ID(), gi)
-			if *dryRun {
-				return nil
+			if err := b.lockGitHubIssue(ctx, repo.ID(), gi); err != nil {
+				return err
 			}
-			_, err := b.ghc.Issues.Lock(ctx, repo.ID().Owner, repo.ID().Repo, int(gi.Number), nil)
-			if ge, ok := err.(*github.ErrorResponse); ok && ge.Response.StatusCode == http.StatusNotFound {
-				// An issue can become 404 on GitHub due to being deleted or transferred. See go.dev/issue/30182.
-				b.deletedIssues[githubIssue{repo.ID(), gi.Number}] = true
+			if b.deletedIssues[githubIssue{repo.ID(), gi.Number}] {
 				return nil
-			} else if err != nil {
-				return err
 			}
 			return b.addLabel(ctx, repo.ID(), gi, frozenDueToAge)
 		})
 	})
 }
 
+// lockGitHubIssue locks the conversation on a GitHub issue.
+func (b *gopherbot) lockGitHubIssue(ctx context.Context, repoID maintner.GitHubRepoID, gi *maintner.GitHubIssue) error {
+	if *dryRun {
+		b.planIssue(ctx, repoID, gi.Number, "lock")
+		return nil
+	}
+	_, err := b.is.Lock(ctx, repoID.Owner, repoID.Repo, int(gi.Number), nil)
+	if ge, ok := err.(*github.ErrorResponse); ok && ge.Response.StatusCode == http.StatusNotFound {
+		// An issue can become 404 on GitHub due to being deleted or transferred. See go.dev/issue/30182.
+		b.deletedIssues[githubIssue{repoID, gi.Number}] = true
+		return nil
+	}
+	return err
+}
+
 // labelProposals adds the "Proposal" label and "Proposal" milestone
 // to open issues with title beginning with "Proposal:". It tries not
 // to get into an edit war with a human.
@@ -1054,31 +1195,13 @@ func (b *gopherbot) setSubrepoMilestones(ctx context.Context) error {
 			// These get vendored in. Don't mess with them.
 			return nil
 		case "x/vgo":
-			// Handled by setMiscMilestones
+			// Handled by the "set vgo milestone" rule in rules.yaml.
 			return nil
 		}
 		return b.setMilestone(ctx, b.gorepo.ID(), gi, unreleased)
 	})
 }
 
-func (b *gopherbot) setMiscMilestones(ctx context.Context) error {
-	return b.foreachIssue(b.gorepo, open, func(gi *maintner.GitHubIssue) error {
-		if !gi.Milestone.IsNone() || gi.HasEvent("demilestoned") || gi.HasEvent("milestoned") {
-			return nil
-		}
-		if strings.Contains(gi.Title, "gccgo") { // TODO: better gccgo bug report heuristic?
-			return b.setMilestone(ctx, b.gorepo.ID(), gi, gccgo)
-		}
-		if strings.HasPrefix(gi.Title, "x/vgo") {
-			return b.setMilestone(ctx, b.gorepo.ID(), gi, vgo)
-		}
-		if strings.HasPrefix(gi.Title, "x/vuln") {
-			return b.setMilestone(ctx, b.gorepo.ID(), gi, vulnUnplanned)
-		}
-		return nil
-	})
-}
-
 func (b *gopherbot) setVSCodeGoMilestones(ctx context.Context) error {
 	vscode := b.corpus.GitHub().Repo("golang", "vscode-go")
 	if vscode == nil {
@@ -1096,24 +1219,6 @@ func (b *gopherbot) setVSCodeGoMilestones(ctx context.Context) error {
 	})
 }
 
-func (b *gopherbot) labelAccessIssues(ctx context.Context) error {
-	return b.foreachIssue(b.gorepo, open, func(gi *maintner.GitHubIssue) error {
-		if !strings.HasPrefix(gi.Title, "access: ") || gi.HasLabel("Access") || gi.HasEvent("unlabeled") {
-			return nil
-		}
-		return b.addLabel(ctx, b.gorepo.ID(), gi, "Access")
-	})
-}
-
-func (b *gopherbot) labelBuildIssues(ctx context.Context) error {
-	return b.foreachIssue(b.gorepo, open, func(gi *maintner.GitHubIssue) error {
-		if !strings.HasPrefix(gi.Title, "x/build") || gi.HasLabel("Builders") || gi.HasEvent("unlabeled") {
-			return nil
-		}
-		return b.addLabel(ctx, b.gorepo.ID(), gi, "Builders")
-	})
-}
-
 func (b *gopherbot) labelCompilerRuntimeIssues(ctx context.Context) error {
 	entries, err := getAllCodeOwners(ctx)
 	if err != nil {
@@ -1151,15 +1256,6 @@ func (b *gopherbot) labelCompilerRuntimeIssues(ctx context.Context) error {
 	})
 }
 
-func (b *gopherbot) labelMobileIssues(ctx context.Context) error {
-	return b.foreachIssue(b.gorepo, open, func(gi *maintner.GitHubIssue) error {
-		if !strings.HasPrefix(gi.Title, "x/mobile") || gi.HasLabel("mobile") || gi.HasEvent("unlabeled") {
-			return nil
-		}
-		return b.addLabel(ctx, b.gorepo.ID(), gi, "mobile")
-	})
-}
-
 func (b *gopherbot) labelDocumentationIssues(ctx context.Context) error {
 	const documentation = "Documentation"
 	return b.corpus.GitHub().ForeachRepo(func(repo *maintner.GitHubRepo) error {
@@ -1178,56 +1274,6 @@ func (b *gopherbot) labelDocumentationIssues(ctx context.Context) error {
 	})
 }
 
-func (b *gopherbot) labelToolsIssues(ctx context.Context) error {
-	return b.foreachIssue(b.gorepo, open, func(gi *maintner.GitHubIssue) error {
-		if !strings.HasPrefix(gi.Title, "x/tools") || gi.HasLabel("Tools") || gi.HasEvent("unlabeled") {
-			return nil
-		}
-		return b.addLabel(ctx, b.gorepo.ID(), gi, "Tools")
-	})
-}
-
-func (b *gopherbot) labelWebsiteIssues(ctx context.Context) error {
-	return b.foreachIssue(b.gorepo, open, func(gi *maintner.GitHubIssue) error {
-		hasWebsiteTitle := strings.HasPrefix(gi.Title, "x/website:")
-		if !hasWebsiteTitle || gi.HasLabel("website") || gi.HasEvent("unlabeled") {
-			return nil
-		}
-		return b.addLabel(ctx, b.gorepo.ID(), gi, "website")
-	})
-}
-
-func (b *gopherbot) labelPkgsiteIssues(ctx context.Context) error {
-	return b.foreachIssue(b.gorepo, open, func(gi *maintner.GitHubIssue) error {
-		hasPkgsiteTitle := strings.HasPrefix(gi.Title, "x/pkgsite:")
-		if !hasPkgsiteTitle || gi.HasLabel("pkgsite") || gi.HasEvent("unlabeled") {
-			return nil
-		}
-		return b.addLabel(ctx, b.gorepo.ID(), gi, "pkgsite")
-	})
-}
-
-func (b *gopherbot) labelProxyIssues(ctx context.Context) error {
-	return b.foreachIssue(b.gorepo, open, func(gi *maintner.GitHubIssue) error {
-		hasProxyTitle := strings.Contains(gi.Title, "proxy.golang.org") || strings.Contains(gi.Title, "sum.golang.org") || strings.Contains(gi.Title, "index.golang.org")
-		if !hasProxyTitle || gi.HasLabel("proxy.golang.org") || gi.HasEvent("unlabeled") {
-			return nil
-		}
-		return b.addLabel(ctx, b.gorepo.ID(), gi, "proxy.golang.org")
-	})
-}
-
-func (b *gopherbot) labelVulnIssues(ctx context.Context) error {
-	return b.foreachIssue(b.gorepo, open, func(gi *maintner.GitHubIssue) error {
-		hasVulnTitle := strings.HasPrefix(gi.Title, "x/vuln:") || strings.HasPrefix(gi.Title, "x/vuln/") ||
-			strings.HasPrefix(gi.Title, "x/vulndb:") || strings.HasPrefix(gi.Title, "x/vulndb/")
-		if !hasVulnTitle || gi.HasLabel("vulncheck or vulndb") || gi.HasEvent("unlabeled") {
-			return nil
-		}
-		return b.addLabel(ctx, b.gorepo.ID(), gi, "vulncheck or vulndb")
-	})
-}
-
 // handleGoplsIssues labels and asks for additional information on gopls issues.
 //
 // This is necessary because gopls issues often require additional information to diagnose,
@@ -1685,6 +1731,7 @@ func (b *gopherbot) unwaitCLs(ctx context.Context) error {
 					err := b.onLatestCL(ctx, cl, func() error {
 						if *dryRun {
 							log.Printf("[dry run] would remove hashtag 'wait-author' from CL %d", cl.Number)
+							b.planCL(ctx, clRef{Project: gp.Project(), Number: cl.Number}, "remove-hashtags", "wait-author")
 							return nil
 						}
 						_, err := b.gerrit.RemoveHashtags(ctx, fmt.Sprint(cl.Number), "wait-author")
@@ -1715,6 +1762,9 @@ func (b *gopherbot) topicToHashtag(ctx context.Context) error {
 	for _, cl := range waitTopicCLs {
 		if *dryRun {
 			log.Printf("[dry run] would replace 'wait-release' topic with hashtag on CL %d (%.32s…)", cl.ChangeNumber, cl.Subject)
+			ref := clRef{Project: cl.Project, Number: int32(cl.ChangeNumber)}
+			b.planCL(ctx, ref, "add-hashtags", "wait-release")
+			b.planCL(ctx, ref, "delete-topic")
 			continue
 		}
 		_, err := b.gerrit.AddHashtags(ctx, cl.ID, "wait-release")
@@ -1906,7 +1956,7 @@ func (b *gopherbot) openCherryPickIssues(ctx context.Context) error {
 			if err != nil {
 				return err
 			}
-			openedIssues = append(openedIssues, fmt.Sprintf("#%d (for %s)", id, rel))
+			openedIssues = append(openedIssues, fmt.Sprintf("%s (for %s)", issueMention(id), rel))
 		}
 		return b.addGitHubComment(ctx, b.gorepo, gi.Number, fmt.Sprintf("Backport issue(s) opened: %s.\n\nRemember to create the cherry-pick CL(s) as soon as the patch is submitted to master, according to https://go.dev/wiki/MinorReleases.", strings.Join(openedIssues, ", ")))
 	})
@@ -2363,6 +2413,7 @@ func (b *gopherbot) assignReviewersToCLs(ctx context.Context) error {
 				// No owners found for the change. Add the #no-owners tag.
 				log.Printf("Adding no-owners tag to change %s...", changeURL)
 				if *dryRun {
+					b.planCL(ctx, clRef{Project: gc.project, Number: gc.num}, "add-hashtags", tagNoOwners)
 					return nil
 				}
 				if _, err := b.gerrit.AddHashtags(ctx, gc.ID(), tagNoOwners); err != nil {
@@ -2389,6 +2440,15 @@ func (b *gopherbot) assignReviewersToCLs(ctx context.Context) error {
 			}
 			if *dryRun {
 				log.Printf("[dry run] Would set review on %s: %+v", changeURL, review)
+				var reviewers []string
+				for _, r := range review.Reviewers {
+					state := r.State
+					if state == "" {
+						state = "REVIEWER"
+					}
+					reviewers = append(reviewers, state+":"+r.Reviewer)
+				}
+				b.planCL(ctx, clRef{Project: gc.project, Number: gc.num}, "set-review", reviewers...)
 				return nil
 			}
 			log.Printf("Setting review on %s: %+v", changeURL, review)
@@ -2444,12 +2504,14 @@ func (b *gopherbot) abandonScratchReviews(ctx context.Context) error {
 		if b.deletedChanges[gerritChange{scratchProject.Project(), cl.Number}] || !cl.Meta.Commit.CommitTime.Before(tooOld) {
 			return nil
 		}
+		const msg = "Auto-abandoning old scratch review."
 		if *dryRun {
 			log.Printf("[dry-run] would've closed scratch CL https://go.dev/cl/%d ...", cl.Number)
+			b.planCL(withReason(ctx, "no activity for a week"), clRef{Project: scratchProject.Project(), Number: cl.Number, Meta: cl.Meta.Commit.Hash.String()}, "abandon", msg)
 			return nil
 		}
 		log.Printf("closing scratch CL https://go.dev/cl/%d ...", cl.Number)
-		err := b.gerrit.AbandonChange(ctx, fmt.Sprint(cl.Number), "Auto-abandoning old scratch review.")
+		err := b.gerrit.AbandonChange(ctx, fmt.Sprint(cl.Number), msg)
 		if err != nil && strings.Contains(err.Error(), "404 Not Found") {
 			return nil
 		}
@@ -2682,6 +2744,7 @@ func (b *gopherbot) autoSubmitCLs(ctx context.Context) error {
 
 			if *dryRun {
 				log.Printf("[dry-run] would've submitted CL https://golang.org/cl/%d ...", cl.Number)
+				b.planCL(ctx, clRef{Project: gp.Project(), Number: cl.Number, Meta: cl.Meta.Commit.Hash.String()}, "submit")
 				return nil
 			}
 			log.Printf("submitting CL https://golang.org/cl/%d ...", cl.Number)
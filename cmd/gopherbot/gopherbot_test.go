@@ -180,6 +180,8 @@ func TestLabelMutations(t *testing.T) {
 }
 
 type fakeIssuesService struct {
+	issuesService // nil; only the label methods below are implemented
+
 	labels map[int][]string
 }
 
@@ -335,7 +337,7 @@ func TestRemoveLabels(t *testing.T) {
 	b := &gopherbot{}
 	for _, tc := range testCases {
 		// Clear any previous state from fakeIssuesService since some test cases may skip calls to it.
-		fis := &fakeIssuesService{map[int][]string{
+		fis := &fakeIssuesService{labels: map[int][]string{
 			int(tc.gi.Number): tc.ghLabels,
 		}}
 		b.is = fis
@@ -0,0 +1,375 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+// This file holds a harness for testing gopherbot tasks offline.
+// newTestBot loads a corpus from a mutation log fixture in testdata
+// and connects the bot to in-memory fakes of GitHub and Gerrit
+// that record the calls made to them, for comparison against
+// the calls a test expects.
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v74/github"
+	"golang.org/x/build/gerrit"
+	"golang.org/x/build/maintner"
+	"golang.org/x/build/maintner/maintnerd/apipb"
+	"golang.org/x/build/maintner/maintpb"
+	"golang.org/x/tools/txtar"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/prototext"
+)
+
+// newTestBot returns a gopherbot whose corpus is loaded from the named
+// fixture in testdata, along with the fakes it uses for GitHub and Gerrit.
+//
+// A fixture is a txtar archive in which each file holds one
+// maintpb.Mutation in text format. The mutations are applied in order;
+// the file names only serve to describe them.
+func newTestBot(t *testing.T, fixture string) (*gopherbot, *fakeGitHub, *fakeGerrit) {
+	t.Helper()
+	old := *dryRun
+	*dryRun = false
+	t.Cleanup(func() { *dryRun = old })
+
+	ar, err := txtar.ParseFile(filepath.Join("testdata", fixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var src fixtureSource
+	for _, f := range ar.Files {
+		m := new(maintpb.Mutation)
+		if err := prototext.Unmarshal(f.Data, m); err != nil {
+			t.Fatalf("%s: %s: %v", fixture, f.Name, err)
+		}
+		src = append(src, m)
+	}
+	corpus := new(maintner.Corpus)
+	if err := corpus.Initialize(context.Background(), src); err != nil {
+		t.Fatal(err)
+	}
+
+	gh := &fakeGitHub{corpus: corpus, nextIssue: 1000}
+	gc := &fakeGerrit{corpus: corpus}
+	b := &gopherbot{
+		corpus:         corpus,
+		gorepo:         corpus.GitHub().Repo("golang", "go"),
+		is:             gh,
+		gerrit:         gc,
+		mc:             fakeMaintner{},
+		deletedChanges: map[gerritChange]bool{},
+		deletedIssues:  map[githubIssue]bool{},
+	}
+	return b, gh, gc
+}
+
+// fixtureSource is a maintner.MutationSource that sends a fixed list of mutations.
+type fixtureSource []*maintpb.Mutation
+
+func (s fixtureSource) GetMutations(ctx context.Context) <-chan maintner.MutationStreamEvent {
+	ch := make(chan maintner.MutationStreamEvent, len(s)+1)
+	for _, m := range s {
+		ch <- maintner.MutationStreamEvent{Mutation: m}
+	}
+	ch <- maintner.MutationStreamEvent{End: true}
+	return ch
+}
+
+// fakeMaintner is a maintner service that knows about Go 1.24 and Go 1.25.
+type fakeMaintner struct {
+	apipb.MaintnerServiceClient // nil; only the methods below are implemented
+}
+
+func (fakeMaintner) ListGoReleases(ctx context.Context, req *apipb.ListGoReleasesRequest, opts ...grpc.CallOption) (*apipb.ListGoReleasesResponse, error) {
+	return &apipb.ListGoReleasesResponse{Releases: []*apipb.GoRelease{
+		{Major: 1, Minor: 25, Patch: 2, TagName: "go1.25.2"},
+		{Major: 1, Minor: 24, Patch: 8, TagName: "go1.24.8"},
+	}}, nil
+}
+
+// fakeGitHub is an issuesService that answers queries from the corpus
+// and the issues and comments created through it, and records all calls
+// made to it.
+type fakeGitHub struct {
+	corpus    *maintner.Corpus
+	nextIssue int                               // number of the next issue created
+	created   []*github.Issue                   // issues created
+	comments  map[string][]*github.IssueComment // comments created, by "owner/repo#number"
+	calls     []string                          // calls made, in order
+}
+
+func (f *fakeGitHub) record(format string, args ...any) {
+	f.calls = append(f.calls, fmt.Sprintf(format, args...))
+}
+
+// issue returns the corpus's copy of the given issue, or nil.
+func (f *fakeGitHub) issue(owner, repo string, number int) *maintner.GitHubIssue {
+	r := f.corpus.GitHub().Repo(owner, repo)
+	if r == nil {
+		return nil
+	}
+	return r.Issue(int32(number))
+}
+
+var errNotFound = &github.ErrorResponse{Response: &http.Response{
+	Status:     http.StatusText(http.StatusNotFound),
+	StatusCode: http.StatusNotFound,
+}}
+
+func (f *fakeGitHub) Get(ctx context.Context, owner, repo string, number int) (*github.Issue, *github.Response, error) {
+	f.record("Get %s/%s#%d", owner, repo, number)
+	gi := f.issue(owner, repo, number)
+	if gi == nil {
+		return nil, nil, errNotFound
+	}
+	return &github.Issue{Number: new(number), Title: new(gi.Title)}, nil, nil
+}
+
+func (f *fakeGitHub) ListByRepo(ctx context.Context, owner, repo string, opts *github.IssueListByRepoOptions) ([]*github.Issue, *github.Response, error) {
+	f.record("ListByRepo %s/%s", owner, repo)
+	return f.created, nil, nil
+}
+
+func (f *fakeGitHub) Create(ctx context.Context, owner, repo string, issue *github.IssueRequest) (*github.Issue, *github.Response, error) {
+	f.record("Create %s/%s %q labels=%q", owner, repo, issue.GetTitle(), issue.GetLabels())
+	num := f.nextIssue
+	f.nextIssue++
+	i := &github.Issue{Number: new(num), Title: issue.Title}
+	f.created = append(f.created, i)
+	return i, nil, nil
+}
+
+func (f *fakeGitHub) Edit(ctx context.Context, owner, repo string, number int, issue *github.IssueRequest) (*github.Issue, *github.Response, error) {
+	var fields []string
+	if issue.Milestone != nil {
+		fields = append(fields, fmt.Sprintf("milestone=%d", issue.GetMilestone()))
+	}
+	if issue.State != nil {
+		fields = append(fields, "state="+issue.GetState())
+	}
+	if issue.StateReason != nil {
+		fields = append(fields, "reason="+issue.GetStateReason())
+	}
+	f.record("Edit %s/%s#%d %s", owner, repo, number, strings.Join(fields, " "))
+	return &github.Issue{Number: new(number)}, nil, nil
+}
+
+func (f *fakeGitHub) Lock(ctx context.Context, owner, repo string, number int, opts *github.LockIssueOptions) (*github.Response, error) {
+	f.record("Lock %s/%s#%d", owner, repo, number)
+	return nil, nil
+}
+
+func (f *fakeGitHub) ListComments(ctx context.Context, owner, repo string, number int, opts *github.IssueListCommentsOptions) ([]*github.IssueComment, *github.Response, error) {
+	f.record("ListComments %s/%s#%d", owner, repo, number)
+	return f.comments[fmt.Sprintf("%s/%s#%d", owner, repo, number)], nil, nil
+}
+
+func (f *fakeGitHub) CreateComment(ctx context.Context, owner, repo string, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+	f.record("CreateComment %s/%s#%d %q", owner, repo, number, comment.GetBody())
+	if f.comments == nil {
+		f.comments = make(map[string][]*github.IssueComment)
+	}
+	key := fmt.Sprintf("%s/%s#%d", owner, repo, number)
+	f.comments[key] = append(f.comments[key], comment)
+	return comment, nil, nil
+}
+
+func (f *fakeGitHub) ListLabelsByIssue(ctx context.Context, owner, repo string, number int, opt *github.ListOptions) ([]*github.Label, *github.Response, error) {
+	f.record("ListLabelsByIssue %s/%s#%d", owner, repo, number)
+	gi := f.issue(owner, repo, number)
+	if gi == nil {
+		return nil, nil, errNotFound
+	}
+	var labels []*github.Label
+	for _, id := range slices.Sorted(maps.Keys(gi.Labels)) {
+		labels = append(labels, &github.Label{Name: new(gi.Labels[id].Name)})
+	}
+	return labels, nil, nil
+}
+
+func (f *fakeGitHub) AddLabelsToIssue(ctx context.Context, owner, repo string, number int, labels []string) ([]*github.Label, *github.Response, error) {
+	f.record("AddLabelsToIssue %s/%s#%d %q", owner, repo, number, labels)
+	return nil, nil, nil
+}
+
+func (f *fakeGitHub) RemoveLabelForIssue(ctx context.Context, owner, repo string, number int, label string) (*github.Response, error) {
+	f.record("RemoveLabelForIssue %s/%s#%d %q", owner, repo, number, label)
+	return nil, nil
+}
+
+// fakeGerrit is a gerritService for go-review.googlesource.com
+// that answers queries about CLs from the corpus and the messages
+// posted through it, and records all calls made to it.
+// It has no groups, reviewers or topics.
+type fakeGerrit struct {
+	corpus   *maintner.Corpus
+	messages map[int32][]string // messages posted, by CL number
+	calls    []string           // calls made, in order
+}
+
+func (f *fakeGerrit) record(format string, args ...any) {
+	f.calls = append(f.calls, fmt.Sprintf(format, args...))
+}
+
+// cl returns the corpus's copy of the CL with the given ID,
+// which may be a "project~number", a number, or a Change-Id.
+func (f *fakeGerrit) cl(changeID string) *maintner.GerritCL {
+	project, num, ok := strings.Cut(changeID, "~")
+	if !ok {
+		project, num = "", changeID
+	}
+	n, err := strconv.Atoi(num)
+	var found *maintner.GerritCL
+	f.corpus.Gerrit().ForeachProjectUnsorted(func(gp *maintner.GerritProject) error {
+		if gp.Server() != "go.googlesource.com" || project != "" && gp.Project() != project {
+			return nil
+		}
+		return gp.ForeachCLUnsorted(func(cl *maintner.GerritCL) error {
+			if err == nil && cl.Number == int32(n) || err != nil && cl.ChangeID() == changeID {
+				found = cl
+				return errStopIteration
+			}
+			return nil
+		})
+	})
+	return found
+}
+
+// changeInfo returns the ChangeInfo for the given change.
+func (f *fakeGerrit) changeInfo(changeID string) (*gerrit.ChangeInfo, error) {
+	cl := f.cl(changeID)
+	if cl == nil {
+		return nil, gerrit.ErrResourceNotExist
+	}
+	ci := &gerrit.ChangeInfo{
+		ID:              cl.Project.Project() + "~" + fmt.Sprint(cl.Number),
+		ChangeNumber:    int(cl.Number),
+		Project:         cl.Project.Project(),
+		Branch:          cl.Branch(),
+		ChangeID:        cl.ChangeID(),
+		Subject:         cl.Subject(),
+		Status:          strings.ToUpper(cl.Status),
+		CurrentRevision: cl.Commit.Hash.String(),
+	}
+	for _, m := range cl.Messages {
+		ci.Messages = append(ci.Messages, gerrit.ChangeMessageInfo{Message: m.Message})
+	}
+	for _, m := range f.messages[cl.Number] {
+		ci.Messages = append(ci.Messages, gerrit.ChangeMessageInfo{Message: m})
+	}
+	return ci, nil
+}
+
+func (f *fakeGerrit) QueryChanges(ctx context.Context, q string, opts ...gerrit.QueryChangesOpt) ([]*gerrit.ChangeInfo, error) {
+	f.record("QueryChanges %q", q)
+	return nil, nil
+}
+
+func (f *fakeGerrit) GetChange(ctx context.Context, changeID string, opts ...gerrit.QueryChangesOpt) (*gerrit.ChangeInfo, error) {
+	f.record("GetChange %s", changeID)
+	return f.changeInfo(changeID)
+}
+
+func (f *fakeGerrit) GetChangeDetail(ctx context.Context, changeID string, opts ...gerrit.QueryChangesOpt) (*gerrit.ChangeInfo, error) {
+	f.record("GetChangeDetail %s", changeID)
+	return f.changeInfo(changeID)
+}
+
+func (f *fakeGerrit) ListFiles(ctx context.Context, changeID, revision string) (map[string]*gerrit.FileInfo, error) {
+	f.record("ListFiles %s %s", changeID, revision)
+	cl := f.cl(changeID)
+	if cl == nil {
+		return nil, gerrit.ErrResourceNotExist
+	}
+	files := make(map[string]*gerrit.FileInfo)
+	for _, file := range cl.Commit.Files {
+		files[file.File] = &gerrit.FileInfo{}
+	}
+	return files, nil
+}
+
+func (f *fakeGerrit) ListReviewers(ctx context.Context, changeID string) ([]gerrit.ReviewerInfo, error) {
+	f.record("ListReviewers %s", changeID)
+	return nil, nil
+}
+
+func (f *fakeGerrit) GetMergeable(ctx context.Context, changeID, revision string) (gerrit.MergeableInfo, error) {
+	f.record("GetMergeable %s %s", changeID, revision)
+	return gerrit.MergeableInfo{Mergeable: true}, nil
+}
+
+func (f *fakeGerrit) GetRevisionActions(ctx context.Context, changeID, revision string) (map[string]*gerrit.ActionInfo, error) {
+	f.record("GetRevisionActions %s %s", changeID, revision)
+	return nil, nil
+}
+
+func (f *fakeGerrit) GetRelatedChanges(ctx context.Context, changeID, revision string) (*gerrit.RelatedChangesInfo, error) {
+	f.record("GetRelatedChanges %s %s", changeID, revision)
+	return &gerrit.RelatedChangesInfo{}, nil
+}
+
+func (f *fakeGerrit) GetGroupMembers(ctx context.Context, groupID string) ([]gerrit.AccountInfo, error) {
+	f.record("GetGroupMembers %s", groupID)
+	return nil, nil
+}
+
+func (f *fakeGerrit) SetReview(ctx context.Context, changeID, revision string, review gerrit.ReviewInput) error {
+	var fields []string
+	if review.Message != "" {
+		fields = append(fields, fmt.Sprintf("message=%q", review.Message))
+	}
+	for _, r := range review.Reviewers {
+		state := r.State
+		if state == "" {
+			state = "REVIEWER"
+		}
+		fields = append(fields, state+"="+r.Reviewer)
+	}
+	for label, value := range review.Labels {
+		fields = append(fields, fmt.Sprintf("%s%+d", label, value))
+	}
+	f.record("SetReview %s %s %s", changeID, revision, strings.Join(fields, " "))
+	if cl := f.cl(changeID); cl != nil && review.Message != "" {
+		if f.messages == nil {
+			f.messages = make(map[int32][]string)
+		}
+		f.messages[cl.Number] = append(f.messages[cl.Number], review.Message)
+	}
+	return nil
+}
+
+func (f *fakeGerrit) AddHashtags(ctx context.Context, changeID string, tags ...string) ([]string, error) {
+	f.record("AddHashtags %s %q", changeID, tags)
+	return nil, nil
+}
+
+func (f *fakeGerrit) RemoveHashtags(ctx context.Context, changeID string, tags ...string) ([]string, error) {
+	f.record("RemoveHashtags %s %q", changeID, tags)
+	return nil, nil
+}
+
+func (f *fakeGerrit) DeleteTopic(ctx context.Context, changeID string) error {
+	f.record("DeleteTopic %s", changeID)
+	return nil
+}
+
+func (f *fakeGerrit) AbandonChange(ctx context.Context, changeID string, message ...string) error {
+	f.record("AbandonChange %s %q", changeID, message)
+	return nil
+}
+
+func (f *fakeGerrit) SubmitChange(ctx context.Context, changeID string) (gerrit.ChangeInfo, error) {
+	f.record("SubmitChange %s", changeID)
+	return gerrit.ChangeInfo{}, nil
+}
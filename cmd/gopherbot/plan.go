@@ -0,0 +1,519 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/build/gerrit"
+	"golang.org/x/build/internal/foreach"
+	"golang.org/x/build/internal/gophers"
+	"golang.org/x/build/maintner"
+)
+
+// An actionPlan is the list of changes gopherbot would make,
+// recorded in dry-run mode for review and later application
+// with "gopherbot apply".
+type actionPlan struct {
+	Created time.Time        `json:"created"`
+	Actions []*plannedAction `json:"actions"`
+}
+
+// A plannedAction is a single change to an issue or CL.
+type plannedAction struct {
+	Task   string    `json:"task"`             // task or rule that planned the action
+	Reason string    `json:"reason,omitempty"` // why, if the task says
+	Issue  *issueRef `json:"issue,omitempty"`  // target issue, or its repo for "create-issue"
+	CL     *clRef    `json:"cl,omitempty"`     // target CL
+	Action string    `json:"action"`           // see applyAction
+	Ref    string    `json:"ref,omitempty"`    // for "create-issue", the placeholder for the new issue
+	Args   []string  `json:"args,omitempty"`   // labels, comment text, and so on
+}
+
+// newIssueRx matches the placeholders by which planned actions
+// refer to issues created earlier in the plan.
+var newIssueRx = regexp.MustCompile(`\{new-issue-[0-9]+\}`)
+
+// newIssuePlaceholder returns the placeholder for the n'th issue
+// created in a plan, such as "{new-issue-1}".
+func newIssuePlaceholder(n int) string {
+	return fmt.Sprintf("{new-issue-%d}", n)
+}
+
+// An issueRef identifies a GitHub issue.
+type issueRef struct {
+	Repo   string `json:"repo"` // "owner/repo"
+	Number int32  `json:"number,omitempty"`
+}
+
+// A clRef identifies a Gerrit CL on go-review.googlesource.com,
+// by project and number if known, and otherwise by Change-Id.
+type clRef struct {
+	Project  string `json:"project,omitempty"`
+	Number   int32  `json:"number,omitempty"`
+	ChangeID string `json:"change_id,omitempty"`
+
+	// Meta is the hash of the CL's meta commit when a submit or
+	// abandon action was planned. Those actions are only applied
+	// if the CL hasn't changed since.
+	Meta string `json:"meta,omitempty"`
+}
+
+// id returns an identifier for c that the Gerrit API accepts.
+func (c *clRef) id() string {
+	if c.Number == 0 {
+		return c.ChangeID
+	}
+	if c.Project == "" {
+		return fmt.Sprint(c.Number)
+	}
+	return gerritChange{c.Project, c.Number}.ID()
+}
+
+// String returns the one-line text form of a, as printed by writeText.
+func (a *plannedAction) String() string {
+	var target string
+	switch {
+	case a.Issue != nil && a.Issue.Number == 0:
+		target = "github.com/" + a.Issue.Repo
+	case a.Issue != nil && a.Issue.Repo == "golang/go":
+		target = fmt.Sprintf("go.dev/issue/%d", a.Issue.Number)
+	case a.Issue != nil:
+		target = fmt.Sprintf("github.com/%s/issues/%d", a.Issue.Repo, a.Issue.Number)
+	case a.CL != nil && a.CL.Number != 0:
+		target = fmt.Sprintf("go.dev/cl/%d", a.CL.Number)
+	case a.CL != nil:
+		target = "go-review.googlesource.com/q/" + a.CL.ChangeID
+	}
+	var args []string
+	for _, arg := range a.Args {
+		args = append(args, strconv.Quote(arg))
+	}
+	s := fmt.Sprintf("%s: %s %s", a.Task, target, a.Action)
+	if a.Ref != "" {
+		s += " " + a.Ref
+	}
+	if len(args) > 0 {
+		s += " " + strings.Join(args, " ")
+	}
+	if a.Reason != "" {
+		s += " # " + a.Reason
+	}
+	return s
+}
+
+// writeText writes p to w, one action per line, for reading and diffing.
+func (p *actionPlan) writeText(w io.Writer) error {
+	for _, a := range p.Actions {
+		if _, err := fmt.Fprintln(w, a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// write writes p to the named file: as JSON if the name ends in .json,
+// and otherwise as text.
+func (p *actionPlan) write(file string) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	if strings.HasSuffix(file, ".json") {
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "\t")
+		err = enc.Encode(p)
+	} else {
+		err = p.writeText(f)
+	}
+	if err1 := f.Close(); err == nil {
+		err = err1
+	}
+	return err
+}
+
+// readPlan reads a plan written as JSON by write.
+func readPlan(file string) (*actionPlan, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	p := new(actionPlan)
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	return p, nil
+}
+
+type reasonKey struct{}
+
+// withReason returns a context that records reason as the explanation
+// for the actions planned using it.
+func withReason(ctx context.Context, reason string) context.Context {
+	return context.WithValue(ctx, reasonKey{}, reason)
+}
+
+// planAction records a planned action in b's plan, if b is making one.
+func (b *gopherbot) planAction(ctx context.Context, a *plannedAction) {
+	if b.plan == nil {
+		return
+	}
+	a.Task = b.task
+	a.Reason, _ = ctx.Value(reasonKey{}).(string)
+	b.plan.Actions = append(b.plan.Actions, a)
+}
+
+// planIssue records a planned action on an issue.
+func (b *gopherbot) planIssue(ctx context.Context, repoID maintner.GitHubRepoID, num int32, action string, args ...string) {
+	b.planAction(ctx, &plannedAction{Issue: &issueRef{repoID.String(), num}, Action: action, Args: args})
+}
+
+// planCL records a planned action on a CL.
+func (b *gopherbot) planCL(ctx context.Context, cl clRef, action string, args ...string) {
+	b.planAction(ctx, &plannedAction{CL: &cl, Action: action, Args: args})
+}
+
+// applyPlan applies the actions in p that are still needed,
+// given the current state of the corpus. It doesn't stop if
+// it encounters an error, but reports errors at the end.
+//
+// The placeholders for issues created by the plan are replaced
+// by the numbers of the issues in the actions that follow.
+func (b *gopherbot) applyPlan(ctx context.Context, p *actionPlan) []error {
+	var errs []error
+	newIssues := make(map[string]int) // placeholder → issue number
+	for _, a := range p.Actions {
+		b.task = a.Task
+		ctx := withReason(ctx, a.Reason)
+		a, err := resolveNewIssues(a, newIssues)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%v: %v", a, err))
+			continue
+		}
+		done, err := b.applyAction(ctx, a, newIssues)
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("%v: %v", a, err))
+		case done != "":
+			log.Printf("skipping %v: %s", a, done)
+		case *dryRun:
+			log.Printf("[dry-run] would apply %v", a)
+		default:
+			log.Printf("applied %v", a)
+		}
+	}
+	return errs
+}
+
+// resolveNewIssues returns a copy of a in which the placeholders for
+// issues created earlier in the plan are replaced by the issues'
+// numbers, as recorded in newIssues. In dry-run mode, the placeholders
+// for issues that weren't created are left as is.
+func resolveNewIssues(a *plannedAction, newIssues map[string]int) (*plannedAction, error) {
+	if !slices.ContainsFunc(a.Args, newIssueRx.MatchString) {
+		return a, nil
+	}
+	var err error
+	resolved := *a
+	resolved.Args = make([]string, len(a.Args))
+	for i, arg := range a.Args {
+		resolved.Args[i] = newIssueRx.ReplaceAllStringFunc(arg, func(ref string) string {
+			num, ok := newIssues[ref]
+			if !ok {
+				if !*dryRun && err == nil {
+					err = fmt.Errorf("issue %s wasn't created", ref)
+				}
+				return ref
+			}
+			return strconv.Itoa(num)
+		})
+	}
+	return &resolved, err
+}
+
+// applyAction applies a, unless the corpus shows that it is no longer
+// needed, in which case applyAction returns a description of why not.
+// It records the numbers of the issues it creates, or finds already
+// exist, in newIssues.
+func (b *gopherbot) applyAction(ctx context.Context, a *plannedAction, newIssues map[string]int) (done string, err error) {
+	if a.Issue != nil {
+		return b.applyIssueAction(ctx, a, newIssues)
+	}
+	if a.CL != nil {
+		return b.applyCLAction(ctx, a)
+	}
+	return "", fmt.Errorf("no issue or CL")
+}
+
+func (b *gopherbot) applyIssueAction(ctx context.Context, a *plannedAction, newIssues map[string]int) (done string, err error) {
+	repoID, ok := parseRepoID(a.Issue.Repo)
+	if !ok {
+		return "", fmt.Errorf("bad repo %q", a.Issue.Repo)
+	}
+	repo := b.corpus.GitHub().Repo(repoID.Owner, repoID.Repo)
+	if repo == nil {
+		return "", fmt.Errorf("repo %s not in corpus", repoID)
+	}
+	if a.Action == "create-issue" {
+		if len(a.Args) < 2 {
+			return "", fmt.Errorf("create-issue needs a title and body")
+		}
+		if repoID.String() != "golang/go" {
+			return "", fmt.Errorf("can only create issues in golang/go")
+		}
+		title, body, labels := a.Args[0], a.Args[1], a.Args[2:]
+		if num := b.issueWithTitle(title); num != 0 {
+			newIssues[a.Ref] = int(num)
+			return fmt.Sprintf("issue already exists as #%d", num), nil
+		}
+		if *dryRun {
+			return "", nil
+		}
+		num, err := b.createGitHubIssue(ctx, title, body, labels, time.Time{})
+		if err != nil {
+			return "", err
+		}
+		newIssues[a.Ref] = num
+		return "", nil
+	}
+	gi := repo.Issue(a.Issue.Number)
+	if gi == nil || gi.NotExist || b.deletedIssues[githubIssue{repoID, gi.Number}] {
+		return "issue does not exist", nil
+	}
+	switch a.Action {
+	case "add-labels":
+		var labels []string
+		for _, l := range a.Args {
+			if !gi.HasLabel(l) {
+				labels = append(labels, l)
+			}
+		}
+		if len(labels) == 0 {
+			return "labels already present", nil
+		}
+		return "", b.addLabels(ctx, repoID, gi, labels)
+	case "remove-labels":
+		if !slices.ContainsFunc(a.Args, gi.HasLabel) {
+			return "labels already absent", nil
+		}
+		return "", b.removeLabels(ctx, repoID, gi, a.Args)
+	case "set-milestone":
+		if len(a.Args) != 2 {
+			return "", fmt.Errorf("set-milestone needs a number and name")
+		}
+		num, err := strconv.Atoi(a.Args[0])
+		if err != nil {
+			return "", fmt.Errorf("bad milestone number: %v", err)
+		}
+		if !gi.Milestone.IsNone() && !gi.Milestone.IsUnknown() && int(gi.Milestone.Number) == num {
+			return "milestone already set", nil
+		}
+		return "", b.setMilestone(ctx, repoID, gi, milestone{num, a.Args[1]})
+	case "comment":
+		if len(a.Args) != 1 {
+			return "", fmt.Errorf("comment needs a message")
+		}
+		// addGitHubComment skips duplicate comments itself.
+		return "", b.addGitHubComment(ctx, repo, gi.Number, a.Args[0])
+	case "close":
+		if gi.Closed {
+			return "issue already closed", nil
+		}
+		reason := completed
+		if len(a.Args) > 0 && a.Args[0] == *notPlanned {
+			reason = notPlanned
+		}
+		return "", b.closeGitHubIssue(ctx, repoID, gi.Number, reason)
+	case "lock":
+		if gi.Locked {
+			return "issue already locked", nil
+		}
+		return "", b.lockGitHubIssue(ctx, repoID, gi)
+	}
+	return "", fmt.Errorf("unknown issue action %q", a.Action)
+}
+
+func (b *gopherbot) applyCLAction(ctx context.Context, a *plannedAction) (done string, err error) {
+	changeID := a.CL.id()
+	gp, cl, err := b.findCL(a.CL)
+	if err != nil {
+		return "", err
+	}
+	if cl == nil || b.deletedChanges[gerritChange{gp.Project(), cl.Number}] {
+		return "CL does not exist", nil
+	}
+	// Comments, such as the congratulations on a first CL,
+	// may be for CLs that are no longer open.
+	if cl.Status != "new" && a.Action != "comment" {
+		return "CL is " + cl.Status, nil
+	}
+	tags := cl.Meta.Hashtags()
+	switch a.Action {
+	case "add-hashtags":
+		if !slices.ContainsFunc(a.Args, func(t string) bool { return !tags.Contains(t) }) {
+			return "hashtags already present", nil
+		}
+	case "remove-hashtags":
+		if !slices.ContainsFunc(a.Args, tags.Contains) {
+			return "hashtags already absent", nil
+		}
+	case "delete-topic":
+		if clTopic(cl) == "" {
+			return "topic already deleted", nil
+		}
+	case "comment":
+		for _, m := range cl.Messages {
+			if slices.ContainsFunc(a.Args, func(phrase string) bool { return strings.Contains(m.Message, phrase) }) {
+				return "comment already posted", nil
+			}
+		}
+	case "set-review":
+		if hasReviewers(cl, a.Args) {
+			return "reviewers already added", nil
+		}
+	case "abandon", "submit":
+		// The conditions for these were checked when the plan was
+		// made. A new patch set, vote or message since may have
+		// changed them, so don't act on a CL that changed at all.
+		if a.CL.Meta == "" {
+			return "", fmt.Errorf("plan doesn't record the state of the CL to %s", a.Action)
+		}
+		if meta := cl.Meta.Commit.Hash.String(); meta != a.CL.Meta {
+			return "", fmt.Errorf("CL changed since the plan was made (meta commit %s, planned at %s); not applying %s", meta, a.CL.Meta, a.Action)
+		}
+	}
+	if *dryRun {
+		return "", nil
+	}
+	switch a.Action {
+	case "add-hashtags":
+		_, err = b.gerrit.AddHashtags(ctx, changeID, a.Args...)
+	case "remove-hashtags":
+		_, err = b.gerrit.RemoveHashtags(ctx, changeID, a.Args...)
+	case "delete-topic":
+		err = b.gerrit.DeleteTopic(ctx, changeID)
+	case "comment":
+		if len(a.Args) == 0 {
+			return "", fmt.Errorf("comment needs a message")
+		}
+		// addGerritComment skips duplicate comments itself.
+		err = b.addGerritComment(ctx, changeID, a.Args[0], &gerritCommentOpts{OldPhrases: a.Args[1:]})
+	case "set-review":
+		var review gerrit.ReviewInput
+		for _, arg := range a.Args {
+			state, email, ok := strings.Cut(arg, ":")
+			if !ok || state != "REVIEWER" && state != "CC" {
+				return "", fmt.Errorf("bad reviewer %q; want REVIEWER:email or CC:email", arg)
+			}
+			r := gerrit.ReviewerInput{Reviewer: email}
+			if state == "CC" {
+				r.State = "CC"
+			}
+			review.Reviewers = append(review.Reviewers, r)
+		}
+		err = b.gerrit.SetReview(ctx, changeID, "current", review)
+	case "abandon":
+		err = b.gerrit.AbandonChange(ctx, changeID, a.Args...)
+	case "submit":
+		_, err = b.gerrit.SubmitChange(ctx, changeID)
+	default:
+		return "", fmt.Errorf("unknown CL action %q", a.Action)
+	}
+	return "", err
+}
+
+// findCL returns the CL that c refers to, and its project, looking it up
+// in the corpus by Change-Id or number if c doesn't name the project.
+// It returns a nil CL if there is none.
+func (b *gopherbot) findCL(c *clRef) (*maintner.GerritProject, *maintner.GerritCL, error) {
+	if c.Project != "" {
+		gp := b.corpus.Gerrit().Project("go.googlesource.com", c.Project)
+		if gp == nil {
+			return nil, nil, fmt.Errorf("project %s not in corpus", c.Project)
+		}
+		return gp, gp.CL(c.Number), nil
+	}
+	num := c.Number
+	if num == 0 {
+		// The Change-Id may be a CL number or "project~number".
+		id := c.ChangeID
+		if project, n, ok := strings.Cut(id, "~"); ok {
+			return b.findCL(&clRef{Project: project, Number: parseCLNumber(n)})
+		}
+		num = parseCLNumber(id)
+	}
+	var foundProject *maintner.GerritProject
+	var found *maintner.GerritCL
+	b.corpus.Gerrit().ForeachProjectUnsorted(func(gp *maintner.GerritProject) error {
+		if gp.Server() != "go.googlesource.com" {
+			return nil
+		}
+		return gp.ForeachCLUnsorted(func(cl *maintner.GerritCL) error {
+			if num != 0 && cl.Number == num || num == 0 && cl.ChangeID() == c.ChangeID {
+				foundProject, found = gp, cl
+				return errStopIteration
+			}
+			return nil
+		})
+	})
+	return foundProject, found, nil
+}
+
+// parseCLNumber returns the CL number s, or 0 if s isn't one.
+func parseCLNumber(s string) int32 {
+	n, err := strconv.ParseInt(s, 10, 32)
+	if err != nil {
+		return 0
+	}
+	return int32(n)
+}
+
+// clTopic returns the topic of cl, from the latest
+// of its meta commits that sets or deletes the topic.
+func clTopic(cl *maintner.GerritCL) string {
+	for _, m := range slices.Backward(cl.Metas) {
+		if !strings.Contains(m.Commit.Msg, "\nTopic:") {
+			continue
+		}
+		var topic string
+		foreach.LineStr(m.Commit.Msg, func(ln string) error {
+			if t, ok := strings.CutPrefix(ln, "Topic:"); ok {
+				topic = strings.TrimSpace(t)
+			}
+			return nil
+		})
+		return topic
+	}
+	return ""
+}
+
+// hasReviewers reports whether the meta commits of cl show that the
+// reviewers in args, in the form of the arguments of "set-review",
+// were all added to it, as reviewers or CCs.
+func hasReviewers(cl *maintner.GerritCL, args []string) bool {
+	var emails []string
+	for _, id := range reviewersInMetas(cl.Metas) {
+		if p := gophers.GetPerson(id + gerritInstanceID); p != nil {
+			emails = append(emails, p.Emails...)
+		}
+	}
+	for _, arg := range args {
+		_, email, _ := strings.Cut(arg, ":")
+		if !slices.Contains(emails, email) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,235 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/build/maintner"
+)
+
+func TestPlanDryRun(t *testing.T) {
+	defer func(old bool) { *dryRun = old }(*dryRun)
+	*dryRun = true
+
+	fis := &fakeIssuesService{}
+	b := &gopherbot{is: fis, plan: &actionPlan{}}
+	goRepo := maintner.GitHubRepoID{Owner: "golang", Repo: "go"}
+	vscode := maintner.GitHubRepoID{Owner: "golang", Repo: "vscode-go"}
+	gi := &maintner.GitHubIssue{
+		Number: 123,
+		Labels: map[int64]*maintner.GitHubLabel{1: {Name: "WaitingForInfo"}},
+	}
+	ctx := context.Background()
+
+	b.task = "label things"
+	if err := b.addLabels(withReason(ctx, "title matches"), goRepo, gi, []string{"Builders", "WaitingForInfo"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.removeLabels(ctx, vscode, gi, []string{"WaitingForInfo", "NeedsFix"}); err != nil {
+		t.Fatal(err)
+	}
+	b.task = "close things"
+	if err := b.closeGitHubIssue(ctx, goRepo, gi.Number, notPlanned); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.setMilestone(ctx, goRepo, gi, milestone{23, "Gccgo"}); err != nil {
+		t.Fatal(err)
+	}
+	b.planCL(ctx, clRef{Project: "tools", Number: 456}, "add-hashtags", "wait-release")
+	b.planCL(ctx, clRef{ChangeID: "I0123"}, "comment", "Hello")
+
+	if len(fis.labels) != 0 {
+		t.Errorf("dry run changed labels: %v", fis.labels)
+	}
+
+	var buf bytes.Buffer
+	if err := b.plan.writeText(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `label things: go.dev/issue/123 add-labels "Builders" # title matches
+label things: github.com/golang/vscode-go/issues/123 remove-labels "WaitingForInfo"
+close things: go.dev/issue/123 close "not_planned"
+close things: go.dev/issue/123 set-milestone "23" "Gccgo"
+close things: go.dev/cl/456 add-hashtags "wait-release"
+close things: go-review.googlesource.com/q/I0123 comment "Hello"
+`
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("plan text differs: (-want +got)\n%s", diff)
+	}
+
+	// The JSON form must round-trip, for gopherbot apply.
+	b.plan.Created = time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	file := filepath.Join(t.TempDir(), "plan.json")
+	if err := b.plan.write(file); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readPlan(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(b.plan, got); diff != "" {
+		t.Errorf("plan differs after round trip: (-want +got)\n%s", diff)
+	}
+}
+
+func TestCLRefID(t *testing.T) {
+	for _, tc := range []struct {
+		ref  clRef
+		want string
+	}{
+		{clRef{Project: "tools", Number: 456}, "tools~456"},
+		{clRef{Number: 456}, "456"},
+		{clRef{ChangeID: "I0123"}, "I0123"},
+	} {
+		if got := tc.ref.id(); got != tc.want {
+			t.Errorf("%+v.id() = %q, want %q", tc.ref, got, tc.want)
+		}
+	}
+}
+
+func TestApplyPlan(t *testing.T) {
+	b, gh, gc := newTestBot(t, "cherrypick.txtar")
+	ctx := context.Background()
+
+	*dryRun = true
+	b.plan = &actionPlan{}
+	if err := b.openCherryPickIssues(ctx); err != nil {
+		t.Fatal(err)
+	}
+	*dryRun = false
+	var buf bytes.Buffer
+	if err := b.plan.writeText(&buf); err != nil {
+		t.Fatal(err)
+	}
+	// The comment on issue 10 must refer to the backport issue
+	// by the placeholder of the create-issue action.
+	for _, s := range []string{
+		`github.com/golang/go create-issue {new-issue-1} "crypto/tls: handshake fails [1.24 backport]"`,
+		`go.dev/issue/10 comment "Backport issue(s) opened: #{new-issue-1} (for 1.24)`,
+	} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("plan doesn't contain %q:\n%s", s, buf.String())
+		}
+	}
+
+	gh.calls = nil
+	if errs := b.applyPlan(ctx, b.plan); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	want := []string{
+		`ListByRepo golang/go`,
+		`Create golang/go "crypto/tls: handshake fails [1.24 backport]" labels=["CherryPickCandidate" "Security"]`,
+		`ListComments golang/go#10`,
+		`CreateComment golang/go#10 "Backport issue(s) opened: #1000 (for 1.24).\n\nRemember to create the cherry-pick CL(s) as soon as the patch is submitted to master, according to https://go.dev/wiki/MinorReleases."`,
+		`ListLabelsByIssue golang/go#11`,
+		`RemoveLabelForIssue golang/go#11 "CherryPickCandidate"`,
+	}
+	if diff := cmp.Diff(want, gh.calls); diff != "" {
+		t.Errorf("GitHub calls mismatch (-want +got):\n%s", diff)
+	}
+
+	// Applying the plan again must not create the issue or comment twice.
+	gh.calls = nil
+	if errs := b.applyPlan(ctx, b.plan); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	for _, c := range gh.calls {
+		if strings.HasPrefix(c, "Create") {
+			t.Errorf("second apply made call %s", c)
+		}
+	}
+	if len(gc.calls) > 0 {
+		t.Errorf("unexpected Gerrit calls: %q", gc.calls)
+	}
+}
+
+func TestApplyPlanCLComment(t *testing.T) {
+	b, _, gc := newTestBot(t, "congrats.txtar")
+	ctx := context.Background()
+
+	*dryRun = true
+	b.plan = &actionPlan{}
+	if err := b.congratulateNewContributors(ctx); err != nil {
+		t.Fatal(err)
+	}
+	*dryRun = false
+	if len(b.plan.Actions) != 2 {
+		t.Fatalf("planned %d actions, want 2:\n%v", len(b.plan.Actions), b.plan.Actions)
+	}
+
+	gc.calls = nil
+	if errs := b.applyPlan(ctx, b.plan); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if n := countPrefix(gc.calls, "SetReview"); n != 2 {
+		t.Errorf("first apply made %d SetReview calls, want 2: %q", n, gc.calls)
+	}
+
+	// The comments are addressed by Change-Id only,
+	// and mustn't be posted twice either.
+	gc.calls = nil
+	if errs := b.applyPlan(ctx, b.plan); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if n := countPrefix(gc.calls, "SetReview"); n != 0 {
+		t.Errorf("second apply made %d SetReview calls, want 0: %q", n, gc.calls)
+	}
+}
+
+// countPrefix returns the number of calls that begin with prefix.
+func countPrefix(calls []string, prefix string) int {
+	n := 0
+	for _, c := range calls {
+		if strings.HasPrefix(c, prefix) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestApplyPlanStaleCL(t *testing.T) {
+	b, _, gc := newTestBot(t, "congrats.txtar")
+	ctx := context.Background()
+	meta := b.corpus.Gerrit().Project("go.googlesource.com", "go").CL(1001).Meta.Commit.Hash.String()
+
+	for _, tc := range []struct {
+		meta    string
+		wantErr bool
+	}{
+		{meta: "", wantErr: true},
+		{meta: "00000000000000000000000000000000000000ff", wantErr: true},
+		{meta: meta},
+	} {
+		gc.calls = nil
+		p := &actionPlan{Actions: []*plannedAction{
+			{Task: "abandon things", CL: &clRef{Project: "go", Number: 1001, Meta: tc.meta}, Action: "abandon"},
+			{Task: "submit things", CL: &clRef{Project: "go", Number: 1001, Meta: tc.meta}, Action: "submit"},
+		}}
+		errs := b.applyPlan(ctx, p)
+		if tc.wantErr {
+			if len(errs) != 2 {
+				t.Errorf("applying a plan with meta %q: got errors %v, want 2", tc.meta, errs)
+			}
+			if len(gc.calls) > 0 {
+				t.Errorf("applying a plan with meta %q made Gerrit calls %q, want none", tc.meta, gc.calls)
+			}
+			continue
+		}
+		if len(errs) > 0 {
+			t.Errorf("applying a plan with the current meta: %v", errs)
+		}
+		want := []string{`AbandonChange go~1001 []`, `SubmitChange go~1001`}
+		if diff := cmp.Diff(want, gc.calls); diff != "" {
+			t.Errorf("Gerrit calls mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
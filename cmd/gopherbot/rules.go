@@ -0,0 +1,516 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/build/maintner"
+	yaml "gopkg.in/yaml.v3"
+)
+
+// builtinRules holds the gardening rules that gopherbot applies
+// unless the -rules flag names a different file.
+//
+//go:embed rules.yaml
+var builtinRules []byte
+
+// A rule is a declarative gardening policy, such as "label open golang/go
+// issues whose title starts with x/build as Builders". Rules are read from
+// rules.yaml, so that new policies don't need new Go code.
+//
+// Each rule is run like a task, using its name; it applies its actions to
+// every open issue (or, with Kind "cl", every open CL) that it matches.
+type rule struct {
+	Name    string      `yaml:"name"`
+	Kind    string      `yaml:"kind"` // "issue" (the default) or "cl"
+	Match   ruleMatch   `yaml:"match"`
+	Actions ruleActions `yaml:"actions"`
+}
+
+// A ruleMatch selects the issues or CLs a rule applies to.
+// All of the non-empty conditions must hold.
+type ruleMatch struct {
+	// Repos lists the repos the rule applies to: GitHub repos like
+	// "golang/vscode-go" for issue rules (default "golang/go"), and
+	// go.googlesource.com projects like "tools" for CL rules (default all).
+	Repos []string `yaml:"repos"`
+
+	Title    string `yaml:"title"`     // regexp that must match the issue title or CL subject
+	NotTitle string `yaml:"not_title"` // regexp that must not match the title or subject
+
+	Labels    []string `yaml:"labels"`     // issue labels that must all be present
+	NotLabels []string `yaml:"not_labels"` // issue labels that must all be absent
+
+	Hashtags    []string `yaml:"hashtags"`     // CL hashtags that must all be present
+	NotHashtags []string `yaml:"not_hashtags"` // CL hashtags that must all be absent
+
+	// Authors lists the GitHub logins (for issues) or Gerrit
+	// owner emails (for CLs) of which one must be the author.
+	Authors []string `yaml:"authors"`
+
+	MinAge time.Duration `yaml:"min_age"` // minimum time since creation, like "720h"
+	MaxAge time.Duration `yaml:"max_age"` // maximum time since creation
+
+	title, notTitle *regexp.Regexp
+}
+
+// ruleActions are the changes a rule makes to the issues or CLs it matches.
+//
+// As in gopherbot's other tasks, a rule defers to people: it adds no labels
+// to an issue that has ever had a label removed, and sets no milestone on
+// an issue that has, or has ever had, a milestone.
+type ruleActions struct {
+	AddLabels    []string   `yaml:"add_labels"`    // issues only
+	RemoveLabels []string   `yaml:"remove_labels"` // issues only
+	Milestone    *milestone `yaml:"milestone"`     // issues only
+
+	AddHashtags    []string `yaml:"add_hashtags"`    // CLs only
+	RemoveHashtags []string `yaml:"remove_hashtags"` // CLs only
+
+	Comment string `yaml:"comment"` // posted once; for CLs, as a review message
+
+	// Close closes the issue or abandons the CL.
+	// For issues, CloseReason may be "completed" (the default)
+	// or "not_planned".
+	Close       bool   `yaml:"close"`
+	CloseReason string `yaml:"close_reason"`
+}
+
+// UnmarshalYAML decodes a milestone written as {number: 23, name: Gccgo}.
+func (m *milestone) UnmarshalYAML(n *yaml.Node) error {
+	var v struct {
+		Number int    `yaml:"number"`
+		Name   string `yaml:"name"`
+	}
+	if err := n.Decode(&v); err != nil {
+		return err
+	}
+	*m = milestone{v.Number, v.Name}
+	return nil
+}
+
+// parseRules parses and checks a YAML list of rules.
+func parseRules(data []byte) ([]*rule, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var rules []*rule
+	if err := dec.Decode(&rules); err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var errs []error
+	for i, r := range rules {
+		if r.Name == "" {
+			errs = append(errs, fmt.Errorf("rule %d: missing name", i+1))
+			continue
+		}
+		if seen[r.Name] {
+			errs = append(errs, fmt.Errorf("rule %q: duplicate name", r.Name))
+		}
+		seen[r.Name] = true
+		if err := r.init(); err != nil {
+			errs = append(errs, fmt.Errorf("rule %q: %v", r.Name, err))
+		}
+	}
+	return rules, errors.Join(errs...)
+}
+
+// loadRules returns the rules in the named file,
+// or the built-in rules if file is empty.
+func loadRules(file string) ([]*rule, error) {
+	data := builtinRules
+	if file != "" {
+		var err error
+		data, err = os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		file = "rules.yaml"
+	}
+	rules, err := parseRules(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	return rules, nil
+}
+
+// init checks r and compiles its regexps.
+func (r *rule) init() error {
+	m, a := &r.Match, &r.Actions
+	var err error
+	if m.Title != "" {
+		if m.title, err = regexp.Compile(m.Title); err != nil {
+			return err
+		}
+	}
+	if m.NotTitle != "" {
+		if m.notTitle, err = regexp.Compile(m.NotTitle); err != nil {
+			return err
+		}
+	}
+	if m.MaxAge != 0 && m.MaxAge < m.MinAge {
+		return fmt.Errorf("max_age %v is less than min_age %v", m.MaxAge, m.MinAge)
+	}
+	switch r.Kind {
+	case "", "issue":
+		r.Kind = "issue"
+		if len(m.Repos) == 0 {
+			m.Repos = []string{"golang/go"}
+		}
+		for _, repo := range m.Repos {
+			if _, ok := parseRepoID(repo); !ok {
+				return fmt.Errorf("bad GitHub repo %q; want owner/repo", repo)
+			}
+		}
+		if len(m.Hashtags) > 0 || len(m.NotHashtags) > 0 || len(a.AddHashtags) > 0 || len(a.RemoveHashtags) > 0 {
+			return fmt.Errorf("hashtags only apply to CLs")
+		}
+		switch a.CloseReason {
+		case "", "completed", "not_planned":
+		default:
+			return fmt.Errorf("unknown close_reason %q; want completed or not_planned", a.CloseReason)
+		}
+		if a.Milestone != nil && (a.Milestone.Number == 0 || a.Milestone.Name == "") {
+			return fmt.Errorf("milestone needs both number and name")
+		}
+	case "cl":
+		if len(m.Labels) > 0 || len(m.NotLabels) > 0 || len(a.AddLabels) > 0 || len(a.RemoveLabels) > 0 || a.Milestone != nil {
+			return fmt.Errorf("labels and milestones only apply to issues")
+		}
+		if a.CloseReason != "" {
+			return fmt.Errorf("close_reason only applies to issues")
+		}
+	default:
+		return fmt.Errorf("unknown kind %q; want issue or cl", r.Kind)
+	}
+	if len(a.AddLabels) == 0 && len(a.RemoveLabels) == 0 && a.Milestone == nil &&
+		len(a.AddHashtags) == 0 && len(a.RemoveHashtags) == 0 && a.Comment == "" && !a.Close {
+		return fmt.Errorf("no actions")
+	}
+	return nil
+}
+
+// parseRepoID parses a GitHub repo of the form "owner/repo".
+func parseRepoID(s string) (maintner.GitHubRepoID, bool) {
+	owner, repo, ok := strings.Cut(s, "/")
+	if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return maintner.GitHubRepoID{}, false
+	}
+	return maintner.GitHubRepoID{Owner: owner, Repo: repo}, true
+}
+
+// A ruleTarget is what a rule knows about an issue or CL.
+type ruleTarget struct {
+	Repo       string    // "owner/repo" for issues, Gerrit project for CLs
+	Number     int32     // issue or CL number
+	Title      string    // issue title or CL subject
+	Tags       []string  // issue labels or CL hashtags
+	Author     string    // GitHub login or Gerrit owner email
+	Created    time.Time // creation time
+	Unlabeled  bool      // the issue has had a label removed
+	Milestoned bool      // the issue has, or has had, a milestone
+}
+
+// issueTarget returns the ruleTarget for gi in the given repo.
+func issueTarget(repoID maintner.GitHubRepoID, gi *maintner.GitHubIssue) *ruleTarget {
+	t := &ruleTarget{
+		Repo:       repoID.String(),
+		Number:     gi.Number,
+		Title:      gi.Title,
+		Created:    gi.Created,
+		Unlabeled:  gi.HasEvent("unlabeled"),
+		Milestoned: !gi.Milestone.IsNone() || gi.HasEvent("milestoned") || gi.HasEvent("demilestoned"),
+	}
+	if gi.User != nil {
+		t.Author = gi.User.Login
+	}
+	for _, l := range gi.Labels {
+		t.Tags = append(t.Tags, l.Name)
+	}
+	slices.Sort(t.Tags)
+	return t
+}
+
+// clTarget returns the ruleTarget for cl.
+func clTarget(cl *maintner.GerritCL) *ruleTarget {
+	t := &ruleTarget{
+		Repo:    cl.Project.Project(),
+		Number:  cl.Number,
+		Title:   cl.Subject(),
+		Created: cl.Created,
+	}
+	if p := cl.Owner(); p != nil {
+		t.Author = p.Email()
+	}
+	cl.Meta.Hashtags().Foreach(func(tag string) {
+		t.Tags = append(t.Tags, tag)
+	})
+	slices.Sort(t.Tags)
+	return t
+}
+
+// matches reports whether r applies to t at time now.
+func (r *rule) matches(t *ruleTarget, now time.Time) bool {
+	m := &r.Match
+	if len(m.Repos) > 0 && !slices.Contains(m.Repos, t.Repo) {
+		return false
+	}
+	if m.title != nil && !m.title.MatchString(t.Title) ||
+		m.notTitle != nil && m.notTitle.MatchString(t.Title) {
+		return false
+	}
+	for _, tag := range append(m.Labels, m.Hashtags...) {
+		if !slices.Contains(t.Tags, tag) {
+			return false
+		}
+	}
+	for _, tag := range append(m.NotLabels, m.NotHashtags...) {
+		if slices.Contains(t.Tags, tag) {
+			return false
+		}
+	}
+	if len(m.Authors) > 0 && !slices.Contains(m.Authors, t.Author) {
+		return false
+	}
+	age := now.Sub(t.Created)
+	if m.MinAge != 0 && age < m.MinAge || m.MaxAge != 0 && age > m.MaxAge {
+		return false
+	}
+	return true
+}
+
+// String describes the conditions of m, such as
+// `title matches "^x/build", labels include ["Builders"]`.
+func (m *ruleMatch) String() string {
+	var conds []string
+	add := func(format string, v any) {
+		conds = append(conds, fmt.Sprintf(format, v))
+	}
+	if m.Title != "" {
+		add("title matches %q", m.Title)
+	}
+	if m.NotTitle != "" {
+		add("title does not match %q", m.NotTitle)
+	}
+	for _, c := range []struct {
+		what string
+		tags []string
+	}{
+		{"labels include", m.Labels},
+		{"labels exclude", m.NotLabels},
+		{"hashtags include", m.Hashtags},
+		{"hashtags exclude", m.NotHashtags},
+		{"author is one of", m.Authors},
+	} {
+		if len(c.tags) > 0 {
+			add(c.what+" %q", c.tags)
+		}
+	}
+	if m.MinAge != 0 {
+		add("older than %v", m.MinAge)
+	}
+	if m.MaxAge != 0 {
+		add("newer than %v", m.MaxAge)
+	}
+	if len(conds) == 0 {
+		return "always"
+	}
+	return strings.Join(conds, ", ")
+}
+
+// A ruleAction is a single change that a rule makes to an issue or CL.
+type ruleAction struct {
+	Op  string // "add-label", "remove-label", "milestone", "add-hashtag", "remove-hashtag", "comment", "close"
+	Arg string // label, milestone name, hashtag, comment text or close reason
+}
+
+func (a ruleAction) String() string {
+	if a.Arg == "" {
+		return a.Op
+	}
+	return fmt.Sprintf("%s %q", a.Op, a.Arg)
+}
+
+// plan returns the actions r takes on t at time now: none if r doesn't
+// match t, and otherwise those of r's actions that would change t.
+func (r *rule) plan(t *ruleTarget, now time.Time) []ruleAction {
+	if !r.matches(t, now) {
+		return nil
+	}
+	a := &r.Actions
+	var plan []ruleAction
+	// add plans op for each of tags that t has (if has) or lacks (if !has).
+	add := func(op string, tags []string, has bool) {
+		for _, tag := range tags {
+			if slices.Contains(t.Tags, tag) == has {
+				plan = append(plan, ruleAction{op, tag})
+			}
+		}
+	}
+	if !t.Unlabeled {
+		add("add-label", a.AddLabels, false)
+	}
+	add("remove-label", a.RemoveLabels, true)
+	if a.Milestone != nil && !t.Milestoned {
+		plan = append(plan, ruleAction{"milestone", a.Milestone.Name})
+	}
+	add("add-hashtag", a.AddHashtags, false)
+	add("remove-hashtag", a.RemoveHashtags, true)
+	if a.Comment != "" {
+		plan = append(plan, ruleAction{"comment", a.Comment})
+	}
+	if a.Close {
+		plan = append(plan, ruleAction{"close", a.CloseReason})
+	}
+	return plan
+}
+
+// applyRule applies r to the open issues or CLs in the corpus.
+func (b *gopherbot) applyRule(ctx context.Context, r *rule) error {
+	now := time.Now()
+	ctx = withReason(ctx, r.Match.String())
+	if r.Kind == "cl" {
+		return b.corpus.Gerrit().ForeachProjectUnsorted(func(gp *maintner.GerritProject) error {
+			if gp.Server() != "go.googlesource.com" ||
+				len(r.Match.Repos) > 0 && !slices.Contains(r.Match.Repos, gp.Project()) {
+				return nil
+			}
+			return gp.ForeachOpenCL(func(cl *maintner.GerritCL) error {
+				if b.deletedChanges[gerritChange{gp.Project(), cl.Number}] || cl.Private {
+					return nil
+				}
+				return b.applyCLActions(ctx, r, cl, r.plan(clTarget(cl), now))
+			})
+		})
+	}
+	for _, name := range r.Match.Repos {
+		id, _ := parseRepoID(name)
+		repo := b.corpus.GitHub().Repo(id.Owner, id.Repo)
+		if repo == nil {
+			log.Printf("rule %q: repo %s not in corpus", r.Name, name)
+			continue
+		}
+		err := b.foreachIssue(repo, open, func(gi *maintner.GitHubIssue) error {
+			return b.applyIssueActions(ctx, r, repo, gi, r.plan(issueTarget(id, gi), now))
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyIssueActions applies the actions planned by r to gi.
+func (b *gopherbot) applyIssueActions(ctx context.Context, r *rule, repo *maintner.GitHubRepo, gi *maintner.GitHubIssue, plan []ruleAction) error {
+	var addLabels, removeLabels []string
+	for _, a := range plan {
+		switch a.Op {
+		case "add-label":
+			addLabels = append(addLabels, a.Arg)
+		case "remove-label":
+			removeLabels = append(removeLabels, a.Arg)
+		}
+	}
+	if len(addLabels) > 0 {
+		if err := b.addLabels(ctx, repo.ID(), gi, addLabels); err != nil {
+			return err
+		}
+	}
+	if len(removeLabels) > 0 {
+		if err := b.removeLabels(ctx, repo.ID(), gi, removeLabels); err != nil {
+			return err
+		}
+	}
+	for _, a := range plan {
+		var err error
+		switch a.Op {
+		case "milestone":
+			err = b.setMilestone(ctx, repo.ID(), gi, *r.Actions.Milestone)
+		case "comment":
+			err = b.addGitHubComment(ctx, repo, gi.Number, a.Arg)
+		case "close":
+			reason := completed
+			if a.Arg == "not_planned" {
+				reason = notPlanned
+			}
+			printIssue(r.Name, repo.ID(), gi)
+			err = b.closeGitHubIssue(ctx, repo.ID(), gi.Number, reason)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyCLActions applies the actions planned by r to cl.
+func (b *gopherbot) applyCLActions(ctx context.Context, r *rule, cl *maintner.GerritCL, plan []ruleAction) error {
+	changeID := fmt.Sprint(cl.Number)
+	var addTags, removeTags []string
+	for _, a := range plan {
+		switch a.Op {
+		case "add-hashtag":
+			addTags = append(addTags, a.Arg)
+		case "remove-hashtag":
+			removeTags = append(removeTags, a.Arg)
+		}
+	}
+	ref := clRef{Project: cl.Project.Project(), Number: cl.Number}
+	if len(addTags) > 0 || len(removeTags) > 0 {
+		if *dryRun {
+			log.Printf("[dry-run] %s: would add hashtags %q and remove hashtags %q on https://go.dev/cl/%d", r.Name, addTags, removeTags, cl.Number)
+			if len(addTags) > 0 {
+				b.planCL(ctx, ref, "add-hashtags", addTags...)
+			}
+			if len(removeTags) > 0 {
+				b.planCL(ctx, ref, "remove-hashtags", removeTags...)
+			}
+		} else {
+			if len(addTags) > 0 {
+				if _, err := b.gerrit.AddHashtags(ctx, changeID, addTags...); err != nil {
+					return err
+				}
+			}
+			if len(removeTags) > 0 {
+				if _, err := b.gerrit.RemoveHashtags(ctx, changeID, removeTags...); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for _, a := range plan {
+		switch a.Op {
+		case "comment":
+			if err := b.addGerritComment(ctx, changeID, a.Arg, nil); err != nil {
+				return err
+			}
+		case "close":
+			if *dryRun {
+				log.Printf("[dry-run] %s: would abandon https://go.dev/cl/%d", r.Name, cl.Number)
+				abandon := ref
+				abandon.Meta = cl.Meta.Commit.Hash.String()
+				b.planCL(ctx, abandon, "abandon")
+				continue
+			}
+			log.Printf("%s: abandoning https://go.dev/cl/%d", r.Name, cl.Number)
+			if err := b.gerrit.AbandonChange(ctx, changeID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
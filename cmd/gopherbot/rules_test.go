@@ -0,0 +1,193 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// planAll returns the actions each of rules takes on t, keyed by rule name.
+func planAll(rules []*rule, t *ruleTarget, now time.Time) map[string][]ruleAction {
+	plans := make(map[string][]ruleAction)
+	for _, r := range rules {
+		if p := r.plan(t, now); len(p) > 0 {
+			plans[r.Name] = p
+		}
+	}
+	return plans
+}
+
+func TestBuiltinRules(t *testing.T) {
+	rules, err := parseRules(builtinRules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range rules {
+		for _, task := range tasks {
+			if r.Name == task.name {
+				t.Errorf("rule %q has the same name as a task", r.Name)
+			}
+		}
+	}
+
+	now := time.Now()
+	testCases := []struct {
+		target *ruleTarget
+		want   map[string][]ruleAction
+	}{
+		{
+			&ruleTarget{Repo: "golang/go", Title: "x/build/cmd/gomote: crash"},
+			map[string][]ruleAction{"label build issues": {{"add-label", "Builders"}}},
+		},
+		{
+			&ruleTarget{Repo: "golang/go", Title: "x/build: flaky builder", Tags: []string{"Builders"}},
+			map[string][]ruleAction{},
+		},
+		{
+			&ruleTarget{Repo: "golang/go", Title: "x/tools/gopls: slow", Unlabeled: true},
+			map[string][]ruleAction{},
+		},
+		{
+			&ruleTarget{Repo: "golang/vscode-go", Title: "x/tools: not golang/go"},
+			map[string][]ruleAction{},
+		},
+		{
+			&ruleTarget{Repo: "golang/go", Title: "cmd/go: proxy.golang.org returns 410"},
+			map[string][]ruleAction{"label proxy.golang.org issues": {{"add-label", "proxy.golang.org"}}},
+		},
+		{
+			&ruleTarget{Repo: "golang/go", Title: "x/vulndb: report for GHSA-xxxx"},
+			map[string][]ruleAction{
+				"label vulncheck or vulndb issues": {{"add-label", "vulncheck or vulndb"}},
+				"set vuln milestone":               {{"milestone", "vuln/unplanned"}},
+			},
+		},
+		{
+			&ruleTarget{Repo: "golang/go", Title: "x/vuln: crash", Milestoned: true},
+			map[string][]ruleAction{"label vulncheck or vulndb issues": {{"add-label", "vulncheck or vulndb"}}},
+		},
+		{
+			&ruleTarget{Repo: "golang/go", Title: "x/vgo: gccgo support"},
+			map[string][]ruleAction{"set gccgo milestone": {{"milestone", "Gccgo"}}},
+		},
+		{
+			&ruleTarget{Repo: "golang/go", Title: "x/vgo: vendoring"},
+			map[string][]ruleAction{"set vgo milestone": {{"milestone", "vgo"}}},
+		},
+	}
+	for _, tc := range testCases {
+		got := planAll(rules, tc.target, now)
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("%s %q: plans differ: (-want +got)\n%s", tc.target.Repo, tc.target.Title, diff)
+		}
+	}
+}
+
+func TestRulePlan(t *testing.T) {
+	rules, err := parseRules([]byte(`
+- name: stale needs info
+  match:
+    repos: [golang/go, golang/vscode-go]
+    labels: [WaitingForInfo]
+    not_labels: [NeedsFix]
+    authors: [gopher]
+    min_age: 720h
+  actions:
+    remove_labels: [WaitingForInfo]
+    comment: Closing for lack of information.
+    close: true
+    close_reason: not_planned
+- name: wait-release on old CLs
+  kind: cl
+  match:
+    repos: [tools]
+    title: '^gopls'
+    not_hashtags: [wait-release]
+    max_age: 24h
+  actions:
+    add_hashtags: [wait-release, triaged]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	old := now.Add(-31 * 24 * time.Hour)
+	testCases := []struct {
+		desc   string
+		target *ruleTarget
+		want   map[string][]ruleAction
+	}{
+		{
+			"stale issue",
+			&ruleTarget{Repo: "golang/vscode-go", Tags: []string{"WaitingForInfo"}, Author: "gopher", Created: old},
+			map[string][]ruleAction{"stale needs info": {
+				{"remove-label", "WaitingForInfo"},
+				{"comment", "Closing for lack of information."},
+				{"close", "not_planned"},
+			}},
+		},
+		{
+			"issue too new",
+			&ruleTarget{Repo: "golang/go", Tags: []string{"WaitingForInfo"}, Author: "gopher", Created: now.Add(-time.Hour)},
+			map[string][]ruleAction{},
+		},
+		{
+			"excluded label",
+			&ruleTarget{Repo: "golang/go", Tags: []string{"NeedsFix", "WaitingForInfo"}, Author: "gopher", Created: old},
+			map[string][]ruleAction{},
+		},
+		{
+			"other author",
+			&ruleTarget{Repo: "golang/go", Tags: []string{"WaitingForInfo"}, Author: "other", Created: old},
+			map[string][]ruleAction{},
+		},
+		{
+			"new CL",
+			&ruleTarget{Repo: "tools", Title: "gopls: fix hover", Tags: []string{"triaged"}, Created: now.Add(-time.Hour)},
+			map[string][]ruleAction{"wait-release on old CLs": {{"add-hashtag", "wait-release"}}},
+		},
+		{
+			"CL in other project",
+			&ruleTarget{Repo: "go", Title: "gopls: fix hover", Created: now.Add(-time.Hour)},
+			map[string][]ruleAction{},
+		},
+	}
+	for _, tc := range testCases {
+		got := planAll(rules, tc.target, now)
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("%s: plans differ: (-want +got)\n%s", tc.desc, diff)
+		}
+	}
+}
+
+func TestParseRulesErrors(t *testing.T) {
+	testCases := []struct {
+		yaml string
+		want string
+	}{
+		{"- name: x\n  actions: {comment: hi}\n  bogus: 1\n", "field bogus not found"},
+		{"- actions: {comment: hi}\n", "rule 1: missing name"},
+		{"- name: x\n  actions: {comment: hi}\n- name: x\n  actions: {comment: hi}\n", `rule "x": duplicate name`},
+		{"- name: x\n  match: {title: '('}\n  actions: {comment: hi}\n", "missing closing )"},
+		{"- name: x\n", "no actions"},
+		{"- name: x\n  kind: pr\n  actions: {comment: hi}\n", "unknown kind"},
+		{"- name: x\n  actions: {add_hashtags: [a]}\n", "hashtags only apply to CLs"},
+		{"- name: x\n  kind: cl\n  actions: {add_labels: [a]}\n", "labels and milestones only apply to issues"},
+		{"- name: x\n  match: {repos: [go]}\n  actions: {comment: hi}\n", "want owner/repo"},
+		{"- name: x\n  actions: {milestone: {name: Go1.99}}\n", "milestone needs both number and name"},
+		{"- name: x\n  actions: {close: true, close_reason: wontfix}\n", "unknown close_reason"},
+		{"- name: x\n  match: {min_age: 48h, max_age: 24h}\n  actions: {comment: hi}\n", "max_age 24h0m0s is less than min_age 48h0m0s"},
+	}
+	for _, tc := range testCases {
+		_, err := parseRules([]byte(tc.yaml))
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("parseRules(%q) = %v, want error containing %q", tc.yaml, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUpdateNeeds(t *testing.T) {
+	b, gh, gc := newTestBot(t, "updateneeds.txtar")
+	if err := b.updateNeeds(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`ListLabelsByIssue golang/go#1`,
+		`RemoveLabelForIssue golang/go#1 "NeedsInvestigation"`,
+	}
+	if diff := cmp.Diff(want, gh.calls); diff != "" {
+		t.Errorf("GitHub calls mismatch (-want +got):\n%s", diff)
+	}
+	if len(gc.calls) > 0 {
+		t.Errorf("unexpected Gerrit calls: %q", gc.calls)
+	}
+}
+
+func TestOpenCherryPickIssues(t *testing.T) {
+	b, gh, gc := newTestBot(t, "cherrypick.txtar")
+	if err := b.openCherryPickIssues(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`ListByRepo golang/go`,
+		`Create golang/go "crypto/tls: handshake fails [1.24 backport]" labels=["CherryPickCandidate" "Security"]`,
+		`ListComments golang/go#10`,
+		`CreateComment golang/go#10 "Backport issue(s) opened: #1000 (for 1.24).\n\nRemember to create the cherry-pick CL(s) as soon as the patch is submitted to master, according to https://go.dev/wiki/MinorReleases."`,
+		`ListLabelsByIssue golang/go#11`,
+		`RemoveLabelForIssue golang/go#11 "CherryPickCandidate"`,
+	}
+	if diff := cmp.Diff(want, gh.calls); diff != "" {
+		t.Errorf("GitHub calls mismatch (-want +got):\n%s", diff)
+	}
+	if len(gc.calls) > 0 {
+		t.Errorf("unexpected Gerrit calls: %q", gc.calls)
+	}
+}
+
+func TestCongratulateNewContributors(t *testing.T) {
+	b, gh, gc := newTestBot(t, "congrats.txtar")
+	if err := b.congratulateNewContributors(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	const (
+		cl1001 = "I00000000000000000000000000000000000003e9"
+		cl2001 = "I00000000000000000000000000000000000007d1"
+	)
+	want := []string{
+		`GetChange ` + cl1001,
+		fmt.Sprintf(`SetReview %s 00000000000000000000000000000000000007d2 message=%q`, cl1001, freezeCongratsMsg),
+		`GetChange ` + cl2001,
+		fmt.Sprintf(`SetReview %s 0000000000000000000000000000000000000fa2 message=%q`, cl2001, defaultCongratsMsg),
+	}
+	// Contributors are congratulated in no particular order,
+	// but each CL's GetChange must come right before its SetReview.
+	got := slices.Clone(gc.calls)
+	if len(got) == 4 && got[0] != want[0] {
+		got[0], got[1], got[2], got[3] = got[2], got[3], got[0], got[1]
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Gerrit calls mismatch (-want +got):\n%s", diff)
+	}
+	if len(gh.calls) > 0 {
+		t.Errorf("unexpected GitHub calls: %q", gh.calls)
+	}
+	for _, email := range []string{"new@example.com", "repeat@example.com", "merged@example.com", "tools@example.com"} {
+		if !b.knownContributors[email] {
+			t.Errorf("%s is not a known contributor after congratulating", email)
+		}
+	}
+
+	// Running the task again shouldn't congratulate anyone twice.
+	gc.calls = nil
+	if err := b.congratulateNewContributors(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(gc.calls) > 0 {
+		t.Errorf("second run made Gerrit calls: %q", gc.calls)
+	}
+}
@@ -69,13 +69,17 @@ func (r *Report) BootstrapDir(version string) (dir string, err error) {
 		b.Err = err
 	}()
 
+	// Both of these may change b.Dir, so read it after they return.
 	if r.Full {
-		return b.Dir, r.BootstrapBuild(b, version)
+		err = r.BootstrapBuild(b, version)
+	} else {
+		err = r.BootstrapPrebuilt(b, version)
 	}
-	return b.Dir, r.BootstrapPrebuilt(b, version)
+	return b.Dir, err
 }
 
 // BootstrapPrebuilt downloads a prebuilt toolchain.
+// If the toolchain is in the cache, BootstrapPrebuilt uses it instead.
 func (r *Report) BootstrapPrebuilt(b *Bootstrap, version string) error {
 	for _, dl := range r.dl {
 		if strings.HasPrefix(dl.Version, version+".") {
@@ -85,35 +89,74 @@ func (r *Report) BootstrapPrebuilt(b *Bootstrap, version string) error {
 		}
 	}
 
-	url := "https://go.dev/dl/" + version + "." + runtime.GOOS + "-" + runtime.GOARCH + ".tar.gz"
+	name := version + "." + runtime.GOOS + "-" + runtime.GOARCH + ".tar.gz"
 	unpack := UnpackTarGz
 	if runtime.GOOS == "windows" {
-		url = strings.TrimSuffix(url, ".tar.gz") + ".zip"
+		name = strings.TrimSuffix(name, ".tar.gz") + ".zip"
 		unpack = UnpackZip
 	}
+	var sum string
+	for _, dl := range r.dl {
+		for _, f := range dl.Files {
+			if f.Name == name {
+				sum = f.SHA256
+			}
+		}
+	}
 
-	arch, err := Get(&b.Log, url)
+	dir, cached := b.Dir, false
+	if sum != "" {
+		var err error
+		dir, cached, err = r.cache.ToolchainDir(sum, b.Dir)
+		if err != nil {
+			return err
+		}
+	}
+	b.Dir = filepath.Join(dir, "go")
+	if cached {
+		b.Log.Printf("using cached %s", name)
+		return nil
+	}
+	arch, err := r.cache.Get(&b.Log, "https://go.dev/dl/"+name, sum)
 	if err != nil {
 		return err
 	}
-	if err := unpack(b.Dir, arch); err != nil {
+	if sum != "" && SHA256(arch) != sum {
+		return fmt.Errorf("go.dev/dl-listed SHA256 %s does not match download SHA256 %s", sum, SHA256(arch))
+	}
+	if err := unpack(dir, arch); err != nil {
 		return err
 	}
-	b.Dir = filepath.Join(b.Dir, "go")
+	if sum != "" {
+		return r.cache.MarkComplete(dir)
+	}
 	return nil
 }
 
-// BootstrapBuild builds the named bootstrap toolchain and returns
-// the directory containing the GOROOT for the build.
+// BootstrapBuild builds the named bootstrap toolchain in b.Dir.
+// If a toolchain built from the same source is in the cache,
+// BootstrapBuild sets b.Dir to it instead.
 func (r *Report) BootstrapBuild(b *Bootstrap, version string) error {
 	tgz, err := GerritTarGz(&b.Log, "go", "refs/heads/release-branch."+version)
 	if err != nil {
 		return err
 	}
+	dir, cached, err := r.cache.ToolchainDir(SHA256(tgz)+"-"+runtime.GOOS+"-"+runtime.GOARCH, b.Dir)
+	if err != nil {
+		return err
+	}
+	b.Dir = dir
+	if cached {
+		b.Log.Printf("using cached build of %s", version)
+		return nil
+	}
 	if err := UnpackTarGz(b.Dir, tgz); err != nil {
 		return err
 	}
-	return r.Build(&b.Log, b.Dir, version, nil, nil)
+	if err := r.Build(&b.Log, b.Dir, version, nil, nil); err != nil {
+		return err
+	}
+	return r.cache.MarkComplete(b.Dir)
 }
 
 // Build runs a Go make.bash/make.bat/make.rc in the named goroot
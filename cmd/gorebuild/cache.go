@@ -0,0 +1,105 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// A Cache is a local, content-addressed cache of downloads and bootstrap
+// toolchains, shared between runs of gorebuild.
+// Downloads are keyed by the SHA256 listed for them on go.dev/dl,
+// so a cached file is only used if it is exactly the file that would
+// be downloaded. A nil *Cache caches nothing.
+type Cache struct {
+	Dir string
+}
+
+// Get is like the top-level Get, but if sum is non-empty, it returns the
+// cached copy of the content with that SHA256, if any, and otherwise
+// caches the downloaded content after checking that it has that SHA256.
+func (c *Cache) Get(log *Log, url, sum string) ([]byte, error) {
+	if c == nil || sum == "" {
+		return Get(log, url)
+	}
+	file := filepath.Join(c.Dir, "sha256", sum)
+	if data, err := os.ReadFile(file); err == nil {
+		if SHA256(data) == sum {
+			log.Printf("using cached %s", url)
+			return data, nil
+		}
+		log.Printf("ignoring corrupt cache entry %s", file)
+	}
+	data, err := Get(log, url)
+	if err != nil {
+		return nil, err
+	}
+	if got := SHA256(data); got != sum {
+		// Let the caller decide what to do with the mismatch,
+		// but don't cache the content under the wrong key.
+		return data, nil
+	}
+	if err := c.write(file, data); err != nil {
+		log.Printf("caching %s: %v", url, err)
+	}
+	return data, nil
+}
+
+// write atomically writes data to the named file.
+func (c *Cache) write(file string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0777); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(file), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if err1 := f.Close(); err == nil {
+		err = err1
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), file)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+// completeMarker is the name of the file written to a cached toolchain
+// directory once it is complete.
+const completeMarker = ".gorebuild-complete"
+
+// ToolchainDir returns the directory in which to keep the bootstrap
+// toolchain with the given key, typically the SHA256 of the archive it was
+// unpacked or built from, and whether the directory holds a complete
+// toolchain from an earlier run. If the toolchain is incomplete, the
+// directory is emptied, ready to be filled and marked with MarkComplete.
+// With a nil Cache, ToolchainDir returns fallback and false.
+func (c *Cache) ToolchainDir(key, fallback string) (string, bool, error) {
+	if c == nil {
+		return fallback, false, nil
+	}
+	dir := filepath.Join(c.Dir, "toolchain", key)
+	if _, err := os.Stat(filepath.Join(dir, completeMarker)); err == nil {
+		return dir, true, nil
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return "", false, fmt.Errorf("clearing incomplete toolchain: %v", err)
+	}
+	return dir, false, nil
+}
+
+// MarkComplete records that the toolchain directory dir returned by
+// ToolchainDir is complete.
+func (c *Cache) MarkComplete(dir string) error {
+	if c == nil {
+		return nil
+	}
+	return os.WriteFile(filepath.Join(dir, completeMarker), nil, 0666)
+}
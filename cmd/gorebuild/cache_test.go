@@ -0,0 +1,132 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestCacheGet(t *testing.T) {
+	content := []byte("hello, gopher")
+	sum := SHA256(content)
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write(content)
+	}))
+	defer srv.Close()
+
+	c := &Cache{Dir: t.TempDir()}
+	var log Log
+	for range 2 {
+		data, err := c.Get(&log, srv.URL+"/file", sum)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != string(content) {
+			t.Fatalf("Get = %q, want %q", data, content)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("server got %d requests, want 1", requests)
+	}
+
+	// Content that doesn't match the expected SHA256 is returned
+	// for the caller to report, but not cached.
+	if _, err := c.Get(&log, srv.URL+"/file", SHA256([]byte("other"))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Get(&log, srv.URL+"/file", SHA256([]byte("other"))); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 3 {
+		t.Errorf("server got %d requests, want 3", requests)
+	}
+
+	// A nil cache always downloads.
+	var nc *Cache
+	if _, err := nc.Get(&log, srv.URL+"/file", sum); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 4 {
+		t.Errorf("server got %d requests, want 4", requests)
+	}
+}
+
+func TestCacheToolchainDir(t *testing.T) {
+	c := &Cache{Dir: t.TempDir()}
+	dir, ok, err := c.ToolchainDir("abc", "")
+	if err != nil || ok {
+		t.Fatalf("ToolchainDir = %q, %v, %v; want incomplete", dir, ok, err)
+	}
+	if err := c.MarkComplete(dir); err == nil {
+		t.Fatalf("MarkComplete succeeded before the directory was created")
+	}
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.MarkComplete(dir); err != nil {
+		t.Fatal(err)
+	}
+	if dir2, ok, err := c.ToolchainDir("abc", ""); err != nil || !ok || dir2 != dir {
+		t.Errorf("ToolchainDir after MarkComplete = %q, %v, %v; want %q, true, nil", dir2, ok, err, dir)
+	}
+
+	var nc *Cache
+	if dir, ok, err := nc.ToolchainDir("abc", "fallback"); dir != "fallback" || ok || err != nil {
+		t.Errorf("nil ToolchainDir = %q, %v, %v; want fallback, false, nil", dir, ok, err)
+	}
+}
+
+func TestReusePrevious(t *testing.T) {
+	prev := &Report{}
+	prel := &Release{Version: "go1.99"}
+	prev.Releases = []*Release{prel}
+	for _, name := range []string{"go1.99.linux-amd64.tar.gz", "go1.99.darwin-arm64.tar.gz", "go1.99.windows-amd64.zip"} {
+		f := &File{Name: name, SHA256: "old"}
+		f.Log.Status = PASS
+		prel.Files = append(prel.Files, f)
+	}
+	prel.Files[2].Log.Status = FAIL
+
+	// Round-trip through JSON, as when read from gorebuild.json.
+	js, err := json.Marshal(prev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev = new(Report)
+	if err := json.Unmarshal(js, prev); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &Report{prev: prev}
+	rel := &Release{Version: "go1.99"}
+	r.Releases = []*Release{rel}
+	linux := r.File(rel, "go1.99.linux-amd64.tar.gz", "linux", "amd64")
+	linux.dl = &DLFile{SHA256: "old"}
+	darwin := r.File(rel, "go1.99.darwin-arm64.tar.gz", "darwin", "arm64")
+	darwin.dl = &DLFile{SHA256: "new"}
+	windows := r.File(rel, "go1.99.windows-amd64.zip", "windows", "amd64")
+	windows.dl = &DLFile{SHA256: "old"}
+	r.reusePrevious()
+
+	if !linux.reused || linux.Log.Status != PASS || linux.SHA256 != "old" {
+		t.Errorf("linux file: reused=%v status=%q sha256=%q; want reused PASS with old SHA256", linux.reused, linux.Log.Status, linux.SHA256)
+	}
+	if darwin.reused {
+		t.Errorf("darwin file reused, but its posted SHA256 changed")
+	}
+	if windows.reused {
+		t.Errorf("windows file reused, but it failed before")
+	}
+	if !r.allReused(rel, "linux", "amd64") || r.allReused(rel, "darwin", "arm64") {
+		t.Errorf("allReused(linux) = %v, allReused(darwin) = %v; want true, false",
+			r.allReused(rel, "linux", "amd64"), r.allReused(rel, "darwin", "arm64"))
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+
+	"golang.org/x/build/internal/installer/darwinpkg"
+	"golang.org/x/build/internal/installer/installertest"
+	"golang.org/x/build/internal/installer/windowsmsi"
+)
+
+// TestPureGoInstallers checks that the installers written without
+// the platform packaging tools can be verified against the archives.
+func TestPureGoInstallers(t *testing.T) {
+	tgz := installertest.Tgz(t, "go1.27.0", map[string]string{
+		"LICENSE":            "Copyright (c) 2009 The Go Authors.\n",
+		"bin/go":             "not really go",
+		"src/cmd/go/main.go": "package main\n",
+	})
+
+	t.Run("darwin", func(t *testing.T) {
+		var pkg bytes.Buffer
+		opt := darwinpkg.InstallerOptions{GOARCH: "arm64", MinMacOSVersion: "12"}
+		if err := darwinpkg.WriteInstaller(&pkg, bytes.NewReader(tgz), opt); err != nil {
+			t.Fatal(err)
+		}
+		var log Log
+		ok := DiffDarwinPkg(&log, tgz, pkg.Bytes())
+		for _, m := range log.Messages {
+			t.Log(m.Text)
+		}
+		if !ok {
+			t.Errorf("DiffDarwinPkg reported a mismatch")
+		}
+	})
+
+	t.Run("windows", func(t *testing.T) {
+		var msi bytes.Buffer
+		if err := windowsmsi.WriteInstaller(&msi, bytes.NewReader(tgz), windowsmsi.InstallerOptions{GOARCH: "amd64"}); err != nil {
+			t.Fatal(err)
+		}
+		var log Log
+		ok, skip := DiffWindowsMsi(&log, tgzToZip(t, tgz), msi.Bytes())
+		if skip {
+			// DiffWindowsMsi unpacks the MSI with msiextract, so without it
+			// this test doesn't check the MSI at all. The windowsmsi tests
+			// still check its contents against the archive with their own
+			// MSI reader.
+			t.Skip("msiextract not found; not checking the MSI with DiffWindowsMsi")
+		}
+		for _, m := range log.Messages {
+			t.Log(m.Text)
+		}
+		if !ok {
+			t.Errorf("DiffWindowsMsi reported a mismatch")
+		}
+	})
+}
+
+// tgzToZip returns a zip archive with the regular files in tgz.
+func tgzToZip(t *testing.T, tgz []byte) []byte {
+	t.Helper()
+	zr, err := gzip.NewReader(bytes.NewReader(tgz))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(zr)
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		w, err := zw.Create(hdr.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.Copy(w, tr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
@@ -39,6 +39,8 @@ func SHA256(data []byte) string {
 // it tries fetching multiple times with delays before giving up.
 // If it encounters a 429 Too Many Requests status code, it
 // returns early with an error satisfying [errors.As](err, [tooManyRequestsError]).
+//
+// A file:// URL names a local file, which Get reads directly.
 func Get(log *Log, url string) (_ []byte, err error) {
 	defer func() {
 		if err != nil && log != nil {
@@ -46,6 +48,17 @@ func Get(log *Log, url string) (_ []byte, err error) {
 		}
 	}()
 
+	if file, ok := strings.CutPrefix(url, "file://"); ok {
+		data, err := os.ReadFile(filepath.FromSlash(file))
+		if err != nil {
+			return nil, fmt.Errorf("get %s: %v", url, err)
+		}
+		if log != nil {
+			log.Printf("read %s", url)
+		}
+		return data, nil
+	}
+
 	// Fetching happens over an unreliable network connection,
 	// and will fail sometimes. Be willing to try a few times.
 	const maxTries = 5
@@ -28,6 +28,18 @@
 //     denotes the files for a specific system at a specific Go version.
 //
 // The -p flag specifies how many toolchain rebuilds to run in parallel (default 2).
+// Each rebuild covers one Go version for one system.
+//
+// The -cache flag names a directory in which gorebuild keeps the files it
+// downloads from https://go.dev/dl/ and the bootstrap toolchains it downloads
+// or builds, for use by later runs (default: a gorebuild directory in the
+// user's cache directory). Downloads are keyed by their posted SHA256,
+// so a cached file is only used in place of the identical download.
+//
+// The -resume flag makes gorebuild reuse the results of files that passed
+// in the gorebuild.json written by an earlier run, as long as their
+// posted SHA256 hasn't changed. Systems whose files all passed are not
+// rebuilt.
 //
 // When running on linux-amd64, gorebuild does a full bootstrap, building Go 1.4
 // (written in C) with the host C compiler, then building Go 1.17 with Go 1.4,
@@ -41,7 +53,12 @@
 // identical copy of the file posted at https://go.dev/dl/.
 // Similarly, gorebuild checks that the local rebuild produces a bit-for-bit
 // identical copy of the module form of the toolchain used by Go 1.21's
-// toolchain downloads (also served by https://go.dev/dl/).
+// toolchain downloads: the golang.org/toolchain module's .zip, .mod and .info
+// files, served by the module proxy at https://go.dev/dl/mod.
+// The -proxy flag names a different module proxy to check against.
+// Like GOPROXY, it may be a file:// URL naming a directory laid out as
+// a module proxy. When a module file does not match, gorebuild reports
+// the differing files in the zip or fields in the .mod and .info files.
 //
 // However, in a few cases gorebuild does not insist on a bit-for-bit comparison.
 // These cases are:
@@ -69,8 +86,8 @@
 //     rather than considered a failure.
 //
 // Gorebuild prints log messages to standard error but also accumulates them
-// in a structured report. Before exiting, it writes the report as JSON to gorebuild.json
-// and as HTML to gorebuild.html.
+// in a structured report. As each rebuild finishes, and again before exiting,
+// it writes the report as JSON to gorebuild.json and as HTML to gorebuild.html.
 //
 // Gorebuild exits with status 0 when it succeeds in writing a report,
 // whether or not the report verified all the posted files.
@@ -85,10 +102,25 @@ import (
 	"html/template"
 	"log"
 	"os"
+	"path/filepath"
 	"strings"
 )
 
-var pFlag = flag.Int("p", 2, "run `n` builds in parallel")
+var (
+	pFlag      = flag.Int("p", 2, "run `n` builds in parallel")
+	cacheFlag  = flag.String("cache", defaultCacheDir(), "cache downloads and bootstrap toolchains in `dir` (empty to disable)")
+	resumeFlag = flag.Bool("resume", false, "reuse passing results from gorebuild.json in the current directory")
+	proxyFlag  = flag.String("proxy", "https://go.dev/dl/mod", "check the module form of the toolchain against the module proxy at `url` (https:// or file://)")
+)
+
+// defaultCacheDir returns the default directory for the -cache flag.
+func defaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "gorebuild")
+}
 
 func usage() {
 	fmt.Fprintf(os.Stderr, "usage: gorebuild [flags] [goos-goarch][@version]...\n")
@@ -117,15 +149,24 @@ func main() {
 }
 
 func reformat(file string) {
-	data, err := os.ReadFile(file)
+	r, err := readReport(file)
 	if err != nil {
 		log.Fatal(err)
 	}
+	writeHTML(r)
+}
+
+// readReport reads a report written by writeJSON.
+func readReport(file string) (*Report, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
 	var r Report
 	if err := json.Unmarshal(data, &r); err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("%s: %v", file, err)
 	}
-	writeHTML(&r)
+	return &r, nil
 }
 
 func writeJSON(r *Report) {
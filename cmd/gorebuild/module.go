@@ -0,0 +1,150 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// toolchainModule is the module path of the module form of the Go toolchain,
+// which the go command downloads when switching toolchains.
+const toolchainModule = "golang.org/toolchain"
+
+// isModFile reports whether name is the name of a file in the module form
+// of the toolchain, such as "v0.0.1-go1.21.3.linux-amd64.zip",
+// rather than a file posted on go.dev/dl.
+func isModFile(name string) bool {
+	return strings.HasPrefix(name, "v")
+}
+
+// modVersion returns the module version of the module file with the given name:
+// "v0.0.1-go1.21.3.linux-amd64" for "v0.0.1-go1.21.3.linux-amd64.zip".
+func modVersion(name string) string {
+	for _, ext := range []string{".zip", ".mod", ".info"} {
+		if v, ok := strings.CutSuffix(name, ext); ok {
+			return v
+		}
+	}
+	return name
+}
+
+// ModURL returns the URL of the module file with the given name
+// on the module proxy at proxy, which may be an https:// or file:// URL.
+func ModURL(proxy, name string) string {
+	return strings.TrimSuffix(proxy, "/") + "/" + toolchainModule + "/@v/" + name
+}
+
+// DiffModFile diffs the module files rebuilt and posted, which have the given name,
+// reporting any differences to log and applying fix to files in module zips before
+// comparing them. It reports whether the files match.
+func DiffModFile(log *Log, name string, rebuilt, posted []byte, fix Fixer) bool {
+	switch {
+	case strings.HasSuffix(name, ".zip"):
+		return DiffModZip(log, modVersion(name), rebuilt, posted, fix)
+	case strings.HasSuffix(name, ".mod"):
+		return DiffArchive(log, IndexGoMod(log, rebuilt), IndexGoMod(log, posted), diffField)
+	case strings.HasSuffix(name, ".info"):
+		return DiffArchive(log, IndexModInfo(log, rebuilt), IndexModInfo(log, posted), diffField)
+	}
+	log.Printf("%s: unknown module file type", name)
+	return false
+}
+
+// diffField is a check function for [DiffArchive] for indexes of fields.
+func diffField(log *Log, rebuilt, posted *ModField) bool {
+	if rebuilt.Value != posted.Value {
+		log.Printf("%s: rebuilt = %s, posted = %s", rebuilt.Name, rebuilt.Value, posted.Value)
+		return false
+	}
+	return true
+}
+
+// A ModField is a single field of a module .info or .mod file.
+type ModField struct {
+	Name  string
+	Value string
+}
+
+// IndexModInfo parses data as a module .info file and returns an index of its fields,
+// with values in JSON form.
+func IndexModInfo(log *Log, data []byte) map[string]*ModField {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		log.Printf("parsing info: %v", err)
+		return nil
+	}
+	ix := make(map[string]*ModField)
+	for k, v := range fields {
+		ix[k] = &ModField{k, string(v)}
+	}
+	return ix
+}
+
+// IndexGoMod parses data as a go.mod file and returns an index of its directives,
+// keyed by the directive and, for requirements, the module path.
+func IndexGoMod(log *Log, data []byte) map[string]*ModField {
+	f, err := modfile.ParseLax("go.mod", data, nil)
+	if err != nil {
+		log.Printf("parsing go.mod: %v", err)
+		return nil
+	}
+	ix := make(map[string]*ModField)
+	add := func(name, value string) {
+		ix[name] = &ModField{name, value}
+	}
+	if f.Module != nil {
+		add("module", f.Module.Mod.Path)
+	}
+	if f.Go != nil {
+		add("go", f.Go.Version)
+	}
+	if f.Toolchain != nil {
+		add("toolchain", f.Toolchain.Name)
+	}
+	for _, r := range f.Require {
+		add("require "+r.Mod.Path, r.Mod.Version)
+	}
+	return ix
+}
+
+// DiffModZip diffs the module zips rebuilt and posted, for the given version
+// of the toolchain module, reporting any differences to log and applying fix
+// to files before comparing them. In addition to the checks made by [DiffZip],
+// it checks that all the files in the rebuilt zip are in the module's directory,
+// as the go command requires.
+// It reports whether the archives match.
+func DiffModZip(log *Log, version string, rebuilt, posted []byte, fix Fixer) bool {
+	prefix := fmt.Sprintf("%s@%s/", toolchainModule, version)
+	ok := true
+	for name := range IndexZip(log, rebuilt, nil) {
+		if !strings.HasPrefix(name, prefix) {
+			log.Printf("%s: rebuilt file not in %s", name, prefix)
+			ok = false
+		}
+	}
+	return DiffZip(log, rebuilt, posted, fix) && ok
+}
+
+// CheckModFiles checks that the rebuilt module files for version are consistent
+// with each other, as the go command expects: the .info file names the version,
+// and the .mod file declares the toolchain module. It returns the problems found.
+func CheckModFiles(log *Log, version string, info, mod []byte) []string {
+	var problems []string
+	if ix := IndexModInfo(log, info); ix == nil {
+		problems = append(problems, "cannot parse .info file")
+	} else if f := ix["Version"]; f == nil || f.Value != fmt.Sprintf("%q", version) {
+		problems = append(problems, fmt.Sprintf(".info file does not list Version %q", version))
+	}
+	if ix := IndexGoMod(log, mod); ix == nil {
+		problems = append(problems, "cannot parse .mod file")
+	} else if f := ix["module"]; f == nil || f.Value != toolchainModule {
+		problems = append(problems, fmt.Sprintf(".mod file does not declare module %s", toolchainModule))
+	}
+	return problems
+}
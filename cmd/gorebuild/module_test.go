@@ -0,0 +1,149 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testModVersion = "v0.0.1-go1.99.0.linux-amd64"
+
+// modZip returns a module zip holding the named files,
+// each with its name as its content.
+func modZip(t *testing.T, names ...string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range names {
+		w, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     name,
+			Method:   zip.Deflate,
+			Modified: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(name))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDiffModFile(t *testing.T) {
+	prefix := "golang.org/toolchain@" + testModVersion + "/"
+	good := modZip(t, prefix+"bin/go", prefix+"VERSION")
+	tests := []struct {
+		name             string
+		rebuilt, posted  string
+		wantOK           bool
+		wantLogSubstring string
+	}{
+		{
+			name:    testModVersion + ".info",
+			rebuilt: `{"Version":"` + testModVersion + `","Time":"2026-01-01T00:00:00Z"}`,
+			posted:  `{"Version":"` + testModVersion + `", "Time":"2026-01-01T00:00:00Z"}`,
+			wantOK:  true,
+		},
+		{
+			name:             testModVersion + ".info",
+			rebuilt:          `{"Version":"` + testModVersion + `","Time":"2026-01-01T00:00:00Z"}`,
+			posted:           `{"Version":"` + testModVersion + `","Time":"2026-01-02T00:00:00Z"}`,
+			wantLogSubstring: `Time: rebuilt = "2026-01-01T00:00:00Z", posted = "2026-01-02T00:00:00Z"`,
+		},
+		{
+			name:    testModVersion + ".mod",
+			rebuilt: "module golang.org/toolchain\n",
+			posted:  "module golang.org/toolchain\n",
+			wantOK:  true,
+		},
+		{
+			name:             testModVersion + ".mod",
+			rebuilt:          "module golang.org/toolchain\n",
+			posted:           "module golang.org/toolchain\ngo 1.21\n",
+			wantLogSubstring: "go: unexpected file in posted archive",
+		},
+		{
+			name:    testModVersion + ".zip",
+			rebuilt: string(good),
+			posted:  string(good),
+			wantOK:  true,
+		},
+		{
+			name:             testModVersion + ".zip",
+			rebuilt:          string(modZip(t, prefix+"bin/go", prefix+"VERSION")),
+			posted:           string(modZip(t, prefix+"bin/go")),
+			wantLogSubstring: prefix + "VERSION: missing from posted archive",
+		},
+		{
+			name:             testModVersion + ".zip",
+			rebuilt:          string(modZip(t, "go/bin/go")),
+			posted:           string(modZip(t, "go/bin/go")),
+			wantLogSubstring: "go/bin/go: rebuilt file not in " + prefix,
+		},
+	}
+	for _, tt := range tests {
+		var log Log
+		ok := DiffModFile(&log, tt.name, []byte(tt.rebuilt), []byte(tt.posted), nil)
+		var msgs []string
+		for _, m := range log.Messages {
+			msgs = append(msgs, m.Text)
+		}
+		text := strings.Join(msgs, "\n")
+		if ok != tt.wantOK {
+			t.Errorf("DiffModFile(%s, %q, %q) = %v, want %v; log:\n%s", tt.name, tt.rebuilt, tt.posted, ok, tt.wantOK, text)
+		}
+		if !strings.Contains(text, tt.wantLogSubstring) {
+			t.Errorf("DiffModFile(%s, %q, %q) log:\n%s\nwant %q", tt.name, tt.rebuilt, tt.posted, text, tt.wantLogSubstring)
+		}
+	}
+}
+
+func TestCheckModFiles(t *testing.T) {
+	var log Log
+	info := []byte(`{"Version":"` + testModVersion + `","Time":"2026-01-01T00:00:00Z"}`)
+	if p := CheckModFiles(&log, testModVersion, info, []byte("module golang.org/toolchain\n")); len(p) != 0 {
+		t.Errorf("CheckModFiles of consistent files = %q, want none", p)
+	}
+	if p := CheckModFiles(&log, "v0.0.1-go1.99.1.linux-amd64", info, []byte("module example.com/toolchain\n")); len(p) != 2 {
+		t.Errorf("CheckModFiles of inconsistent files = %q, want 2 problems", p)
+	}
+}
+
+func TestDownloadFileProxy(t *testing.T) {
+	dir := t.TempDir()
+	name := testModVersion + ".mod"
+	file := filepath.Join(dir, "golang.org", "toolchain", "@v", name)
+	if err := os.MkdirAll(filepath.Dir(file), 0777); err != nil {
+		t.Fatal(err)
+	}
+	want := "module golang.org/toolchain\n"
+	if err := os.WriteFile(file, []byte(want), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &Report{proxy: "file://" + filepath.ToSlash(dir)}
+	rel := &Release{Version: "go1.99.0"}
+	f := r.File(rel, name, "linux", "amd64")
+	data, ok := r.Download(f)
+	if !ok || string(data) != want {
+		t.Fatalf("Download = %q, %v; want %q, true", data, ok, want)
+	}
+	if f.SHA256 != SHA256([]byte(want)) {
+		t.Errorf("SHA256 = %s, want %s", f.SHA256, SHA256([]byte(want)))
+	}
+
+	missing := r.File(rel, testModVersion+".info", "linux", "amd64")
+	if _, ok := r.Download(missing); ok {
+		t.Errorf("Download of missing file succeeded")
+	}
+}
@@ -6,13 +6,17 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	goversion "go/version"
+	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
 	"runtime"
 	"runtime/debug"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -34,7 +38,12 @@ type Report struct {
 	Releases   []*Release   // releases reproduced
 	Log        Log
 
-	dl []*DLRelease // information from go.dev/dl
+	dl    []*DLRelease // information from go.dev/dl
+	cache *Cache       // cache of downloads and bootstraps, or nil
+	proxy string       // module proxy serving the module form of the toolchain
+	prev  *Report      // earlier report whose passing results can be reused, or nil
+
+	saveMu sync.Mutex // held while saving the report
 }
 
 // A Bootstrap describes the result of building or obtaining a bootstrap toolchain.
@@ -50,6 +59,7 @@ type Release struct {
 	Version string // Go version string "go1.21.3"
 	Log     Log
 	dl      *DLRelease
+	src     func() ([]byte, error) // returns the release's source code
 
 	mu    sync.Mutex
 	Files []*File // Files reproduced
@@ -64,9 +74,12 @@ type File struct {
 	Log    Log
 	dl     *DLFile
 
-	cache bool
-	mu    sync.Mutex
-	data  []byte
+	cache  bool
+	reused bool // result reused from an earlier report
+	mu     sync.Mutex
+	data   []byte
+
+	shaMu sync.Mutex // guards SHA256 while other goroutines may marshal f
 }
 
 // A Log contains timestamped log messages as well as an overall
@@ -157,6 +170,19 @@ func Run(args []string) *Report {
 	if err != nil {
 		return r
 	}
+	r.proxy = *proxyFlag
+	if *cacheFlag != "" {
+		r.cache = &Cache{Dir: *cacheFlag}
+	}
+	if *resumeFlag {
+		r.prev, err = readReport("gorebuild.json")
+		if errors.Is(err, fs.ErrNotExist) {
+			r.Log.Printf("no gorebuild.json to resume from")
+			r.prev, err = nil, nil
+		} else if err != nil {
+			return r
+		}
+	}
 
 	r.dl, err = DLReleases(&r.Log)
 	if err != nil {
@@ -194,48 +220,71 @@ func Run(args []string) *Report {
 		}
 	}
 
+	if r.prev != nil {
+		r.reusePrevious()
+	}
+
 	// Do the work.
 	// Fetch or build the bootstraps single-threaded.
 	for _, rel := range r.Releases {
-		// If BootstrapVersion fails, the parallel loop will report that.
+		// If BootstrapVersion fails, the workers will report that.
 		bver, _ := BootstrapVersion(rel.Version)
 		if bver != "" {
 			r.BootstrapDir(bver)
 		}
 	}
 
-	// Run every file in its own goroutine.
-	// Limit parallelism with channel.
+	// Rebuild each release for each system using a pool of N workers.
+	// Each rebuild checks all the files for its system as a side effect.
 	N := *pFlag
 	if N < 1 {
 		log.Fatalf("invalid parallelism -p=%d", *pFlag)
 	}
-	limit := make(chan int, N)
-	for range N {
-		limit <- 1
+	type job struct {
+		rel  *Release
+		file *File
 	}
+	var jobs []job
 	for _, rel := range r.Releases {
-		// Download source code.
-		src, err := GerritTarGz(&rel.Log, "go", "refs/tags/"+rel.Version)
-		if err != nil {
-			rel.Log.Printf("FAIL: downloading source: %v", err)
-			continue
-		}
-
-		// Reproduce all the files.
+		rel.src = sync.OnceValues(func() ([]byte, error) {
+			src, err := GerritTarGz(&rel.Log, "go", "refs/tags/"+rel.Version)
+			if err != nil {
+				rel.Log.Printf("FAIL: downloading source: %v", err)
+			}
+			return src, err
+		})
 		for _, file := range rel.Files {
-			<-limit
-			go func() {
-				defer func() { limit <- 1 }()
-				r.ReproFile(rel, file, src)
-			}()
+			if file.dl == nil || file.dl.Kind != "archive" {
+				// Checked as a side effect of rebuilding a different file.
+				continue
+			}
+			if r.allReused(rel, file.GOOS, file.GOARCH) {
+				file.Log.Printf("skipping rebuild: all files for %s-%s verified by earlier run", file.GOOS, file.GOARCH)
+				continue
+			}
+			jobs = append(jobs, job{rel, file})
 		}
 	}
-
-	// Wait for goroutines to finish.
+	r.checkpoint()
+	work := make(chan job)
+	var wg sync.WaitGroup
 	for range N {
-		<-limit
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range work {
+				if src, err := j.rel.src(); err == nil {
+					r.ReproFile(j.rel, j.file, src)
+				}
+				r.checkpoint()
+			}
+		}()
 	}
+	for _, j := range jobs {
+		work <- j
+	}
+	close(work)
+	wg.Wait()
 
 	// Collect results.
 	// Sort the list of work for nicer presentation.
@@ -321,11 +370,6 @@ func (r *Report) ReproFile(rel *Release, file *File, src []byte) (err error) {
 		}
 	}()
 
-	if file.dl == nil || file.dl.Kind != "archive" {
-		// Checked as a side effect of rebuilding a different file.
-		return nil
-	}
-
 	file.Log.Printf("start %s", file.Name)
 
 	goroot := filepath.Join(r.Work, fmt.Sprintf("repro-%s-%s-%s", rel.Version, file.GOOS, file.GOARCH))
@@ -349,11 +393,15 @@ func (r *Report) ReproFile(rel *Release, file *File, src []byte) (err error) {
 	if err != nil {
 		return err
 	}
+	modFiles := make(map[string][]byte)
 	for _, b := range built {
 		data, err := os.ReadFile(filepath.Join(distpack, b.Name()))
 		if err != nil {
 			return err
 		}
+		if isModFile(b.Name()) {
+			modFiles[b.Name()] = data
+		}
 
 		// Look up file from posted list.
 		// For historical reasons, the linux-arm downloads are named linux-armv6l.
@@ -372,19 +420,13 @@ func (r *Report) ReproFile(rel *Release, file *File, src []byte) (err error) {
 
 		match := bytes.Equal(data, pubData)
 		if !match && file.GOOS == "darwin" {
-			if strings.HasSuffix(bf.Name, ".tar.gz") && DiffTarGz(&bf.Log, data, pubData, StripDarwinSig) ||
-				strings.HasSuffix(bf.Name, ".zip") && DiffZip(&bf.Log, data, pubData, StripDarwinSig) {
+			if (strings.HasSuffix(bf.Name, ".tar.gz") || strings.HasSuffix(bf.Name, ".zip")) && diffFile(&bf.Log, bf.Name, data, pubData, StripDarwinSig) {
 				bf.Log.Printf("verified match after stripping signatures from executables")
 				match = true
 			}
 		}
 		if !match {
-			if strings.HasSuffix(bf.Name, ".tar.gz") {
-				DiffTarGz(&bf.Log, data, pubData, nil)
-			}
-			if strings.HasSuffix(bf.Name, ".zip") {
-				DiffZip(&bf.Log, data, pubData, nil)
-			}
+			diffFile(&bf.Log, bf.Name, data, pubData, nil)
 			bf.Log.Printf("FAIL: rebuilt SHA256 %s does not match public download SHA256 %s", SHA256(data), SHA256(pubData))
 			continue
 		}
@@ -398,9 +440,37 @@ func (r *Report) ReproFile(rel *Release, file *File, src []byte) (err error) {
 			}
 		}
 	}
+
+	// Check that the module files fit together.
+	// A mismatch would also show up as a failure to match the posted
+	// files, but this explains what the go command would reject.
+	mod := "v0.0.1-" + rel.Version + "." + file.GOOS + "-" + file.GOARCH
+	if info, gomod := modFiles[mod+".info"], modFiles[mod+".mod"]; info != nil && gomod != nil {
+		if problems := CheckModFiles(&file.Log, mod, info, gomod); len(problems) > 0 {
+			mf := r.File(rel, mod+".info", file.GOOS, file.GOARCH)
+			for _, p := range problems {
+				mf.Log.Printf("FAIL: rebuilt %s", p)
+			}
+		}
+	}
 	return nil
 }
 
+// diffFile diffs the rebuilt and posted copies of the named file,
+// reporting any differences to log and applying fix to files in archives
+// before comparing them. It reports whether the files match.
+func diffFile(log *Log, name string, rebuilt, posted []byte, fix Fixer) bool {
+	switch {
+	case isModFile(name):
+		return DiffModFile(log, name, rebuilt, posted, fix)
+	case strings.HasSuffix(name, ".tar.gz"):
+		return DiffTarGz(log, rebuilt, posted, fix)
+	case strings.HasSuffix(name, ".zip"):
+		return DiffZip(log, rebuilt, posted, fix)
+	}
+	return false
+}
+
 func (r *Report) ReproWindowsMsi(rel *Release, file *File, zip []byte) {
 	mf := r.File(rel, strings.TrimSuffix(file.Name, ".zip")+".msi", file.GOOS, file.GOARCH)
 	if mf.dl == nil {
@@ -435,9 +505,9 @@ func (r *Report) ReproDarwinPkg(rel *Release, file *File, tgz []byte) {
 }
 
 func (r *Report) Download(f *File) ([]byte, bool) {
-	url := "https://go.dev/dl/"
-	if strings.HasPrefix(f.Name, "v") {
-		url += "mod/golang.org/toolchain/@v/"
+	url := "https://go.dev/dl/" + f.Name
+	if isModFile(f.Name) {
+		url = ModURL(r.proxy, f.Name)
 	}
 	if f.cache {
 		f.mu.Lock()
@@ -446,7 +516,11 @@ func (r *Report) Download(f *File) ([]byte, bool) {
 			return f.data, true
 		}
 	}
-	data, err := Get(&f.Log, url+f.Name)
+	var want string
+	if f.dl != nil {
+		want = f.dl.SHA256
+	}
+	data, err := r.cache.Get(&f.Log, url, want)
 	if err != nil {
 		f.Log.Printf("FAIL: cannot download public copy")
 		return nil, false
@@ -457,12 +531,122 @@ func (r *Report) Download(f *File) ([]byte, bool) {
 		f.Log.Printf("FAIL: go.dev/dl-listed SHA256 %s does not match public download SHA256 %s", f.dl.SHA256, sum)
 		return nil, false
 	}
+	f.shaMu.Lock()
+	f.SHA256 = sum
+	f.shaMu.Unlock()
 	if f.cache {
 		f.data = data
 	}
 	return data, true
 }
 
+// reusePrevious copies the results of files that passed in r.prev
+// to the corresponding files in r, provided that the posted files
+// haven't changed since.
+func (r *Report) reusePrevious() {
+	for _, rel := range r.Releases {
+		var prel *Release
+		for _, pr := range r.prev.Releases {
+			if pr.Version == rel.Version {
+				prel = pr
+			}
+		}
+		if prel == nil {
+			continue
+		}
+		for _, f := range rel.Files {
+			for _, pf := range prel.Files {
+				if pf.Name != f.Name || pf.Log.Status != PASS {
+					continue
+				}
+				if f.dl != nil && pf.SHA256 != f.dl.SHA256 {
+					f.Log.Printf("posted file changed since earlier run; verifying again")
+					continue
+				}
+				f.SHA256 = pf.SHA256
+				f.Log.Messages = append(f.Log.Messages, pf.Log.Messages...)
+				f.Log.Printf("PASS: verified by earlier run started at %s", r.prev.Start.UTC().Format(time.DateTime))
+				f.reused = true
+			}
+		}
+	}
+}
+
+// allReused reports whether all the files of rel for the given system
+// reuse the results of an earlier run.
+func (r *Report) allReused(rel *Release, goos, goarch string) bool {
+	if r.prev == nil {
+		return false
+	}
+	for _, f := range rel.Files {
+		if f.GOOS == goos && f.GOARCH == goarch && !f.reused {
+			return false
+		}
+	}
+	return true
+}
+
+// checkpoint saves the current state of r, so that progress can be
+// followed while gorebuild is running and so that a later run can
+// resume from it.
+func (r *Report) checkpoint() {
+	r.saveMu.Lock()
+	defer r.saveMu.Unlock()
+	// Work on a copy, because other goroutines are still updating r.
+	js, err := json.Marshal(r)
+	if err != nil {
+		log.Printf("checkpoint: %v", err)
+		return
+	}
+	var copy Report
+	if err := json.Unmarshal(js, &copy); err != nil {
+		log.Printf("checkpoint: %v", err)
+		return
+	}
+	writeJSON(&copy)
+	writeHTML(&copy)
+}
+
+// MarshalJSON marshals l while holding l.mu, so that l can be marshaled
+// while other goroutines are logging to it.
+func (l *Log) MarshalJSON() ([]byte, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return json.Marshal(struct {
+		Name     string
+		Messages []Message
+		Status   Status
+	}{l.Name, l.Messages, l.Status})
+}
+
+// MarshalJSON marshals f while holding f.shaMu, so that f can be
+// marshaled while another goroutine is verifying it.
+func (f *File) MarshalJSON() ([]byte, error) {
+	f.shaMu.Lock()
+	sum := f.SHA256
+	f.shaMu.Unlock()
+	return json.Marshal(struct {
+		Name   string
+		GOOS   string
+		GOARCH string
+		SHA256 string
+		Log    *Log
+	}{f.Name, f.GOOS, f.GOARCH, sum, &f.Log})
+}
+
+// MarshalJSON marshals rel while holding rel.mu, so that rel can be
+// marshaled while other goroutines are adding files to it.
+func (rel *Release) MarshalJSON() ([]byte, error) {
+	rel.mu.Lock()
+	files := slices.Clone(rel.Files)
+	rel.mu.Unlock()
+	return json.Marshal(struct {
+		Version string
+		Log     *Log
+		Files   []*File
+	}{rel.Version, &rel.Log, files})
+}
+
 func (r *Report) Release(version string) *Release {
 	for _, rel := range r.Releases {
 		if rel.Version == version {
@@ -0,0 +1,231 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Releasetargets prints the targets that Go releases are built for,
+// and how they change between releases.
+//
+// Usage:
+//
+//	releasetargets [-format=markdown|json] [version...]
+//	releasetargets -diff [-format=markdown|json] old new
+//
+// Versions are Go 1.x releases, such as go1.27 or 1.27.
+//
+// Without -diff, releasetargets prints the release targets of each
+// version, or of all the releases they're configured for, with their
+// port class, extra environment variables, and minimum macOS version.
+//
+// With -diff, it prints the targets that were added, removed, or changed
+// from the old version to the new one, as release notes and release
+// checks need.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"golang.org/x/build/internal/releasetargets"
+	"golang.org/x/build/maintner/maintnerd/maintapi/version"
+)
+
+var (
+	flagDiff   = flag.Bool("diff", false, "print the changes to release targets between two versions")
+	flagFormat = flag.String("format", "markdown", `output format: "markdown" or "json"`)
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: releasetargets [-format=markdown|json] [version...]\n")
+	fmt.Fprintf(os.Stderr, "       releasetargets -diff [-format=markdown|json] old new\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	log.SetPrefix("releasetargets: ")
+	log.SetFlags(0)
+	flag.Usage = usage
+	flag.Parse()
+	if *flagFormat != "markdown" && *flagFormat != "json" {
+		usage()
+	}
+
+	var releases []int
+	for _, arg := range flag.Args() {
+		x, err := parseRelease(arg)
+		if err != nil {
+			log.Fatal(err)
+		}
+		releases = append(releases, x)
+	}
+	var err error
+	if *flagDiff {
+		if len(releases) != 2 {
+			usage()
+		}
+		err = writeDiff(os.Stdout, *flagFormat, releases[0], releases[1])
+	} else {
+		if len(releases) == 0 {
+			releases = releasetargets.Releases()
+		}
+		err = writeMatrix(os.Stdout, *flagFormat, releases)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// parseRelease returns x for a version such as go1.x or 1.x.
+func parseRelease(arg string) (int, error) {
+	x, ok := version.Go1PointX("go" + strings.TrimPrefix(arg, "go"))
+	if !ok {
+		return 0, fmt.Errorf("invalid Go version %q", arg)
+	}
+	if !slices.Contains(releasetargets.Releases(), x) {
+		return 0, fmt.Errorf("no release targets configured for Go 1.%d", x)
+	}
+	return x, nil
+}
+
+// jsonTarget is the JSON encoding of a release target.
+type jsonTarget struct {
+	Name            string   `json:"name"`
+	GOOS            string   `json:"goos"`
+	GOARCH          string   `json:"goarch"`
+	FirstClass      bool     `json:"firstClass"`
+	ExtraEnv        []string `json:"extraEnv,omitempty"`
+	MinMacOSVersion string   `json:"minMacOSVersion,omitempty"`
+}
+
+func newJSONTarget(t *releasetargets.Target) *jsonTarget {
+	if t == nil {
+		return nil
+	}
+	return &jsonTarget{
+		Name:            t.Name,
+		GOOS:            t.GOOS,
+		GOARCH:          t.GOARCH,
+		FirstClass:      !t.SecondClass,
+		ExtraEnv:        t.ExtraEnv,
+		MinMacOSVersion: t.MinMacOSVersion,
+	}
+}
+
+// sortedTargets returns the targets of release x sorted by name.
+func sortedTargets(x int) []*releasetargets.Target {
+	var targets []*releasetargets.Target
+	for _, t := range releasetargets.TargetsForGo1Point(x) {
+		targets = append(targets, t)
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Name < targets[j].Name })
+	return targets
+}
+
+// writeMatrix writes the release targets of releases in format.
+func writeMatrix(w io.Writer, format string, releases []int) error {
+	if format == "json" {
+		type jsonRelease struct {
+			Version string        `json:"version"`
+			Targets []*jsonTarget `json:"targets"`
+		}
+		var out []jsonRelease
+		for _, x := range releases {
+			r := jsonRelease{Version: fmt.Sprintf("go1.%d", x)}
+			for _, t := range sortedTargets(x) {
+				r.Targets = append(r.Targets, newJSONTarget(t))
+			}
+			out = append(out, r)
+		}
+		return writeJSON(w, out)
+	}
+
+	for i, x := range releases {
+		if i > 0 {
+			fmt.Fprintf(w, "\n")
+		}
+		fmt.Fprintf(w, "## Go 1.%d release targets\n\n", x)
+		fmt.Fprintf(w, "| Target | GOOS | GOARCH | Class | Extra env | Minimum macOS |\n")
+		fmt.Fprintf(w, "|---|---|---|---|---|---|\n")
+		for _, t := range sortedTargets(x) {
+			var env []string
+			for _, e := range t.ExtraEnv {
+				env = append(env, "`"+e+"`")
+			}
+			fmt.Fprintf(w, "| %s | %s | %s | %s | %s | %s |\n", t.Name, t.GOOS, t.GOARCH, portClass(t), strings.Join(env, " "), t.MinMacOSVersion)
+		}
+	}
+	return nil
+}
+
+// writeDiff writes the changes to release targets from release old to new in format.
+func writeDiff(w io.Writer, format string, old, new int) error {
+	changes := releasetargets.Diff(releasetargets.TargetsForGo1Point(old), releasetargets.TargetsForGo1Point(new))
+	if format == "json" {
+		type jsonChange struct {
+			Name    string      `json:"name"`
+			Kind    string      `json:"kind"`
+			Old     *jsonTarget `json:"old,omitempty"`
+			New     *jsonTarget `json:"new,omitempty"`
+			Details []string    `json:"details,omitempty"`
+		}
+		out := struct {
+			Old     string       `json:"old"`
+			New     string       `json:"new"`
+			Changes []jsonChange `json:"changes"`
+		}{
+			Old:     fmt.Sprintf("go1.%d", old),
+			New:     fmt.Sprintf("go1.%d", new),
+			Changes: []jsonChange{},
+		}
+		for _, c := range changes {
+			out.Changes = append(out.Changes, jsonChange{
+				Name:    c.Name,
+				Kind:    c.Kind(),
+				Old:     newJSONTarget(c.Old),
+				New:     newJSONTarget(c.New),
+				Details: c.Details(),
+			})
+		}
+		return writeJSON(w, out)
+	}
+
+	fmt.Fprintf(w, "## Release target changes from Go 1.%d to Go 1.%d\n\n", old, new)
+	if len(changes) == 0 {
+		fmt.Fprintf(w, "No changes.\n")
+		return nil
+	}
+	for _, c := range changes {
+		switch c.Kind() {
+		case "added":
+			fmt.Fprintf(w, "- Added %s (%s).\n", c.Name, portClass(c.New))
+		case "removed":
+			fmt.Fprintf(w, "- Removed %s (%s).\n", c.Name, portClass(c.Old))
+		default:
+			fmt.Fprintf(w, "- Changed %s: %s.\n", c.Name, strings.Join(c.Details(), ", "))
+		}
+	}
+	return nil
+}
+
+func portClass(t *releasetargets.Target) string {
+	if t.SecondClass {
+		return "second class port"
+	}
+	return "first class port"
+}
+
+func writeJSON(w io.Writer, v any) error {
+	b, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
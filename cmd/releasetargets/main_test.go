@@ -0,0 +1,106 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseRelease(t *testing.T) {
+	for _, arg := range []string{"go1.27", "1.27", "go1.27.3", "go1.27rc1"} {
+		if x, err := parseRelease(arg); err != nil || x != 27 {
+			t.Errorf("parseRelease(%q) = %d, %v, want 27, nil", arg, x, err)
+		}
+	}
+	for _, arg := range []string{"", "1", "go2.0", "go1.10"} {
+		if x, err := parseRelease(arg); err == nil {
+			t.Errorf("parseRelease(%q) = %d, nil, want error", arg, x)
+		}
+	}
+}
+
+func TestWriteMatrix(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeMatrix(&buf, "markdown", []int{27}); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"## Go 1.27 release targets\n",
+		"| darwin-arm64 | darwin | arm64 | first class port |  | 13 |\n",
+		"| linux-armv6l | linux | arm | first class port | `GOARM=6` |  |\n",
+		"| aix-ppc64 | aix | ppc64 | second class port |  |  |\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("markdown matrix doesn't contain %q:\n%s", want, &buf)
+		}
+	}
+
+	buf.Reset()
+	if err := writeMatrix(&buf, "json", []int{26, 27}); err != nil {
+		t.Fatal(err)
+	}
+	var releases []struct {
+		Version string
+		Targets []jsonTarget
+	}
+	if err := json.Unmarshal(buf.Bytes(), &releases); err != nil {
+		t.Fatal(err)
+	}
+	if len(releases) != 2 || releases[0].Version != "go1.26" || releases[1].Version != "go1.27" {
+		t.Fatalf("got releases %+v, want go1.26 and go1.27", releases)
+	}
+	for _, target := range releases[1].Targets {
+		if target.Name == "darwin-amd64" && (!target.FirstClass || target.MinMacOSVersion != "13") {
+			t.Errorf("go1.27 target %+v, want first class with minimum macOS 13", target)
+		}
+	}
+}
+
+func TestWriteDiff(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeDiff(&buf, "markdown", 26, 27); err != nil {
+		t.Fatal(err)
+	}
+	want := `## Release target changes from Go 1.26 to Go 1.27
+
+- Changed darwin-amd64: minimum macOS version 12 → 13.
+- Changed darwin-arm64: minimum macOS version 12 → 13.
+`
+	if got := buf.String(); got != want {
+		t.Errorf("markdown diff:\n%s\nwant:\n%s", got, want)
+	}
+
+	buf.Reset()
+	if err := writeDiff(&buf, "json", 25, 26); err != nil {
+		t.Fatal(err)
+	}
+	var diff struct {
+		Old, New string
+		Changes  []struct {
+			Name, Kind string
+			Old, New   *jsonTarget
+		}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &diff); err != nil {
+		t.Fatal(err)
+	}
+	if diff.Old != "go1.25" || diff.New != "go1.26" || len(diff.Changes) != 1 {
+		t.Fatalf("got diff %+v, want one change from go1.25 to go1.26", diff)
+	}
+	if c := diff.Changes[0]; c.Name != "freebsd-riscv64" || c.Kind != "removed" || c.Old == nil || c.New != nil {
+		t.Errorf("got change %+v, want freebsd-riscv64 removed", c)
+	}
+
+	buf.Reset()
+	if err := writeDiff(&buf, "markdown", 27, 27); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(buf.String(), "No changes.\n") {
+		t.Errorf("markdown diff of a release with itself:\n%s", &buf)
+	}
+}
@@ -0,0 +1,321 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/build/gerrit"
+	"golang.org/x/build/maintner"
+	"golang.org/x/build/maintner/godata"
+	"golang.org/x/build/relnote"
+)
+
+// A coverageReport describes how well the release notes in doc/next cover
+// the changes made for a Go release: the API additions in api/next, the
+// accepted proposals referred to by merged CLs, and the CLs with RELNOTE
+// comments.
+type coverageReport struct {
+	Version string         `json:"version"` // such as "1.24"
+	Since   time.Time      `json:"since"`   // when the tree opened
+	Items   []coverageItem `json:"items"`
+}
+
+// A coverageItem is a change that should be mentioned in the release notes.
+type coverageItem struct {
+	Kind     string                 `json:"kind"`               // "api", "proposal" or "relnote"
+	ID       string                 `json:"id"`                 // unique within Kind
+	Title    string                 `json:"title,omitempty"`    // issue title or CL subject
+	URL      string                 `json:"url,omitempty"`      // issue or CL
+	Fragment string                 `json:"fragment,omitempty"` // release-note fragment, relative to GOROOT
+	Owners   []string               `json:"owners,omitempty"`   // owners of the CLs that made the change
+	Status   relnote.CoverageStatus `json:"status"`
+	Details  []string               `json:"details,omitempty"` // API features, RELNOTE text, or problems with the fragment
+
+	issue int // for API items, the issue that introduced the features
+}
+
+func (it coverageItem) key() string { return it.Kind + " " + it.ID }
+
+// counts returns the number of items in r and how many are documented.
+func (r *coverageReport) counts() (documented, total int) {
+	for _, it := range r.Items {
+		if it.Status == relnote.Documented {
+			documented++
+		}
+	}
+	return documented, len(r.Items)
+}
+
+// Percent returns the percentage of items in r that are documented.
+func (r *coverageReport) Percent() float64 {
+	documented, total := r.counts()
+	if total == 0 {
+		return 100
+	}
+	return 100 * float64(documented) / float64(total)
+}
+
+// checkAgainst returns an error if coverage has dropped since baseline:
+// if an item documented in baseline no longer is, or if the percentage
+// of documented items is lower.
+func (r *coverageReport) checkAgainst(baseline *coverageReport) error {
+	status := make(map[string]relnote.CoverageStatus)
+	for _, it := range r.Items {
+		status[it.key()] = it.Status
+	}
+	var errs []error
+	for _, it := range baseline.Items {
+		if it.Status != relnote.Documented {
+			continue
+		}
+		if st, ok := status[it.key()]; ok && st != relnote.Documented {
+			errs = append(errs, fmt.Errorf("%s %s was documented, but is now %s", it.Kind, it.ID, st))
+		}
+	}
+	if got, was := r.Percent(), baseline.Percent(); got < was {
+		errs = append(errs, fmt.Errorf("coverage dropped from %.1f%% to %.1f%%", was, got))
+	}
+	return errors.Join(errs...)
+}
+
+// coverage writes a report on the release-note coverage of the changes
+// since treeOpenDate to w, in the given format: "text", "json" or "html".
+// If baselineFile is non-empty, it names a JSON report from an earlier run,
+// and coverage returns an error if coverage has dropped since.
+func coverage(w io.Writer, goroot, version string, treeOpenDate time.Time, format, baselineFile string) error {
+	var baseline *coverageReport
+	if baselineFile != "" {
+		data, err := os.ReadFile(baselineFile)
+		if err != nil {
+			return err
+		}
+		baseline = new(coverageReport)
+		if err := json.Unmarshal(data, baseline); err != nil {
+			return fmt.Errorf("%s: %v", baselineFile, err)
+		}
+	}
+	if treeOpenDate.IsZero() {
+		var err error
+		treeOpenDate, err = findTreeOpenDate(goroot)
+		if err != nil {
+			return err
+		}
+	}
+	log.Printf("checking release-note coverage in %s since %s", goroot, treeOpenDate.Format(time.DateOnly))
+
+	docFS := os.DirFS(filepath.Join(goroot, "doc", "next"))
+	apiItems, err := apiCoverageItems(os.DirFS(filepath.Join(goroot, "api", "next")), docFS)
+	if err != nil {
+		return err
+	}
+	clItems, owners, titles, err := clCoverageItems(docFS, treeOpenDate)
+	if err != nil {
+		return err
+	}
+	for i := range apiItems {
+		apiItems[i].Title = titles[apiItems[i].issue]
+		apiItems[i].Owners = owners[apiItems[i].issue]
+	}
+	r := &coverageReport{
+		Version: version,
+		Since:   treeOpenDate,
+		Items:   append(apiItems, clItems...),
+	}
+	if err := writeCoverage(w, r, format); err != nil {
+		return err
+	}
+	if baseline != nil {
+		return r.checkAgainst(baseline)
+	}
+	return nil
+}
+
+// apiCoverageItems returns an item for each group of features
+// in the api files of apiFS that share a fragment in docFS.
+func apiCoverageItems(apiFS, docFS fs.FS) ([]coverageItem, error) {
+	files, err := fs.Glob(apiFS, "*.txt")
+	if err != nil {
+		return nil, err
+	}
+	var items []coverageItem
+	for _, f := range files {
+		cov, err := relnote.CheckAPICoverage(apiFS, f, docFS)
+		if err != nil {
+			return nil, fmt.Errorf("api/next/%s: %v", f, err)
+		}
+		for _, c := range cov {
+			it := coverageItem{
+				Kind:     "api",
+				ID:       c.Fragment,
+				Fragment: path.Join("doc/next", c.Fragment),
+				Status:   c.Status,
+				URL:      fmt.Sprintf("https://go.dev/issue/%d", c.Features[0].Issue),
+				issue:    c.Features[0].Issue,
+			}
+			for _, f := range c.Features {
+				it.Details = append(it.Details, fmt.Sprintf("pkg %s, %s", f.Package, f.Feature))
+			}
+			if c.Problem != "" {
+				it.Details = append(it.Details, c.Problem)
+			}
+			items = append(items, it)
+		}
+	}
+	return items, nil
+}
+
+// clCoverageItems returns an item for each accepted proposal referred to
+// by a CL merged since cutoff, and for each such CL with a RELNOTE comment.
+// It also returns the owners of the CLs that refer to each issue, and the
+// titles of those issues, keyed by issue number.
+func clCoverageItems(docFS fs.FS, cutoff time.Time) (items []coverageItem, owners map[int][]string, titles map[int]string, _ error) {
+	ctx := context.Background()
+	mentioned := newMentioned()
+	if err := infoFromDocFiles(docFS, mentioned, func(ToDo) {}); err != nil {
+		return nil, nil, nil, err
+	}
+	// As in todosFromCLs, inline comments must come from Gerrit.
+	gerritClient := gerrit.NewClient("https://go-review.googlesource.com", gerrit.NoAuth)
+	matchedCLs, err := findCLsWithRelNote(gerritClient, cutoff)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	corpus, err := godata.Get(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	gh := corpus.GitHub().Repo("golang", "go")
+	status := func(file string) (relnote.CoverageStatus, string) {
+		if file == "" {
+			return relnote.Missing, ""
+		}
+		return relnote.FragmentStatus(docFS, file), path.Join("doc/next", file)
+	}
+
+	owners = make(map[int][]string)
+	titles = make(map[int]string)
+	proposals := make(map[int]*coverageItem)
+	err = forEachMergedCL(corpus, cutoff, func(cl *maintner.GerritCL) error {
+		var owner string
+		if p := cl.Owner(); p != nil {
+			owner = p.Email()
+		}
+		num := int(cl.Number)
+		if _, ok := matchedCLs[num]; ok {
+			comments, err := gerritClient.ListChangeComments(ctx, fmt.Sprint(num))
+			if err != nil {
+				return err
+			}
+			if rn := clRelNote(cl, comments); rn != "" {
+				it := coverageItem{
+					Kind:    "relnote",
+					ID:      fmt.Sprintf("CL %d", num),
+					Title:   cl.Subject(),
+					URL:     fmt.Sprintf("https://go.dev/cl/%d", num),
+					Details: []string{rn},
+				}
+				if owner != "" {
+					it.Owners = []string{owner}
+				}
+				it.Status, it.Fragment = status(mentioned.CLs[num])
+				items = append(items, it)
+			}
+		}
+		for _, n := range issueNumbers(cl) {
+			if owner != "" && !slices.Contains(owners[n], owner) {
+				owners[n] = append(owners[n], owner)
+			}
+			issue := gh.Issue(int32(n))
+			if issue == nil {
+				continue
+			}
+			titles[n] = issue.Title
+			if !hasLabel(issue, "Proposal-Accepted") {
+				continue
+			}
+			it := proposals[n]
+			if it == nil {
+				it = &coverageItem{
+					Kind:  "proposal",
+					ID:    fmt.Sprintf("#%d", n),
+					Title: issue.Title,
+					URL:   fmt.Sprintf("https://go.dev/issue/%d", n),
+				}
+				it.Status, it.Fragment = status(mentioned.Issues[n])
+				proposals[n] = it
+			}
+			it.Details = append(it.Details, fmt.Sprintf("CL %d: %s", num, cl.Subject()))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	for n, it := range proposals {
+		it.Owners = owners[n]
+		slices.Sort(it.Details)
+		items = append(items, *it)
+	}
+	for _, o := range owners {
+		slices.Sort(o)
+	}
+	slices.SortFunc(items, func(a, b coverageItem) int { return strings.Compare(a.key(), b.key()) })
+	return items, owners, titles, nil
+}
+
+// writeCoverage writes r to w in the given format.
+func writeCoverage(w io.Writer, r *coverageReport, format string) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "\t")
+		return enc.Encode(r)
+	case "html":
+		return coverageTmpl.Execute(w, r)
+	case "text":
+		documented, total := r.counts()
+		if _, err := fmt.Fprintf(w, "Go %s release notes document %d of %d changes (%.1f%%).\n", r.Version, documented, total, r.Percent()); err != nil {
+			return err
+		}
+		for _, it := range r.Items {
+			if it.Status == relnote.Documented {
+				continue
+			}
+			fmt.Fprintf(w, "\n%s %s: %s\n", it.Kind, it.ID, it.Status)
+			if it.Title != "" {
+				fmt.Fprintf(w, "\t%s\n", it.Title)
+			}
+			if len(it.Owners) > 0 {
+				fmt.Fprintf(w, "\towners: %s\n", strings.Join(it.Owners, ", "))
+			}
+			for _, d := range it.Details {
+				fmt.Fprintf(w, "\t%s\n", d)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown coverage format %q; want text, json or html", format)
+	}
+}
+
+//go:embed coverage.html
+var coverageHTML string
+
+var coverageTmpl = template.Must(template.New("coverage").Parse(coverageHTML))
@@ -0,0 +1,95 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"golang.org/x/build/relnote"
+)
+
+func TestAPICoverageItems(t *testing.T) {
+	apiFS := fstest.MapFS{
+		"1.txt": {Data: []byte("pkg foo, func F() #1\npkg foo, type T #1\n")},
+		"2.txt": {Data: []byte("pkg bar, type T #2\n")},
+	}
+	docFS := fstest.MapFS{
+		"6-stdlib/99-minor/foo/1.md": {Data: []byte("The new [F] function returns a [T].\n")},
+	}
+	items, err := apiCoverageItems(apiFS, docFS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want 2: %+v", len(items), items)
+	}
+	if it := items[0]; it.Status != relnote.Documented || it.Fragment != "doc/next/6-stdlib/99-minor/foo/1.md" || len(it.Details) != 2 {
+		t.Errorf("items[0] = %+v, want documented foo/1.md with 2 features", it)
+	}
+	if it := items[1]; it.Status != relnote.Missing || it.URL != "https://go.dev/issue/2" || it.issue != 2 {
+		t.Errorf("items[1] = %+v, want missing bar/2.md for issue 2", it)
+	}
+}
+
+func TestCoverageCheckAgainst(t *testing.T) {
+	report := func(statuses ...relnote.CoverageStatus) *coverageReport {
+		r := &coverageReport{Version: "1.99"}
+		for i, st := range statuses {
+			r.Items = append(r.Items, coverageItem{Kind: "proposal", ID: string(rune('a' + i)), Status: st})
+		}
+		return r
+	}
+	baseline := report(relnote.Documented, relnote.Missing)
+	for _, test := range []struct {
+		name    string
+		current *coverageReport
+		wantErr string
+	}{
+		{"unchanged", report(relnote.Documented, relnote.Missing), ""},
+		{"improved", report(relnote.Documented, relnote.Documented), ""},
+		{"regressed", report(relnote.ToDo, relnote.Documented), "proposal a was documented, but is now todo"},
+		{"diluted", report(relnote.Documented, relnote.Missing, relnote.Missing), "coverage dropped from 50.0% to 33.3%"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.current.checkAgainst(baseline)
+			if test.wantErr == "" {
+				if err != nil {
+					t.Errorf("checkAgainst = %v, want nil", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("checkAgainst = %v, want error containing %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestWriteCoverage(t *testing.T) {
+	r := &coverageReport{
+		Version: "1.99",
+		Items: []coverageItem{
+			{Kind: "api", ID: "6-stdlib/99-minor/foo/1.md", Status: relnote.Documented},
+			{Kind: "relnote", ID: "CL 123", Title: "foo: add <T>", Owners: []string{"gopher@golang.org"}, Status: relnote.Missing},
+		},
+	}
+	for format, want := range map[string]string{
+		"text": "Go 1.99 release notes document 1 of 2 changes (50.0%).\n\nrelnote CL 123: missing\n\tfoo: add <T>\n\towners: gopher@golang.org\n",
+		"json": `"status": "missing"`,
+		"html": "foo: add &lt;T&gt;",
+	} {
+		var buf bytes.Buffer
+		if err := writeCoverage(&buf, r, format); err != nil {
+			t.Fatalf("writeCoverage(%s): %v", format, err)
+		}
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("writeCoverage(%s) = %q, want it to contain %q", format, buf.String(), want)
+		}
+	}
+	if err := writeCoverage(new(bytes.Buffer), r, "pdf"); err == nil {
+		t.Errorf("writeCoverage(pdf) succeeded, want error")
+	}
+}
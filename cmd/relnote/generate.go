@@ -6,6 +6,7 @@ package main
 
 import (
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -23,8 +24,10 @@ template: false
 
 // generate takes the root of the Go repo.
 // It generates release notes by combining the fragments in the doc/next directory
-// of the repo.
-func generate(version, goRoot string) error {
+// of the repo, and writes them in the given format: "markdown", "html" or "text".
+// It warns about links to standard library symbols that don't exist in the
+// repo's api directory, or fails if strict is set.
+func generate(version, goRoot, format string, strict bool) error {
 	if goRoot == "" {
 		goRoot = runtime.GOROOT()
 	}
@@ -33,9 +36,31 @@ func generate(version, goRoot string) error {
 	if err != nil {
 		return fmt.Errorf("merging %s: %v", dir, err)
 	}
-	out := markdown.ToMarkdown(doc)
-	out = fmt.Sprintf(prefixFormat, version) + out
-	outFile := fmt.Sprintf("go1.%s.md", version)
+	api, err := relnote.LoadAPI(os.DirFS(filepath.Join(goRoot, "api")))
+	if err != nil {
+		return fmt.Errorf("loading API: %v", err)
+	}
+	if err := relnote.CheckSymbolLinks(doc, api); err != nil {
+		if strict {
+			return fmt.Errorf("bad symbol links in %s:\n%v", dir, err)
+		}
+		log.Printf("warning: bad symbol links in %s:\n%v", dir, err)
+	}
+	var out, ext string
+	switch format {
+	case "", "markdown":
+		out = fmt.Sprintf(prefixFormat, version) + markdown.ToMarkdown(doc)
+		ext = "md"
+	case "html":
+		out = relnote.ToHTML(doc)
+		ext = "html"
+	case "text":
+		out = relnote.ToText(doc)
+		ext = "txt"
+	default:
+		return fmt.Errorf("unknown format %q; want markdown, html or text", format)
+	}
+	outFile := fmt.Sprintf("go1.%s.%s", version, ext)
 	if err := os.WriteFile(outFile, []byte(out), 0644); err != nil {
 		return err
 	}
@@ -22,6 +22,9 @@ var (
 	verbose    = flag.Bool("v", false, "print verbose logging")
 	goroot     = flag.String("goroot", runtime.GOROOT(), "root of Go repo containing docs")
 	todosSince = flag.String("since", "", "earliest to look for TODOs, in YYYY-MM-DD format")
+	format     = flag.String("format", "", "output format: markdown (default), html or text for generate; text (default), json or html for coverage")
+	baseline   = flag.String("baseline", "", "JSON coverage report from an earlier run; coverage fails if coverage has dropped since")
+	strict     = flag.Bool("strict", false, "make generate fail on links to symbols that aren't in the api directory, instead of warning")
 )
 
 func usage() {
@@ -31,6 +34,8 @@ func usage() {
 	fmt.Fprintf(out, "      generate release notes from doc/next\n")
 	fmt.Fprintf(out, "   relnote [flags] todo\n")
 	fmt.Fprintf(out, "      report which release notes need to be written\n")
+	fmt.Fprintf(out, "   relnote [flags] coverage\n")
+	fmt.Fprintf(out, "      report which changes are covered by release notes\n")
 	fmt.Fprintln(out)
 	flag.PrintDefaults()
 }
@@ -56,8 +61,8 @@ func main() {
 	if cmd := flag.Arg(0); cmd != "" {
 		switch cmd {
 		case "generate":
-			err = generate(version, *goroot)
-		case "todo":
+			err = generate(version, *goroot, *format, *strict)
+		case "todo", "coverage":
 			var sinceDate time.Time
 			if *todosSince != "" {
 				sinceDate, err = time.Parse(time.DateOnly, *todosSince)
@@ -65,7 +70,15 @@ func main() {
 					log.Fatalf("-since flag: %v", err)
 				}
 			}
-			err = todo(os.Stdout, *goroot, sinceDate)
+			if cmd == "todo" {
+				err = todo(os.Stdout, *goroot, sinceDate)
+			} else {
+				f := *format
+				if f == "" {
+					f = "text"
+				}
+				err = coverage(os.Stdout, *goroot, "1."+version, sinceDate, f, *baseline)
+			}
 		default:
 			err = fmt.Errorf("unknown command %q", cmd)
 		}
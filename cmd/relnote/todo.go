@@ -50,7 +50,7 @@ func todo(w io.Writer, goroot string, treeOpenDate time.Time) error {
 	var todos []ToDo
 	addToDo := func(td ToDo) { todos = append(todos, td) }
 
-	mentioned := mentioned{Issues: make(map[int]bool), CLs: make(map[int]bool)}
+	mentioned := newMentioned()
 	nextDir := filepath.Join(goroot, "doc", "next")
 	if err := infoFromDocFiles(os.DirFS(nextDir), mentioned, addToDo); err != nil {
 		return err
@@ -61,14 +61,28 @@ func todo(w io.Writer, goroot string, treeOpenDate time.Time) error {
 	return writeToDos(w, todos)
 }
 
-// mentioned collects mentions within the existing relnotes.
+// mentioned collects mentions within the existing relnotes,
+// recording the first file that mentions each issue or CL.
 type mentioned struct {
-	Issues map[int]bool
-	CLs    map[int]bool
+	Issues map[int]string
+	CLs    map[int]string
 }
 
-func (m mentioned) AddIssue(num int) { m.Issues[num] = true }
-func (m mentioned) AddCL(num int)    { m.CLs[num] = true }
+func newMentioned() mentioned {
+	return mentioned{Issues: make(map[int]string), CLs: make(map[int]string)}
+}
+
+func (m mentioned) AddIssue(num int, file string) {
+	if _, ok := m.Issues[num]; !ok {
+		m.Issues[num] = file
+	}
+}
+
+func (m mentioned) AddCL(num int, file string) {
+	if _, ok := m.CLs[num]; !ok {
+		m.CLs[num] = file
+	}
+}
 
 // findTreeOpenDate returns the time of the most recent commit to the file that
 // determines the version of Go under development.
@@ -124,7 +138,7 @@ func infoFromFile(dir fs.FS, filename string, mentioned mentioned, addToDo func(
 		if err != nil {
 			return fmt.Errorf("%s: %v", filename, err)
 		}
-		mentioned.AddIssue(num)
+		mentioned.AddIssue(num, filename)
 	}
 	f, err := dir.Open(filename)
 	if err != nil {
@@ -147,14 +161,14 @@ func infoFromFile(dir fs.FS, filename string, mentioned mentioned, addToDo func(
 			if err != nil {
 				return fmt.Errorf("%s:%d: %v", filename, ln, err)
 			}
-			mentioned.AddIssue(num)
+			mentioned.AddIssue(num, filename)
 		}
 		for _, matches := range clRE.FindAllStringSubmatch(line, -1) {
 			num, err := strconv.Atoi(matches[1])
 			if err != nil {
 				return fmt.Errorf("%s:%d: %v", filename, ln, err)
 			}
-			mentioned.AddCL(num)
+			mentioned.AddCL(num, filename)
 		}
 	}
 	return scan.Err()
@@ -175,6 +189,23 @@ func todosFromCLs(cutoff time.Time, mentioned mentioned, add func(ToDo)) error {
 		return err
 	}
 	gh := corpus.GitHub().Repo("golang", "go")
+	return forEachMergedCL(corpus, cutoff, func(cl *maintner.GerritCL) error {
+		// Add a TODO if the CL has a "RELNOTE=" comment.
+		// These are deprecated, but we look for them just in case.
+		if _, ok := matchedCLs[int(cl.Number)]; ok && mentioned.CLs[int(cl.Number)] == "" {
+			if err := todoFromRelnote(ctx, cl, gerritClient, add); err != nil {
+				return err
+			}
+		}
+		// Add a TODO if the CL refers to an accepted proposal.
+		todoFromProposal(cl, gh, mentioned.Issues, add)
+		return nil
+	})
+}
+
+// forEachMergedCL calls f for each CL of a go.googlesource.com project
+// in corpus that was merged to the master branch after cutoff.
+func forEachMergedCL(corpus *maintner.Corpus, cutoff time.Time, f func(*maintner.GerritCL) error) error {
 	return corpus.Gerrit().ForeachProjectUnsorted(func(gp *maintner.GerritProject) error {
 		if gp.Server() != "go.googlesource.com" {
 			return nil
@@ -191,16 +222,7 @@ func todosFromCLs(cutoff time.Time, mentioned mentioned, add func(ToDo)) error {
 				// Was in a previous release; not for this one.
 				return nil
 			}
-			// Add a TODO if the CL has a "RELNOTE=" comment.
-			// These are deprecated, but we look for them just in case.
-			if _, ok := matchedCLs[int(cl.Number)]; ok && !mentioned.CLs[int(cl.Number)] {
-				if err := todoFromRelnote(ctx, cl, gerritClient, add); err != nil {
-					return err
-				}
-			}
-			// Add a TODO if the CL refers to an accepted proposal.
-			todoFromProposal(cl, gh, mentioned.Issues, add)
-			return nil
+			return f(cl)
 		})
 	})
 }
@@ -227,9 +249,9 @@ func todoFromRelnote(ctx context.Context, cl *maintner.GerritCL, gc *gerrit.Clie
 	return nil
 }
 
-func todoFromProposal(cl *maintner.GerritCL, gh *maintner.GitHubRepo, mentionedIssues map[int]bool, add func(ToDo)) {
+func todoFromProposal(cl *maintner.GerritCL, gh *maintner.GitHubRepo, mentionedIssues map[int]string, add func(ToDo)) {
 	for _, num := range issueNumbers(cl) {
-		if mentionedIssues[num] {
+		if mentionedIssues[num] != "" {
 			continue
 		}
 		if issue := gh.Issue(int32(num)); issue != nil && hasLabel(issue, "Proposal-Accepted") {
@@ -65,6 +65,7 @@ var (
 	servingFilesBase = flag.String("serving-files-base", "", "Storage for serving files. gs://bucket/path or file:///path/to/serving.")
 	edgeCacheURL     = flag.String("edge-cache-url", "", "URL release files appear at when published to the CDN, e.g. https://dl.google.com/go.")
 	websiteUploadURL = flag.String("website-upload-url", "", "URL to POST website file data to, e.g. https://go.dev/dl/upload.")
+	linuxPackages    = flag.Bool("linux-packages", false, "Build .deb and .rpm packages for Linux release targets alongside the archives.")
 
 	cloudBuildProject = flag.String("cloud-build-project", "", "GCP project to run miscellaneous Cloud Build tasks")
 	cloudBuildAccount = flag.String("cloud-build-account", "", "Service account to run miscellaneous Cloud Build tasks")
@@ -124,13 +125,14 @@ func main() {
 		return
 	}
 
-	var dbPool db.PGDBTX
-	dbPool, err := pgxpool.Connect(ctx, *pgConnect)
+	pool, err := pgxpool.Connect(ctx, *pgConnect)
 	if err != nil {
 		log.Fatalln("pgxpool.Connect:", err)
 	}
-	defer dbPool.Close()
-	dbPool = &relui.MetricsDB{PGDBTX: dbPool}
+	defer pool.Close()
+	var dbPool db.PGDBTX = &relui.MetricsDB{PGDBTX: pool}
+	events := relui.NewEventBroker()
+	go events.Run(ctx, pool)
 
 	// Define the site header and external service configuration.
 	// The site header communicates to humans what will happen
@@ -302,7 +304,9 @@ func main() {
 		PublishFile: func(f task.WebsiteFile) error {
 			return publishFile(*websiteUploadURL, userPassAuth, f)
 		},
-		ApproveAction: relui.ApproveActionDep(dbPool),
+		ApproveAction:        relui.ApproveActionDep(dbPool),
+		ApprovePublishAction: relui.ApproveActionDepWithRule(dbPool, relui.PublishApprovalRule),
+		LinuxPackages:        *linuxPackages,
 	}
 	githubHTTPClient := oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: *githubToken}))
 	githubClient := &task.GitHubClient{
@@ -463,7 +467,7 @@ func main() {
 	} else {
 		criaDB = criadb.NewDevDatabase()
 	}
-	var h http.Handler = relui.NewServer(dbPool, w, base, siteHeader, ms, criaDB)
+	var h http.Handler = relui.NewServer(dbPool, w, base, siteHeader, ms, criaDB, events)
 	if prod {
 		iapAudience := buildenv.Production.IAPServiceAudience("relui-internal")
 		h = access.RequireIAPAuthHandler(h, iapAudience)
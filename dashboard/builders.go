@@ -7,6 +7,7 @@
 package dashboard
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"slices"
@@ -121,6 +122,11 @@ var slowBotAliases = map[string]string{
 // Initialization happens below, via calls to addBuilder.
 var Builders = map[string]*BuildConfig{}
 
+// definedBuilders are the builders in Builders as they're defined,
+// before addBuilder stops the ones that no longer run in the coordinator.
+// Lint checks them, since their policies are still maintained.
+var definedBuilders = map[string]*BuildConfig{}
+
 // GoBootstrap is the bootstrap Go version.
 //
 // For bootstrap versions Go 1.21.0 and newer,
@@ -324,26 +330,6 @@ var Hosts = map[string]*HostConfig{
 		ContainerImage: "linux-x86-sid:latest",
 		SSHUsername:    "root",
 	},
-	"host-linux-amd64-wasip1-wasm-wasmedge": {
-		Notes:          "Container with wasmedge for testing wasip1/wasm.",
-		ContainerImage: "wasip1-wasm-wasmedge:latest",
-		SSHUsername:    "root",
-	},
-	"host-linux-amd64-wasip1-wasm-wasmer": {
-		Notes:          "Container with wasmer for testing wasip1/wasm.",
-		ContainerImage: "wasip1-wasm-wasmer:latest",
-		SSHUsername:    "root",
-	},
-	"host-linux-amd64-wasip1-wasm-wasmtime": {
-		Notes:          "Container with wasmtime for testing wasip1/wasm.",
-		ContainerImage: "wasip1-wasm-wasmtime:latest",
-		SSHUsername:    "root",
-	},
-	"host-linux-amd64-wasip1-wasm-wazero": {
-		Notes:          "Container with Wazero for testing wasip1/wasm.",
-		ContainerImage: "wasip1-wasm-wazero:latest",
-		SSHUsername:    "root",
-	},
 	"host-linux-amd64-wsl": {
 		Notes:     "Windows 10 WSL2 Ubuntu",
 		Owners:    []*gophers.Person{gh("mengzhuo")},
@@ -584,42 +570,51 @@ func gh(githubUsername string) *gophers.Person {
 
 func init() {
 	for key, c := range Hosts {
-		if key == "" {
-			panic("empty string key in Hosts")
+		if err := c.normalize(key); err != nil {
+			panic(err)
 		}
-		if c.HostType == "" {
-			c.HostType = key
-		}
-		if c.HostType != key {
-			panic(fmt.Sprintf("HostType %q != key %q", c.HostType, key))
-		}
-		if c.HostArch == "" {
-			f := strings.Split(c.HostType, "-")
-			if len(f) < 3 {
-				panic(fmt.Sprintf("invalid HostType %q", c.HostType))
-			}
-			c.HostArch = f[1] + "-" + f[2] // "linux-amd64"
-			if f[2] == "arm" {
-				c.HostArch += "-7" // assume newer ARM
-			}
-		}
-		if c.GoBootstrap == "" {
-			c.GoBootstrap = GoBootstrap
-		}
-		nSet := 0
-		if c.VMImage != "" {
-			nSet++
-		}
-		if c.ContainerImage != "" && !c.IsEC2 {
-			nSet++
-		}
-		if c.IsReverse {
-			nSet++
+	}
+}
+
+// normalize fills in the defaults of the fields of c, whose key in
+// Hosts is key. It returns an error if the fields are inconsistent.
+func (c *HostConfig) normalize(key string) error {
+	if key == "" {
+		return errors.New("empty string key in Hosts")
+	}
+	if c.HostType == "" {
+		c.HostType = key
+	}
+	if c.HostType != key {
+		return fmt.Errorf("HostType %q != key %q", c.HostType, key)
+	}
+	if c.HostArch == "" {
+		f := strings.Split(c.HostType, "-")
+		if len(f) < 3 {
+			return fmt.Errorf("invalid HostType %q", c.HostType)
 		}
-		if nSet != 1 {
-			panic(fmt.Sprintf("exactly one of VMImage, ContainerImage, IsReverse must be set for host %q; got %v", key, nSet))
+		c.HostArch = f[1] + "-" + f[2] // "linux-amd64"
+		if f[2] == "arm" {
+			c.HostArch += "-7" // assume newer ARM
 		}
 	}
+	if c.GoBootstrap == "" {
+		c.GoBootstrap = GoBootstrap
+	}
+	nSet := 0
+	if c.VMImage != "" {
+		nSet++
+	}
+	if c.ContainerImage != "" && !c.IsEC2 {
+		nSet++
+	}
+	if c.IsReverse {
+		nSet++
+	}
+	if nSet != 1 {
+		return fmt.Errorf("exactly one of VMImage, ContainerImage, IsReverse must be set for host %q; got %v", key, nSet)
+	}
+	return nil
 }
 
 // CosArch defines the different COS images types used.
@@ -876,6 +871,11 @@ type BuildConfig struct {
 
 	// isRestricted marks if a builder should be restricted to a subset of users.
 	isRestricted bool
+
+	// policyDesc optionally describes the tryBot, buildsRepo, and
+	// distTestAdjust policies, keyed by field name, for Explain.
+	// See the policyRef method.
+	policyDesc map[string]string
 }
 
 // Env returns the environment variables this builder should run with.
@@ -1146,17 +1146,36 @@ func (c *BuildConfig) PrivateGoProxy() bool { return c.privateGoProxy }
 // ("master", "release-branch.go1.12") as a post-submit build
 // that shows up on https://build.golang.org/.
 func (c *BuildConfig) BuildsRepoPostSubmit(repo, branch, goBranch string) bool {
+	return c.decidePostSubmit(repo, branch, goBranch, nil)
+}
+
+// decidePostSubmit implements BuildsRepoPostSubmit,
+// recording the steps of the decision in t.
+func (c *BuildConfig) decidePostSubmit(repo, branch, goBranch string, t *trace) bool {
 	if c.tryOnly {
-		return false
+		return t.step("tryOnly", false, "the builder only does trybot builds")
 	}
-	return c.buildsRepoAtAll(repo, branch, goBranch)
+	return c.decideBuildsRepo(repo, branch, goBranch, t)
 }
 
 // BuildsRepoTryBot reports whether the build configuration type c
 // should build the given repo ("go", "net", etc) and branch
 // ("master", "release-branch.go1.12") as a trybot.
 func (c *BuildConfig) BuildsRepoTryBot(repo, branch, goBranch string) bool {
-	return c.tryBot != nil && c.tryBot(repo, branch, goBranch) && c.buildsRepoAtAll(repo, branch, goBranch)
+	return c.decideTryBot(repo, branch, goBranch, nil)
+}
+
+// decideTryBot implements BuildsRepoTryBot,
+// recording the steps of the decision in t.
+func (c *BuildConfig) decideTryBot(repo, branch, goBranch string, t *trace) bool {
+	if c.tryBot == nil {
+		return t.step("tryBot", false, "the builder has no trybot policy")
+	}
+	if !c.tryBot(repo, branch, goBranch) {
+		return t.step("tryBot", false, "trybot policy %s denies %s", c.policyRef("tryBot", c.tryBot), repo)
+	}
+	t.step("tryBot", true, "trybot policy %s allows %s", c.policyRef("tryBot", c.tryBot), repo)
+	return c.decideBuildsRepo(repo, branch, goBranch, t)
 }
 
 // ShouldRunDistTest reports whether the named cmd/dist test should be
@@ -1175,6 +1194,12 @@ func (c *BuildConfig) BuildsRepoTryBot(repo, branch, goBranch string) bool {
 // since both the test and its skips would be in one repository rather than two,
 // and having effect when tests are run locally by developers.
 func (c *BuildConfig) ShouldRunDistTest(distTest string, isNormalTry bool) bool {
+	return c.decideDistTest(distTest, isNormalTry, nil)
+}
+
+// decideDistTest implements ShouldRunDistTest,
+// recording the steps of the decision in t.
+func (c *BuildConfig) decideDistTest(distTest string, isNormalTry bool, t *trace) bool {
 	run := true
 
 	// This section implements the default cmd/dist test policy.
@@ -1184,14 +1209,22 @@ func (c *BuildConfig) ShouldRunDistTest(distTest string, isNormalTry bool) bool
 		fastestBuilder := c.Name == "linux-amd64"
 		if slowPortableTest && !fastestBuilder {
 			// Don't run the test on this builder.
-			run = false
+			run = t.step("default", false, "slow portable test %s only runs on linux-amd64 in normal trybot builds", distTest)
 		}
 	}
+	if run {
+		t.step("default", true, "the default dist test policy runs %s", distTest)
+	}
 
 	// Individual builders have historically sometimes adjusted the cmd/dist test policy.
 	// Over time these can migrate to better ways of doing platform-based or speed-based test skips.
 	if c.distTestAdjust != nil {
-		run = c.distTestAdjust(run, distTest, isNormalTry)
+		adjusted := c.distTestAdjust(run, distTest, isNormalTry)
+		verb := "keeps"
+		if adjusted != run {
+			verb = "changes"
+		}
+		run = t.step("distTestAdjust", adjusted, "dist test policy %s %s the decision", c.policyRef("distTestAdjust", c.distTestAdjust), verb)
 	}
 
 	return run
@@ -1207,6 +1240,12 @@ func (c *BuildConfig) ShouldRunDistTest(distTest string, isNormalTry bool) bool
 // branch of Go itself. It's required if repo != "go". When repo ==
 // "go", the goBranch defaults to the value of branch.
 func (c *BuildConfig) buildsRepoAtAll(repo, branch, goBranch string) bool {
+	return c.decideBuildsRepo(repo, branch, goBranch, nil)
+}
+
+// decideBuildsRepo implements buildsRepoAtAll,
+// recording the steps of the decision in t.
+func (c *BuildConfig) decideBuildsRepo(repo, branch, goBranch string, t *trace) bool {
 	if goBranch == "" {
 		if repo == "go" {
 			goBranch = branch
@@ -1222,36 +1261,49 @@ func (c *BuildConfig) buildsRepoAtAll(repo, branch, goBranch string) bool {
 	}
 	// Don't build old branches.
 	const minGo1x = 11
-	for _, b := range []string{branch, goBranch} {
+	for i, b := range []string{branch, goBranch} {
+		if i == 1 && b == branch {
+			break // already checked
+		}
 		if bmaj, bmin, ok := version.ParseReleaseBranch(b); ok {
 			if bmaj != 1 || bmin < minGo1x {
-				return false
+				return t.step("releaseBranch", false, "%s is older than Go 1.%d", b, minGo1x)
 			}
 			bmm := types.MajorMinor{Major: bmaj, Minor: bmin}
 			if bmm.Less(c.MinimumGoVersion) {
-				return false
+				return t.step("releaseBranch", false, "%s is older than the builder's minimum Go version, %d.%d", b, c.MinimumGoVersion.Major, c.MinimumGoVersion.Minor)
 			}
 			if repo == "exp" {
 				// Don't test exp against release branches; it's experimental.
-				return false
+				return t.step("releaseBranch", false, "exp isn't tested against release branches")
 			}
 			if repo == "pkgsite" && bmm.Less(types.MajorMinor{Major: 1, Minor: 23}) {
 				// x/pkgsite started requiring Go 1.23 sooner. See CL 609142.
-				return false
+				return t.step("releaseBranch", false, "pkgsite requires Go 1.23 or newer")
 			}
+			t.step("releaseBranch", true, "%s is a supported release branch", b)
 		}
 	}
 
 	// Build dev.boringcrypto branches only on linux/amd64 and windows/386 (see go.dev/issue/26791).
 	if repo == "go" && (branch == "dev.boringcrypto" || strings.HasPrefix(branch, "dev.boringcrypto.")) {
 		if c.Name != "linux-amd64" && !strings.HasPrefix(c.Name, "windows-386") {
-			return false
+			return t.step("boringcrypto", false, "%s is only built on linux-amd64 and windows-386", branch)
 		}
+		t.step("boringcrypto", true, "%s is built on %s", branch, c.Name)
 	}
 	if p := c.buildsRepo; p != nil {
-		return p(repo, branch, goBranch)
+		b := p(repo, branch, goBranch)
+		verb := "denies"
+		if b {
+			verb = "allows"
+		}
+		return t.step("buildsRepo", b, "repo policy %s %s %s", c.policyRef("buildsRepo", p), verb, repo)
+	}
+	if !buildRepoByDefault(repo) {
+		return t.step("buildsRepo", false, "%s isn't built by default", repo)
 	}
-	return buildRepoByDefault(repo)
+	return t.step("buildsRepo", true, "%s is built by default", repo)
 }
 
 // buildRepoByDefault reports whether builders should do builds
@@ -2783,136 +2835,84 @@ func init() {
 		RunBench:     true,
 		SkipSnapshot: true,
 	})
-	addBuilder(BuildConfig{
-		Name:       "wasip1-wasm-wazero",
-		HostType:   "host-linux-amd64-wasip1-wasm-wazero",
-		buildsRepo: wasip1Default,
-		distTestAdjust: func(run bool, distTest string, isNormalTry bool) bool {
-			if isNormalTry && (strings.Contains(distTest, "/internal/") || distTest == "reboot") {
-				// Skip some tests in an attempt to speed up normal trybots, inherited from CL 121938.
-				run = false
-			}
-			return run
-		},
-		numTryTestHelpers: 3,
-		env: []string{
-			"GOOS=wasip1", "GOARCH=wasm", "GOHOSTOS=linux", "GOHOSTARCH=amd64",
-			"PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin:/workdir/go/misc/wasm",
-			"GO_DISABLE_OUTBOUND_NETWORK=1", "GOWASIRUNTIME=wazero",
-		},
-	})
-	addBuilder(BuildConfig{
-		Name:       "wasip1-wasm-wasmtime",
-		HostType:   "host-linux-amd64-wasip1-wasm-wasmtime",
-		tryBot:     explicitTrySet("go"),
-		buildsRepo: wasip1Default,
-		distTestAdjust: func(run bool, distTest string, isNormalTry bool) bool {
-			if isNormalTry && (strings.Contains(distTest, "/internal/") || distTest == "reboot") {
-				// Skip some tests in an attempt to speed up normal trybots, inherited from CL 121938.
-				run = false
-			}
-			return run
-		},
-		numTryTestHelpers: 3,
-		env: []string{
-			"GOOS=wasip1", "GOARCH=wasm", "GOHOSTOS=linux", "GOHOSTARCH=amd64",
-			"PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin:/workdir/go/misc/wasm",
-			"GO_DISABLE_OUTBOUND_NETWORK=1", "GOWASIRUNTIME=wasmtime",
-		},
-	})
-	addBuilder(BuildConfig{
-		Name:        "wasip1-wasm-wasmer",
-		HostType:    "host-linux-amd64-wasip1-wasm-wasmer",
-		KnownIssues: []int{59907},
-		buildsRepo:  wasip1Default,
-		distTestAdjust: func(run bool, distTest string, isNormalTry bool) bool {
-			if isNormalTry && (strings.Contains(distTest, "/internal/") || distTest == "reboot") {
-				// Skip some tests in an attempt to speed up normal trybots, inherited from CL 121938.
-				run = false
-			}
-			return run
-		},
-		numTryTestHelpers: 3,
-		env: []string{
-			"GOOS=wasip1", "GOARCH=wasm", "GOHOSTOS=linux", "GOHOSTARCH=amd64",
-			"PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin:/workdir/go/misc/wasm",
-			"GO_DISABLE_OUTBOUND_NETWORK=1", "GOWASIRUNTIME=wasmer",
-		},
-	})
-	addBuilder(BuildConfig{
-		Name:        "wasip1-wasm-wasmedge",
-		HostType:    "host-linux-amd64-wasip1-wasm-wasmedge",
-		KnownIssues: []int{60097},
-		buildsRepo:  wasip1Default,
-		distTestAdjust: func(run bool, distTest string, isNormalTry bool) bool {
-			if isNormalTry && (strings.Contains(distTest, "/internal/") || distTest == "reboot") {
-				// Skip some tests in an attempt to speed up normal trybots, inherited from CL 121938.
-				run = false
-			}
-			return run
-		},
-		numTryTestHelpers: 3,
-		env: []string{
-			"GOOS=wasip1", "GOARCH=wasm", "GOHOSTOS=linux", "GOHOSTARCH=amd64",
-			"PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin:/workdir/go/misc/wasm",
-			"GO_DISABLE_OUTBOUND_NETWORK=1", "GOWASIRUNTIME=wasmedge",
-		},
-	})
+
+	// More hosts and builders are defined in builders.yaml.
+	c, err := ParseConfig(builtinConfig)
+	if err != nil {
+		panic(err)
+	}
+	builtin = c
+	addConfig(builtin)
 }
 
 // addBuilder adds c to the Builders map after doing some checks.
 func addBuilder(c BuildConfig) {
+	if _, dup := Builders[c.Name]; dup {
+		panic("dup name " + c.Name)
+	}
+	if err := c.check(Hosts[c.HostType]); err != nil {
+		panic(err)
+	}
+
+	definedBuilders[c.Name] = new(c)
+
+	if migration.BuildersPortedToLUCI[c.Name] && migration.StopPortedBuilder {
+		c.buildsRepo = func(_, _, _ string) bool { return false }
+		c.setPolicyDesc("buildsRepo", "(stopped in the coordinator: ported to LUCI)")
+		c.Notes = "Unavailable in the coordinator. Use LUCI (https://go.dev/wiki/LUCI) instead."
+	} else if migration.StopAllLegacyBuilders {
+		c.buildsRepo = func(_, _, _ string) bool { return false }
+		c.setPolicyDesc("buildsRepo", "(stopped in the coordinator: all legacy builders are stopped)")
+		c.Notes = "Unavailable in the coordinator. Look for a tracking issue to add this builder to LUCI (https://go.dev/wiki/LUCI) instead."
+	}
+
+	Builders[c.Name] = &c
+}
+
+// check returns an error if the fields of c are inconsistent.
+// The hc argument is the HostConfig of c.HostType, or nil if it's undefined.
+func (c *BuildConfig) check(hc *HostConfig) error {
 	if c.Name == "" {
-		panic("empty name")
+		return errors.New("empty name")
 	}
 	if c.HostType == "" {
-		panic(fmt.Sprintf("missing HostType for builder %q", c.Name))
+		return fmt.Errorf("missing HostType for builder %q", c.Name)
 	}
-	if _, dup := Builders[c.Name]; dup {
-		panic("dup name " + c.Name)
+	if hc == nil {
+		return fmt.Errorf("undefined HostType %q for builder %q", c.HostType, c.Name)
 	}
-	if c.HostConfig().GoogleReverse && !c.IsReverse() {
-		panic("GoogleReverse is set but the builder isn't reverse")
-	}
-	if _, ok := Hosts[c.HostType]; !ok {
-		panic(fmt.Sprintf("undefined HostType %q for builder %q", c.HostType, c.Name))
+	if hc.GoogleReverse && !hc.IsReverse {
+		return errors.New("GoogleReverse is set but the builder isn't reverse")
 	}
 	if c.SkipSnapshot && (c.numTestHelpers > 0 || c.numTryTestHelpers > 0) {
-		panic(fmt.Sprintf("config %q's SkipSnapshot is not compatible with sharded test helpers", c.Name))
+		return fmt.Errorf("config %q's SkipSnapshot is not compatible with sharded test helpers", c.Name)
 	}
 	for i, issue := range c.KnownIssues {
 		if issue == 0 {
-			panic(fmt.Errorf("config %q's KnownIssues slice has a zero issue at index %d", c.Name, i))
+			return fmt.Errorf("config %q's KnownIssues slice has a zero issue at index %d", c.Name, i)
 		}
 	}
 
 	types := 0
-	for _, fn := range []func() bool{c.IsReverse, c.IsContainer, c.IsVM} {
-		if fn() {
+	for _, b := range []bool{hc.IsReverse, hc.IsContainer(), hc.IsVM()} {
+		if b {
 			types++
 		}
 	}
 	if types != 1 {
-		panic(fmt.Sprintf("build config %q host type inconsistent (must be Reverse, Image, or VM)", c.Name))
-	}
-
-	if migration.BuildersPortedToLUCI[c.Name] && migration.StopPortedBuilder {
-		c.buildsRepo = func(_, _, _ string) bool { return false }
-		c.Notes = "Unavailable in the coordinator. Use LUCI (https://go.dev/wiki/LUCI) instead."
-	} else if migration.StopAllLegacyBuilders {
-		c.buildsRepo = func(_, _, _ string) bool { return false }
-		c.Notes = "Unavailable in the coordinator. Look for a tracking issue to add this builder to LUCI (https://go.dev/wiki/LUCI) instead."
+		return fmt.Errorf("build config %q host type inconsistent (must be Reverse, Image, or VM)", c.Name)
 	}
-
-	Builders[c.Name] = &c
+	return nil
 }
 
 // TestingKnobForceEnableLinuxAMD64 is a helper intended to be used in tests
 // that need to force the linux-amd64 builder to build all repositories.
 func TestingKnobForceEnableLinuxAMD64() (cleanup func()) {
-	old := Builders["linux-amd64"].buildsRepo
-	Builders["linux-amd64"].buildsRepo = func(_, _, _ string) bool { return true }
-	return func() { Builders["linux-amd64"].buildsRepo = old }
+	c := Builders["linux-amd64"]
+	old, oldDesc := c.buildsRepo, c.policyDesc
+	c.buildsRepo = func(_, _, _ string) bool { return true }
+	c.setPolicyDesc("buildsRepo", "(forced on for testing)")
+	return func() { c.buildsRepo, c.policyDesc = old, oldDesc }
 }
 
 // fasterTrybots is a distTestAdjust policy function.
@@ -3062,21 +3062,6 @@ func plan9Default(repo, branch, goBranch string) bool {
 	}
 }
 
-// wasip1Default returns whether we should build the repo and branch on wasip1.
-func wasip1Default(repo, branch, goBranch string) bool {
-	b := buildRepoByDefault(repo) && atLeastGo1(goBranch, 21)
-	switch repo {
-	case "benchmarks", "debug", "perf", "pkgsite", "talks", "tools", "tour", "website":
-		// Don't test these golang.org/x repos.
-		b = false
-	}
-	if repo != "go" && !(branch == "master" && goBranch == "master") {
-		// For golang.org/x repos, don't test non-latest versions.
-		b = false
-	}
-	return b
-}
-
 // disabledBuilder is a buildsRepo policy function that always return false.
 func disabledBuilder(repo, branch, goBranch string) bool { return false }
 
@@ -0,0 +1,382 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dashboard
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/build/internal/gophers"
+	"golang.org/x/build/types"
+	"gopkg.in/yaml.v3"
+)
+
+// builtinConfig holds the hosts and builders that are defined in data
+// rather than in Go. They're added to Hosts and Builders by the init
+// func that adds the other builders.
+//
+//go:embed builders.yaml
+var builtinConfig []byte
+
+// builtin is builtinConfig parsed.
+var builtin *Config
+
+// A Config is a set of host and builder configurations
+// parsed from a configuration file.
+type Config struct {
+	Hosts    map[string]*HostConfig // keyed by HostType
+	Builders []*BuildConfig         // in the order of the file
+
+	repoPolicies     map[string]*policy
+	distTestPolicies map[string]*policy
+	policies         map[string]builderPolicies // keyed by builder name
+}
+
+// builderPolicies are the compiled policies of a builder,
+// which are nil if unset.
+type builderPolicies struct {
+	buildsRepo, tryBot, distTestAdjust *policy
+}
+
+// configFile is the format of a configuration file, in YAML.
+//
+// Repo policies and dist test policies are expressions in the
+// language described at the top of policy.go. The named policies
+// in RepoPolicies and DistTestPolicies can be used by name in
+// the policies of builders and in each other.
+type configFile struct {
+	RepoPolicies     map[string]string   `yaml:"repo_policies"`
+	DistTestPolicies map[string]string   `yaml:"dist_test_policies"`
+	Hosts            map[string]hostFile `yaml:"hosts"` // keyed by HostType
+	Builders         []builderFile       `yaml:"builders"`
+}
+
+// hostFile is the format of a host in a configuration file.
+// Its fields are those of HostConfig, except as noted.
+type hostFile struct {
+	Notes               string   `yaml:"notes"`
+	Owners              []string `yaml:"owners"` // GitHub usernames
+	HostArch            string   `yaml:"host_arch"`
+	GoBootstrap         string   `yaml:"go_bootstrap"`
+	VMImage             string   `yaml:"vm_image"`
+	ContainerImage      string   `yaml:"container_image"`
+	IsReverse           bool     `yaml:"reverse"`
+	MachineType         string   `yaml:"machine_type"`
+	RegularDisk         bool     `yaml:"regular_disk"`
+	MinCPUPlatform      string   `yaml:"min_cpu_platform"`
+	CosArchitecture     string   `yaml:"cos_architecture"` // "amd64" or "arm64"
+	IsEC2               bool     `yaml:"ec2"`
+	CustomDeleteTimeout string   `yaml:"custom_delete_timeout"` // as accepted by time.ParseDuration
+	ExpectNum           int      `yaml:"expect_num"`
+	HermeticReverse     bool     `yaml:"hermetic_reverse"`
+	GoogleReverse       bool     `yaml:"google_reverse"`
+	NestedVirt          bool     `yaml:"nested_virt"`
+	KonletVMImage       string   `yaml:"konlet_vm_image"`
+	Env                 []string `yaml:"env"`
+	SSHUsername         string   `yaml:"ssh_username"`
+	RootDriveSizeGB     int64    `yaml:"root_drive_size_gb"`
+}
+
+// builderFile is the format of a builder in a configuration file.
+// Its fields are those of BuildConfig, except as noted.
+type builderFile struct {
+	Name        string `yaml:"name"`
+	HostType    string `yaml:"host_type"`
+	Notes       string `yaml:"notes"`
+	KnownIssues []int  `yaml:"known_issues"`
+
+	// TryBot is a repo policy. If empty, the builder isn't a trybot.
+	TryBot  string `yaml:"try_bot"`
+	TryOnly bool   `yaml:"try_only"`
+
+	// BuildsRepo is a repo policy.
+	// If empty, the builder builds the repos that buildRepoByDefault reports.
+	BuildsRepo string `yaml:"builds_repo"`
+
+	// DistTestAdjust is a dist test policy. If empty, the builder
+	// uses the default cmd/dist test policy.
+	DistTestAdjust string `yaml:"dist_test_adjust"`
+
+	CompileOnly         bool     `yaml:"compile_only"`
+	FlakyNet            bool     `yaml:"flaky_net"`
+	RunBench            bool     `yaml:"run_bench"`
+	MinimumGoVersion    string   `yaml:"minimum_go_version"` // such as "1.21"
+	SkipSnapshot        bool     `yaml:"skip_snapshot"`
+	StopAfterMake       bool     `yaml:"stop_after_make"`
+	PrivateGoProxy      bool     `yaml:"private_go_proxy"`
+	InstallRacePackages []string `yaml:"install_race_packages"`
+	GoDeps              []string `yaml:"go_deps"`
+	NumTestHelpers      int      `yaml:"num_test_helpers"`
+	NumTryTestHelpers   int      `yaml:"num_try_test_helpers"`
+	Env                 []string `yaml:"env"`
+	MakeScriptArgs      []string `yaml:"make_script_args"`
+	AllScriptArgs       []string `yaml:"all_script_args"`
+	Restricted          bool     `yaml:"restricted"`
+}
+
+var (
+	builderNameRE = regexp.MustCompile(`^[a-z0-9]+-[a-z0-9]+(-[a-zA-Z0-9_.]+)*$`)
+	goDepRE       = regexp.MustCompile(`^[0-9a-f]{40}$`)
+)
+
+// ParseConfig parses a configuration in YAML form.
+//
+// Builders may use the hosts in the configuration and those in Hosts.
+// ParseConfig doesn't add the hosts and builders to Hosts and Builders.
+func ParseConfig(data []byte) (*Config, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var f configFile
+	if err := dec.Decode(&f); err != nil {
+		return nil, fmt.Errorf("dashboard: parsing config: %v", err)
+	}
+
+	c := &Config{
+		Hosts:    make(map[string]*HostConfig),
+		policies: make(map[string]builderPolicies),
+	}
+	var errs []error
+	var err error
+	c.repoPolicies, err = compilePolicies(repoPolicy, f.RepoPolicies)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("repo_policies: %v", err))
+	}
+	c.distTestPolicies, err = compilePolicies(distTestPolicy, f.DistTestPolicies)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("dist_test_policies: %v", err))
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(f.Hosts)) {
+		hc, err := f.Hosts[key].hostConfig(key)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("hosts: %s: %v", key, err))
+			continue
+		}
+		c.Hosts[key] = hc
+	}
+
+	seen := make(map[string]bool)
+	for i, b := range f.Builders {
+		name := b.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i)
+		}
+		if seen[b.Name] {
+			errs = append(errs, fmt.Errorf("builders: %s: duplicate builder", name))
+			continue
+		}
+		seen[b.Name] = true
+		bc, ps, err := b.buildConfig(c)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("builders: %s: %v", name, err))
+			continue
+		}
+		c.Builders = append(c.Builders, bc)
+		c.policies[bc.Name] = ps
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("dashboard: config: %w", err)
+	}
+	return c, nil
+}
+
+// LoadConfig reads and parses the named configuration file.
+// If file is empty, it returns the built-in configuration,
+// from builders.yaml.
+func LoadConfig(file string) (*Config, error) {
+	if file == "" {
+		return builtin, nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	c, err := ParseConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	return c, nil
+}
+
+// addConfig adds the hosts and builders of c to Hosts and Builders.
+func addConfig(c *Config) {
+	for key, hc := range c.Hosts {
+		if _, dup := Hosts[key]; dup {
+			panic("dup host " + key)
+		}
+		Hosts[key] = hc
+	}
+	for _, bc := range c.Builders {
+		addBuilder(*bc)
+	}
+}
+
+// host returns the HostConfig for hostType, from c or from Hosts.
+func (c *Config) host(hostType string) *HostConfig {
+	if hc, ok := c.Hosts[hostType]; ok {
+		return hc
+	}
+	return Hosts[hostType]
+}
+
+// hostConfig returns the HostConfig for h, whose HostType is key.
+func (h hostFile) hostConfig(key string) (*HostConfig, error) {
+	hc := &HostConfig{
+		HostArch:        h.HostArch,
+		GoBootstrap:     h.GoBootstrap,
+		VMImage:         h.VMImage,
+		ContainerImage:  h.ContainerImage,
+		IsReverse:       h.IsReverse,
+		machineType:     h.MachineType,
+		RegularDisk:     h.RegularDisk,
+		MinCPUPlatform:  h.MinCPUPlatform,
+		IsEC2:           h.IsEC2,
+		ExpectNum:       h.ExpectNum,
+		HermeticReverse: h.HermeticReverse,
+		GoogleReverse:   h.GoogleReverse,
+		NestedVirt:      h.NestedVirt,
+		KonletVMImage:   h.KonletVMImage,
+		env:             h.Env,
+		Notes:           h.Notes,
+		SSHUsername:     h.SSHUsername,
+		RootDriveSizeGB: h.RootDriveSizeGB,
+	}
+	if h.HostArch != "" && strings.Count(h.HostArch, "-") < 1 {
+		return nil, fmt.Errorf("host_arch %q isn't of the form GOOS-GOARCH or GOOS-GOARCH-suffix", h.HostArch)
+	}
+	switch h.CosArchitecture {
+	case "":
+	case "amd64":
+		hc.cosArchitecture = CosArchAMD64
+	case "arm64":
+		hc.cosArchitecture = CosArchARM64
+	default:
+		return nil, fmt.Errorf("cos_architecture %q isn't amd64 or arm64", h.CosArchitecture)
+	}
+	if h.CustomDeleteTimeout != "" {
+		d, err := time.ParseDuration(h.CustomDeleteTimeout)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("custom_delete_timeout %q isn't a positive duration", h.CustomDeleteTimeout)
+		}
+		hc.CustomDeleteTimeout = d
+	}
+	if h.ExpectNum < 0 || h.RootDriveSizeGB < 0 {
+		return nil, errors.New("expect_num and root_drive_size_gb can't be negative")
+	}
+	if err := checkEnv(h.Env); err != nil {
+		return nil, err
+	}
+	for _, username := range h.Owners {
+		p := gophers.GetPerson("@" + username)
+		if p == nil {
+			return nil, fmt.Errorf("owner with GitHub username %s does not exist in the golang.org/x/build/internal/gophers package", username)
+		}
+		hc.Owners = append(hc.Owners, p)
+	}
+	if err := hc.normalize(key); err != nil {
+		return nil, err
+	}
+	return hc, nil
+}
+
+// buildConfig returns the BuildConfig for b, which is in c,
+// and its compiled policies.
+func (b builderFile) buildConfig(c *Config) (*BuildConfig, builderPolicies, error) {
+	var ps builderPolicies
+	bc := &BuildConfig{
+		Name:                b.Name,
+		HostType:            b.HostType,
+		KnownIssues:         b.KnownIssues,
+		Notes:               b.Notes,
+		tryOnly:             b.TryOnly,
+		CompileOnly:         b.CompileOnly,
+		FlakyNet:            b.FlakyNet,
+		RunBench:            b.RunBench,
+		SkipSnapshot:        b.SkipSnapshot,
+		StopAfterMake:       b.StopAfterMake,
+		privateGoProxy:      b.PrivateGoProxy,
+		InstallRacePackages: b.InstallRacePackages,
+		GoDeps:              b.GoDeps,
+		numTestHelpers:      b.NumTestHelpers,
+		numTryTestHelpers:   b.NumTryTestHelpers,
+		env:                 b.Env,
+		makeScriptArgs:      b.MakeScriptArgs,
+		allScriptArgs:       b.AllScriptArgs,
+		isRestricted:        b.Restricted,
+	}
+	if !builderNameRE.MatchString(b.Name) {
+		return nil, ps, fmt.Errorf("name %q isn't of the form GOOS-GOARCH or GOOS-GOARCH-suffix", b.Name)
+	}
+	if err := bc.check(c.host(b.HostType)); err != nil {
+		return nil, ps, err
+	}
+	if b.MinimumGoVersion != "" {
+		major, minor, ok := strings.Cut(b.MinimumGoVersion, ".")
+		x, err1 := strconv.Atoi(major)
+		y, err2 := strconv.Atoi(minor)
+		if !ok || err1 != nil || err2 != nil || x < 1 || y < 0 {
+			return nil, ps, fmt.Errorf("minimum_go_version %q isn't of the form 1.N", b.MinimumGoVersion)
+		}
+		bc.MinimumGoVersion = types.MajorMinor{Major: x, Minor: y}
+	}
+	for _, dep := range b.GoDeps {
+		if !goDepRE.MatchString(dep) {
+			return nil, ps, fmt.Errorf("go_deps: %q isn't a full git commit hash", dep)
+		}
+	}
+	if b.NumTestHelpers < 0 || b.NumTryTestHelpers < 0 {
+		return nil, ps, errors.New("num_test_helpers and num_try_test_helpers can't be negative")
+	}
+	if b.TryOnly && b.TryBot == "" {
+		return nil, ps, errors.New("try_only is set, but try_bot isn't")
+	}
+	if err := checkEnv(b.Env); err != nil {
+		return nil, ps, err
+	}
+
+	var err error
+	if b.TryBot != "" {
+		if ps.tryBot, err = compilePolicy(repoPolicy, b.TryBot, c.repoPolicies); err != nil {
+			return nil, ps, fmt.Errorf("try_bot: %v", err)
+		}
+		bc.tryBot = ps.tryBot.repoFunc()
+		bc.setPolicyDesc("tryBot", "`"+b.TryBot+"`")
+	}
+	if b.BuildsRepo != "" {
+		if ps.buildsRepo, err = compilePolicy(repoPolicy, b.BuildsRepo, c.repoPolicies); err != nil {
+			return nil, ps, fmt.Errorf("builds_repo: %v", err)
+		}
+		bc.buildsRepo = ps.buildsRepo.repoFunc()
+		bc.setPolicyDesc("buildsRepo", "`"+b.BuildsRepo+"`")
+	}
+	if b.DistTestAdjust != "" {
+		if ps.distTestAdjust, err = compilePolicy(distTestPolicy, b.DistTestAdjust, c.distTestPolicies); err != nil {
+			return nil, ps, fmt.Errorf("dist_test_adjust: %v", err)
+		}
+		bc.distTestAdjust = ps.distTestAdjust.distTestFunc()
+		bc.setPolicyDesc("distTestAdjust", "`"+b.DistTestAdjust+"`")
+	}
+	return bc, ps, nil
+}
+
+// checkEnv reports whether env is a list of "key=value" pairs.
+func checkEnv(env []string) error {
+	for _, kv := range env {
+		if k, _, ok := strings.Cut(kv, "="); !ok || k == "" {
+			return fmt.Errorf("env: %q isn't of the form key=value", kv)
+		}
+	}
+	return nil
+}
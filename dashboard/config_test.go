@@ -0,0 +1,220 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dashboard
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseConfig(t *testing.T) {
+	c, err := ParseConfig([]byte(`
+repo_policies:
+  mainRepos: repo in ["go", "net", "sys"] && atLeastGo1(goBranch, 22)
+dist_test_policies:
+  noReboot: run && test != "reboot"
+hosts:
+  host-linux-amd64-test:
+    notes: A test host.
+    container_image: linux-x86-test:latest
+    machine_type: n2-standard-8
+    custom_delete_timeout: 2h
+    env: [GOEXPERIMENT=test]
+    owners: [dmitshur]
+builders:
+  - name: linux-amd64-test
+    host_type: host-linux-amd64-test
+    known_issues: [12345]
+    try_bot: repo == "go"
+    builds_repo: mainRepos || repo == "exp"
+    dist_test_adjust: noReboot
+    minimum_go_version: "1.22"
+    num_test_helpers: 2
+    env: [GO_TEST_TIMEOUT_SCALE=2]
+  - name: linux-amd64-localdev-test
+    host_type: host-linux-amd64-localdev
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hc := c.Hosts["host-linux-amd64-test"]
+	if hc == nil {
+		t.Fatalf("config has no host-linux-amd64-test, hosts are %v", c.Hosts)
+	}
+	if hc.HostType != "host-linux-amd64-test" || hc.HostArch != "linux-amd64" || hc.GoBootstrap != GoBootstrap {
+		t.Errorf("host has HostType %q, HostArch %q, GoBootstrap %q, want them set by default", hc.HostType, hc.HostArch, hc.GoBootstrap)
+	}
+	if hc.MachineType() != "n2-standard-8" || hc.CustomDeleteTimeout != 2*time.Hour || len(hc.Owners) != 1 {
+		t.Errorf("host is %+v, want settings from config", hc)
+	}
+
+	if len(c.Builders) != 2 {
+		t.Fatalf("config has %d builders, want 2", len(c.Builders))
+	}
+	bc := c.Builders[0]
+	bc.TestHostConf = hc
+	if bc.Name != "linux-amd64-test" || !slices.Equal(bc.KnownIssues, []int{12345}) || bc.MinimumGoVersion.Minor != 22 {
+		t.Errorf("builder is %+v, want settings from config", bc)
+	}
+	if got, want := bc.Env(), []string{"GO_BUILDER_NAME=linux-amd64-test", "GOEXPERIMENT=test", "GO_TEST_TIMEOUT_SCALE=2"}; !slices.Equal(got, want) {
+		t.Errorf("builder Env() = %q, want %q", got, want)
+	}
+	if got := bc.NumTestHelpers(true); got != 2 {
+		t.Errorf("builder NumTestHelpers(true) = %d, want 2", got)
+	}
+	for _, tt := range []struct {
+		repo, branch, goBranch string
+		post, try              bool
+	}{
+		{"go", "master", "master", true, true},
+		{"go", "release-branch.go1.22", "release-branch.go1.22", true, true},
+		{"go", "release-branch.go1.21", "release-branch.go1.21", false, false},
+		{"net", "master", "master", true, false},
+		{"exp", "master", "master", true, false},
+		{"tools", "master", "master", false, false},
+	} {
+		if got := bc.BuildsRepoPostSubmit(tt.repo, tt.branch, tt.goBranch); got != tt.post {
+			t.Errorf("BuildsRepoPostSubmit(%q, %q, %q) = %v, want %v", tt.repo, tt.branch, tt.goBranch, got, tt.post)
+		}
+		if got := bc.BuildsRepoTryBot(tt.repo, tt.branch, tt.goBranch); got != tt.try {
+			t.Errorf("BuildsRepoTryBot(%q, %q, %q) = %v, want %v", tt.repo, tt.branch, tt.goBranch, got, tt.try)
+		}
+	}
+	if bc.ShouldRunDistTest("reboot", false) || !bc.ShouldRunDistTest("go_test:runtime", false) {
+		t.Errorf("builder runs reboot or doesn't run go_test:runtime, want the reverse")
+	}
+
+	// The second builder uses a host from Hosts, and the default policies.
+	bc = c.Builders[1]
+	if bc.tryBot != nil || bc.buildsRepo != nil || bc.distTestAdjust != nil {
+		t.Errorf("builder %s has policies set, want none", bc.Name)
+	}
+}
+
+func TestParseConfigErrors(t *testing.T) {
+	_, err := ParseConfig([]byte(`
+repo_policies:
+  bad: repo
+dist_test_policies:
+  loop: loop
+hosts:
+  host-linux-amd64-test:
+    notes: A host with both a VM and a container image.
+    vm_image: linux-test
+    container_image: linux-test:latest
+  host-linux-amd64-test2:
+    container_image: linux-test:latest
+    cos_architecture: riscv64
+    owners: [nobody-by-this-name-1234]
+builders:
+  - name: linux-amd64-test
+    host_type: host-linux-amd64-nope
+  - name: linux-amd64-test
+    host_type: host-linux-amd64-localdev
+  - name: nodash
+    host_type: host-linux-amd64-localdev
+  - name: linux-amd64-test2
+    host_type: host-linux-amd64-localdev
+    try_only: true
+    builds_repo: nope
+    env: [NOEQUALS]
+`))
+	if err == nil {
+		t.Fatal("ParseConfig succeeded, want error")
+	}
+	for _, want := range []string{
+		"repo_policies: bad: policy has type string, want bool",
+		"dist_test_policies: loop: offset 0: policy loop refers to itself",
+		`hosts: host-linux-amd64-test: exactly one of VMImage, ContainerImage, IsReverse must be set for host "host-linux-amd64-test"; got 2`,
+		`hosts: host-linux-amd64-test2: cos_architecture "riscv64" isn't amd64 or arm64`,
+		`builders: linux-amd64-test: undefined HostType "host-linux-amd64-nope" for builder "linux-amd64-test"`,
+		"builders: linux-amd64-test: duplicate builder",
+		`builders: nodash: name "nodash" isn't of the form GOOS-GOARCH or GOOS-GOARCH-suffix`,
+		"builders: linux-amd64-test2: try_only is set, but try_bot isn't",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseConfig error:\n%v\nwant it to contain %q", err, want)
+		}
+	}
+
+	_, err = ParseConfig([]byte("builders:\n  - name: linux-amd64\n    host: host-linux-amd64-localdev\n"))
+	if err == nil || !strings.Contains(err.Error(), "field host not found") {
+		t.Errorf("ParseConfig with unknown field: got error %v, want field not found", err)
+	}
+}
+
+func TestBuiltinConfig(t *testing.T) {
+	for _, bc := range builtin.Builders {
+		if Builders[bc.Name] == nil {
+			t.Errorf("builder %s from builders.yaml isn't in Builders", bc.Name)
+		}
+	}
+	for key, hc := range builtin.Hosts {
+		if Hosts[key] != hc {
+			t.Errorf("host %s from builders.yaml isn't in Hosts", key)
+		}
+	}
+	if problems := Lint(builtin); len(problems) > 0 {
+		t.Errorf("Lint(builtin) = %v, want no problems", problems)
+	}
+}
+
+func TestLint(t *testing.T) {
+	c, err := ParseConfig([]byte(`
+repo_policies:
+  unused: repo == "go"
+  masterOnly: branch == "master" && goBranch == "master"
+dist_test_policies:
+  noop: run
+hosts:
+  host-linux-amd64-test:
+    container_image: linux-x86-test:latest
+  host-linux-amd64-unused:
+    container_image: linux-x86-unused:latest
+builders:
+  - name: linux-amd64-never
+    host_type: host-linux-amd64-test
+    builds_repo: repo == "go" && atMostGo1(branch, 10)
+  - name: linux-amd64-trynever
+    host_type: host-linux-amd64-test
+    try_bot: repo == "exp"
+    env: [GOEXPERIMENT=a]
+  - name: linux-amd64-a
+    host_type: host-linux-amd64-test
+    builds_repo: masterOnly
+    dist_test_adjust: noop
+    env: [GOEXPERIMENT=b, GOAMD64=v3]
+  - name: linux-amd64-b
+    host_type: host-linux-amd64-test
+    builds_repo: repo == "net"
+    dist_test_adjust: "false"
+    env: [GOAMD64=v3, GOEXPERIMENT=b]
+  - name: linux-amd64-alpine-copy
+    host_type: host-linux-amd64-alpine
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, p := range Lint(c) {
+		got = append(got, p.String())
+	}
+	want := []string{
+		"linux-amd64-never: builds no repo on any branch",
+		"linux-amd64-trynever: try_bot matches no repo that the builder builds",
+		"linux-amd64-a: dist_test_adjust never changes the default dist test policy",
+		"linux-amd64-b: dist_test_adjust skips every dist test",
+		"linux-amd64-b: does the same builds as linux-amd64-a on the same host with the same environment, such as net on master with go on master",
+		"linux-amd64-alpine: does the same builds as linux-amd64-alpine-copy on the same host with the same environment, such as arch on dev.lint with go on dev.lint",
+		"host-linux-amd64-unused: host is not used by any builder",
+		"unused: repo policy is not used by any builder",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Lint problems:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dashboard
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// An Explanation explains the decisions of a builder about
+// the builds of a repo and branch, and optionally about a
+// cmd/dist test in those builds.
+type Explanation struct {
+	Builder  string `json:"builder"`
+	Repo     string `json:"repo"`
+	Branch   string `json:"branch"`
+	GoBranch string `json:"goBranch"`
+
+	// PostSubmit and TryBot are the decisions of
+	// BuildsRepoPostSubmit and BuildsRepoTryBot.
+	PostSubmit Decision `json:"postSubmit"`
+	TryBot     Decision `json:"tryBot"`
+
+	// DistTest is the cmd/dist test explained, if any.
+	// DistTestNormalTry and DistTestOther are the decisions of
+	// ShouldRunDistTest for it in normal trybot builds and
+	// in other builds.
+	DistTest          string    `json:"distTest,omitempty"`
+	DistTestNormalTry *Decision `json:"distTestNormalTry,omitempty"`
+	DistTestOther     *Decision `json:"distTestOther,omitempty"`
+}
+
+// A Decision is the result of a decision and the steps that led to it.
+type Decision struct {
+	Result bool   `json:"result"`
+	Steps  []Step `json:"steps"`
+}
+
+// A Step is a check made in a decision, in the order they were made.
+// The last step of a decision that's false is the one that denied it.
+type Step struct {
+	// Check is the check, such as "tryOnly", "tryBot",
+	// "releaseBranch", "boringcrypto", or "buildsRepo" for
+	// builds, and "default" or "distTestAdjust" for dist tests.
+	Check  string `json:"check"`
+	Allow  bool   `json:"allow"` // whether the check allowed the build or test
+	Reason string `json:"reason"`
+}
+
+func (s Step) String() string {
+	result := "deny"
+	if s.Allow {
+		result = "allow"
+	}
+	return fmt.Sprintf("%s: %s: %s", s.Check, result, s.Reason)
+}
+
+// Explain explains the decisions of c about the post-submit and trybot
+// builds of repo ("go", "net", etc.) on branch, using Go on goBranch,
+// as BuildsRepoPostSubmit and BuildsRepoTryBot make them.
+// If repo is "go", goBranch may be empty.
+//
+// If distTest isn't empty, Explain also explains whether c runs
+// that cmd/dist test, as ShouldRunDistTest decides.
+func (c *BuildConfig) Explain(repo, branch, goBranch, distTest string) (*Explanation, error) {
+	if repo == "" || branch == "" {
+		return nil, errors.New("missing repo or branch")
+	}
+	if goBranch == "" {
+		if repo != "go" {
+			return nil, fmt.Errorf("missing Go branch for repo %s", repo)
+		}
+		goBranch = branch
+	}
+	e := &Explanation{
+		Builder:  c.Name,
+		Repo:     repo,
+		Branch:   branch,
+		GoBranch: goBranch,
+		DistTest: distTest,
+	}
+	decide := func(f func(t *trace) bool) Decision {
+		t := new(trace)
+		return Decision{Result: f(t), Steps: t.steps}
+	}
+	e.PostSubmit = decide(func(t *trace) bool { return c.decidePostSubmit(repo, branch, goBranch, t) })
+	e.TryBot = decide(func(t *trace) bool { return c.decideTryBot(repo, branch, goBranch, t) })
+	if distTest != "" {
+		e.DistTestNormalTry = new(decide(func(t *trace) bool { return c.decideDistTest(distTest, true, t) }))
+		e.DistTestOther = new(decide(func(t *trace) bool { return c.decideDistTest(distTest, false, t) }))
+	}
+	return e, nil
+}
+
+// A trace records the steps of a decision.
+// A nil *trace records nothing.
+type trace struct {
+	steps []Step
+}
+
+// step records a step in which check allowed the decision, if allow
+// is set, or denied it, for the reason given by format and args.
+// It returns allow.
+func (t *trace) step(check string, allow bool, format string, args ...any) bool {
+	if t != nil {
+		t.steps = append(t.steps, Step{Check: check, Allow: allow, Reason: fmt.Sprintf(format, args...)})
+	}
+	return allow
+}
+
+// policyRef returns a reference to policy function f, in the field
+// of c named field, that formats as a description of the policy.
+func (c *BuildConfig) policyRef(field string, f any) policyRef {
+	return policyRef{c, field, f}
+}
+
+// setPolicyDesc sets the description of the policy in the field of c
+// named field, for Explain.
+func (c *BuildConfig) setPolicyDesc(field, desc string) {
+	c.policyDesc = maps.Clone(c.policyDesc) // it may be shared with a copy of c
+	if c.policyDesc == nil {
+		c.policyDesc = make(map[string]string)
+	}
+	c.policyDesc[field] = desc
+}
+
+type policyRef struct {
+	c     *BuildConfig
+	field string
+	f     any
+}
+
+// String returns the description of the policy, if it has one,
+// or else the name of the Go function that implements it.
+func (r policyRef) String() string {
+	if desc, ok := r.c.policyDesc[r.field]; ok {
+		return desc
+	}
+	name := runtime.FuncForPC(reflect.ValueOf(r.f).Pointer()).Name()
+	name = strings.TrimPrefix(name, "golang.org/x/build/dashboard.")
+	// Policies made by functions like defaultPlus are closures
+	// named like defaultPlus.func1. Those defined in the init
+	// funcs that add the builders have no useful name.
+	name, _, _ = strings.Cut(name, ".func")
+	if strings.HasPrefix(name, "init.") {
+		return "(defined inline in builders.go)"
+	}
+	return name
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dashboard
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/build/internal/migration"
+	"golang.org/x/build/types"
+)
+
+func TestExplain(t *testing.T) {
+	c := &BuildConfig{
+		Name:             "linux-amd64-test",
+		HostType:         "host-linux-amd64-bullseye",
+		tryBot:           explicitTrySet("go"),
+		buildsRepo:       defaultPlus("exp"),
+		distTestAdjust:   macTestPolicy,
+		MinimumGoVersion: types.MajorMinor{Major: 1, Minor: 21},
+	}
+	steps := func(d Decision) []string {
+		var s []string
+		for _, step := range d.Steps {
+			s = append(s, step.String())
+		}
+		return s
+	}
+
+	tests := []struct {
+		repo, branch, goBranch string
+		post, try              []string
+	}{
+		{
+			"go", "master", "",
+			[]string{"buildsRepo: allow: repo policy defaultPlus allows go"},
+			[]string{
+				"tryBot: allow: trybot policy explicitTrySet allows go",
+				"buildsRepo: allow: repo policy defaultPlus allows go",
+			},
+		},
+		{
+			"tools", "master", "release-branch.go1.20",
+			[]string{"releaseBranch: deny: release-branch.go1.20 is older than the builder's minimum Go version, 1.21"},
+			[]string{"tryBot: deny: trybot policy explicitTrySet denies tools"},
+		},
+		{
+			"exp", "master", "release-branch.go1.22",
+			[]string{
+				"releaseBranch: deny: exp isn't tested against release branches",
+			},
+			[]string{"tryBot: deny: trybot policy explicitTrySet denies exp"},
+		},
+		{
+			"go", "release-branch.go1.9", "",
+			[]string{"releaseBranch: deny: release-branch.go1.9 is older than Go 1.11"},
+			[]string{
+				"tryBot: allow: trybot policy explicitTrySet allows go",
+				"releaseBranch: deny: release-branch.go1.9 is older than Go 1.11",
+			},
+		},
+		{
+			"go", "dev.boringcrypto", "",
+			[]string{"boringcrypto: deny: dev.boringcrypto is only built on linux-amd64 and windows-386"},
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		e, err := c.Explain(tt.repo, tt.branch, tt.goBranch, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		name := fmt.Sprintf("Explain(%q, %q, %q)", tt.repo, tt.branch, tt.goBranch)
+		if got := steps(e.PostSubmit); !slices.Equal(got, tt.post) {
+			t.Errorf("%s.PostSubmit steps:\n%s\nwant:\n%s", name, strings.Join(got, "\n"), strings.Join(tt.post, "\n"))
+		}
+		if e.PostSubmit.Result != c.BuildsRepoPostSubmit(e.Repo, e.Branch, e.GoBranch) {
+			t.Errorf("%s.PostSubmit.Result = %v, want the result of BuildsRepoPostSubmit", name, e.PostSubmit.Result)
+		}
+		if tt.try != nil {
+			if got := steps(e.TryBot); !slices.Equal(got, tt.try) {
+				t.Errorf("%s.TryBot steps:\n%s\nwant:\n%s", name, strings.Join(got, "\n"), strings.Join(tt.try, "\n"))
+			}
+		}
+		if e.TryBot.Result != c.BuildsRepoTryBot(e.Repo, e.Branch, e.GoBranch) {
+			t.Errorf("%s.TryBot.Result = %v, want the result of BuildsRepoTryBot", name, e.TryBot.Result)
+		}
+		if e.DistTestNormalTry != nil || e.DistTestOther != nil {
+			t.Errorf("%s has dist test decisions, want none", name)
+		}
+	}
+
+	e, err := c.Explain("go", "master", "", "race")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := steps(*e.DistTestNormalTry), []string{
+		"default: allow: the default dist test policy runs race",
+		"distTestAdjust: deny: dist test policy macTestPolicy changes the decision",
+	}; !slices.Equal(got, want) {
+		t.Errorf("DistTestNormalTry steps:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if got, want := steps(*e.DistTestOther), []string{
+		"default: allow: the default dist test policy runs race",
+		"distTestAdjust: allow: dist test policy macTestPolicy keeps the decision",
+	}; !slices.Equal(got, want) {
+		t.Errorf("DistTestOther steps:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if _, err := c.Explain("net", "master", "", ""); err == nil {
+		t.Errorf("Explain without a Go branch for net succeeded, want error")
+	}
+}
+
+func TestExplainConfigPolicy(t *testing.T) {
+	e, err := builtin.Builders[0].Explain("tools", "master", "master", "reboot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Step{"buildsRepo", false, "repo policy `wasip1Default` denies tools"}
+	if got := e.PostSubmit.Steps; len(got) != 1 || got[0] != want {
+		t.Errorf("PostSubmit steps = %v, want [%v]", got, want)
+	}
+	want = Step{"distTestAdjust", false, "dist test policy `wasip1FasterTrybots` changes the decision"}
+	if got := e.DistTestNormalTry.Steps; len(got) != 2 || got[1] != want {
+		t.Errorf("DistTestNormalTry steps = %v, want [default, %v]", got, want)
+	}
+}
+
+func TestExplainStoppedBuilder(t *testing.T) {
+	if !migration.StopAllLegacyBuilders {
+		t.Skip("test requires legacy builders to be stopped")
+	}
+	e, err := Builders["linux-amd64"].Explain("go", "master", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := e.PostSubmit.Steps; e.PostSubmit.Result || len(got) != 1 || !strings.Contains(got[0].Reason, "stopped in the coordinator") {
+		t.Errorf("PostSubmit = %+v, want denied because the builder is stopped", e.PostSubmit)
+	}
+}
@@ -0,0 +1,251 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dashboard
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"golang.org/x/build/repos"
+)
+
+// A Problem is a problem that Lint found in a configuration.
+type Problem struct {
+	Name    string // the builder, host, or named policy with the problem
+	Message string
+}
+
+func (p Problem) String() string { return p.Name + ": " + p.Message }
+
+// Lint reports problems in c, and in the builders defined in Go
+// in builders.go, that ParseConfig accepts, but that are likely mistakes:
+//
+//   - builders that don't build any repo on any branch, unless
+//     they're disabled, or whose trybot policy doesn't match any
+//     of their builds;
+//   - builders that do the same builds as another builder,
+//     on the same host with the same environment;
+//   - dist test policies that never change the decision
+//     of the default policy, or that skip every test;
+//   - hosts and named policies that nothing uses.
+//
+// Lint evaluates policies on a sample of repos, branches, and dist
+// tests, made from the Go repos and the literals in the policies,
+// so it can miss problems that only show up outside the sample.
+func Lint(c *Config) []Problem {
+	var problems []Problem
+	report := func(name, format string, args ...any) {
+		problems = append(problems, Problem{name, fmt.Sprintf(format, args...)})
+	}
+	s := c.lintSample()
+
+	// The builders defined in Go are checked along with those
+	// of c, since c's builders can duplicate them. They're checked
+	// as defined, even if they're stopped in the coordinator.
+	builders := slices.Clone(c.Builders)
+	for _, name := range slices.Sorted(maps.Keys(definedBuilders)) {
+		if !builtin.defines(name) && !c.defines(name) {
+			builders = append(builders, definedBuilders[name])
+		}
+	}
+
+	for _, bc := range builders {
+		// Builders that only do trybot builds can also be
+		// requested as SlowBots, so a builder builds a repo
+		// if buildsRepoAtAll allows it at all.
+		var builds, try bool
+		for _, b := range s.builds {
+			builds = builds || bc.buildsRepoAtAll(b.repo, b.branch, b.goBranch)
+			try = try || bc.BuildsRepoTryBot(b.repo, b.branch, b.goBranch)
+		}
+		switch {
+		case !builds && !bc.disabled():
+			report(bc.Name, "builds no repo on any branch")
+		case bc.tryBot != nil && !try:
+			report(bc.Name, "try_bot matches no repo that the builder builds")
+		}
+
+		if bc.distTestAdjust != nil {
+			dflt := *bc
+			dflt.distTestAdjust = nil
+			changed, runs := false, false
+			for _, test := range s.tests {
+				for _, isTry := range []bool{false, true} {
+					run := bc.ShouldRunDistTest(test, isTry)
+					changed = changed || run != dflt.ShouldRunDistTest(test, isTry)
+					runs = runs || run
+				}
+			}
+			if !changed {
+				report(bc.Name, "dist_test_adjust never changes the default dist test policy")
+			} else if !runs {
+				report(bc.Name, "dist_test_adjust skips every dist test")
+			}
+		}
+	}
+
+	// Builders with the same key run the same builds,
+	// if their repo policies overlap.
+	key := func(bc *BuildConfig) string {
+		return fmt.Sprintf("%s %q %v %v %v %v %q %q", bc.HostType, slices.Sorted(slices.Values(bc.env)),
+			bc.IsRace(), bc.IsLongTest(), bc.CompileOnly, bc.StopAfterMake, bc.makeScriptArgs, bc.allScriptArgs)
+	}
+	for i, x := range builders {
+		for _, y := range builders[i+1:] {
+			if key(x) != key(y) || knownDuplicates[y.Name] == x.Name {
+				continue
+			}
+			for _, b := range s.builds {
+				if x.BuildsRepoPostSubmit(b.repo, b.branch, b.goBranch) && y.BuildsRepoPostSubmit(b.repo, b.branch, b.goBranch) {
+					report(y.Name, "does the same builds as %s on the same host with the same environment, such as %s", x.Name, b)
+					break
+				}
+			}
+		}
+	}
+
+	used := make(map[string]bool)
+	for _, bc := range builders {
+		used[bc.HostType] = true
+	}
+	for _, key := range slices.Sorted(maps.Keys(c.Hosts)) {
+		if !used[key] {
+			report(key, "host is not used by any builder")
+		}
+	}
+
+	usedPolicies := make(map[string]bool)
+	for _, ps := range c.policies {
+		for _, p := range []*policy{ps.buildsRepo, ps.tryBot, ps.distTestAdjust} {
+			if p != nil {
+				for _, ref := range p.refs {
+					usedPolicies[ref] = true
+				}
+			}
+		}
+	}
+	for _, named := range []map[string]*policy{c.repoPolicies, c.distTestPolicies} {
+		for _, name := range slices.Sorted(maps.Keys(named)) {
+			if !usedPolicies[name] {
+				report(name, "%s is not used by any builder", named[name].kind)
+			}
+		}
+	}
+	return problems
+}
+
+// knownDuplicates maps builders defined in Go to the builders that
+// they do the same builds as, which Lint doesn't report. The builders
+// named for a Debian release do the same builds as those for Debian
+// stable while it is that release, so that the builds carry on when
+// Debian stable moves on.
+var knownDuplicates = map[string]string{
+	"linux-386-bullseye":   "linux-386",
+	"linux-amd64-bullseye": "linux-amd64",
+}
+
+// disabled reports whether bc is explicitly disabled
+// by the disabledBuilder repo policy.
+func (bc *BuildConfig) disabled() bool {
+	return bc.buildsRepo != nil && bc.policyRef("buildsRepo", bc.buildsRepo).String() == "disabledBuilder"
+}
+
+// defines reports whether c defines the named builder.
+// A nil Config defines no builders.
+func (c *Config) defines(name string) bool {
+	if c == nil {
+		return false
+	}
+	_, ok := c.policies[name]
+	return ok
+}
+
+// A lintBuild is a build of a repo that Lint evaluates builders on.
+type lintBuild struct {
+	repo, branch, goBranch string
+}
+
+func (b lintBuild) String() string {
+	if b.repo == "go" {
+		return fmt.Sprintf("go on %s", b.branch)
+	}
+	return fmt.Sprintf("%s on %s with go on %s", b.repo, b.branch, b.goBranch)
+}
+
+// A lintSample is the sample of builds and dist tests
+// that Lint evaluates builders on.
+type lintSample struct {
+	builds []lintBuild
+	tests  []string
+}
+
+// lintSample returns the sample to evaluate the builders of c on.
+func (c *Config) lintSample() lintSample {
+	repoSet := make(map[string]bool)
+	for proj := range repos.ByGerritProject {
+		repoSet[proj] = true
+	}
+	branchSet := map[string]bool{"master": true, "dev.lint": true}
+	testSet := map[string]bool{"api": true, "reboot": true, "test:0_5": true, "go_test:runtime": true, "go_test:cmd/internal/obj": true}
+
+	const minGo1x = 11 // the oldest release branch buildsRepoAtAll builds
+	maxGo1x := minGo1x
+	addRepoPolicy := func(p *policy) {
+		if p == nil {
+			return
+		}
+		for _, s := range p.strs {
+			if s == "" {
+				continue
+			}
+			if s == "master" || strings.HasPrefix(s, "dev.") || strings.HasPrefix(s, "release-branch.") || strings.HasPrefix(s, "internal-branch.") {
+				branchSet[s] = true
+			} else {
+				repoSet[s] = true
+			}
+		}
+		for _, n := range p.ints {
+			maxGo1x = max(maxGo1x, n)
+		}
+	}
+	for _, p := range c.repoPolicies {
+		addRepoPolicy(p)
+	}
+	for _, bc := range c.Builders {
+		ps := c.policies[bc.Name]
+		addRepoPolicy(ps.buildsRepo)
+		addRepoPolicy(ps.tryBot)
+		if ps.distTestAdjust != nil {
+			for _, s := range ps.distTestAdjust.strs {
+				testSet[s] = true
+			}
+		}
+		maxGo1x = max(maxGo1x, bc.MinimumGoVersion.Minor)
+	}
+	for _, p := range c.distTestPolicies {
+		for _, s := range p.strs {
+			testSet[s] = true
+		}
+	}
+	delete(testSet, "")
+	for x := minGo1x; x <= maxGo1x+1; x++ {
+		branchSet[fmt.Sprintf("release-branch.go1.%d", x)] = true
+	}
+
+	var s lintSample
+	branches := slices.Sorted(maps.Keys(branchSet))
+	for _, repo := range slices.Sorted(maps.Keys(repoSet)) {
+		for _, b := range branches {
+			s.builds = append(s.builds, lintBuild{repo, b, b})
+			if repo != "go" && b != "master" {
+				s.builds = append(s.builds, lintBuild{repo, "master", b})
+			}
+		}
+	}
+	s.tests = slices.Sorted(maps.Keys(testSet))
+	return s
+}
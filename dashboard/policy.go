@@ -0,0 +1,583 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dashboard
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Builder policies in configuration files are written as expressions
+// in a small language, instead of as Go functions like defaultPlus or
+// mipsDistTestPolicy. An expression has the syntax
+//
+//	expr    = and { "||" and } .
+//	and     = unary { "&&" unary } .
+//	unary   = "!" unary | compare .
+//	compare = operand [ ( "==" | "!=" ) operand | "in" list ] .
+//	operand = name | call | string | int | "(" expr ")" .
+//	call    = name "(" [ expr { "," expr } ] ")" .
+//	list    = "[" [ string { "," string } ] "]" .
+//
+// where strings are Go double-quoted string literals and ints are
+// decimal. An expression must evaluate to a bool.
+//
+// The names true and false are the boolean constants.
+// Repo policies (builds_repo and try_bot) may use the string
+// variables repo, branch, and goBranch, which have the meaning of
+// the parameters of BuildConfig.BuildsRepoPostSubmit.
+// Dist test policies (dist_test_adjust) may use the variables
+// run (bool), test (string), and try (bool), which are the
+// parameters of a distTestAdjust function.
+//
+// The functions are
+//
+//	buildRepoByDefault(repo string) bool
+//	atLeastGo1(branch string, min int) bool
+//	atMostGo1(branch string, max int) bool
+//	hasPrefix(s, prefix string) bool
+//	contains(s, substr string) bool
+//
+// which behave as their Go counterparts.
+//
+// Any other name refers to a named policy of the same kind,
+// defined in the repo_policies or dist_test_policies section
+// of the configuration.
+
+// A policyKind is the kind of a policy, which decides
+// the variables that it may use.
+type policyKind int
+
+const (
+	repoPolicy     policyKind = iota // a buildsRepo or tryBot policy
+	distTestPolicy                   // a distTestAdjust policy
+)
+
+func (k policyKind) String() string {
+	if k == distTestPolicy {
+		return "dist test policy"
+	}
+	return "repo policy"
+}
+
+// A policyEnv holds the values of the variables of a policy.
+type policyEnv struct {
+	repo, branch, goBranch string // for repo policies
+
+	run  bool // for dist test policies
+	test string
+	try  bool
+}
+
+// A valueType is the type of a policy expression.
+type valueType int
+
+const (
+	boolType valueType = iota
+	stringType
+	intType
+)
+
+func (t valueType) String() string {
+	switch t {
+	case stringType:
+		return "string"
+	case intType:
+		return "int"
+	}
+	return "bool"
+}
+
+var policyVars = map[string]struct {
+	kind policyKind
+	typ  valueType
+	get  func(*policyEnv) any
+}{
+	"repo":     {repoPolicy, stringType, func(e *policyEnv) any { return e.repo }},
+	"branch":   {repoPolicy, stringType, func(e *policyEnv) any { return e.branch }},
+	"goBranch": {repoPolicy, stringType, func(e *policyEnv) any { return e.goBranch }},
+	"run":      {distTestPolicy, boolType, func(e *policyEnv) any { return e.run }},
+	"test":     {distTestPolicy, stringType, func(e *policyEnv) any { return e.test }},
+	"try":      {distTestPolicy, boolType, func(e *policyEnv) any { return e.try }},
+}
+
+var policyFuncs = map[string]struct {
+	args []valueType
+	call func(args []any) bool
+}{
+	"buildRepoByDefault": {
+		[]valueType{stringType},
+		func(a []any) bool { return buildRepoByDefault(a[0].(string)) },
+	},
+	"atLeastGo1": {
+		[]valueType{stringType, intType},
+		func(a []any) bool { return atLeastGo1(a[0].(string), a[1].(int)) },
+	},
+	"atMostGo1": {
+		[]valueType{stringType, intType},
+		func(a []any) bool { return atMostGo1(a[0].(string), a[1].(int)) },
+	},
+	"hasPrefix": {
+		[]valueType{stringType, stringType},
+		func(a []any) bool { return strings.HasPrefix(a[0].(string), a[1].(string)) },
+	},
+	"contains": {
+		[]valueType{stringType, stringType},
+		func(a []any) bool { return strings.Contains(a[0].(string), a[1].(string)) },
+	},
+}
+
+// A policy is a compiled policy expression.
+type policy struct {
+	src  string
+	kind policyKind
+	eval func(*policyEnv) bool
+
+	// strs and ints are the string and int literals in the policy
+	// and in the named policies it refers to, for Lint.
+	strs []string
+	ints []int
+	// refs are the names of the named policies it refers to.
+	refs []string
+}
+
+// repoFunc returns p as a buildsRepo or tryBot policy function.
+func (p *policy) repoFunc() func(repo, branch, goBranch string) bool {
+	return func(repo, branch, goBranch string) bool {
+		return p.eval(&policyEnv{repo: repo, branch: branch, goBranch: goBranch})
+	}
+}
+
+// distTestFunc returns p as a distTestAdjust policy function.
+func (p *policy) distTestFunc() func(run bool, distTest string, isNormalTry bool) bool {
+	return func(run bool, distTest string, isNormalTry bool) bool {
+		return p.eval(&policyEnv{run: run, test: distTest, try: isNormalTry})
+	}
+}
+
+// compilePolicy compiles the policy expression src of the given kind.
+// Names that aren't variables or functions are looked up in named.
+func compilePolicy(kind policyKind, src string, named map[string]*policy) (*policy, error) {
+	return compilePolicyFunc(kind, src, func(name string) (*policy, error) {
+		if p := named[name]; p != nil && p.kind == kind {
+			return p, nil
+		}
+		return nil, fmt.Errorf("undefined: %s", name)
+	})
+}
+
+// compilePolicies compiles the named policies in srcs,
+// which may refer to each other by name.
+func compilePolicies(kind policyKind, srcs map[string]string) (map[string]*policy, error) {
+	named := make(map[string]*policy)
+	var errs []error
+	busy := make(map[string]bool)
+	var lookup func(name string) (*policy, error)
+	lookup = func(name string) (*policy, error) {
+		if p := named[name]; p != nil {
+			return p, nil
+		}
+		src, ok := srcs[name]
+		if !ok {
+			return nil, fmt.Errorf("undefined: %s", name)
+		}
+		if busy[name] {
+			return nil, fmt.Errorf("policy %s refers to itself", name)
+		}
+		busy[name] = true
+		defer delete(busy, name)
+		p, err := compilePolicyFunc(kind, src, lookup)
+		if err != nil && len(busy) > 1 {
+			return nil, fmt.Errorf("in policy %s: %v", name, err)
+		} else if err != nil {
+			return nil, err
+		}
+		named[name] = p
+		return p, nil
+	}
+	for _, name := range slices.Sorted(maps.Keys(srcs)) {
+		if _, ok := policyVars[name]; ok || policyFuncs[name].call != nil || name == "true" || name == "false" || name == "in" {
+			errs = append(errs, fmt.Errorf("%s: policy name is predeclared", name))
+			continue
+		}
+		if !isPolicyName(name) {
+			errs = append(errs, fmt.Errorf("%q: invalid policy name", name))
+			continue
+		}
+		if _, err := lookup(name); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", name, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return named, nil
+}
+
+func isPolicyName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, c := range s {
+		if !isNameChar(c) || i == 0 && '0' <= c && c <= '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isNameChar(c rune) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+// compilePolicyFunc compiles the policy expression src of the given kind,
+// looking up the named policies it refers to with lookup.
+func compilePolicyFunc(kind policyKind, src string, lookup func(string) (*policy, error)) (*policy, error) {
+	toks, err := lexPolicy(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &policyParser{toks: toks, kind: kind, lookup: lookup, policy: &policy{src: src, kind: kind}}
+	x, err := p.expr()
+	if err == nil && p.peek().kind != tokEOF {
+		err = p.errorf("unexpected %s", p.peek())
+	}
+	if err == nil && x.typ != boolType {
+		err = fmt.Errorf("policy has type %s, want bool", x.typ)
+	}
+	if err != nil {
+		return nil, err
+	}
+	p.policy.eval = func(e *policyEnv) bool { return x.eval(e).(bool) }
+	return p.policy, nil
+}
+
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokName
+	tokString
+	tokInt
+	tokOp
+)
+
+type token struct {
+	kind tokKind
+	text string
+	off  int // byte offset in the source
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of policy"
+	}
+	return strconv.Quote(t.text)
+}
+
+// lexPolicy splits src into tokens, ending with a tokEOF token.
+func lexPolicy(src string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+			continue
+		case isNameChar(rune(c)):
+			j := i
+			for j < len(src) && isNameChar(rune(src[j])) {
+				j++
+			}
+			kind := tokName
+			if '0' <= c && c <= '9' {
+				kind = tokInt
+			}
+			toks = append(toks, token{kind, src[i:j], i})
+			i = j
+			continue
+		case c == '"':
+			j := i + 1
+			for j < len(src) && src[j] != '"' {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("offset %d: unterminated string", i)
+			}
+			toks = append(toks, token{tokString, src[i : j+1], i})
+			i = j + 1
+			continue
+		}
+		if i+1 < len(src) {
+			switch op := src[i : i+2]; op {
+			case "==", "!=", "&&", "||":
+				toks = append(toks, token{tokOp, op, i})
+				i += 2
+				continue
+			}
+		}
+		if !strings.ContainsRune("!()[],", rune(c)) {
+			return nil, fmt.Errorf("offset %d: unexpected character %q", i, c)
+		}
+		toks = append(toks, token{tokOp, src[i : i+1], i})
+		i++
+	}
+	return append(toks, token{tokEOF, "", len(src)}), nil
+}
+
+// A policyExpr is a type-checked policy expression.
+type policyExpr struct {
+	typ  valueType
+	eval func(*policyEnv) any
+}
+
+type policyParser struct {
+	toks   []token
+	pos    int
+	kind   policyKind
+	lookup func(string) (*policy, error)
+	policy *policy // the policy being compiled
+}
+
+func (p *policyParser) peek() token { return p.toks[p.pos] }
+
+func (p *policyParser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token if it is the operator op.
+func (p *policyParser) accept(op string) bool {
+	if t := p.peek(); t.kind == tokOp && t.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *policyParser) expect(op string) error {
+	if !p.accept(op) {
+		return p.errorf("unexpected %s, want %q", p.peek(), op)
+	}
+	return nil
+}
+
+func (p *policyParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("offset %d: %s", p.peek().off, fmt.Sprintf(format, args...))
+}
+
+// binary parses a left-associative sequence of the boolean
+// operator op, with operands parsed by sub.
+func (p *policyParser) binary(op string, sub func() (policyExpr, error)) (policyExpr, error) {
+	x, err := sub()
+	if err != nil {
+		return x, err
+	}
+	for {
+		t := p.peek()
+		if !p.accept(op) {
+			return x, nil
+		}
+		y, err := sub()
+		if err != nil {
+			return y, err
+		}
+		if x.typ != boolType || y.typ != boolType {
+			return x, fmt.Errorf("offset %d: %s applied to %s and %s, want bool", t.off, op, x.typ, y.typ)
+		}
+		xe, ye := x.eval, y.eval
+		if op == "&&" {
+			x.eval = func(e *policyEnv) any { return xe(e).(bool) && ye(e).(bool) }
+		} else {
+			x.eval = func(e *policyEnv) any { return xe(e).(bool) || ye(e).(bool) }
+		}
+	}
+}
+
+func (p *policyParser) expr() (policyExpr, error) { return p.binary("||", p.and) }
+
+func (p *policyParser) and() (policyExpr, error) { return p.binary("&&", p.unary) }
+
+func (p *policyParser) unary() (policyExpr, error) {
+	t := p.peek()
+	if !p.accept("!") {
+		return p.compare()
+	}
+	x, err := p.unary()
+	if err != nil {
+		return x, err
+	}
+	if x.typ != boolType {
+		return x, fmt.Errorf("offset %d: ! applied to %s, want bool", t.off, x.typ)
+	}
+	xe := x.eval
+	x.eval = func(e *policyEnv) any { return !xe(e).(bool) }
+	return x, nil
+}
+
+func (p *policyParser) compare() (policyExpr, error) {
+	x, err := p.operand()
+	if err != nil {
+		return x, err
+	}
+	t := p.peek()
+	switch {
+	case t.kind == tokOp && (t.text == "==" || t.text == "!="):
+		p.next()
+		y, err := p.operand()
+		if err != nil {
+			return y, err
+		}
+		if x.typ != y.typ {
+			return x, fmt.Errorf("offset %d: mismatched types %s and %s", t.off, x.typ, y.typ)
+		}
+		xe, ye, neq := x.eval, y.eval, t.text == "!="
+		return policyExpr{boolType, func(e *policyEnv) any { return (xe(e) == ye(e)) != neq }}, nil
+	case t.kind == tokName && t.text == "in":
+		p.next()
+		if x.typ != stringType {
+			return x, fmt.Errorf("offset %d: in applied to %s, want string", t.off, x.typ)
+		}
+		if err := p.expect("["); err != nil {
+			return x, err
+		}
+		var list []string
+		for !p.accept("]") {
+			if len(list) > 0 {
+				if err := p.expect(","); err != nil {
+					return x, err
+				}
+			}
+			s, err := p.str()
+			if err != nil {
+				return x, err
+			}
+			list = append(list, s)
+		}
+		xe := x.eval
+		return policyExpr{boolType, func(e *policyEnv) any { return slices.Contains(list, xe(e).(string)) }}, nil
+	}
+	return x, nil
+}
+
+// str parses a string literal.
+func (p *policyParser) str() (string, error) {
+	t := p.peek()
+	if t.kind != tokString {
+		return "", p.errorf("unexpected %s, want string", t)
+	}
+	p.next()
+	s, err := strconv.Unquote(t.text)
+	if err != nil {
+		return "", fmt.Errorf("offset %d: invalid string %s", t.off, t.text)
+	}
+	p.policy.strs = append(p.policy.strs, s)
+	return s, nil
+}
+
+func (p *policyParser) operand() (policyExpr, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokString:
+		s, err := p.str()
+		return policyExpr{stringType, func(*policyEnv) any { return s }}, err
+	case tokInt:
+		p.next()
+		n, err := strconv.Atoi(t.text)
+		if err != nil {
+			return policyExpr{}, fmt.Errorf("offset %d: invalid int %s", t.off, t.text)
+		}
+		p.policy.ints = append(p.policy.ints, n)
+		return policyExpr{intType, func(*policyEnv) any { return n }}, nil
+	case tokOp:
+		if p.accept("(") {
+			x, err := p.expr()
+			if err != nil {
+				return x, err
+			}
+			return x, p.expect(")")
+		}
+		return policyExpr{}, p.errorf("unexpected %s", t)
+	case tokEOF:
+		return policyExpr{}, p.errorf("unexpected %s", t)
+	}
+
+	p.next()
+	if p.peek().kind == tokOp && p.peek().text == "(" {
+		return p.call(t)
+	}
+	switch t.text {
+	case "true", "false":
+		b := t.text == "true"
+		return policyExpr{boolType, func(*policyEnv) any { return b }}, nil
+	case "in":
+		return policyExpr{}, fmt.Errorf("offset %d: unexpected %s", t.off, t)
+	}
+	if v, ok := policyVars[t.text]; ok {
+		if v.kind != p.kind {
+			return policyExpr{}, fmt.Errorf("offset %d: %s can't be used in a %s", t.off, t.text, p.kind)
+		}
+		return policyExpr{v.typ, v.get}, nil
+	}
+	if _, ok := policyFuncs[t.text]; ok {
+		return policyExpr{}, fmt.Errorf("offset %d: function %s must be called", t.off, t.text)
+	}
+	named, err := p.lookup(t.text)
+	if err != nil {
+		return policyExpr{}, fmt.Errorf("offset %d: %v", t.off, err)
+	}
+	p.policy.strs = append(p.policy.strs, named.strs...)
+	p.policy.ints = append(p.policy.ints, named.ints...)
+	p.policy.refs = append(p.policy.refs, t.text)
+	p.policy.refs = append(p.policy.refs, named.refs...)
+	return policyExpr{boolType, func(e *policyEnv) any { return named.eval(e) }}, nil
+}
+
+// call parses the arguments of a call to the function named by t.
+func (p *policyParser) call(t token) (policyExpr, error) {
+	fn, ok := policyFuncs[t.text]
+	if !ok {
+		return policyExpr{}, fmt.Errorf("offset %d: undefined function %s", t.off, t.text)
+	}
+	p.next() // "("
+	var args []policyExpr
+	for !p.accept(")") {
+		if len(args) > 0 {
+			if err := p.expect(","); err != nil {
+				return policyExpr{}, err
+			}
+		}
+		x, err := p.expr()
+		if err != nil {
+			return x, err
+		}
+		args = append(args, x)
+	}
+	if len(args) != len(fn.args) {
+		return policyExpr{}, fmt.Errorf("offset %d: %s takes %d arguments, got %d", t.off, t.text, len(fn.args), len(args))
+	}
+	for i, x := range args {
+		if x.typ != fn.args[i] {
+			return policyExpr{}, fmt.Errorf("offset %d: argument %d of %s has type %s, want %s", t.off, i+1, t.text, x.typ, fn.args[i])
+		}
+	}
+	return policyExpr{boolType, func(e *policyEnv) any {
+		vals := make([]any, len(args))
+		for i, x := range args {
+			vals[i] = x.eval(e)
+		}
+		return fn.call(vals)
+	}}, nil
+}
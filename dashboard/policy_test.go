@@ -0,0 +1,131 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dashboard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRepoPolicy(t *testing.T) {
+	named, err := compilePolicies(repoPolicy, map[string]string{
+		"onlyMaster":  `branch == "master" && goBranch == "master"`,
+		"masterOrNet": `onlyMaster || repo == "net"`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		src                    string
+		repo, branch, goBranch string
+		want                   bool
+	}{
+		{`true`, "go", "master", "master", true},
+		{`!true`, "go", "master", "master", false},
+		{`repo == "go"`, "go", "master", "master", true},
+		{`repo != "go"`, "go", "master", "master", false},
+		{`repo in ["net", "sys"]`, "sys", "master", "master", true},
+		{`repo in []`, "sys", "master", "master", false},
+		{`!(repo in ["net", "sys"])`, "tools", "master", "master", true},
+		{`buildRepoByDefault(repo)`, "exp", "master", "master", false},
+		{`buildRepoByDefault(repo) || repo == "exp"`, "exp", "master", "master", true},
+		{`atLeastGo1(goBranch, 21)`, "net", "master", "release-branch.go1.20", false},
+		{`atLeastGo1(goBranch, 21)`, "net", "master", "release-branch.go1.21", true},
+		{`atMostGo1(branch, 21)`, "go", "master", "master", false},
+		{`hasPrefix(branch, "dev.")`, "go", "dev.boringcrypto", "dev.boringcrypto", true},
+		{`contains("a\"b", "\"")`, "go", "master", "master", true},
+		{`false || true && false`, "go", "master", "master", false},
+		{`(false || true) && !false`, "go", "master", "master", true},
+		{`onlyMaster`, "go", "release-branch.go1.21", "release-branch.go1.21", false},
+		{`masterOrNet`, "net", "release-branch.go1.21", "release-branch.go1.21", true},
+	}
+	for _, tt := range tests {
+		p, err := compilePolicy(repoPolicy, tt.src, named)
+		if err != nil {
+			t.Errorf("compilePolicy(%q): %v", tt.src, err)
+			continue
+		}
+		if got := p.repoFunc()(tt.repo, tt.branch, tt.goBranch); got != tt.want {
+			t.Errorf("%s for (%q, %q, %q) = %v, want %v", tt.src, tt.repo, tt.branch, tt.goBranch, got, tt.want)
+		}
+	}
+}
+
+func TestDistTestPolicy(t *testing.T) {
+	p, err := compilePolicy(distTestPolicy, `run && !(try && (hasPrefix(test, "test:") || test == "reboot"))`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := p.distTestFunc()
+	for _, tt := range []struct {
+		run  bool
+		test string
+		try  bool
+		want bool
+	}{
+		{true, "reboot", false, true},
+		{true, "reboot", true, false},
+		{true, "test:0_5", true, false},
+		{true, "go_test:runtime", true, true},
+		{false, "go_test:runtime", false, false},
+	} {
+		if got := f(tt.run, tt.test, tt.try); got != tt.want {
+			t.Errorf("f(%v, %q, %v) = %v, want %v", tt.run, tt.test, tt.try, got, tt.want)
+		}
+	}
+}
+
+func TestPolicyErrors(t *testing.T) {
+	tests := []struct {
+		kind policyKind
+		src  string
+		want string
+	}{
+		{repoPolicy, ``, "offset 0: unexpected end of policy"},
+		{repoPolicy, `repo`, "policy has type string, want bool"},
+		{repoPolicy, `repo == 1`, "offset 5: mismatched types string and int"},
+		{repoPolicy, `repo && true`, "offset 5: && applied to string and bool, want bool"},
+		{repoPolicy, `!repo`, "offset 0: ! applied to string, want bool"},
+		{repoPolicy, `repo == "go" true`, `offset 13: unexpected "true"`},
+		{repoPolicy, `repo in ["go" "net"]`, `offset 14: unexpected "\"net\"", want ","`},
+		{repoPolicy, `repo in [repo]`, `offset 9: unexpected "repo", want string`},
+		{repoPolicy, `repo == "go`, "offset 8: unterminated string"},
+		{repoPolicy, `repo = "go"`, `offset 5: unexpected character '='`},
+		{repoPolicy, `test == "api"`, "offset 0: test can't be used in a repo policy"},
+		{distTestPolicy, `repo == "go"`, "offset 0: repo can't be used in a dist test policy"},
+		{repoPolicy, `atLeastGo1(branch)`, "offset 0: atLeastGo1 takes 2 arguments, got 1"},
+		{repoPolicy, `atLeastGo1(branch, "21")`, "offset 0: argument 2 of atLeastGo1 has type string, want int"},
+		{repoPolicy, `hasPrefix`, "offset 0: function hasPrefix must be called"},
+		{repoPolicy, `nope(repo)`, "offset 0: undefined function nope"},
+		{repoPolicy, `repo == "go" || nope`, "offset 16: undefined: nope"},
+	}
+	for _, tt := range tests {
+		_, err := compilePolicy(tt.kind, tt.src, nil)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("compilePolicy(%v, %q) = %v, want %q", tt.kind, tt.src, err, tt.want)
+		}
+	}
+}
+
+func TestCompilePoliciesErrors(t *testing.T) {
+	_, err := compilePolicies(repoPolicy, map[string]string{
+		"a":    `b`,
+		"b":    `a`,
+		"repo": `true`,
+		"x-y":  `true`,
+	})
+	if err == nil {
+		t.Fatal("compilePolicies succeeded, want error")
+	}
+	for _, want := range []string{
+		"a: offset 0: in policy b: offset 0: policy a refers to itself",
+		"repo: policy name is predeclared",
+		`"x-y": invalid policy name`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("compilePolicies error:\n%v\nwant it to contain %q", err, want)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/build/devapp/internal/str"
+)
+
+// A Distribution summarizes a set of values.
+type Distribution struct {
+	Count  int     `json:"count"`
+	Median float64 `json:"median"`
+	P90    float64 `json:"p90"` // 90th percentile
+	Mean   float64 `json:"mean"`
+}
+
+// distribution returns the distribution of xs, sorting xs.
+func distribution(xs []float64) Distribution {
+	if len(xs) == 0 {
+		return Distribution{}
+	}
+	slices.Sort(xs)
+	var sum float64
+	for _, x := range xs {
+		sum += x
+	}
+	return Distribution{
+		Count:  len(xs),
+		Median: percentile(xs, 50),
+		P90:    percentile(xs, 90),
+		Mean:   sum / float64(len(xs)),
+	}
+}
+
+// percentile returns the p'th percentile of the sorted values xs,
+// by the nearest-rank method.
+func percentile(xs []float64, p int) float64 {
+	i := int(math.Ceil(float64(p)/100*float64(len(xs)))) - 1
+	return xs[max(i, 0)]
+}
+
+// Latency holds the review latency metrics of a set of CLs.
+// Durations are in hours.
+type Latency struct {
+	// FirstReview is the time from creation to first review
+	// of the CLs first reviewed in the window of the query.
+	FirstReview Distribution `json:"firstReview"`
+
+	// Submit is the time from creation to submission, and Rounds
+	// the number of review rounds, of the CLs merged in the window.
+	Submit Distribution `json:"submit"`
+	Rounds Distribution `json:"rounds"`
+}
+
+// LatencyOf returns the review latency metrics of cls,
+// counting the events in the time window of q.
+func LatencyOf(cls []*CL, q Query) Latency {
+	var firstReview, submit, rounds []float64
+	for _, c := range cls {
+		if q.in(c.FirstReview) {
+			firstReview = append(firstReview, c.FirstReview.Sub(c.Created).Hours())
+		}
+		if c.Merged() && q.in(c.Closed) {
+			submit = append(submit, c.Closed.Sub(c.Created).Hours())
+			rounds = append(rounds, float64(c.Rounds))
+		}
+	}
+	return Latency{
+		FirstReview: distribution(firstReview),
+		Submit:      distribution(submit),
+		Rounds:      distribution(rounds),
+	}
+}
+
+// A LatencyPoint is the review latency in a week.
+type LatencyPoint struct {
+	Week time.Time `json:"week"` // start of the week
+	Latency
+}
+
+// WeeklyLatency returns the review latency metrics of cls in
+// each week of the time window of q, starting on Mondays (UTC).
+// The window must be set.
+func WeeklyLatency(cls []*CL, q Query) []LatencyPoint {
+	var points []LatencyPoint
+	for week := startOfWeek(q.Since); week.Before(q.Until); week = week.AddDate(0, 0, 7) {
+		wq := q
+		wq.Since, wq.Until = week, week.AddDate(0, 0, 7)
+		points = append(points, LatencyPoint{Week: week, Latency: LatencyOf(cls, wq)})
+	}
+	return points
+}
+
+// startOfWeek returns the start of the week (UTC) that contains t.
+func startOfWeek(t time.Time) time.Time {
+	t = t.UTC()
+	y, m, d := t.Date()
+	days := (int(t.Weekday()) + 6) % 7 // days since Monday
+	return time.Date(y, m, d-days, 0, 0, 0, 0, time.UTC)
+}
+
+// A ReviewerLoad is the review load of a reviewer.
+type ReviewerLoad struct {
+	Reviewer string `json:"reviewer"` // Gerrit email
+
+	// Reviewed is the number of CLs the reviewer reviewed
+	// that were open in the window of the query.
+	Reviewed int `json:"reviewed"`
+
+	// Open is the number of open CLs the reviewer is assigned to.
+	Open int `json:"open"`
+
+	// FirstReview is the time to first review, in hours,
+	// of the CLs the reviewer was the first to review
+	// in the window of the query.
+	FirstReview Distribution `json:"firstReview"`
+}
+
+// ReviewerLoads returns the review load of each reviewer of cls,
+// ordered by decreasing number of open and reviewed CLs.
+func ReviewerLoads(cls []*CL, q Query) []ReviewerLoad {
+	loads := make(map[string]*ReviewerLoad)
+	firstReview := make(map[string][]float64)
+	load := func(r string) *ReviewerLoad {
+		l := loads[r]
+		if l == nil {
+			l = &ReviewerLoad{Reviewer: r}
+			loads[r] = l
+		}
+		return l
+	}
+	for _, c := range cls {
+		for _, r := range c.Reviewers {
+			load(r).Reviewed++
+		}
+		if c.Status == "new" {
+			for _, r := range c.Assigned {
+				load(r).Open++
+			}
+		}
+		if len(c.Reviewers) > 0 && q.in(c.FirstReview) {
+			r := c.Reviewers[0]
+			firstReview[r] = append(firstReview[r], c.FirstReview.Sub(c.Created).Hours())
+		}
+	}
+	var list []ReviewerLoad
+	for r, l := range loads {
+		if q.Reviewer != "" && r != q.Reviewer {
+			continue
+		}
+		l.FirstReview = distribution(firstReview[r])
+		list = append(list, *l)
+	}
+	slices.SortFunc(list, func(a, b ReviewerLoad) int {
+		return cmp.Or(
+			cmp.Compare(b.Open, a.Open),
+			cmp.Compare(b.Reviewed, a.Reviewed),
+			strings.Compare(a.Reviewer, b.Reviewer),
+		)
+	})
+	return list
+}
+
+// A DirThroughput is the throughput of CLs in a directory.
+type DirThroughput struct {
+	Dir       string `json:"dir"`       // in the form <project>/<dir>
+	Opened    int    `json:"opened"`    // CLs created in the window of the query
+	Merged    int    `json:"merged"`    // CLs merged in the window
+	Abandoned int    `json:"abandoned"` // CLs abandoned in the window
+	Open      int    `json:"open"`      // CLs open at the end of the window
+
+	// Submit is the time from creation to submission,
+	// in hours, of the CLs merged in the window.
+	Submit Distribution `json:"submit"`
+}
+
+// DirThroughputs returns the throughput of CLs in each directory
+// that cls change files in, ordered by directory. If depth is
+// positive, directories are truncated to their first depth
+// elements below the root of the repo, so the throughput of a
+// directory includes that of its subdirectories. If q.Dir is set,
+// only it and the directories below it are included.
+func DirThroughputs(cls []*CL, q Query, depth int) []DirThroughput {
+	dirs := make(map[string]*DirThroughput)
+	submit := make(map[string][]float64)
+	for _, c := range cls {
+		seen := make(map[string]bool)
+		for _, dir := range c.Dirs {
+			if depth > 0 && dir != "." {
+				if elems := strings.Split(dir, "/"); len(elems) > depth {
+					dir = strings.Join(elems[:depth], "/")
+				}
+			}
+			dir = repoDir(c.Project, dir)
+			if seen[dir] || q.Dir != "" && !str.HasPathPrefix(dir, q.Dir) {
+				continue
+			}
+			seen[dir] = true
+			d := dirs[dir]
+			if d == nil {
+				d = &DirThroughput{Dir: dir}
+				dirs[dir] = d
+			}
+			if q.in(c.Created) {
+				d.Opened++
+			}
+			if q.in(c.Closed) {
+				if c.Merged() {
+					d.Merged++
+					submit[dir] = append(submit[dir], c.Closed.Sub(c.Created).Hours())
+				} else {
+					d.Abandoned++
+				}
+			}
+			if c.Closed.IsZero() || !q.Until.IsZero() && !c.Closed.Before(q.Until) {
+				d.Open++
+			}
+		}
+	}
+	var list []DirThroughput
+	for dir, d := range dirs {
+		d.Submit = distribution(submit[dir])
+		list = append(list, *d)
+	}
+	slices.SortFunc(list, func(a, b DirThroughput) int {
+		return strings.Compare(a.Dir, b.Dir)
+	})
+	return list
+}
@@ -0,0 +1,309 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package metrics computes review metrics for the Gerrit CLs in a
+// maintner corpus, such as the time to first review, the time to
+// submit, the number of review rounds, the load of reviewers, and
+// the throughput of directories.
+//
+// The facts about CLs that the metrics are computed from are kept in a
+// History, which is updated incrementally as the corpus changes.
+package metrics
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/build/devapp/internal/str"
+	"golang.org/x/build/devapp/owners"
+	"golang.org/x/build/internal/gophers"
+	"golang.org/x/build/maintner"
+)
+
+// A CL holds the facts about a Gerrit CL that metrics are computed from.
+type CL struct {
+	Project string    `json:"project"` // Gerrit project, such as "go"
+	Number  int32     `json:"number"`
+	Branch  string    `json:"branch"`
+	Owner   string    `json:"owner"`  // Gerrit email of the owner, if known
+	Status  string    `json:"status"` // "new", "merged", or "abandoned"
+	Created time.Time `json:"created"`
+
+	// FirstReview is when someone other than the owner
+	// first reviewed the CL, if anyone has.
+	FirstReview time.Time `json:"firstReview,omitzero"`
+
+	// Closed is when the CL was merged or abandoned, if it was.
+	Closed time.Time `json:"closed,omitzero"`
+
+	// Rounds is the number of patch sets that were reviewed
+	// by someone other than the owner.
+	Rounds int `json:"rounds"`
+
+	// Reviewers are the Gerrit emails of the people other than the
+	// owner who reviewed the CL, in the order of their first review.
+	// People not known to the internal/gophers package are identified
+	// by their Gerrit account instead.
+	Reviewers []string `json:"reviewers"`
+
+	// Assigned are the Gerrit emails of the current reviewers of the CL,
+	// as reported by owners.Reviewers.
+	Assigned []string `json:"assigned"`
+
+	// Dirs are the directories of the files changed by the latest
+	// patch set of the CL, relative to the root of the repo,
+	// with "." for the root itself.
+	Dirs []string `json:"dirs"`
+
+	meta maintner.GitHash // the meta commit the facts are from
+}
+
+// Merged reports whether c was merged.
+func (c *CL) Merged() bool { return c.Status == "merged" }
+
+// newCL returns the facts about cl, a CL in project p.
+func newCL(p *maintner.GerritProject, cl *maintner.GerritCL) *CL {
+	c := &CL{
+		Project: p.Project(),
+		Number:  cl.Number,
+		Branch:  cl.Branch(),
+		Status:  cl.Status,
+		Created: cl.Created,
+		meta:    cl.Meta.Commit.Hash,
+	}
+	if o := cl.Owner(); o != nil {
+		c.Owner = gerritEmail(o.Email())
+	}
+
+	if c.Status == "merged" || c.Status == "abandoned" {
+		for _, m := range slices.Backward(cl.Metas) {
+			if strings.Contains(m.Commit.Msg, "autogenerated:gerrit:merged") ||
+				strings.Contains(m.Commit.Msg, "autogenerated:gerrit:abandon") {
+				c.Closed = m.Commit.CommitTime
+				break
+			}
+		}
+	}
+
+	// Messages from the owner are identified by the owner's
+	// Gerrit account, since the CL owner's email is a real one.
+	ownerAccount := strconv.Itoa(cl.OwnerID()) + "@"
+	rounds := make(map[int32]bool)
+	for _, m := range cl.Messages {
+		if m.Author == nil || isAutomated(m) {
+			continue
+		}
+		account := m.Author.Email()
+		if strings.HasPrefix(account, ownerAccount) {
+			continue
+		}
+		if p := gophers.GetPerson(account); p != nil && p.Bot {
+			continue
+		}
+		if c.FirstReview.IsZero() {
+			c.FirstReview = m.Date
+		}
+		if r := gerritEmail(account); !slices.Contains(c.Reviewers, r) {
+			c.Reviewers = append(c.Reviewers, r)
+		}
+		rounds[m.Version] = true
+	}
+	c.Rounds = len(rounds)
+	c.Assigned = slices.Sorted(maps.Keys(owners.Reviewers(cl)))
+
+	dirs := make(map[string]bool)
+	for _, f := range cl.Commit.Files {
+		dirs[path.Dir(f.File)] = true
+	}
+	c.Dirs = slices.Sorted(maps.Keys(dirs))
+	return c
+}
+
+// isAutomated reports whether m was posted by automation,
+// such as a bot reporting the results of TryBots.
+func isAutomated(m *maintner.GerritMessage) bool {
+	return m.Meta != nil && strings.Contains(m.Meta.Msg, "\nTag: autogenerated:")
+}
+
+// gerritEmail returns the Gerrit email of the person with the
+// given email or Gerrit account, or email itself if the person
+// isn't known to the internal/gophers package.
+func gerritEmail(email string) string {
+	if p := gophers.GetPerson(email); p != nil && p.Gerrit != "" {
+		return p.Gerrit
+	}
+	return strings.ToLower(email)
+}
+
+type clKey struct {
+	project string
+	number  int32
+}
+
+// A History holds the facts about the CLs in a maintner corpus.
+// It's safe for concurrent use.
+type History struct {
+	mu  sync.RWMutex
+	cls map[clKey]*CL
+}
+
+// NewHistory returns a new, empty History.
+func NewHistory() *History {
+	return &History{cls: make(map[clKey]*CL)}
+}
+
+// Observe records the facts about cl, a CL in project p, if it has
+// changed since it was last observed, and reports whether it had.
+// Observing every CL after each update of a corpus keeps h up to date
+// with it, while only recomputing the facts about the CLs that changed.
+//
+// The caller must hold the corpus lock, if any, for reading.
+func (h *History) Observe(p *maintner.GerritProject, cl *maintner.GerritCL) bool {
+	key := clKey{p.Project(), cl.Number}
+	h.mu.RLock()
+	old := h.cls[key]
+	h.mu.RUnlock()
+	if old != nil && old.meta == cl.Meta.Commit.Hash {
+		return false
+	}
+	c := newCL(p, cl)
+	h.mu.Lock()
+	h.cls[key] = c
+	h.mu.Unlock()
+	return true
+}
+
+// Add adds c to h, replacing any CL with the same project and number.
+func (h *History) Add(c *CL) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.cls[clKey{c.Project, c.Number}] = c
+}
+
+// Len returns the number of CLs in h.
+func (h *History) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.cls)
+}
+
+// CLs returns the CLs in h that match q, ordered by project and number.
+// The CLs must not be modified.
+func (h *History) CLs(q Query) []*CL {
+	h.mu.RLock()
+	var cls []*CL
+	for _, c := range h.cls {
+		if q.Match(c) {
+			cls = append(cls, c)
+		}
+	}
+	h.mu.RUnlock()
+	slices.SortFunc(cls, func(a, b *CL) int {
+		return cmp.Or(strings.Compare(a.Project, b.Project), cmp.Compare(a.Number, b.Number))
+	})
+	return cls
+}
+
+// A Query selects the CLs that metrics are computed over.
+// Empty fields match all CLs.
+type Query struct {
+	Project  string // Gerrit project
+	Branch   string
+	Dir      string // directory, in the form <project>/<dir>, that the CL changes files in or below
+	Owner    string // Gerrit email of the owner
+	Reviewer string // Gerrit email of a reviewer
+
+	// Since and Until are the time window of the query.
+	// A CL matches if it was open at any time in the window.
+	// Metrics about events, such as the time to submit, only
+	// count the events that happened in the window.
+	Since, Until time.Time
+}
+
+// DefaultWindow is the time window of a query
+// that doesn't set Since.
+const DefaultWindow = 90 * 24 * time.Hour
+
+// ParseQuery returns the query for the URL query parameters
+// project, branch, dir, owner, reviewer, since, and until.
+// The times are dates in the form 2006-01-02, and default
+// to the DefaultWindow before now, and now.
+func ParseQuery(v map[string][]string, now time.Time) (Query, error) {
+	get := func(key string) string {
+		if vs := v[key]; len(vs) > 0 {
+			return vs[0]
+		}
+		return ""
+	}
+	q := Query{
+		Project:  get("project"),
+		Branch:   get("branch"),
+		Dir:      strings.TrimSuffix(get("dir"), "/"),
+		Owner:    get("owner"),
+		Reviewer: get("reviewer"),
+		Since:    now.Add(-DefaultWindow),
+		Until:    now,
+	}
+	for _, t := range []struct {
+		key string
+		t   *time.Time
+	}{
+		{"since", &q.Since},
+		{"until", &q.Until},
+	} {
+		if s := get(t.key); s != "" {
+			d, err := time.Parse(time.DateOnly, s)
+			if err != nil {
+				return Query{}, fmt.Errorf("invalid %s date %q", t.key, s)
+			}
+			*t.t = d
+		}
+	}
+	if !q.Since.Before(q.Until) {
+		return Query{}, fmt.Errorf("since %s isn't before until %s", q.Since.Format(time.DateOnly), q.Until.Format(time.DateOnly))
+	}
+	return q, nil
+}
+
+// Match reports whether q matches c.
+func (q Query) Match(c *CL) bool {
+	switch {
+	case q.Project != "" && c.Project != q.Project,
+		q.Branch != "" && c.Branch != q.Branch,
+		q.Owner != "" && c.Owner != q.Owner,
+		q.Reviewer != "" && !slices.Contains(c.Reviewers, q.Reviewer),
+		!q.Until.IsZero() && !c.Created.Before(q.Until),
+		!q.Since.IsZero() && !c.Closed.IsZero() && c.Closed.Before(q.Since):
+		return false
+	}
+	if q.Dir != "" {
+		return slices.ContainsFunc(c.Dirs, func(dir string) bool {
+			return str.HasPathPrefix(repoDir(c.Project, dir), q.Dir)
+		})
+	}
+	return true
+}
+
+// in reports whether t is in the time window of q.
+func (q Query) in(t time.Time) bool {
+	return !t.IsZero() &&
+		(q.Since.IsZero() || !t.Before(q.Since)) &&
+		(q.Until.IsZero() || t.Before(q.Until))
+}
+
+// repoDir returns the directory dir of the repo of project,
+// in the form <project>/<dir> used by queries.
+func repoDir(project, dir string) string {
+	if dir == "." {
+		return project
+	}
+	return project + "/" + dir
+}
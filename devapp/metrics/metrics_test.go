@@ -0,0 +1,184 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"golang.org/x/build/maintner"
+	"golang.org/x/build/maintner/maintpb"
+)
+
+var t0 = time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+
+func testCL() *maintner.GerritCL {
+	const uuid = "@62eb7196-b449-3ce5-99f1-c037f21e1705>"
+	meta := func(h byte, d time.Duration, author, msg string) *maintner.GitCommit {
+		return &maintner.GitCommit{
+			Hash:       maintner.GitHash([]byte{h}),
+			Author:     &maintner.GitPerson{Str: author},
+			Msg:        "Update patch set 1\n\n" + msg,
+			CommitTime: t0.Add(d),
+		}
+	}
+	owner := "Gerrit User 1000 <1000" + uuid
+	iant := "Gerrit User 5206 <5206" + uuid
+	austin := "Gerrit User 5167 <5167" + uuid
+	luci := "Go LUCI <60063" + uuid
+	metas := []*maintner.GitCommit{
+		meta(1, 0, owner, "Patch-set: 1\nReviewer: "+iant+"\n"),
+		meta(2, time.Hour, luci, "Patch-set: 1\nTag: autogenerated:trybots~happy\n"),
+		meta(3, 2*time.Hour, iant, "Patch-set: 1\nLabel: Code-Review+1\n"),
+		meta(4, 3*time.Hour, owner, "Patch-set: 2\n"),
+		meta(5, 5*time.Hour, austin, "Patch-set: 2\nLabel: Code-Review+2\n"),
+		meta(6, 5*time.Hour, austin, "Patch-set: 2\nReviewer: "+austin+"\n"),
+		meta(7, 24*time.Hour, owner, "Patch-set: 2\nStatus: merged\nTag: autogenerated:gerrit:merged\n"),
+	}
+	cl := &maintner.GerritCL{
+		Project: &maintner.GerritProject{},
+		Number:  12345,
+		Created: t0,
+		Status:  "merged",
+		Commit: &maintner.GitCommit{Files: []*maintpb.GitDiffTreeFile{
+			{File: "src/net/http/server.go"},
+			{File: "src/net/http/client.go"},
+			{File: "README.md"},
+		}},
+	}
+	for _, m := range metas {
+		cl.Metas = append(cl.Metas, &maintner.GerritMeta{Commit: m, CL: cl})
+	}
+	cl.Meta = cl.Metas[len(cl.Metas)-1]
+	for i, v := range []int32{1, 1, 1, 2, 2} {
+		m := metas[i]
+		cl.Messages = append(cl.Messages, &maintner.GerritMessage{Meta: m, Version: v, Date: m.CommitTime, Author: m.Author})
+	}
+	return cl
+}
+
+func TestNewCL(t *testing.T) {
+	got := newCL(&maintner.GerritProject{}, testCL())
+	want := &CL{
+		Number:      12345,
+		Status:      "merged",
+		Created:     t0,
+		FirstReview: t0.Add(2 * time.Hour),
+		Closed:      t0.Add(24 * time.Hour),
+		Rounds:      2,
+		Reviewers:   []string{"iant@golang.org", "austin@google.com"},
+		Assigned:    []string{"austin@google.com", "iant@golang.org"},
+		Dirs:        []string{".", "src/net/http"},
+		meta:        maintner.GitHash("\x07"),
+	}
+	if diff := cmp.Diff(got, want, cmp.AllowUnexported(CL{})); diff != "" {
+		t.Errorf("newCL: (-got +want)\n%s", diff)
+	}
+}
+
+func TestHistoryObserve(t *testing.T) {
+	h := NewHistory()
+	p := &maintner.GerritProject{}
+	cl := testCL()
+	if !h.Observe(p, cl) {
+		t.Errorf("first Observe = false, want true")
+	}
+	if h.Observe(p, cl) {
+		t.Errorf("Observe of unchanged CL = true, want false")
+	}
+	cl.Meta = &maintner.GerritMeta{Commit: &maintner.GitCommit{Hash: maintner.GitHash("\x08")}, CL: cl}
+	cl.Metas = append(cl.Metas, cl.Meta)
+	if !h.Observe(p, cl) {
+		t.Errorf("Observe of changed CL = false, want true")
+	}
+	if h.Len() != 1 {
+		t.Errorf("Len = %d, want 1", h.Len())
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	now := t0
+	q, err := ParseQuery(url.Values{"project": {"go"}, "dir": {"go/src/net/"}, "since": {"2026-08-01"}}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Query{Project: "go", Dir: "go/src/net", Since: time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC), Until: now}
+	if q != want {
+		t.Errorf("ParseQuery = %+v, want %+v", q, want)
+	}
+	if q, _ := ParseQuery(nil, now); !q.Since.Equal(now.Add(-DefaultWindow)) {
+		t.Errorf("ParseQuery(nil).Since = %v, want %v before now", q.Since, DefaultWindow)
+	}
+	for _, v := range []url.Values{
+		{"since": {"yesterday"}},
+		{"since": {"2026-09-02"}, "until": {"2026-09-01"}},
+	} {
+		if _, err := ParseQuery(v, now); err == nil {
+			t.Errorf("ParseQuery(%v) succeeded, want error", v)
+		}
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	day := 24 * time.Hour
+	h := NewHistory()
+	for _, c := range []*CL{
+		{Project: "go", Number: 1, Status: "merged", Created: t0, FirstReview: t0.Add(2 * time.Hour), Closed: t0.Add(2 * day),
+			Rounds: 2, Reviewers: []string{"a@golang.org", "b@golang.org"}, Dirs: []string{"src/net/http", "src/net/http/internal"}},
+		{Project: "go", Number: 2, Status: "merged", Created: t0, FirstReview: t0.Add(4 * time.Hour), Closed: t0.Add(4 * day),
+			Rounds: 1, Reviewers: []string{"b@golang.org"}, Dirs: []string{"src/os"}},
+		{Project: "go", Number: 3, Status: "new", Created: t0.Add(day), Reviewers: []string{}, Assigned: []string{"a@golang.org"}, Dirs: []string{"src/net/http"}},
+		{Project: "go", Number: 4, Status: "abandoned", Created: t0.Add(-30 * day), Closed: t0.Add(-20 * day), Dirs: []string{"src/os"}},
+		{Project: "tools", Number: 5, Status: "merged", Created: t0, FirstReview: t0.Add(6 * time.Hour), Closed: t0.Add(6 * day),
+			Rounds: 3, Reviewers: []string{"c@golang.org"}, Dirs: []string{"."}},
+	} {
+		h.Add(c)
+	}
+	q := Query{Since: t0, Until: t0.Add(7 * day)}
+	cls := h.CLs(q)
+	if len(cls) != 4 {
+		t.Fatalf("CLs(%+v) has %d CLs, want 4", q, len(cls))
+	}
+
+	if got, want := LatencyOf(cls, q), (Latency{
+		FirstReview: Distribution{Count: 3, Median: 4, P90: 6, Mean: 4},
+		Submit:      Distribution{Count: 3, Median: 96, P90: 144, Mean: 96},
+		Rounds:      Distribution{Count: 3, Median: 2, P90: 3, Mean: 2},
+	}); got != want {
+		t.Errorf("LatencyOf = %+v, want %+v", got, want)
+	}
+	if got := WeeklyLatency(cls, q); len(got) != 2 || got[0].Week.Weekday() != time.Monday || got[0].FirstReview.Count != 3 {
+		t.Errorf("WeeklyLatency = %+v, want 2 weeks starting on Monday, with 3 first reviews in the first", got)
+	}
+
+	wantLoads := []ReviewerLoad{
+		{Reviewer: "a@golang.org", Reviewed: 1, Open: 1, FirstReview: Distribution{Count: 1, Median: 2, P90: 2, Mean: 2}},
+		{Reviewer: "b@golang.org", Reviewed: 2, FirstReview: Distribution{Count: 1, Median: 4, P90: 4, Mean: 4}},
+		{Reviewer: "c@golang.org", Reviewed: 1, FirstReview: Distribution{Count: 1, Median: 6, P90: 6, Mean: 6}},
+	}
+	if diff := cmp.Diff(ReviewerLoads(cls, q), wantLoads); diff != "" {
+		t.Errorf("ReviewerLoads: (-got +want)\n%s", diff)
+	}
+
+	q.Project, q.Dir = "go", "go/src/net"
+	wantDirs := []DirThroughput{
+		{Dir: "go/src/net", Opened: 2, Merged: 1, Open: 1, Submit: Distribution{Count: 1, Median: 48, P90: 48, Mean: 48}},
+	}
+	if diff := cmp.Diff(DirThroughputs(h.CLs(q), q, 2), wantDirs); diff != "" {
+		t.Errorf("DirThroughputs: (-got +want)\n%s", diff)
+	}
+	q.Dir = ""
+	got := DirThroughputs(h.CLs(q), q, 0)
+	if diff := cmp.Diff(got, []DirThroughput{
+		{Dir: "go/src/net/http", Opened: 2, Merged: 1, Open: 1, Submit: Distribution{Count: 1, Median: 48, P90: 48, Mean: 48}},
+		{Dir: "go/src/net/http/internal", Opened: 1, Merged: 1, Submit: Distribution{Count: 1, Median: 48, P90: 48, Mean: 48}},
+		{Dir: "go/src/os", Opened: 1, Merged: 1, Submit: Distribution{Count: 1, Median: 96, P90: 96, Mean: 96}},
+	}, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("DirThroughputs without depth: (-got +want)\n%s", diff)
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package owners
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"golang.org/x/build/internal/gophers"
+)
+
+// FileName is the name of the files that hold ownership rules
+// in a repository.
+const FileName = "OWNERS"
+
+// A File is a parsed OWNERS file.
+//
+// An OWNERS file is made of lines in the style of GitHub's CODEOWNERS
+// files. Blank lines and text following a '#' are ignored. Each other
+// line is either a rule:
+//
+//	pattern owner... [secondary: owner...]
+//
+// or the directive
+//
+//	set noparent
+//
+// Owners are GitHub usernames (@rsc), GitHub teams (@golang/compiler),
+// or email addresses, and must be known to the internal/gophers package.
+// The owners following "secondary:" are the secondary owners.
+//
+// Patterns are matched against the slash-separated path of a file,
+// relative to the directory of the OWNERS file:
+//
+//   - A pattern without a slash, such as *.s or zip.go, matches
+//     a file or directory of that name at any depth.
+//   - A pattern with a slash, such as /doc.go or internal/ssa, is
+//     relative to the directory of the OWNERS file, and matches the
+//     file or directory it names, as well as everything in that
+//     directory. A "**" element matches zero or more path elements.
+//   - The pattern * matches everything.
+//
+// The owners of a file are those of the last rule in the file that
+// matches it. A rule without owners matches files that have no owners.
+// A negated rule, with a pattern preceded by '!', excludes the files it
+// matches from the OWNERS file, as if no rule matched them.
+//
+// A file that no rule matches inherits its owners from the OWNERS files
+// in its parent directories, unless the OWNERS file sets noparent.
+type File struct {
+	Dir      string // slash-separated directory of the file, relative to the repo root
+	NoParent bool   // whether the file disables inheritance from parent directories
+	Rules    []*Rule
+}
+
+// A Rule is a rule in an OWNERS file.
+type Rule struct {
+	Pattern string // pattern, without a leading '!'
+	Negate  bool   // whether the pattern is negated
+	Entry   *Entry // the owners of the matched files, or nil if they have none
+}
+
+// ParseFile parses the OWNERS file in dir, a slash-separated path
+// relative to the repo root, with the given contents.
+func ParseFile(dir string, data []byte) (*File, error) {
+	name := path.Join(dir, FileName)
+	f := &File{Dir: dir}
+	var errs []error
+	for i, line := range strings.Split(string(data), "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if err := f.parseLine(fields); err != nil {
+			errs = append(errs, fmt.Errorf("%s:%d: %v", name, i+1, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *File) parseLine(fields []string) error {
+	if fields[0] == "set" {
+		if len(fields) != 2 || fields[1] != "noparent" {
+			return fmt.Errorf("unknown directive %q", strings.Join(fields, " "))
+		}
+		f.NoParent = true
+		return nil
+	}
+
+	r := &Rule{Pattern: fields[0]}
+	if p, ok := strings.CutPrefix(r.Pattern, "!"); ok {
+		r.Pattern, r.Negate = p, true
+	}
+	if err := checkPattern(r.Pattern); err != nil {
+		return err
+	}
+	if r.Negate {
+		if len(fields) > 1 {
+			return fmt.Errorf("negated pattern %s has owners", r.Pattern)
+		}
+		f.Rules = append(f.Rules, r)
+		return nil
+	}
+
+	e := &Entry{Primary: []Owner{}}
+	secondary := false
+	for _, s := range fields[1:] {
+		if s == "secondary:" {
+			if secondary {
+				return fmt.Errorf("duplicate secondary:")
+			}
+			secondary = true
+			continue
+		}
+		o, err := parseOwner(s)
+		if err != nil {
+			return err
+		}
+		if secondary {
+			e.Secondary = append(e.Secondary, o)
+		} else {
+			e.Primary = append(e.Primary, o)
+		}
+	}
+	if len(e.Primary) > 0 || len(e.Secondary) > 0 {
+		r.Entry = e
+	}
+	f.Rules = append(f.Rules, r)
+	return nil
+}
+
+// parseOwner returns the Owner for s, a GitHub username or team
+// with a leading '@', or an email address.
+func parseOwner(s string) (Owner, error) {
+	if !strings.Contains(s, "@") {
+		return Owner{}, fmt.Errorf("owner %q is not a GitHub name with a leading '@' or an email address", s)
+	}
+	p := gophers.GetPerson(s)
+	if p == nil {
+		return Owner{}, fmt.Errorf("owner %s does not exist in the golang.org/x/build/internal/gophers package", s)
+	}
+	return Owner{GitHubUsername: p.GitHub, GerritEmail: p.Gerrit}, nil
+}
+
+// checkPattern returns an error if pattern is malformed.
+func checkPattern(pattern string) error {
+	elems := strings.Split(strings.Trim(pattern, "/"), "/")
+	for _, elem := range elems {
+		if elem == "" || elem == "." || elem == ".." {
+			return fmt.Errorf("invalid pattern %s", pattern)
+		}
+		if elem == "**" {
+			continue
+		}
+		if strings.Contains(elem, "**") {
+			return fmt.Errorf("invalid pattern %s: ** must be a whole path element", pattern)
+		}
+		if _, err := path.Match(elem, ""); err != nil {
+			return fmt.Errorf("invalid pattern %s: %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+// match returns the entry for the file or directory with the
+// slash-separated path name, relative to f.Dir, and reports
+// whether f has a rule for it. If name is empty, it refers to
+// f.Dir itself, which only the patterns * and ** match.
+func (f *File) match(name string) (e *Entry, ok bool) {
+	for _, r := range slices.Backward(f.Rules) {
+		if matchPattern(r.Pattern, name) {
+			if r.Negate {
+				return nil, false
+			}
+			return r.Entry, true
+		}
+	}
+	return nil, false
+}
+
+// matchPattern reports whether the OWNERS pattern matches
+// the slash-separated path name, as described in the File docs.
+func matchPattern(pattern, name string) bool {
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.Trim(pattern, "/")
+	if name == "" {
+		return pattern == "*" || pattern == "**"
+	}
+	elems := strings.Split(name, "/")
+	if !anchored {
+		for _, elem := range elems {
+			if ok, _ := path.Match(pattern, elem); ok || pattern == "**" {
+				return true
+			}
+		}
+		return false
+	}
+	return matchPrefix(strings.Split(pattern, "/"), elems)
+}
+
+// matchPrefix reports whether the pattern elements in pattern
+// match a prefix of the path elements in elems.
+func matchPrefix(pattern, elems []string) bool {
+	if len(pattern) == 0 {
+		return true
+	}
+	if pattern[0] == "**" {
+		for i := range len(elems) + 1 {
+			if matchPrefix(pattern[1:], elems[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(elems) == 0 {
+		return false
+	}
+	ok, _ := path.Match(pattern[0], elems[0])
+	return ok && matchPrefix(pattern[1:], elems[1:])
+}
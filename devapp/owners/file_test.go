@@ -0,0 +1,122 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package owners
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTableMatch(t *testing.T) {
+	fsys := fstest.MapFS{
+		"OWNERS": {Data: []byte(`
+# Owners of the whole repo.
+*              @ianlancetaylor secondary: @rsc
+*.s            @golang/compiler @cherrymui
+/doc/          dmitshur@golang.org
+src/**/*_test.go @neild
+`)},
+		"src/cmd/compile/OWNERS": {Data: []byte(`
+set noparent
+*                     @golang/compiler secondary: @randall77
+!internal/test
+internal/types2/      # no owners
+`)},
+		"src/cmd/compile/internal/ssa/OWNERS": {Data: []byte(`
+* @randall77
+`)},
+		"src/testdata/OWNERS":  {Data: []byte("* @nobody-by-this-name-1234\n")},
+		"src/net/http/main.go": {Data: []byte("package main\n")},
+	}
+	table := NewTable()
+	if err := table.Load("go", fsys); err != nil {
+		t.Fatal(err)
+	}
+
+	root := &Entry{Primary: []Owner{iant}, Secondary: []Owner{rsc}}
+	compiler := &Entry{Primary: []Owner{compilerTeam}, Secondary: []Owner{khr}}
+	for _, tt := range []struct {
+		path string
+		want *Entry
+		ok   bool
+	}{
+		{"go", root, true},
+		{"go/src/net/http/main.go", root, true},
+		{"go/src/runtime/asm_amd64.s", &Entry{Primary: []Owner{compilerTeam, cherryyz}}, true},
+		{"go/doc/go_spec.html", &Entry{Primary: []Owner{dmitshur}}, true},
+		{"go/doc", &Entry{Primary: []Owner{dmitshur}}, true},
+		{"go/src/net/http/main_test.go", &Entry{Primary: []Owner{neild}}, true},
+		{"go/main_test.go", root, true},
+		{"go/src/cmd/compile", compiler, true},
+		{"go/src/cmd/compile/main.go", compiler, true},
+		{"go/src/cmd/compile/asm.s", compiler, true},              // nearest OWNERS file
+		{"go/src/cmd/compile/internal/test/a_test.go", nil, true}, // negated, noparent
+		{"go/src/cmd/compile/internal/types2/api.go", nil, true},  // no owners
+		{"go/src/cmd/compile/internal/ssa/rewrite.go", &Entry{Primary: []Owner{khr}}, true},
+		{"go/src/testdata/a.go", root, true},
+		{"tools/go/ssa/ssa.go", nil, false},
+	} {
+		got, ok := table.Match(tt.path)
+		if ok != tt.ok {
+			t.Errorf("Match(%q) ok = %v, want %v", tt.path, ok, tt.ok)
+		}
+		if diff := cmp.Diff(got, tt.want); diff != "" {
+			t.Errorf("Match(%q): owners differ (-got +want)\n%s", tt.path, diff)
+		}
+	}
+
+	// Reloading a repo into a clone leaves the original as it was.
+	clone := table.Clone()
+	if err := clone.Load("go", fstest.MapFS{"OWNERS": {Data: []byte("* @rsc\n")}}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := clone.Match("go/src/cmd/compile/main.go"); !cmp.Equal(got, &Entry{Primary: []Owner{rsc}}) {
+		t.Errorf("Match in reloaded clone = %v, want %v", got, rsc)
+	}
+	if got, _ := table.Match("go/src/cmd/compile/main.go"); !cmp.Equal(got, compiler) {
+		t.Errorf("Match in original after reloading clone = %v, want %v", got, compiler)
+	}
+
+	// Once installed, the table replaces the built-in entries for its repos.
+	SetTable(table)
+	defer SetTable(nil)
+	if got := match("go/src/archive/zip/a.go"); !cmp.Equal(got, root) {
+		t.Errorf("match with table installed = %v, want %v", got, root)
+	}
+	if got, want := match("crypto/chacha20poly1305/chacha20poly1305.go"), entries["crypto"]; got != want {
+		t.Errorf("match of repo not in table = %v, want %v", got, want)
+	}
+}
+
+func TestParseFileErrors(t *testing.T) {
+	_, err := ParseFile("src", []byte(`
+set parent
+!*.go @rsc
+a**b @rsc
+[ @rsc
+* rsc
+* @nobody-by-this-name-1234
+* @rsc secondary: @ianlancetaylor secondary: @neild
+`))
+	if err == nil {
+		t.Fatal("ParseFile succeeded, want error")
+	}
+	for _, want := range []string{
+		`src/OWNERS:2: unknown directive "set parent"`,
+		"src/OWNERS:3: negated pattern *.go has owners",
+		"src/OWNERS:4: invalid pattern a**b: ** must be a whole path element",
+		"src/OWNERS:5: invalid pattern [: syntax error in pattern",
+		`src/OWNERS:6: owner "rsc" is not a GitHub name with a leading '@' or an email address`,
+		"src/OWNERS:7: owner @nobody-by-this-name-1234 does not exist",
+		"src/OWNERS:8: duplicate secondary:",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseFile error:\n%v\nwant it to contain %q", err, want)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package owners
+
+import (
+	"errors"
+	"io/fs"
+	"maps"
+	"path"
+	"strings"
+	"sync/atomic"
+)
+
+// A Table holds the OWNERS files of a set of repositories.
+//
+// A Table must not be modified after it is passed to SetTable.
+// To update an installed Table, modify a Clone of it and install that.
+type Table struct {
+	files map[string]*File // <repo name>/<dir>, or <repo name> for the root -> File
+	repos map[string]bool  // repos that have been loaded
+}
+
+// NewTable returns a new, empty Table.
+func NewTable() *Table {
+	return &Table{
+		files: make(map[string]*File),
+		repos: make(map[string]bool),
+	}
+}
+
+// Clone returns a copy of t, which may be modified
+// even if t has been passed to SetTable.
+func (t *Table) Clone() *Table {
+	return &Table{
+		files: maps.Clone(t.files),
+		repos: maps.Clone(t.repos),
+	}
+}
+
+// Load loads the OWNERS files in fsys, the file tree of the repository
+// named repo (for example, "go" or "tools"), replacing any previously
+// loaded for it. Files in testdata directories, and in directories
+// whose names begin with a '.', are ignored.
+//
+// The file tree of a remote repository can be loaded with
+// golang.org/x/build/internal/gitfs.
+func (t *Table) Load(repo string, fsys fs.FS) error {
+	files := make(map[string]*File)
+	var errs []error
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if name != "." && (d.Name() == "testdata" || strings.HasPrefix(d.Name(), ".")) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.Name() != FileName {
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		dir := path.Dir(name)
+		if dir == "." {
+			dir = ""
+		}
+		f, err := ParseFile(dir, data)
+		if err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+		files[repoPath(repo, dir)] = f
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	for key := range t.files {
+		if key == repo || strings.HasPrefix(key, repo+"/") {
+			delete(t.files, key)
+		}
+	}
+	maps.Copy(t.files, files)
+	t.repos[repo] = true
+	return nil
+}
+
+// Match returns the owners entry for a path consisting of the repo name
+// and full path of a file or directory within that repo, as described
+// in the File docs. A directory matches the patterns * and ** of its own
+// OWNERS file, and the rules of its parents' OWNERS files.
+//
+// Match reports whether t has loaded the repo. If it hasn't,
+// or no rule gives the path owners, the entry is nil.
+func (t *Table) Match(p string) (e *Entry, ok bool) {
+	repo, rel, _ := strings.Cut(p, "/")
+	if !t.repos[repo] {
+		return nil, false
+	}
+	rel = strings.Trim(path.Clean("/"+rel), "/")
+	for dir := rel; ; {
+		if f := t.files[repoPath(repo, dir)]; f != nil {
+			name := strings.TrimPrefix(strings.TrimPrefix(rel, dir), "/")
+			if e, ok := f.match(name); ok {
+				return e, true
+			}
+			if f.NoParent {
+				return nil, true
+			}
+		}
+		if dir == "" {
+			return nil, true
+		}
+		dir = path.Dir(dir)
+		if dir == "." {
+			dir = ""
+		}
+	}
+}
+
+func repoPath(repo, dir string) string {
+	if dir == "" {
+		return repo
+	}
+	return repo + "/" + dir
+}
+
+// loadedTable is the Table installed by SetTable, if any.
+var loadedTable atomic.Pointer[Table]
+
+// SetTable installs t as the source of owners for the repos it has
+// loaded, in place of the entries built into this package.
+// The owners of other repos are unaffected.
+// A nil t uninstalls the previously installed Table.
+func SetTable(t *Table) {
+	loadedTable.Store(t)
+}
@@ -14,6 +14,7 @@ import (
 	"strings"
 	"sync"
 
+	"golang.org/x/build/devapp/internal/str"
 	"golang.org/x/build/repos"
 )
 
@@ -56,7 +57,8 @@ type Request struct {
 		Paths []string `json:"paths"`
 
 		// All indicates that the response must contain every available
-		// entry about code owners.
+		// entry about code owners. It doesn't include the rules of
+		// OWNERS files loaded by SetTable, which aren't keyed by path.
 		//
 		// If All is true, Paths must be empty.
 		All bool `json:"all"`
@@ -79,35 +81,24 @@ type Response struct {
 // match takes a path consisting of the repo name and full path of a file or
 // directory within that repo and returns the deepest Entry match in the file
 // hierarchy for the given resource.
+//
+// If the Table installed by SetTable has loaded the repo, match
+// uses its OWNERS files instead of the entries in this package.
 func match(path string) *Entry {
+	if t := loadedTable.Load(); t != nil {
+		if e, ok := t.Match(path); ok {
+			return e
+		}
+	}
 	var deepestPath string
 	for p := range entries {
-		if hasPathPrefix(path, p) && len(p) > len(deepestPath) {
+		if str.HasPathPrefix(path, p) && len(p) > len(deepestPath) {
 			deepestPath = p
 		}
 	}
 	return entries[deepestPath]
 }
 
-// hasPathPrefix reports whether the slash-separated path s
-// begins with the elements in prefix.
-//
-// Copied from go/src/cmd/go/internal/str.HasPathPrefix.
-func hasPathPrefix(s, prefix string) bool {
-	if len(s) == len(prefix) {
-		return s == prefix
-	}
-	if prefix == "" {
-		return true
-	}
-	if len(s) > len(prefix) {
-		if prefix[len(prefix)-1] == '/' || s[len(prefix)] == '/' {
-			return s[:len(prefix)] == prefix
-		}
-	}
-	return false
-}
-
 // Handler takes one or more paths and returns a map of each to a matching
 // Entry struct. If no Entry is matched for the path, the value for the key
 // is nil.
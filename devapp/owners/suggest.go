@@ -0,0 +1,210 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package owners
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/build/internal/foreach"
+	"golang.org/x/build/internal/gophers"
+	"golang.org/x/build/maintner"
+)
+
+// A Review is the assignment of a reviewer to a Gerrit CL.
+type Review struct {
+	Reviewer string    `json:"reviewer"` // Gerrit email of the reviewer
+	Project  string    `json:"project"`  // Gerrit project of the CL, such as "go"
+	CL       int32     `json:"cl"`
+	Assigned time.Time `json:"assigned"` // when the reviewer was last added to the CL
+	Open     bool      `json:"open"`     // whether the CL is open
+}
+
+// ReviewHistory returns the current reviewers of the CLs in corpus that
+// were updated since the given time. It omits reviewers that aren't known
+// to the internal/gophers package, and the owners of the CLs.
+//
+// The caller must hold the corpus lock, if any, for reading.
+func ReviewHistory(corpus *maintner.Corpus, since time.Time) ([]Review, error) {
+	var reviews []Review
+	err := corpus.Gerrit().ForeachProjectUnsorted(func(p *maintner.GerritProject) error {
+		return p.ForeachCLUnsorted(func(cl *maintner.GerritCL) error {
+			if cl.Private || cl.Meta.Commit.CommitTime.Before(since) {
+				return nil
+			}
+			var owner string
+			if o := cl.Owner(); o != nil {
+				if p := gophers.GetPerson(o.Email()); p != nil {
+					owner = p.Gerrit
+				}
+			}
+			assigned := Reviewers(cl)
+			for _, email := range slices.Sorted(maps.Keys(assigned)) {
+				if email == owner {
+					continue
+				}
+				reviews = append(reviews, Review{
+					Reviewer: email,
+					Project:  p.Project(),
+					CL:       cl.Number,
+					Assigned: assigned[email],
+					Open:     cl.Status == "new",
+				})
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(reviews, func(a, b Review) int {
+		return cmp.Or(
+			strings.Compare(a.Project, b.Project),
+			cmp.Compare(a.CL, b.CL),
+			strings.Compare(a.Reviewer, b.Reviewer),
+		)
+	})
+	return reviews, nil
+}
+
+// Reviewers returns the Gerrit emails of the current reviewers of cl,
+// mapped to the time they were last added as reviewers, from the
+// Reviewer, CC, and Removed footers of its meta commits. Reviewers that
+// aren't known to the internal/gophers package are omitted.
+func Reviewers(cl *maintner.GerritCL) map[string]time.Time {
+	assigned := make(map[string]time.Time)
+	for _, m := range cl.Metas {
+		if !strings.Contains(m.Commit.Msg, "Reviewer:") &&
+			!strings.Contains(m.Commit.Msg, "CC:") &&
+			!strings.Contains(m.Commit.Msg, "Removed:") {
+			continue
+		}
+		foreach.LineStr(m.Commit.Msg, func(ln string) error {
+			key, _, ok := strings.Cut(ln, ":")
+			if !ok || key != "Reviewer" && key != "CC" && key != "Removed" {
+				return nil
+			}
+			i, j := strings.LastIndexByte(ln, '<'), strings.LastIndexByte(ln, '>')
+			if i < 0 || j < i {
+				return nil
+			}
+			p := gophers.GetPerson(ln[i+1 : j])
+			if p == nil || p.Gerrit == "" {
+				return nil
+			}
+			if key == "Reviewer" {
+				assigned[p.Gerrit] = m.Commit.CommitTime
+			} else {
+				delete(assigned, p.Gerrit)
+			}
+			return nil
+		})
+	}
+	return assigned
+}
+
+// A SuggestRequest is a request for reviewer suggestions.
+type SuggestRequest struct {
+	Paths  []string `json:"paths"`  // paths touched by the CL, as in Request
+	Author string   `json:"author"` // Gerrit email of the CL author
+}
+
+// A SuggestResponse is the response to a SuggestRequest.
+type SuggestResponse struct {
+	Suggestions []Suggestion `json:"suggestions"`
+	Error       string       `json:"error,omitempty"`
+}
+
+// A Suggestion is a reviewer suggested by Suggest.
+type Suggestion struct {
+	Owner     Owner `json:"owner"`
+	Primary   int   `json:"primary"`   // number of paths the reviewer is a primary owner of
+	Secondary int   `json:"secondary"` // number of other paths the reviewer is a secondary owner of
+	Open      int   `json:"open"`      // number of open CLs the reviewer is assigned to
+	Recent    int   `json:"recent"`    // number of CLs the reviewer was assigned to in the last 30 days
+
+	// Score is the ownership of the reviewer, from 0 to 1,
+	// discounted by their load. See Suggest.
+	Score float64 `json:"score"`
+}
+
+// recentReviews is the period in which Suggest counts
+// a review towards the recent load of a reviewer.
+const recentReviews = 30 * 24 * time.Hour
+
+// Suggest suggests reviewers for a CL by author, a Gerrit email, that
+// touches paths, in the form of the paths in a Request. The suggestions
+// are the owners of the paths with a Gerrit email, other than author,
+// ranked by score, from highest to lowest.
+//
+// The ownership of a reviewer is the fraction of the paths they own,
+// with paths they are only a secondary owner of counting half. The load
+// of a reviewer is their number of open CLs in history, plus a quarter of
+// the CLs they were assigned to in the 30 days before now. The score is
+// the ownership divided by 1 + load/4, so an owner of every path with
+// 4 open CLs scores the same as an owner of half the paths with none.
+func Suggest(paths []string, author string, history []Review, now time.Time) []Suggestion {
+	if p := gophers.GetPerson(author); p != nil {
+		author = p.Gerrit
+	}
+	byEmail := make(map[string]*Suggestion)
+	for _, path := range paths {
+		e := match(path)
+		if e == nil {
+			continue
+		}
+		seen := make(map[string]bool)
+		for i, list := range [][]Owner{e.Primary, e.Secondary} {
+			for _, o := range list {
+				if o.GerritEmail == "" || o.GerritEmail == author || seen[o.GerritEmail] {
+					continue
+				}
+				seen[o.GerritEmail] = true
+				s := byEmail[o.GerritEmail]
+				if s == nil {
+					s = &Suggestion{Owner: o}
+					byEmail[o.GerritEmail] = s
+				}
+				if i == 0 {
+					s.Primary++
+				} else {
+					s.Secondary++
+				}
+			}
+		}
+	}
+
+	for _, r := range history {
+		s := byEmail[r.Reviewer]
+		if s == nil {
+			continue
+		}
+		if r.Open {
+			s.Open++
+		}
+		if now.Sub(r.Assigned) < recentReviews {
+			s.Recent++
+		}
+	}
+
+	var suggestions []Suggestion
+	for _, s := range byEmail {
+		ownership := (float64(s.Primary) + float64(s.Secondary)/2) / float64(len(paths))
+		load := float64(s.Open) + float64(s.Recent)/4
+		s.Score = ownership / (1 + load/4)
+		suggestions = append(suggestions, *s)
+	}
+	slices.SortFunc(suggestions, func(a, b Suggestion) int {
+		return cmp.Or(
+			cmp.Compare(b.Score, a.Score),
+			cmp.Compare(b.Primary, a.Primary),
+			strings.Compare(a.Owner.GerritEmail, b.Owner.GerritEmail),
+		)
+	})
+	return suggestions
+}
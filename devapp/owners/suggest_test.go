@@ -0,0 +1,61 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package owners
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/build/maintner"
+)
+
+func TestSuggest(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	history := []Review{
+		{Reviewer: "joetsai@digital-static.net", Assigned: now.Add(-time.Hour), Open: true},
+		{Reviewer: "joetsai@digital-static.net", Assigned: now.Add(-48 * time.Hour), Open: true},
+		{Reviewer: "joetsai@digital-static.net", Assigned: now.Add(-60 * 24 * time.Hour)},
+		{Reviewer: "bradfitz@golang.org", Assigned: now.Add(-time.Hour)},
+	}
+	got := Suggest([]string{"go/src/archive/zip/a.go", "go/src/archive/tar/a.go"}, "iant@golang.org", history, now)
+	want := []Suggestion{
+		{Owner: joetsai, Primary: 2, Open: 2, Recent: 2, Score: 1 / (1 + 2.5/4)},
+		{Owner: bradfitz, Secondary: 1, Recent: 1, Score: 0.25 / (1 + 0.25/4)},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Suggest: (-got +want)\n%s", diff)
+	}
+
+	// Teams and the author aren't suggested.
+	got = Suggest([]string{"go/src/archive/zip/a.go", "go/src/cmd/compile/main.go"}, "joetsai@digital-static.net", nil, now)
+	var emails []string
+	for _, s := range got {
+		emails = append(emails, s.Owner.GerritEmail)
+	}
+	if want := []string{"bradfitz@golang.org", "gri@golang.org", "khr@golang.org", "matthew@go.dev", "moehrmann@google.com"}; !cmp.Equal(emails, want) {
+		t.Errorf("Suggest by joetsai suggests %q, want %q", emails, want)
+	}
+}
+
+func TestReviewers(t *testing.T) {
+	meta := func(day int, msg string) *maintner.GerritMeta {
+		return &maintner.GerritMeta{Commit: &maintner.GitCommit{
+			Msg:        "Update patch set 1\n\n" + msg,
+			CommitTime: time.Date(2026, 10, day, 0, 0, 0, 0, time.UTC),
+		}}
+	}
+	cl := &maintner.GerritCL{Metas: []*maintner.GerritMeta{
+		meta(1, "Patch-set: 1\nReviewer: Gerrit User 5206 <5206@62eb7196-b449-3ce5-99f1-c037f21e1705>\nCC: Gerrit User 5167 <5167@62eb7196-b449-3ce5-99f1-c037f21e1705>\n"),
+		meta(2, "Patch-set: 1\nReviewer: Gerrit User 5167 <5167@62eb7196-b449-3ce5-99f1-c037f21e1705>\n"),
+		meta(3, "Patch-set: 1\nRemoved: Gerrit User 5206 <5206@62eb7196-b449-3ce5-99f1-c037f21e1705>\n"),
+		meta(4, "Patch-set: 1\nReviewer: Gerrit User 999999999 <999999999@62eb7196-b449-3ce5-99f1-c037f21e1705>\n"),
+	}}
+	got := Reviewers(cl)
+	want := map[string]time.Time{"austin@google.com": time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC)}
+	if !cmp.Equal(got, want) {
+		t.Errorf("Reviewers = %v, want %v", got, want)
+	}
+}
@@ -575,6 +575,8 @@ type CommentInfo struct {
 	PatchSet   int          `json:"patch_set,omitempty"`
 	ID         string       `json:"id"`
 	Path       string       `json:"path,omitempty"`
+	Side       string       `json:"side,omitempty"` // "PARENT" for comments on the parent commit; empty otherwise
+	Line       int          `json:"line,omitempty"` // 0 for file comments
 	Message    string       `json:"message,omitempty"`
 	Updated    TimeStamp    `json:"updated"`
 	Author     *AccountInfo `json:"author,omitempty"`
@@ -620,6 +622,7 @@ type ReviewerInput struct {
 // CommentInput contains information for creating an inline comment.
 // See https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#comment-input
 type CommentInput struct {
+	Side       string `json:"side,omitempty"` // "PARENT" to comment on the parent commit
 	Line       int    `json:"line,omitempty"`
 	Message    string `json:"message"`
 	InReplyTo  string `json:"in_reply_to,omitempty"`
@@ -0,0 +1,229 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package darwinpkg
+
+import (
+	"encoding/binary"
+	"path"
+)
+
+// A minimal writer of bill of materials (BOM) files, which list the
+// files a macOS package installs and are read by lsbom and pkgutil.
+// The format isn't documented by Apple; this follows the
+// reimplementation in bomutils (https://github.com/hogliux/bomutils).
+// All integers are big-endian.
+
+const (
+	bomHeaderSize     = 512 // space reserved for the header
+	bomPathsBlockSize = 4096
+	bomPathsPerLeaf   = (bomPathsBlockSize - 12) / 8
+)
+
+// bomWriter accumulates the blocks of a BOM file.
+type bomWriter struct {
+	data   []byte   // contents after the header
+	blocks [][2]int // offset and length of each block; block 0 is null
+}
+
+// block adds a block with the given contents and returns its index.
+func (w *bomWriter) block(b []byte) uint32 {
+	if w.blocks == nil {
+		w.blocks = [][2]int{{0, 0}}
+	}
+	w.blocks = append(w.blocks, [2]int{bomHeaderSize + len(w.data), len(b)})
+	w.data = append(w.data, b...)
+	return uint32(len(w.blocks) - 1)
+}
+
+// paths adds a B-tree node with the given (index0, index1) entries,
+// padded to size, and returns its index.
+func (w *bomWriter) paths(leaf bool, entries [][2]uint32, forward, backward uint32, size int) uint32 {
+	be := binary.BigEndian
+	var b []byte
+	isLeaf := uint16(0)
+	if leaf {
+		isLeaf = 1
+	}
+	b = be.AppendUint16(b, isLeaf)
+	b = be.AppendUint16(b, uint16(len(entries)))
+	b = be.AppendUint32(b, forward)
+	b = be.AppendUint32(b, backward)
+	for _, e := range entries {
+		b = be.AppendUint32(b, e[0])
+		b = be.AppendUint32(b, e[1])
+	}
+	return w.block(append(b, make([]byte, size-len(b))...))
+}
+
+// tree adds a tree header pointing at the node child and returns its index.
+func (w *bomWriter) tree(child uint32, blockSize, pathCount int, unknown byte) uint32 {
+	be := binary.BigEndian
+	b := []byte("tree")
+	b = be.AppendUint32(b, 1) // version
+	b = be.AppendUint32(b, child)
+	b = be.AppendUint32(b, uint32(blockSize))
+	b = be.AppendUint32(b, uint32(pathCount))
+	return w.block(append(b, unknown))
+}
+
+// writeBom returns a BOM file listing entries, which must be
+// in depth-first order, starting with ".".
+func writeBom(entries []pkgEntry) []byte {
+	be := binary.BigEndian
+	var w bomWriter
+
+	// BomInfo: version, number of paths, and one empty info entry.
+	info := be.AppendUint32(nil, 1)
+	info = be.AppendUint32(info, uint32(len(entries)))
+	info = be.AppendUint32(info, 1)
+	info = append(info, make([]byte, 16)...)
+	infoBlock := w.block(info)
+
+	// Each path has a file block with its parent's ID and its name,
+	// and a path info block with its ID and attributes.
+	ids := make(map[string]uint32)
+	var keys [][2]uint32 // path info and file block for each path
+	for i, e := range entries {
+		id := uint32(i + 1)
+		ids[e.name] = id
+		typ, sum, size := byte(1), uint32(0), uint32(len(e.data))
+		if e.isDir() {
+			typ, size = 2, 0
+		} else {
+			sum = cksum(e.data)
+		}
+		var attr []byte
+		attr = append(attr, typ, 1)
+		attr = be.AppendUint16(attr, 0) // architecture
+		attr = be.AppendUint16(attr, uint16(e.mode))
+		attr = be.AppendUint32(attr, 0) // user: root
+		attr = be.AppendUint32(attr, 0) // group: wheel
+		attr = be.AppendUint32(attr, uint32(max(e.mtime.Unix(), 0)))
+		attr = be.AppendUint32(attr, size)
+		attr = append(attr, 1)
+		attr = be.AppendUint32(attr, sum)
+		attr = be.AppendUint32(attr, 0) // link name length
+		attrBlock := w.block(attr)
+		pathInfo := w.block(be.AppendUint32(be.AppendUint32(nil, id), attrBlock))
+
+		parent, name := uint32(0), e.name
+		if e.name != "." {
+			parent, name = ids[pkgDir(e.name)], path.Base(e.name)
+		}
+		file := be.AppendUint32(nil, parent)
+		file = append(file, name...)
+		file = append(file, 0)
+		keys = append(keys, [2]uint32{pathInfo, w.block(file)})
+	}
+
+	// The Paths tree: linked leaves of up to bomPathsPerLeaf paths,
+	// and a root node pointing at them if there's more than one.
+	// Leaves are linked before they're added, so their indexes are
+	// precomputed; each takes one block.
+	var leaves [][][2]uint32
+	for len(keys) > bomPathsPerLeaf {
+		leaves = append(leaves, keys[:bomPathsPerLeaf])
+		keys = keys[bomPathsPerLeaf:]
+	}
+	leaves = append(leaves, keys)
+	first := uint32(len(w.blocks))
+	var children [][2]uint32
+	for i, leaf := range leaves {
+		var forward, backward uint32
+		if i > 0 {
+			backward = first + uint32(i) - 1
+		}
+		if i < len(leaves)-1 {
+			forward = first + uint32(i) + 1
+		}
+		var last uint32
+		if len(leaf) > 0 {
+			last = leaf[len(leaf)-1][1]
+		}
+		children = append(children, [2]uint32{w.paths(true, leaf, forward, backward, bomPathsBlockSize), last})
+	}
+	root := children[0][0]
+	if len(children) > 1 {
+		root = w.paths(false, children, 0, 0, bomPathsBlockSize)
+	}
+	pathsTree := w.tree(root, bomPathsBlockSize, len(entries), 0)
+
+	// Empty hard link, virtual path, and size indexes.
+	hlIndex := w.tree(w.paths(true, nil, 0, 0, bomPathsBlockSize), bomPathsBlockSize, 0, 0)
+	vTree := w.tree(w.paths(true, nil, 0, 0, 128), 128, 0, 1)
+	vIndex := be.AppendUint32(nil, 1)
+	vIndex = be.AppendUint32(vIndex, vTree)
+	vIndex = be.AppendUint32(vIndex, 0)
+	vIndexBlock := w.block(append(vIndex, 0))
+	size64 := w.tree(w.paths(true, nil, 0, 0, 128), 128, 0, 0)
+
+	// The named variables, followed by the block table and an empty free list.
+	var vars []byte
+	vars = be.AppendUint32(vars, 5)
+	for _, v := range []struct {
+		name  string
+		block uint32
+	}{
+		{"BomInfo", infoBlock},
+		{"Paths", pathsTree},
+		{"HLIndex", hlIndex},
+		{"VIndex", vIndexBlock},
+		{"Size64", size64},
+	} {
+		vars = be.AppendUint32(vars, v.block)
+		vars = append(vars, byte(len(v.name)))
+		vars = append(vars, v.name...)
+	}
+	varsOffset := bomHeaderSize + len(w.data)
+	var index []byte
+	index = be.AppendUint32(index, uint32(len(w.blocks)))
+	for _, b := range w.blocks {
+		index = be.AppendUint32(index, uint32(b[0]))
+		index = be.AppendUint32(index, uint32(b[1]))
+	}
+	index = be.AppendUint32(index, 0)
+	indexOffset := varsOffset + len(vars)
+
+	out := make([]byte, bomHeaderSize, indexOffset+len(index))
+	copy(out, "BOMStore")
+	be.PutUint32(out[8:], 1) // version
+	be.PutUint32(out[12:], uint32(len(w.blocks)-1))
+	be.PutUint32(out[16:], uint32(indexOffset))
+	be.PutUint32(out[20:], uint32(len(index)))
+	be.PutUint32(out[24:], uint32(varsOffset))
+	be.PutUint32(out[28:], uint32(len(vars)))
+	out = append(out, w.data...)
+	out = append(out, vars...)
+	return append(out, index...)
+}
+
+// cksumTable is the CRC table for cksum, using the CRC-32 polynomial
+// with the most significant bit first.
+var cksumTable = func() (t [256]uint32) {
+	for i := range t {
+		c := uint32(i) << 24
+		for range 8 {
+			if c&0x80000000 != 0 {
+				c = c<<1 ^ 0x04C11DB7
+			} else {
+				c <<= 1
+			}
+		}
+		t[i] = c
+	}
+	return t
+}()
+
+// cksum returns the POSIX cksum checksum of data, which BOM files record.
+func cksum(data []byte) uint32 {
+	var crc uint32
+	for _, b := range data {
+		crc = crc<<8 ^ cksumTable[byte(crc>>24)^b]
+	}
+	for n := len(data); n > 0; n >>= 8 {
+		crc = crc<<8 ^ cksumTable[byte(crc>>24)^byte(n)]
+	}
+	return ^crc
+}
@@ -0,0 +1,55 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package darwinpkg
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"time"
+)
+
+// A pkgEntry is a file or directory in a package payload or scripts archive.
+type pkgEntry struct {
+	name  string // "." or starting with "./"
+	mode  uint32 // including the file type bits, such as 0100644
+	mtime time.Time
+	data  []byte
+}
+
+const (
+	modeDir  = 0040000
+	modeFile = 0100000
+)
+
+func (e *pkgEntry) isDir() bool { return e.mode&0170000 == modeDir }
+
+// writeCpio writes entries as a cpio archive in the "odc" format
+// that macOS installer payloads use, with files owned by root:wheel.
+// See https://www.mkssoftware.com/docs/man4/cpio.4.asp.
+func writeCpio(w io.Writer, entries []pkgEntry) error {
+	const maxSize = 077777777777
+	bw := bufio.NewWriter(w)
+	for i, e := range entries {
+		if i+1 > 0777777 {
+			return fmt.Errorf("too many files for a cpio archive")
+		}
+		if len(e.data) > maxSize {
+			return fmt.Errorf("%s is too large for a cpio archive", e.name)
+		}
+		mtime := max(e.mtime.Unix(), 0)
+		nlink := 1
+		if e.isDir() {
+			nlink = 2
+		}
+		fmt.Fprintf(bw, "070707%06o%06o%06o%06o%06o%06o%06o%011o%06o%011o%s\x00",
+			0, i+1, e.mode, 0, 0, nlink, 0, mtime, len(e.name)+1, len(e.data), e.name)
+		bw.Write(e.data)
+	}
+	const trailer = "TRAILER!!!"
+	fmt.Fprintf(bw, "070707%06o%06o%06o%06o%06o%06o%06o%011o%06o%011o%s\x00",
+		0, 0, 0, 0, 0, 1, 0, 0, len(trailer)+1, 0, trailer)
+	return bw.Flush()
+}
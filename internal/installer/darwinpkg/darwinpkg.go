@@ -71,42 +71,10 @@ func ConstructInstaller(_ context.Context, workDir, tgzPath string, opt Installe
 	run("mkdir", "pkg-intermediate")
 	putTar(tgzPath, "pkg-root/usr/local")
 	put("/usr/local/go/bin\n", "pkg-root/etc/paths.d/go", 0644)
-	put(`#!/bin/bash
-
-GOROOT=/usr/local/go
-echo "Removing previous installation"
-if [ -d $GOROOT ]; then
-	rm -r $GOROOT
-fi
-`, "pkg-scripts/preinstall", 0755)
+	put(preinstallScript, "pkg-scripts/preinstall", 0755)
 	version := readVERSION("pkg-root/usr/local/go")
-	if goversion.Compare(version, "go1.27") < 0 { // TODO: Delete after Go 1.28.0 is released and it becomes dead code.
-		// This "Fixing permissions" step is the only work done in
-		// the postinstall script. As described in go.dev/issue/74287,
-		// it appears not to be needed anymore, and we have not been
-		// able to uncover information on the exact reason it was added
-		// in the first place.
-		//
-		// Nothing seems to break when this step is no longer done,
-		// quite possibly because it became obsolete sometime along the way
-		// to distpack-based cross-compiled toolchain builds. (After all,
-		// permissions should be handled there, unless something about the
-		// installer itself causes them to break. But then it should probably
-		// fix permissions in the "pkg/tool" directory too, not just "bin".
-		//
-		// If it turns out to be needed in some scenario, hopefully it will
-		// be reported via Go 1.27 pre-releases and we can re-add it with a
-		// clear comment.
-		put(`#!/bin/bash
-
-GOROOT=/usr/local/go
-echo "Fixing permissions"
-cd $GOROOT
-find . -exec chmod ugo+r \{\} +
-find bin -exec chmod ugo+rx \{\} +
-find . -type d -exec chmod ugo+rx \{\} +
-chmod o-w .
-`, "pkg-scripts/postinstall", 0755)
+	if needPostinstall(version) {
+		put(postinstallScript, "pkg-scripts/postinstall", 0755)
 	}
 	run("pkgbuild",
 		"--identifier=org.golang.go",
@@ -124,13 +92,7 @@ chmod o-w .
 	}
 	put(string(bg), "pkg-resources/background.png", 0644)
 	var buf bytes.Buffer
-	distData := darwinDistData{
-		HostArchs:                       map[string]string{"amd64": "x86_64", "arm64": "arm64"}[opt.GOARCH],
-		MinOS:                           opt.MinMacOSVersion,
-		GoVersion:                       version,
-		DetectAMD64InstallerOnARM64Host: opt.GOARCH == "amd64" && goversion.Compare(version, "go1.25rc2") >= 0, // See go.dev/issue/59010.
-	}
-	if err := darwinDistTmpl.ExecuteTemplate(&buf, "dist.xml", distData); err != nil {
+	if err := darwinDistTmpl.ExecuteTemplate(&buf, "dist.xml", newDarwinDistData(opt, version)); err != nil {
 		log.Fatalln("darwinDistTmpl.ExecuteTemplate:", err)
 	}
 	put(buf.String(), "pkg-distribution", 0644)
@@ -160,6 +122,59 @@ func darwinPKGBackground(goarch string) ([]byte, error) {
 
 var darwinDistTmpl = template.Must(template.New("").ParseFS(darwinPKGData, "_data/dist.xml"))
 
+const preinstallScript = `#!/bin/bash
+
+GOROOT=/usr/local/go
+echo "Removing previous installation"
+if [ -d $GOROOT ]; then
+	rm -r $GOROOT
+fi
+`
+
+const postinstallScript = `#!/bin/bash
+
+GOROOT=/usr/local/go
+echo "Fixing permissions"
+cd $GOROOT
+find . -exec chmod ugo+r \{\} +
+find bin -exec chmod ugo+rx \{\} +
+find . -type d -exec chmod ugo+rx \{\} +
+chmod o-w .
+`
+
+// needPostinstall reports whether the installer for the given Go version
+// runs postinstallScript.
+func needPostinstall(version string) bool {
+	// TODO: Delete after Go 1.28.0 is released and it becomes dead code.
+	//
+	// This "Fixing permissions" step is the only work done in
+	// the postinstall script. As described in go.dev/issue/74287,
+	// it appears not to be needed anymore, and we have not been
+	// able to uncover information on the exact reason it was added
+	// in the first place.
+	//
+	// Nothing seems to break when this step is no longer done,
+	// quite possibly because it became obsolete sometime along the way
+	// to distpack-based cross-compiled toolchain builds. (After all,
+	// permissions should be handled there, unless something about the
+	// installer itself causes them to break. But then it should probably
+	// fix permissions in the "pkg/tool" directory too, not just "bin".
+	//
+	// If it turns out to be needed in some scenario, hopefully it will
+	// be reported via Go 1.27 pre-releases and we can re-add it with a
+	// clear comment.
+	return goversion.Compare(version, "go1.27") < 0
+}
+
+func newDarwinDistData(opt InstallerOptions, version string) darwinDistData {
+	return darwinDistData{
+		HostArchs:                       map[string]string{"amd64": "x86_64", "arm64": "arm64"}[opt.GOARCH],
+		MinOS:                           opt.MinMacOSVersion,
+		GoVersion:                       version,
+		DetectAMD64InstallerOnARM64Host: opt.GOARCH == "amd64" && goversion.Compare(version, "go1.25rc2") >= 0, // See go.dev/issue/59010.
+	}
+}
+
 type darwinDistData struct {
 	HostArchs string // hostArchitectures option value.
 	MinOS     string // Minimum required system.version.ProductVersion.
@@ -0,0 +1,216 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package darwinpkg
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+// WriteInstaller writes an unsigned macOS installer package for the
+// Go toolchain .tar.gz binary archive read from tgz to w.
+//
+// Unlike ConstructInstaller, it doesn't need pkgbuild and productbuild,
+// so it can run on any system, and the package depends only on its inputs.
+// It writes the same flat product archive that they do: a xar archive
+// with the distribution definition, the background image, and the
+// org.golang.go.pkg component package holding the payload, its bill of
+// materials, and the installation scripts.
+func WriteInstaller(w io.Writer, tgz io.Reader, opt InstallerOptions) error {
+	var errs []error
+	if opt.GOARCH == "" {
+		errs = append(errs, fmt.Errorf("GOARCH is empty"))
+	}
+	if opt.MinMacOSVersion == "" {
+		errs = append(errs, fmt.Errorf("MinMacOSVersion is empty"))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	bg, err := darwinPKGBackground(opt.GOARCH)
+	if err != nil {
+		return err
+	}
+
+	// The payload: the Go tree in /usr/local/go, and /etc/paths.d/go.
+	entries, err := readTgz(tgz, "./usr/local/")
+	if err != nil {
+		return err
+	}
+	var version string
+	var mtime time.Time
+	for _, e := range entries {
+		if e.name == "./usr/local/go/VERSION" {
+			version, _, _ = strings.Cut(string(e.data), "\n")
+			mtime = e.mtime
+		}
+	}
+	if version == "" {
+		return fmt.Errorf("no VERSION file in Go toolchain archive")
+	}
+	entries = append(entries, pkgEntry{"./etc/paths.d/go", modeFile | 0644, mtime, []byte("/usr/local/go/bin\n")})
+	payload := addDirs(entries, mtime)
+	var installBytes int64
+	for _, e := range payload {
+		installBytes += int64(len(e.data))
+	}
+	installKBytes := (installBytes + 1023) / 1024
+	payloadData, err := gzipCpio(payload)
+	if err != nil {
+		return err
+	}
+
+	scripts := []pkgEntry{
+		{".", modeDir | 0755, mtime, nil},
+		{"./preinstall", modeFile | 0755, mtime, []byte(preinstallScript)},
+	}
+	if needPostinstall(version) {
+		scripts = append(scripts, pkgEntry{"./postinstall", modeFile | 0755, mtime, []byte(postinstallScript)})
+	}
+	scriptsData, err := gzipCpio(scripts)
+	if err != nil {
+		return err
+	}
+
+	var info bytes.Buffer
+	fmt.Fprintf(&info, `<?xml version="1.0" encoding="utf-8" standalone="no"?>
+<pkg-info overwrite-permissions="true" relocatable="false" identifier="org.golang.go" postinstall-action="none" version="%s" format-version="2" install-location="/" auth="root">
+    <payload numberOfFiles="%d" installKBytes="%d"/>
+    <bundle-version/>
+    <upgrade-bundle/>
+    <update-bundle/>
+    <atomic-update-bundle/>
+    <strict-identifier/>
+    <relocate/>
+    <scripts>
+        <preinstall file="./preinstall"/>
+`, xmlEscape(version), len(payload), installKBytes)
+	if needPostinstall(version) {
+		info.WriteString(`        <postinstall file="./postinstall"/>
+`)
+	}
+	info.WriteString(`    </scripts>
+</pkg-info>
+`)
+
+	// The distribution definition, referring to the component package
+	// in the archive, as productbuild rewrites it.
+	var dist bytes.Buffer
+	if err := darwinDistTmpl.ExecuteTemplate(&dist, "dist.xml", newDarwinDistData(opt, version)); err != nil {
+		return err
+	}
+	const pkgRef = `<pkg-ref id="org.golang.go.pkg" auth="Root">org.golang.go.pkg</pkg-ref>`
+	if !bytes.Contains(dist.Bytes(), []byte(pkgRef)) {
+		return fmt.Errorf("dist.xml has no %s", pkgRef)
+	}
+	distribution := bytes.Replace(dist.Bytes(), []byte(pkgRef), fmt.Appendf(nil,
+		`<pkg-ref id="org.golang.go.pkg" auth="Root" version="%s" installKBytes="%d">#org.golang.go.pkg</pkg-ref>`,
+		xmlEscape(version), installKBytes), 1)
+
+	return writeXar(w, []*xarFile{
+		{name: "Distribution", data: distribution, compress: true},
+		{name: "Resources", dir: true, files: []*xarFile{
+			{name: "background.png", data: bg, compress: true},
+		}},
+		{name: "org.golang.go.pkg", dir: true, files: []*xarFile{
+			{name: "Bom", data: writeBom(payload), compress: true},
+			{name: "PackageInfo", data: info.Bytes(), compress: true},
+			{name: "Payload", data: payloadData},
+			{name: "Scripts", data: scriptsData},
+		}},
+	}, mtime)
+}
+
+// readTgz reads the files in a Go toolchain .tar.gz binary archive,
+// which are all in the go directory, as payload entries in dir.
+func readTgz(r io.Reader, dir string) ([]pkgEntry, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	var entries []pkgEntry
+	tr := tar.NewReader(zr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			continue
+		case tar.TypeReg:
+		default:
+			return nil, fmt.Errorf("unsupported file type %q for %s in Go toolchain archive", hdr.Typeflag, hdr.Name)
+		}
+		if !fs.ValidPath(hdr.Name) || !strings.HasPrefix(hdr.Name, "go/") {
+			return nil, fmt.Errorf("unexpected path %q in Go toolchain archive", hdr.Name)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, pkgEntry{dir + hdr.Name, modeFile | uint32(hdr.Mode&0777), hdr.ModTime, data})
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("no files in Go toolchain archive")
+	}
+	return entries, nil
+}
+
+// addDirs returns entries with their parent directories added,
+// modified at mtime, in depth-first order.
+func addDirs(entries []pkgEntry, mtime time.Time) []pkgEntry {
+	seen := make(map[string]bool)
+	var all []pkgEntry
+	for _, e := range entries {
+		for dir := pkgDir(e.name); !seen[dir]; dir = pkgDir(dir) {
+			seen[dir] = true
+			all = append(all, pkgEntry{dir, modeDir | 0755, mtime, nil})
+			if dir == "." {
+				break
+			}
+		}
+		all = append(all, e)
+	}
+	slices.SortFunc(all, func(a, b pkgEntry) int {
+		return slices.Compare(strings.Split(a.name, "/"), strings.Split(b.name, "/"))
+	})
+	return all
+}
+
+// pkgDir returns the directory containing the payload path name.
+func pkgDir(name string) string {
+	if dir := path.Dir(name); dir != "." {
+		return "./" + dir
+	}
+	return "."
+}
+
+// gzipCpio returns entries as a gzip-compressed cpio archive.
+func gzipCpio(entries []pkgEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeCpio(zw, entries); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,356 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package darwinpkg
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/build/internal/installer/installertest"
+)
+
+var testFiles = map[string]string{
+	"LICENSE":              "Copyright (c) 2009 The Go Authors.\n",
+	"bin/go":               "\xcf\xfa\xed\xfe go",
+	"bin/gofmt":            strings.Repeat("gofmt ", 3000),
+	"src/cmd/go/main.go":   "package main\n",
+	"src/cmd/go-x/main.go": "package main\n",
+	"src/empty":            "",
+}
+
+func TestWriteInstaller(t *testing.T) {
+	for _, version := range []string{"go1.26.2", "go1.27.0"} {
+		t.Run(version, func(t *testing.T) {
+			tgz := installertest.Tgz(t, version, testFiles)
+			var buf bytes.Buffer
+			opt := InstallerOptions{GOARCH: "arm64", MinMacOSVersion: "12"}
+			if err := WriteInstaller(&buf, bytes.NewReader(tgz), opt); err != nil {
+				t.Fatal(err)
+			}
+			var again bytes.Buffer
+			if err := WriteInstaller(&again, bytes.NewReader(tgz), opt); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(buf.Bytes(), again.Bytes()) {
+				t.Errorf("WriteInstaller output is not deterministic")
+			}
+
+			files := readTestXar(t, buf.Bytes())
+			if got := slices.Sorted(maps.Keys(files)); !slices.Equal(got, []string{
+				"Distribution",
+				"Resources/background.png",
+				"org.golang.go.pkg/Bom",
+				"org.golang.go.pkg/PackageInfo",
+				"org.golang.go.pkg/Payload",
+				"org.golang.go.pkg/Scripts",
+			}) {
+				t.Errorf("xar files = %q", got)
+			}
+			dist := string(files["Distribution"])
+			if !strings.Contains(dist, `>#org.golang.go.pkg</pkg-ref>`) || !strings.Contains(dist, "Go requires macOS 12 or later.") {
+				t.Errorf("Distribution doesn't refer to the package or lacks the OS check:\n%s", dist)
+			}
+			info := string(files["org.golang.go.pkg/PackageInfo"])
+			if !strings.Contains(info, `version="`+version+`"`) {
+				t.Errorf("PackageInfo doesn't have the version:\n%s", info)
+			}
+
+			payload := readTestCpioGz(t, files["org.golang.go.pkg/Payload"])
+			want := map[string]string{
+				".":                           "dir 0755",
+				"./etc":                       "dir 0755",
+				"./etc/paths.d":               "dir 0755",
+				"./etc/paths.d/go":            "0644 /usr/local/go/bin\n",
+				"./usr":                       "dir 0755",
+				"./usr/local":                 "dir 0755",
+				"./usr/local/go":              "dir 0755",
+				"./usr/local/go/bin":          "dir 0755",
+				"./usr/local/go/src":          "dir 0755",
+				"./usr/local/go/src/cmd":      "dir 0755",
+				"./usr/local/go/src/cmd/go":   "dir 0755",
+				"./usr/local/go/src/cmd/go-x": "dir 0755",
+				"./usr/local/go/VERSION":      "0644 " + version + "\ntime 2026-02-03T04:05:06Z\n",
+			}
+			for name, data := range testFiles {
+				mode := "0644 "
+				if strings.HasPrefix(name, "bin/") {
+					mode = "0755 "
+				}
+				want["./usr/local/go/"+name] = mode + data
+			}
+			if !maps.Equal(payload.files, want) {
+				t.Errorf("payload =\n%q\nwant\n%q", payload.files, want)
+			}
+
+			scripts := readTestCpioGz(t, files["org.golang.go.pkg/Scripts"])
+			_, hasPostinstall := scripts.files["./postinstall"]
+			if want := version == "go1.26.2"; hasPostinstall != want {
+				t.Errorf("has postinstall script = %v, want %v", hasPostinstall, want)
+			}
+			if got := scripts.files["./preinstall"]; got != "0755 "+preinstallScript {
+				t.Errorf("preinstall = %q", got)
+			}
+
+			bom := readTestBom(t, files["org.golang.go.pkg/Bom"])
+			if !slices.Equal(bom, payload.names) {
+				t.Errorf("Bom paths =\n%q\nwant payload order\n%q", bom, payload.names)
+			}
+		})
+	}
+}
+
+func TestWriteInstallerBsdtar(t *testing.T) {
+	bsdtar, err := exec.LookPath("bsdtar")
+	if err != nil {
+		t.Skip("bsdtar not found")
+	}
+	var buf bytes.Buffer
+	opt := InstallerOptions{GOARCH: "amd64", MinMacOSVersion: "12"}
+	if err := WriteInstaller(&buf, bytes.NewReader(installertest.Tgz(t, "go1.27.0", testFiles)), opt); err != nil {
+		t.Fatal(err)
+	}
+	pkg := filepath.Join(t.TempDir(), "go.pkg")
+	if err := os.WriteFile(pkg, buf.Bytes(), 0666); err != nil {
+		t.Fatal(err)
+	}
+	out, err := exec.Command(bsdtar, "-t", "-f", pkg).CombinedOutput()
+	if err != nil {
+		if strings.Contains(string(out), "Unrecognized archive format") {
+			t.Skip("bsdtar lacks xar support")
+		}
+		t.Fatalf("bsdtar: %v\n%s", err, out)
+	}
+	for _, name := range []string{"Distribution", "org.golang.go.pkg/Payload", "org.golang.go.pkg/Bom"} {
+		if !strings.Contains(string(out), name) {
+			t.Errorf("bsdtar listing lacks %s:\n%s", name, out)
+		}
+	}
+}
+
+func TestWriteBomManyPaths(t *testing.T) {
+	// Enough paths for several leaf nodes in the Paths tree.
+	var entries []pkgEntry
+	for i := range 1200 {
+		entries = append(entries, pkgEntry{name: fmt.Sprintf("./go/f%04d", i), mode: modeFile | 0644, data: []byte{byte(i)}})
+	}
+	entries = addDirs(entries, time.Time{})
+	var want []string
+	for _, e := range entries {
+		want = append(want, e.name)
+	}
+	if got := readTestBom(t, writeBom(entries)); !slices.Equal(got, want) {
+		t.Errorf("Bom has %d paths, want %d", len(got), len(want))
+	}
+}
+
+func TestCksum(t *testing.T) {
+	// Values from the cksum command.
+	for _, tt := range []struct {
+		data string
+		want uint32
+	}{
+		{"", 4294967295},
+		{"hello\n", 3015617425},
+		{strings.Repeat("\x00", 100000), 1260869142},
+	} {
+		if got := cksum([]byte(tt.data)); got != tt.want {
+			t.Errorf("cksum(%.10q) = %d, want %d", tt.data, got, tt.want)
+		}
+	}
+}
+
+// readTestXar returns the contents of the files in a xar archive,
+// checking their checksums.
+func readTestXar(t *testing.T, data []byte) map[string][]byte {
+	t.Helper()
+	be := binary.BigEndian
+	if string(data[:4]) != "xar!" || be.Uint16(data[4:]) != 28 || be.Uint32(data[24:]) != 1 {
+		t.Fatal("bad xar header")
+	}
+	ctoc := data[28:][:be.Uint64(data[8:])]
+	heap := data[28+len(ctoc):]
+	if sum := sha1.Sum(ctoc); !bytes.Equal(heap[:sha1.Size], sum[:]) {
+		t.Errorf("bad table of contents checksum")
+	}
+	zr, err := zlib.NewReader(bytes.NewReader(ctoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tocXML, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type file struct {
+		Name  string  `xml:"name"`
+		Type  string  `xml:"type"`
+		Files []*file `xml:"file"`
+		Data  struct {
+			Length   int `xml:"length"`
+			Offset   int `xml:"offset"`
+			Size     int `xml:"size"`
+			Encoding struct {
+				Style string `xml:"style,attr"`
+			} `xml:"encoding"`
+			ArchivedChecksum  string `xml:"archived-checksum"`
+			ExtractedChecksum string `xml:"extracted-checksum"`
+		} `xml:"data"`
+	}
+	var toc struct {
+		Files []*file `xml:"toc>file"`
+	}
+	if err := xml.Unmarshal(tocXML, &toc); err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string][]byte)
+	var walk func(dir string, fs []*file)
+	walk = func(dir string, fs []*file) {
+		for _, f := range fs {
+			name := path.Join(dir, f.Name)
+			if f.Type == "directory" {
+				walk(name, f.Files)
+				continue
+			}
+			archived := heap[f.Data.Offset:][:f.Data.Length]
+			if fmt.Sprintf("%x", sha1.Sum(archived)) != f.Data.ArchivedChecksum {
+				t.Errorf("%s: bad archived checksum", name)
+			}
+			b := archived
+			switch f.Data.Encoding.Style {
+			case "application/x-gzip":
+				zr, err := zlib.NewReader(bytes.NewReader(archived))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if b, err = io.ReadAll(zr); err != nil {
+					t.Fatal(err)
+				}
+			case "application/octet-stream":
+			default:
+				t.Fatalf("%s: unexpected encoding %q", name, f.Data.Encoding.Style)
+			}
+			if len(b) != f.Data.Size || fmt.Sprintf("%x", sha1.Sum(b)) != f.Data.ExtractedChecksum {
+				t.Errorf("%s: bad extracted size or checksum", name)
+			}
+			files[name] = b
+		}
+	}
+	walk("", toc.Files)
+	return files
+}
+
+type testCpio struct {
+	names []string          // in archive order
+	files map[string]string // mode (or "dir") and contents
+}
+
+// readTestCpioGz reads a gzip-compressed odc cpio archive.
+func readTestCpioGz(t *testing.T, data []byte) testCpio {
+	t.Helper()
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := testCpio{files: make(map[string]string)}
+	for {
+		if len(b) < 76 || string(b[:6]) != "070707" {
+			t.Fatalf("bad cpio header %q", b[:min(len(b), 76)])
+		}
+		field := func(s string) int {
+			n, err := strconv.ParseInt(s, 8, 64)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return int(n)
+		}
+		mode, nameLen, size := field(string(b[18:24])), field(string(b[59:65])), field(string(b[65:76]))
+		name := string(b[76 : 76+nameLen-1])
+		content := string(b[76+nameLen:][:size])
+		b = b[76+nameLen+size:]
+		if name == "TRAILER!!!" {
+			break
+		}
+		c.names = append(c.names, name)
+		if mode&0170000 == modeDir {
+			c.files[name] = fmt.Sprintf("dir %04o", mode&0777)
+		} else {
+			c.files[name] = fmt.Sprintf("%04o %s", mode&0777, content)
+		}
+	}
+	return c
+}
+
+// readTestBom returns the paths in a BOM file, in order.
+func readTestBom(t *testing.T, data []byte) []string {
+	t.Helper()
+	be := binary.BigEndian
+	if string(data[:8]) != "BOMStore" {
+		t.Fatal("bad BOM header")
+	}
+	index := data[be.Uint32(data[16:]):]
+	block := func(i uint32) []byte {
+		if i == 0 || i >= be.Uint32(index) {
+			t.Fatalf("bad block index %d", i)
+		}
+		off, n := be.Uint32(index[4+8*i:]), be.Uint32(index[8+8*i:])
+		return data[off:][:n]
+	}
+	vars := data[be.Uint32(data[24:]):]
+	var pathsTree uint32
+	for p, n := vars[4:], be.Uint32(vars); n > 0; n-- {
+		i, size := be.Uint32(p), int(p[4])
+		if string(p[5:5+size]) == "Paths" {
+			pathsTree = i
+		}
+		p = p[5+size:]
+	}
+	tree := block(pathsTree)
+	if string(tree[:4]) != "tree" {
+		t.Fatal("Paths is not a tree")
+	}
+	node := block(be.Uint32(tree[8:]))
+	for be.Uint16(node) == 0 {
+		node = block(be.Uint32(node[12:]))
+	}
+	names := make(map[uint32]string)
+	var paths []string
+	for {
+		for i := range int(be.Uint16(node[2:])) {
+			info, file := block(be.Uint32(node[12+8*i:])), block(be.Uint32(node[16+8*i:]))
+			id, parent := be.Uint32(info), be.Uint32(file)
+			name := string(file[4 : len(file)-1])
+			if parent != 0 {
+				name = names[parent] + "/" + name
+			}
+			names[id] = name
+			paths = append(paths, name)
+		}
+		forward := be.Uint32(node[4:])
+		if forward == 0 {
+			break
+		}
+		node = block(forward)
+	}
+	return paths
+}
@@ -0,0 +1,122 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package darwinpkg
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// A minimal writer of xar archives, the format of flat macOS packages.
+// See https://github.com/mackyle/xar/wiki/xarformat.
+
+// A xarFile is a file or directory in a xar archive.
+type xarFile struct {
+	name     string
+	data     []byte
+	compress bool       // store with zlib ("application/x-gzip") encoding
+	files    []*xarFile // for a directory
+	dir      bool
+}
+
+// writeXar writes a xar archive containing files to w.
+// The table of contents and files are checksummed with SHA-1.
+func writeXar(w io.Writer, files []*xarFile, created time.Time) error {
+	const checksumSize = sha1.Size
+	var heap bytes.Buffer
+	heap.Write(make([]byte, checksumSize)) // table of contents checksum
+
+	var toc strings.Builder
+	toc.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n<xar>\n <toc>\n")
+	fmt.Fprintf(&toc, "  <checksum style=\"sha1\">\n   <offset>0</offset>\n   <size>%d</size>\n  </checksum>\n", checksumSize)
+	fmt.Fprintf(&toc, "  <creation-time>%s</creation-time>\n", created.UTC().Format("2006-01-02T15:04:05"))
+	id := 0
+	var add func(f *xarFile, indent string) error
+	add = func(f *xarFile, indent string) error {
+		id++
+		fmt.Fprintf(&toc, "%s<file id=\"%d\">\n", indent, id)
+		fmt.Fprintf(&toc, "%s <name>%s</name>\n", indent, xmlEscape(f.name))
+		if f.dir {
+			fmt.Fprintf(&toc, "%s <type>directory</type>\n%s <mode>0755</mode>\n", indent, indent)
+			for _, f := range f.files {
+				if err := add(f, indent+" "); err != nil {
+					return err
+				}
+			}
+		} else {
+			archived, encoding := f.data, "application/octet-stream"
+			if f.compress {
+				var b bytes.Buffer
+				zw, err := zlib.NewWriterLevel(&b, zlib.BestCompression)
+				if err != nil {
+					return err
+				}
+				zw.Write(f.data)
+				if err := zw.Close(); err != nil {
+					return err
+				}
+				archived, encoding = b.Bytes(), "application/x-gzip"
+			}
+			fmt.Fprintf(&toc, "%s <type>file</type>\n%s <mode>0644</mode>\n", indent, indent)
+			fmt.Fprintf(&toc, "%s <data>\n", indent)
+			fmt.Fprintf(&toc, "%s  <length>%d</length>\n", indent, len(archived))
+			fmt.Fprintf(&toc, "%s  <offset>%d</offset>\n", indent, heap.Len())
+			fmt.Fprintf(&toc, "%s  <size>%d</size>\n", indent, len(f.data))
+			fmt.Fprintf(&toc, "%s  <encoding style=\"%s\"/>\n", indent, encoding)
+			fmt.Fprintf(&toc, "%s  <archived-checksum style=\"sha1\">%x</archived-checksum>\n", indent, sha1.Sum(archived))
+			fmt.Fprintf(&toc, "%s  <extracted-checksum style=\"sha1\">%x</extracted-checksum>\n", indent, sha1.Sum(f.data))
+			fmt.Fprintf(&toc, "%s </data>\n", indent)
+			heap.Write(archived)
+		}
+		fmt.Fprintf(&toc, "%s</file>\n", indent)
+		return nil
+	}
+	for _, f := range files {
+		if err := add(f, "  "); err != nil {
+			return err
+		}
+	}
+	toc.WriteString(" </toc>\n</xar>\n")
+
+	var ctoc bytes.Buffer
+	zw, err := zlib.NewWriterLevel(&ctoc, zlib.BestCompression)
+	if err != nil {
+		return err
+	}
+	io.WriteString(zw, toc.String())
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	sum := sha1.Sum(ctoc.Bytes())
+	copy(heap.Bytes(), sum[:])
+
+	be := binary.BigEndian
+	var hdr []byte
+	hdr = append(hdr, "xar!"...)
+	hdr = be.AppendUint16(hdr, 28) // header size
+	hdr = be.AppendUint16(hdr, 1)  // version
+	hdr = be.AppendUint64(hdr, uint64(ctoc.Len()))
+	hdr = be.AppendUint64(hdr, uint64(toc.Len()))
+	hdr = be.AppendUint32(hdr, 1) // SHA-1 checksums
+	for _, b := range [][]byte{hdr, ctoc.Bytes(), heap.Bytes()} {
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,48 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package installertest provides helpers for testing the installer
+// writers in the packages under internal/installer.
+package installertest
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Tgz returns a Go toolchain archive with the given files,
+// in addition to a VERSION file. The files are in the go
+// directory of the archive, and those in its bin directory
+// are executable.
+func Tgz[Data ~string | ~[]byte](t testing.TB, version string, files map[string]Data) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+	mtime := time.Date(2026, 2, 3, 4, 5, 6, 0, time.UTC)
+	files = maps.Clone(files)
+	files["VERSION"] = Data(version + "\ntime 2026-02-03T04:05:06Z\n")
+	tw.WriteHeader(&tar.Header{Name: "go/", Typeflag: tar.TypeDir, Mode: 0755, ModTime: mtime})
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		mode := int64(0644)
+		if strings.HasPrefix(name, "bin/") {
+			mode = 0755
+		}
+		tw.WriteHeader(&tar.Header{Name: "go/" + name, Typeflag: tar.TypeReg, Mode: mode, Size: int64(len(files[name])), ModTime: mtime})
+		tw.Write([]byte(files[name]))
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
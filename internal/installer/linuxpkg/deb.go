@@ -0,0 +1,149 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linuxpkg
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/md5"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// WriteDeb writes a Debian binary package for the Go toolchain
+// .tar.gz binary archive read from tgz to w.
+//
+// It writes the same format that dpkg-deb does: an ar archive with
+// the format version, the control archive holding the package metadata
+// and the checksums of its files, and the data archive holding the files.
+// See deb(5).
+func WriteDeb(w io.Writer, tgz io.Reader, opt InstallerOptions) error {
+	arch, ok := archs[opt.GOARCH]
+	if !ok {
+		return fmt.Errorf("unsupported GOARCH %q", opt.GOARCH)
+	}
+	c, err := readContents(tgz)
+	if err != nil {
+		return err
+	}
+
+	files := c.withDirs(true)
+	var installedKB int64
+	var md5sums, conffiles strings.Builder
+	for _, f := range files {
+		if f.isDir() {
+			installedKB++
+			continue
+		}
+		installedKB += (int64(len(f.data)) + 1023) / 1024
+		fmt.Fprintf(&md5sums, "%x  %s\n", md5.Sum(f.data), f.name[1:])
+		if f.conf {
+			fmt.Fprintf(&conffiles, "%s\n", f.name)
+		}
+	}
+	data, err := gzipTar(files)
+	if err != nil {
+		return err
+	}
+
+	var control strings.Builder
+	fmt.Fprintf(&control, "Package: %s\n", packageName)
+	fmt.Fprintf(&control, "Version: %s\n", c.upstreamVersion())
+	fmt.Fprintf(&control, "Architecture: %s\n", arch.deb)
+	fmt.Fprintf(&control, "Maintainer: The Go Authors <golang-dev@googlegroups.com>\n")
+	fmt.Fprintf(&control, "Installed-Size: %d\n", installedKB)
+	fmt.Fprintf(&control, "Section: devel\n")
+	fmt.Fprintf(&control, "Priority: optional\n")
+	fmt.Fprintf(&control, "Homepage: https://go.dev/\n")
+	summary, rest, _ := strings.Cut(description, "\n")
+	fmt.Fprintf(&control, "Description: %s\n", summary)
+	for line := range strings.SplitSeq(rest, "\n") {
+		fmt.Fprintf(&control, " %s\n", line)
+	}
+	controlData, err := gzipTar([]pkgFile{
+		{name: "/", mode: modeDir | 0755, mtime: c.mtime},
+		{name: "/conffiles", mode: modeFile | 0644, mtime: c.mtime, data: []byte(conffiles.String())},
+		{name: "/control", mode: modeFile | 0644, mtime: c.mtime, data: []byte(control.String())},
+		{name: "/md5sums", mode: modeFile | 0644, mtime: c.mtime, data: []byte(md5sums.String())},
+	})
+	if err != nil {
+		return err
+	}
+
+	return writeAr(w, c.mtime, []arMember{
+		{"debian-binary", []byte("2.0\n")},
+		{"control.tar.gz", controlData},
+		{"data.tar.gz", data},
+	})
+}
+
+// gzipTar returns files as a gzip-compressed tar archive, with names
+// relative to "." and owned by root, as in Debian packages.
+func gzipTar(files []pkgFile) ([]byte, error) {
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	tw := tar.NewWriter(zw)
+	for _, f := range files {
+		hdr := &tar.Header{
+			Name:    "." + f.name,
+			Mode:    int64(f.mode & 0777),
+			ModTime: f.mtime,
+			Uname:   "root",
+			Gname:   "root",
+			Format:  tar.FormatGNU,
+		}
+		if f.isDir() {
+			hdr.Typeflag = tar.TypeDir
+			hdr.Name = strings.TrimSuffix(hdr.Name, "/") + "/"
+		} else {
+			hdr.Typeflag = tar.TypeReg
+			hdr.Size = int64(len(f.data))
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(f.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// An arMember is a file in an ar archive.
+type arMember struct {
+	name string
+	data []byte
+}
+
+// writeAr writes members as an ar archive in the common format,
+// owned by root and modified at mtime.
+func writeAr(w io.Writer, mtime time.Time, members []arMember) error {
+	var buf bytes.Buffer
+	buf.WriteString("!<arch>\n")
+	for _, m := range members {
+		if len(m.name) > 16 {
+			return fmt.Errorf("ar member name %q is too long", m.name)
+		}
+		fmt.Fprintf(&buf, "%-16s%-12d%-6d%-6d%-8o%-10d`\n", m.name, max(mtime.Unix(), 0), 0, 0, 0100644, len(m.data))
+		buf.Write(m.data)
+		if len(m.data)%2 != 0 {
+			buf.WriteByte('\n')
+		}
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
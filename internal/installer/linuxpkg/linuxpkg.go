@@ -0,0 +1,181 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package linuxpkg encodes the process of building Debian (.deb) and
+// RPM (.rpm) packages from the given Go toolchain .tar.gz binary archive.
+//
+// The packages are written in pure Go, so they can be built on any
+// system, and they depend only on their inputs. Like the macOS installer,
+// they install the Go toolchain in /usr/local/go, and add its bin
+// directory to PATH for login shells, here via /etc/profile.d/go.sh.
+package linuxpkg
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// InstallerOptions holds options for constructing the packages.
+type InstallerOptions struct {
+	GOARCH string // The target GOARCH.
+}
+
+// packageName is the name of both packages. It's distinct from the
+// golang packages that distributions build themselves.
+const packageName = "go"
+
+const (
+	goRoot      = "/usr/local/go"
+	profilePath = "/etc/profile.d/go.sh"
+)
+
+// profileScript is installed at profilePath.
+const profileScript = `# Add the Go toolchain installed in /usr/local/go to PATH.
+case ":${PATH}:" in
+*:/usr/local/go/bin:*) ;;
+*) PATH="${PATH}:/usr/local/go/bin" ;;
+esac
+`
+
+// description is the package description, with the summary on its first line.
+const description = `The Go programming language toolchain
+Go is an open source programming language that makes it simple to
+build secure, scalable systems. This package installs the Go toolchain
+distributed by the Go project at https://go.dev/dl/ in /usr/local/go.`
+
+// archs maps a GOARCH to its Debian and RPM architecture names.
+// The release targets for GOARCH=arm are built with GOARM=6 and hard floats.
+var archs = map[string]struct{ deb, rpm string }{
+	"386":      {"i386", "i686"},
+	"amd64":    {"amd64", "x86_64"},
+	"arm":      {"armhf", "armv6hl"},
+	"arm64":    {"arm64", "aarch64"},
+	"loong64":  {"loong64", "loongarch64"},
+	"mips":     {"mips", "mips"},
+	"mipsle":   {"mipsel", "mipsel"},
+	"mips64":   {"mips64", "mips64"},
+	"mips64le": {"mips64el", "mips64el"},
+	"ppc64":    {"ppc64", "ppc64"},
+	"ppc64le":  {"ppc64el", "ppc64le"},
+	"riscv64":  {"riscv64", "riscv64"},
+	"s390x":    {"s390x", "s390x"},
+}
+
+// A pkgFile is a file or directory installed by a package.
+type pkgFile struct {
+	name  string // absolute path, such as "/usr/local/go/bin/go"
+	mode  uint32 // including the file type bits, such as 0100644
+	mtime time.Time
+	data  []byte
+	conf  bool // a configuration file
+}
+
+const (
+	modeDir  = 0040000
+	modeFile = 0100000
+)
+
+func (f *pkgFile) isDir() bool { return f.mode&0170000 == modeDir }
+
+// A pkgContents holds what's common to both kinds of package.
+type pkgContents struct {
+	version string    // the Go version, such as "go1.27.0"
+	mtime   time.Time // the modification time of the VERSION file
+	files   []pkgFile // regular files, sorted by name
+}
+
+// versionRE matches the Go versions that are packaged.
+var versionRE = regexp.MustCompile(`^go1(\.[0-9]+)+((rc|beta)[0-9]+)?$`)
+
+// upstreamVersion returns the version of the packages, which is the
+// Go version without the "go" prefix, and with a "~" before any
+// prerelease suffix so that, for example, 1.27rc1 sorts before 1.27.0
+// in both dpkg and rpm.
+func (c *pkgContents) upstreamVersion() string {
+	v := strings.TrimPrefix(c.version, "go")
+	if i := strings.IndexAny(v, "br"); i >= 0 {
+		v = v[:i] + "~" + v[i:]
+	}
+	return v
+}
+
+// readContents reads the files in a Go toolchain .tar.gz binary archive,
+// which are all in the go directory, and adds the profile script.
+func readContents(r io.Reader) (*pkgContents, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	c := new(pkgContents)
+	tr := tar.NewReader(zr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			continue
+		case tar.TypeReg:
+		default:
+			return nil, fmt.Errorf("unsupported file type %q for %s in Go toolchain archive", hdr.Typeflag, hdr.Name)
+		}
+		if !fs.ValidPath(hdr.Name) || !strings.HasPrefix(hdr.Name, "go/") {
+			return nil, fmt.Errorf("unexpected path %q in Go toolchain archive", hdr.Name)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		mtime := hdr.ModTime.Truncate(time.Second)
+		if hdr.Name == "go/VERSION" {
+			c.version, _, _ = strings.Cut(string(data), "\n")
+			c.mtime = mtime
+		}
+		c.files = append(c.files, pkgFile{name: "/usr/local/" + hdr.Name, mode: modeFile | uint32(hdr.Mode&0777), mtime: mtime, data: data})
+	}
+	if len(c.files) == 0 {
+		return nil, errors.New("no files in Go toolchain archive")
+	}
+	if c.version == "" {
+		return nil, fmt.Errorf("no VERSION file in Go toolchain archive")
+	}
+	if !versionRE.MatchString(c.version) {
+		return nil, fmt.Errorf("unexpected Go version %q in Go toolchain archive", c.version)
+	}
+	c.files = append(c.files, pkgFile{name: profilePath, mode: modeFile | 0644, mtime: c.mtime, data: []byte(profileScript), conf: true})
+	slices.SortFunc(c.files, func(a, b pkgFile) int { return strings.Compare(a.name, b.name) })
+	return c, nil
+}
+
+// withDirs returns the files with their parent directories added,
+// sorted by name. Directories outside of goRoot are added only if
+// all is set.
+func (c *pkgContents) withDirs(all bool) []pkgFile {
+	seen := make(map[string]bool)
+	var files []pkgFile
+	for _, f := range c.files {
+		for dir := path.Dir(f.name); !seen[dir]; dir = path.Dir(dir) {
+			seen[dir] = true
+			if !all && dir != goRoot && !strings.HasPrefix(dir, goRoot+"/") {
+				continue
+			}
+			files = append(files, pkgFile{name: dir, mode: modeDir | 0755, mtime: c.mtime})
+		}
+		files = append(files, f)
+	}
+	slices.SortFunc(files, func(a, b pkgFile) int { return strings.Compare(a.name, b.name) })
+	return files
+}
@@ -0,0 +1,512 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linuxpkg
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang.org/x/build/internal/installer/installertest"
+	"golang.org/x/build/internal/releasetargets"
+)
+
+var testFiles = map[string]string{
+	"LICENSE":   "Copyright (c) 2009 The Go Authors.\n",
+	"bin/go":    "\x7fELF go",
+	"bin/gofmt": strings.Repeat("gofmt ", 3000),
+	"src/cmd/go/testdata/script/a_very_long_file_name_that_does_not_fit_in_the_name_field_of_a_ustar_header.txt": "long\n",
+}
+
+// wantFiles returns the regular files the packages should install,
+// with their modes, for a toolchain with testFiles.
+func wantFiles(version string) map[string]string {
+	want := map[string]string{
+		"/etc/profile.d/go.sh":  "0644 " + profileScript,
+		"/usr/local/go/VERSION": "0644 " + version + "\ntime 2026-02-03T04:05:06Z\n",
+	}
+	for name, data := range testFiles {
+		mode := "0644 "
+		if strings.HasPrefix(name, "bin/") {
+			mode = "0755 "
+		}
+		want["/usr/local/go/"+name] = mode + data
+	}
+	return want
+}
+
+func TestUpstreamVersion(t *testing.T) {
+	for _, tt := range []struct {
+		version, want string
+	}{
+		{"go1.27.0", "1.27.0"},
+		{"go1.27.12", "1.27.12"},
+		{"go1.27rc1", "1.27~rc1"},
+		{"go1.27beta2", "1.27~beta2"},
+	} {
+		c := &pkgContents{version: tt.version}
+		if got := c.upstreamVersion(); got != tt.want {
+			t.Errorf("upstreamVersion(%q) = %q, want %q", tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestReadContentsErrors(t *testing.T) {
+	for _, version := range []string{"go1.27.0 X:boringcrypto", "devel go1.28-abcdef", ""} {
+		tgz := installertest.Tgz(t, version, testFiles)
+		if _, err := readContents(bytes.NewReader(tgz)); err == nil {
+			t.Errorf("readContents with version %q succeeded, want error", version)
+		}
+	}
+}
+
+func TestWriteDeb(t *testing.T) {
+	for _, version := range []string{"go1.27.0", "go1.27rc1"} {
+		for _, goarch := range []string{"386", "amd64", "arm", "arm64"} {
+			t.Run(version+"-"+goarch, func(t *testing.T) {
+				tgz := installertest.Tgz(t, version, testFiles)
+				var deb bytes.Buffer
+				if err := WriteDeb(&deb, bytes.NewReader(tgz), InstallerOptions{GOARCH: goarch}); err != nil {
+					t.Fatal(err)
+				}
+				var again bytes.Buffer
+				if err := WriteDeb(&again, bytes.NewReader(tgz), InstallerOptions{GOARCH: goarch}); err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(deb.Bytes(), again.Bytes()) {
+					t.Errorf("WriteDeb isn't deterministic")
+				}
+
+				members := readTestAr(t, deb.Bytes())
+				if got, want := slices.Sorted(maps.Keys(members)), []string{"control.tar.gz", "data.tar.gz", "debian-binary"}; !slices.Equal(got, want) {
+					t.Fatalf("ar members = %q, want %q", got, want)
+				}
+				if got := string(members["debian-binary"]); got != "2.0\n" {
+					t.Errorf("debian-binary = %q, want %q", got, "2.0\n")
+				}
+
+				control := readTestTgz(t, members["control.tar.gz"])
+				fields := make(map[string]string)
+				for line := range strings.SplitSeq(strings.TrimPrefix(control["./control"], "0644 "), "\n") {
+					if k, v, ok := strings.Cut(line, ": "); ok {
+						fields[k] = v
+					}
+				}
+				for k, want := range map[string]string{
+					"Package":      "go",
+					"Version":      strings.Replace(strings.TrimPrefix(version, "go"), "rc", "~rc", 1),
+					"Architecture": archs[goarch].deb,
+				} {
+					if got := fields[k]; got != want {
+						t.Errorf("control field %s = %q, want %q", k, got, want)
+					}
+				}
+				if got, want := control["./conffiles"], "0644 /etc/profile.d/go.sh\n"; got != want {
+					t.Errorf("conffiles = %q, want %q", got, want)
+				}
+
+				data := readTestTgz(t, members["data.tar.gz"])
+				files := make(map[string]string)
+				for name, content := range data {
+					if !strings.HasSuffix(name, "/") {
+						files[strings.TrimPrefix(name, ".")] = content
+					}
+				}
+				if want := wantFiles(version); !maps.Equal(files, want) {
+					t.Errorf("data.tar.gz files = %q, want %q", files, want)
+				}
+				for _, dir := range []string{"./", "./etc/", "./etc/profile.d/", "./usr/local/go/", "./usr/local/go/bin/"} {
+					if got, want := data[dir], "0755 "; got != want {
+						t.Errorf("data.tar.gz directory %s = %q, want %q", dir, got, want)
+					}
+				}
+				var wantSums []string
+				for name, content := range files {
+					_, content, _ = strings.Cut(content, " ")
+					wantSums = append(wantSums, fmt.Sprintf("%x  %s", md5.Sum([]byte(content)), name[1:]))
+				}
+				gotSums := strings.Split(strings.TrimSuffix(strings.TrimPrefix(control["./md5sums"], "0644 "), "\n"), "\n")
+				slices.Sort(gotSums)
+				slices.Sort(wantSums)
+				if !slices.Equal(gotSums, wantSums) {
+					t.Errorf("md5sums = %q, want %q", gotSums, wantSums)
+				}
+			})
+		}
+	}
+}
+
+// TestWriteDebDpkg checks that dpkg-deb accepts the package
+// and extracts the expected files.
+func TestWriteDebDpkg(t *testing.T) {
+	if _, err := exec.LookPath("dpkg-deb"); err != nil {
+		t.Skip("dpkg-deb not found")
+	}
+	tgz := installertest.Tgz(t, "go1.27.0", testFiles)
+	dir := t.TempDir()
+	debPath := filepath.Join(dir, "go.deb")
+	var deb bytes.Buffer
+	if err := WriteDeb(&deb, bytes.NewReader(tgz), InstallerOptions{GOARCH: "amd64"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(debPath, deb.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := exec.Command("dpkg-deb", "--info", debPath).CombinedOutput(); err != nil {
+		t.Fatalf("dpkg-deb --info: %v\n%s", err, out)
+	}
+	root := filepath.Join(dir, "root")
+	if out, err := exec.Command("dpkg-deb", "--extract", debPath, root).CombinedOutput(); err != nil {
+		t.Fatalf("dpkg-deb --extract: %v\n%s", err, out)
+	}
+	for name, want := range wantFiles("go1.27.0") {
+		_, want, _ = strings.Cut(want, " ")
+		got, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(name)))
+		if err != nil {
+			t.Error(err)
+		} else if string(got) != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestWriteRPM(t *testing.T) {
+	for _, version := range []string{"go1.27.0", "go1.27rc1"} {
+		for _, goarch := range []string{"386", "amd64", "arm", "arm64"} {
+			t.Run(version+"-"+goarch, func(t *testing.T) {
+				tgz := installertest.Tgz(t, version, testFiles)
+				var rpm bytes.Buffer
+				if err := WriteRPM(&rpm, bytes.NewReader(tgz), InstallerOptions{GOARCH: goarch}); err != nil {
+					t.Fatal(err)
+				}
+				var again bytes.Buffer
+				if err := WriteRPM(&again, bytes.NewReader(tgz), InstallerOptions{GOARCH: goarch}); err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(rpm.Bytes(), again.Bytes()) {
+					t.Errorf("WriteRPM isn't deterministic")
+				}
+
+				b := rpm.Bytes()
+				if !bytes.HasPrefix(b, []byte("\xed\xab\xee\xdb\x03\x00\x00\x00")) {
+					t.Fatalf("bad lead %x", b[:8])
+				}
+				sig, n := readTestRPMHeader(t, b[96:], rpmTagHeaderSignatures)
+				headerStart := 96 + (n+7)&^7
+				header, n := readTestRPMHeader(t, b[headerStart:], rpmTagHeaderImmutable)
+				headerBytes := b[headerStart : headerStart+n]
+				payload := b[headerStart+n:]
+
+				if got, want := sig[rpmSigTagSize], []int32{int32(len(headerBytes) + len(payload))}; !slices.Equal(got.([]int32), want) {
+					t.Errorf("signature size = %v, want %v", got, want)
+				}
+				if got, want := sig[rpmSigTagSHA256], fmt.Sprintf("%x", sha256.Sum256(headerBytes)); got != want {
+					t.Errorf("signature SHA-256 = %v, want %v", got, want)
+				}
+				if got, want := sig[rpmSigTagSHA1], fmt.Sprintf("%x", sha1.Sum(headerBytes)); got != want {
+					t.Errorf("signature SHA-1 = %v, want %v", got, want)
+				}
+				sum := md5.Sum(b[headerStart:])
+				if got, want := sig[rpmSigTagMD5], sum[:]; !bytes.Equal(got.([]byte), want) {
+					t.Errorf("signature MD5 = %x, want %x", got, want)
+				}
+				if got, want := header[rpmTagPayloadDigest], []string{fmt.Sprintf("%x", sha256.Sum256(payload))}; !slices.Equal(got.([]string), want) {
+					t.Errorf("payload digest = %v, want %v", got, want)
+				}
+
+				wantVersion := strings.Replace(strings.TrimPrefix(version, "go"), "rc", "~rc", 1)
+				for tag, want := range map[int]string{
+					rpmTagName:      "go",
+					rpmTagVersion:   wantVersion,
+					rpmTagRelease:   "1",
+					rpmTagArch:      archs[goarch].rpm,
+					rpmTagOS:        "linux",
+					rpmTagSourceRPM: "go-" + wantVersion + "-1.src.rpm",
+				} {
+					if got := header[tag]; got != want {
+						t.Errorf("tag %d = %q, want %q", tag, got, want)
+					}
+				}
+				if got := slices.Contains(header[rpmTagRequireName].([]string), "rpmlib(TildeInVersions)"); got != strings.Contains(wantVersion, "~") {
+					t.Errorf("requires rpmlib(TildeInVersions) = %v for version %s", got, wantVersion)
+				}
+
+				// The header's file list matches the payload.
+				zr, err := gzip.NewReader(bytes.NewReader(payload))
+				if err != nil {
+					t.Fatal(err)
+				}
+				cpio, err := io.ReadAll(zr)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got, want := sig[rpmSigTagPayloadSize], []int32{int32(len(cpio))}; !slices.Equal(got.([]int32), want) {
+					t.Errorf("signature payload size = %v, want %v", got, want)
+				}
+				entries := readTestCpio(t, cpio)
+				dirNames, baseNames := header[rpmTagDirNames].([]string), header[rpmTagBaseNames].([]string)
+				dirIndexes, modes := header[rpmTagDirIndexes].([]int32), header[rpmTagFileModes].([]int16)
+				digests := header[rpmTagFileDigests].([]string)
+				if len(entries) != len(baseNames) {
+					t.Fatalf("payload has %d files, header has %d", len(entries), len(baseNames))
+				}
+				files := make(map[string]string)
+				for i, e := range entries {
+					name := dirNames[dirIndexes[i]] + baseNames[i]
+					if e.name != "."+name {
+						t.Errorf("payload file %d is %s, header has %s", i, e.name, name)
+					}
+					if uint16(modes[i]) != uint16(e.mode) {
+						t.Errorf("%s has mode %o in payload, %o in header", name, e.mode, uint16(modes[i]))
+					}
+					if e.mode&0170000 == modeDir {
+						continue
+					}
+					if got, want := digests[i], fmt.Sprintf("%x", sha256.Sum256([]byte(e.data))); got != want {
+						t.Errorf("%s has digest %s in header, want %s", name, got, want)
+					}
+					files[name] = fmt.Sprintf("%04o %s", e.mode&0777, e.data)
+				}
+				if want := wantFiles(version); !maps.Equal(files, want) {
+					t.Errorf("payload files = %q, want %q", files, want)
+				}
+				if got, want := dirNames[0], "/etc/profile.d/"; got != want {
+					t.Errorf("first directory is %q, want %q", got, want)
+				}
+				if slices.Contains(dirNames, "/") || slices.Contains(baseNames, "etc") || slices.Contains(baseNames, "local") {
+					t.Errorf("package owns system directories: %q", dirNames)
+				}
+			})
+		}
+	}
+}
+
+// TestWriteRPMBsdtar checks that bsdtar can read the payload,
+// if it's installed.
+func TestWriteRPMBsdtar(t *testing.T) {
+	if _, err := exec.LookPath("bsdtar"); err != nil {
+		t.Skip("bsdtar not found")
+	}
+	tgz := installertest.Tgz(t, "go1.27.0", testFiles)
+	var rpm bytes.Buffer
+	if err := WriteRPM(&rpm, bytes.NewReader(tgz), InstallerOptions{GOARCH: "amd64"}); err != nil {
+		t.Fatal(err)
+	}
+	rpmPath := filepath.Join(t.TempDir(), "go.rpm")
+	if err := os.WriteFile(rpmPath, rpm.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out, err := exec.Command("bsdtar", "-xOf", rpmPath, "./usr/local/go/bin/gofmt").CombinedOutput()
+	if err != nil {
+		t.Fatalf("bsdtar: %v\n%s", err, out)
+	}
+	if got, want := string(out), testFiles["bin/gofmt"]; got != want {
+		t.Errorf("bsdtar extracted %d bytes, want %d", len(got), len(want))
+	}
+}
+
+func TestWriteUnsupported(t *testing.T) {
+	tgz := installertest.Tgz(t, "go1.27.0", testFiles)
+	if err := WriteDeb(io.Discard, bytes.NewReader(tgz), InstallerOptions{GOARCH: "wasm"}); err == nil {
+		t.Errorf("WriteDeb with GOARCH=wasm succeeded, want error")
+	}
+	if err := WriteRPM(io.Discard, bytes.NewReader(tgz), InstallerOptions{}); err == nil {
+		t.Errorf("WriteRPM with empty GOARCH succeeded, want error")
+	}
+}
+
+// TestReleaseTargetArchs checks that packages can be built
+// for each Linux release target of each Go release.
+func TestReleaseTargetArchs(t *testing.T) {
+	for _, x := range releasetargets.Releases() {
+		for name, target := range releasetargets.TargetsForGo1Point(x) {
+			if _, ok := archs[target.GOARCH]; target.GOOS == "linux" && !ok {
+				t.Errorf("Go 1.%d release target %s: GOARCH %q isn't supported", x, name, target.GOARCH)
+			}
+		}
+	}
+}
+
+// readTestAr returns the members of an ar archive.
+func readTestAr(t *testing.T, b []byte) map[string][]byte {
+	t.Helper()
+	b, ok := bytes.CutPrefix(b, []byte("!<arch>\n"))
+	if !ok {
+		t.Fatalf("bad ar magic")
+	}
+	members := make(map[string][]byte)
+	for len(b) > 0 {
+		if len(b) < 60 || string(b[58:60]) != "`\n" {
+			t.Fatalf("bad ar header %q", b[:min(len(b), 60)])
+		}
+		name := strings.TrimSpace(string(b[:16]))
+		size, err := strconv.Atoi(strings.TrimSpace(string(b[48:58])))
+		if err != nil {
+			t.Fatal(err)
+		}
+		members[name] = b[60 : 60+size]
+		b = b[60+size+size%2:]
+	}
+	return members
+}
+
+// readTestTgz returns the entries of a .tar.gz archive owned by root,
+// as their permissions followed by a space and their contents.
+func readTestTgz(t *testing.T, b []byte) map[string]string {
+	t.Helper()
+	zr, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(zr)
+	entries := make(map[string]string)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Uid != 0 || hdr.Gid != 0 || hdr.Uname != "root" || hdr.Gname != "root" {
+			t.Errorf("%s is owned by %d(%s):%d(%s), want root", hdr.Name, hdr.Uid, hdr.Uname, hdr.Gid, hdr.Gname)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries[hdr.Name] = fmt.Sprintf("%04o %s", hdr.Mode, data)
+	}
+	return entries
+}
+
+// readTestRPMHeader reads an RPM header structure with the given
+// region tag, and returns its entries by tag and its length.
+func readTestRPMHeader(t *testing.T, b []byte, region int) (map[int]any, int) {
+	t.Helper()
+	be := binary.BigEndian
+	if !bytes.HasPrefix(b, []byte{0x8e, 0xad, 0xe8, 0x01, 0, 0, 0, 0}) {
+		t.Fatalf("bad header magic %x", b[:8])
+	}
+	il, dl := int(be.Uint32(b[8:])), int(be.Uint32(b[12:]))
+	index, store := b[16:16+16*il], b[16+16*il:16+16*il+dl]
+	entries := make(map[int]any)
+	lastTag, end := 0, 0
+	for i := range il {
+		e := index[16*i:]
+		tag, typ, offset, count := int(be.Uint32(e)), int(be.Uint32(e[4:])), int(be.Uint32(e[8:])), int(be.Uint32(e[12:]))
+		if i == 0 {
+			trailer := store[offset:]
+			if tag != region || typ != rpmTypeBin || count != 16 || offset != dl-16 ||
+				int(be.Uint32(trailer)) != region || int(int32(be.Uint32(trailer[8:]))) != -16*il {
+				t.Fatalf("bad region entry %d %d %d %d, trailer %x", tag, typ, offset, count, trailer)
+			}
+			continue
+		}
+		if tag <= lastTag || offset < end {
+			t.Fatalf("entry for tag %d at %d isn't sorted after tag %d ending at %d", tag, offset, lastTag, end)
+		}
+		lastTag = tag
+		data := store[offset:]
+		switch typ {
+		case rpmTypeString, rpmTypeI18NString:
+			s, _, _ := bytes.Cut(data, []byte{0})
+			entries[tag] = string(s)
+			end = offset + len(s) + 1
+		case rpmTypeStringArray:
+			var ss []string
+			end = offset
+			for range count {
+				s, _, _ := bytes.Cut(data, []byte{0})
+				ss = append(ss, string(s))
+				data = data[len(s)+1:]
+				end += len(s) + 1
+			}
+			entries[tag] = ss
+		case rpmTypeBin:
+			entries[tag] = data[:count]
+			end = offset + count
+		case rpmTypeInt16:
+			if offset%2 != 0 {
+				t.Errorf("tag %d at misaligned offset %d", tag, offset)
+			}
+			var ns []int16
+			for i := range count {
+				ns = append(ns, int16(be.Uint16(data[2*i:])))
+			}
+			entries[tag] = ns
+			end = offset + 2*count
+		case rpmTypeInt32:
+			if offset%4 != 0 {
+				t.Errorf("tag %d at misaligned offset %d", tag, offset)
+			}
+			var ns []int32
+			for i := range count {
+				ns = append(ns, int32(be.Uint32(data[4*i:])))
+			}
+			entries[tag] = ns
+			end = offset + 4*count
+		default:
+			t.Fatalf("unexpected type %d for tag %d", typ, tag)
+		}
+	}
+	if end != dl-16 {
+		t.Errorf("entries end at %d, want %d", end, dl-16)
+	}
+	return entries, 16 + 16*il + dl
+}
+
+type testCpioEntry struct {
+	name string
+	mode uint32
+	data string
+}
+
+// readTestCpio returns the entries of a "newc" cpio archive owned by root.
+func readTestCpio(t *testing.T, b []byte) []testCpioEntry {
+	t.Helper()
+	var entries []testCpioEntry
+	for {
+		if len(b) < 110 || string(b[:6]) != "070701" {
+			t.Fatalf("bad cpio header %q", b[:min(len(b), 110)])
+		}
+		var fields [13]uint32
+		for i := range fields {
+			n, err := strconv.ParseUint(string(b[6+8*i:14+8*i]), 16, 32)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fields[i] = uint32(n)
+		}
+		mode, uid, gid, size, nameSize := fields[1], fields[2], fields[3], int(fields[6]), int(fields[11])
+		name := string(b[110 : 110+nameSize-1])
+		b = b[(110+nameSize+3)&^3:]
+		if name == "TRAILER!!!" {
+			break
+		}
+		if uid != 0 || gid != 0 {
+			t.Errorf("%s is owned by %d:%d, want root", name, uid, gid)
+		}
+		entries = append(entries, testCpioEntry{name, mode, string(b[:size])})
+		b = b[(size+3)&^3:]
+	}
+	if len(b) != 0 {
+		t.Errorf("%d bytes after cpio trailer", len(b))
+	}
+	return entries
+}
@@ -0,0 +1,364 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linuxpkg
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"slices"
+	"strings"
+)
+
+// WriteRPM writes an unsigned RPM binary package for the Go toolchain
+// .tar.gz binary archive read from tgz to w.
+//
+// It writes the same format that rpmbuild does: the lead, the signature
+// header holding the package's sizes and digests, the header holding
+// its metadata and file list, and a gzip-compressed cpio payload.
+// See https://rpm-software-management.github.io/rpm/manual/format_v4.html.
+func WriteRPM(w io.Writer, tgz io.Reader, opt InstallerOptions) error {
+	arch, ok := archs[opt.GOARCH]
+	if !ok {
+		return fmt.Errorf("unsupported GOARCH %q", opt.GOARCH)
+	}
+	c, err := readContents(tgz)
+	if err != nil {
+		return err
+	}
+
+	files := c.withDirs(false)
+	var size int64
+	var (
+		sizes, mtimes, flags, inodes, devices, dirIndexes []int32
+		modes, rdevs                                      []int16
+		digests, linkTos, users, groups, langs            []string
+		baseNames, dirNames                               []string
+	)
+	for i, f := range files {
+		var digest string
+		if !f.isDir() {
+			size += int64(len(f.data))
+			digest = fmt.Sprintf("%x", sha256.Sum256(f.data))
+		}
+		var flag int32
+		if f.conf {
+			flag = rpmFileConfig | rpmFileNoReplace
+		}
+		dir, base := path.Split(f.name)
+		di := slices.Index(dirNames, dir)
+		if di < 0 {
+			di = len(dirNames)
+			dirNames = append(dirNames, dir)
+		}
+		sizes = append(sizes, int32(len(f.data)))
+		mtimes = append(mtimes, int32(f.mtime.Unix()))
+		flags = append(flags, flag)
+		inodes = append(inodes, int32(i+1))
+		devices = append(devices, 1)
+		dirIndexes = append(dirIndexes, int32(di))
+		modes = append(modes, int16(f.mode))
+		rdevs = append(rdevs, 0)
+		digests = append(digests, digest)
+		linkTos = append(linkTos, "")
+		users = append(users, "root")
+		groups = append(groups, "root")
+		langs = append(langs, "")
+		baseNames = append(baseNames, base)
+	}
+	if size > math.MaxInt32 {
+		return fmt.Errorf("Go toolchain of %d bytes is too large for an RPM package", size)
+	}
+
+	var cpio bytes.Buffer
+	if err := writeCpio(&cpio, files); err != nil {
+		return err
+	}
+	var payload bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&payload, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	zw.Write(cpio.Bytes())
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	version, release := c.upstreamVersion(), "1"
+	requires := []struct{ name, version string }{
+		{"rpmlib(CompressedFileNames)", "3.0.4-1"},
+		{"rpmlib(FileDigests)", "4.6.0-1"},
+		{"rpmlib(PayloadFilesHavePrefix)", "4.0-1"},
+	}
+	if strings.Contains(version, "~") {
+		requires = append(requires, struct{ name, version string }{"rpmlib(TildeInVersions)", "4.10.0-1"})
+	}
+	var reqNames, reqVersions []string
+	var reqFlags []int32
+	for _, r := range requires {
+		reqNames = append(reqNames, r.name)
+		reqVersions = append(reqVersions, r.version)
+		reqFlags = append(reqFlags, rpmSenseRPMLib|rpmSenseLess|rpmSenseEqual)
+	}
+	summary, rest, _ := strings.Cut(description, "\n")
+	header := encodeRPMHeader(rpmTagHeaderImmutable, []rpmEntry{
+		{rpmTagHeaderI18NTable, []string{"C"}},
+		{rpmTagName, packageName},
+		{rpmTagVersion, version},
+		{rpmTagRelease, release},
+		{rpmTagSummary, rpmI18NString(summary)},
+		{rpmTagDescription, rpmI18NString(strings.ReplaceAll(rest, "\n", " "))},
+		{rpmTagBuildTime, []int32{int32(c.mtime.Unix())}},
+		{rpmTagSize, []int32{int32(size)}},
+		{rpmTagLicense, "BSD-3-Clause"},
+		{rpmTagGroup, rpmI18NString("Development/Languages")},
+		{rpmTagURL, "https://go.dev/"},
+		{rpmTagOS, "linux"},
+		{rpmTagArch, arch.rpm},
+		{rpmTagFileSizes, sizes},
+		{rpmTagFileModes, modes},
+		{rpmTagFileRdevs, rdevs},
+		{rpmTagFileMtimes, mtimes},
+		{rpmTagFileDigests, digests},
+		{rpmTagFileLinkTos, linkTos},
+		{rpmTagFileFlags, flags},
+		{rpmTagFileUserName, users},
+		{rpmTagFileGroupName, groups},
+		{rpmTagSourceRPM, fmt.Sprintf("%s-%s-%s.src.rpm", packageName, version, release)},
+		{rpmTagProvideName, []string{packageName, fmt.Sprintf("%s(%s)", packageName, arch.rpm)}},
+		{rpmTagRequireFlags, reqFlags},
+		{rpmTagRequireName, reqNames},
+		{rpmTagRequireVersion, reqVersions},
+		{rpmTagFileDevices, devices},
+		{rpmTagFileInodes, inodes},
+		{rpmTagFileLangs, langs},
+		{rpmTagProvideFlags, []int32{rpmSenseEqual, rpmSenseEqual}},
+		{rpmTagProvideVersion, []string{version + "-" + release, version + "-" + release}},
+		{rpmTagDirIndexes, dirIndexes},
+		{rpmTagBaseNames, baseNames},
+		{rpmTagDirNames, dirNames},
+		{rpmTagPayloadFormat, "cpio"},
+		{rpmTagPayloadCompressor, "gzip"},
+		{rpmTagPayloadFlags, "9"},
+		{rpmTagFileDigestAlgo, []int32{rpmDigestSHA256}},
+		{rpmTagPayloadDigest, []string{fmt.Sprintf("%x", sha256.Sum256(payload.Bytes()))}},
+		{rpmTagPayloadDigestAlgo, []int32{rpmDigestSHA256}},
+	})
+
+	md5sum := md5.New()
+	md5sum.Write(header)
+	md5sum.Write(payload.Bytes())
+	signature := encodeRPMHeader(rpmTagHeaderSignatures, []rpmEntry{
+		{rpmSigTagSHA1, fmt.Sprintf("%x", sha1.Sum(header))},
+		{rpmSigTagSHA256, fmt.Sprintf("%x", sha256.Sum256(header))},
+		{rpmSigTagSize, []int32{int32(len(header) + payload.Len())}},
+		{rpmSigTagMD5, md5sum.Sum(nil)},
+		{rpmSigTagPayloadSize, []int32{int32(cpio.Len())}},
+	})
+	// The signature header is padded to a multiple of 8 bytes.
+	signature = append(signature, make([]byte, -len(signature)&7)...)
+
+	// The lead is obsolete, but still required. Only its magic number,
+	// version, and type are checked.
+	lead := make([]byte, 96)
+	copy(lead, "\xed\xab\xee\xdb\x03\x00")
+	copy(lead[10:76], fmt.Sprintf("%s-%s-%s", packageName, version, release))
+	binary.BigEndian.PutUint16(lead[76:], 1) // Linux
+	binary.BigEndian.PutUint16(lead[78:], 5) // a header-style signature follows
+
+	for _, b := range [][]byte{lead, signature, header, payload.Bytes()} {
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Header tags, from rpmtag.h.
+const (
+	rpmTagHeaderSignatures  = 62
+	rpmTagHeaderImmutable   = 63
+	rpmTagHeaderI18NTable   = 100
+	rpmTagName              = 1000
+	rpmTagVersion           = 1001
+	rpmTagRelease           = 1002
+	rpmTagSummary           = 1004
+	rpmTagDescription       = 1005
+	rpmTagBuildTime         = 1006
+	rpmTagSize              = 1009
+	rpmTagLicense           = 1014
+	rpmTagGroup             = 1016
+	rpmTagURL               = 1020
+	rpmTagOS                = 1021
+	rpmTagArch              = 1022
+	rpmTagFileSizes         = 1028
+	rpmTagFileModes         = 1030
+	rpmTagFileRdevs         = 1033
+	rpmTagFileMtimes        = 1034
+	rpmTagFileDigests       = 1035
+	rpmTagFileLinkTos       = 1036
+	rpmTagFileFlags         = 1037
+	rpmTagFileUserName      = 1039
+	rpmTagFileGroupName     = 1040
+	rpmTagSourceRPM         = 1044
+	rpmTagProvideName       = 1047
+	rpmTagRequireFlags      = 1048
+	rpmTagRequireName       = 1049
+	rpmTagRequireVersion    = 1050
+	rpmTagFileDevices       = 1095
+	rpmTagFileInodes        = 1096
+	rpmTagFileLangs         = 1097
+	rpmTagProvideFlags      = 1112
+	rpmTagProvideVersion    = 1113
+	rpmTagDirIndexes        = 1116
+	rpmTagBaseNames         = 1117
+	rpmTagDirNames          = 1118
+	rpmTagPayloadFormat     = 1124
+	rpmTagPayloadCompressor = 1125
+	rpmTagPayloadFlags      = 1126
+	rpmTagFileDigestAlgo    = 5011
+	rpmTagPayloadDigest     = 5092
+	rpmTagPayloadDigestAlgo = 5093
+
+	rpmSigTagSHA1        = 269
+	rpmSigTagSHA256      = 273
+	rpmSigTagSize        = 1000
+	rpmSigTagMD5         = 1004
+	rpmSigTagPayloadSize = 1007
+)
+
+// Header data types.
+const (
+	rpmTypeInt16       = 3
+	rpmTypeInt32       = 4
+	rpmTypeString      = 6
+	rpmTypeBin         = 7
+	rpmTypeStringArray = 8
+	rpmTypeI18NString  = 9
+)
+
+const (
+	rpmFileConfig    = 1 << 0
+	rpmFileNoReplace = 1 << 4
+
+	rpmSenseLess   = 1 << 1
+	rpmSenseEqual  = 1 << 3
+	rpmSenseRPMLib = 1 << 24
+
+	rpmDigestSHA256 = 8
+)
+
+// An rpmEntry is a tag and its value, which is a string,
+// rpmI18NString, []string, []byte, []int16, or []int32.
+type rpmEntry struct {
+	tag   int
+	value any
+}
+
+// An rpmI18NString is a translatable string. Only the "C" locale is recorded.
+type rpmI18NString string
+
+// encodeRPMHeader returns a header structure holding entries,
+// which must be sorted by tag, in the immutable region with the
+// given tag.
+func encodeRPMHeader(region int, entries []rpmEntry) []byte {
+	be := binary.BigEndian
+	var index, store []byte
+	addIndex := func(tag, typ, offset, count int) {
+		index = be.AppendUint32(index, uint32(tag))
+		index = be.AppendUint32(index, uint32(typ))
+		index = be.AppendUint32(index, uint32(offset))
+		index = be.AppendUint32(index, uint32(count))
+	}
+	for _, e := range entries {
+		var typ, count, align int
+		var data []byte
+		switch v := e.value.(type) {
+		case string:
+			typ, count, data = rpmTypeString, 1, append([]byte(v), 0)
+		case rpmI18NString:
+			typ, count, data = rpmTypeI18NString, 1, append([]byte(v), 0)
+		case []string:
+			typ, count = rpmTypeStringArray, len(v)
+			for _, s := range v {
+				data = append(append(data, s...), 0)
+			}
+		case []byte:
+			typ, count, data = rpmTypeBin, len(v), v
+		case []int16:
+			typ, count, align = rpmTypeInt16, len(v), 2
+			for _, n := range v {
+				data = be.AppendUint16(data, uint16(n))
+			}
+		case []int32:
+			typ, count, align = rpmTypeInt32, len(v), 4
+			for _, n := range v {
+				data = be.AppendUint32(data, uint32(n))
+			}
+		default:
+			panic(fmt.Sprintf("unexpected type %T for RPM tag %d", e.value, e.tag))
+		}
+		if align > 0 {
+			store = append(store, make([]byte, -len(store)&(align-1))...)
+		}
+		addIndex(e.tag, typ, len(store), count)
+		store = append(store, data...)
+	}
+
+	// The region's trailer is at the end of the store, and refers
+	// back to the start of the index, which includes the region's entry.
+	trailer := len(store)
+	store = be.AppendUint32(store, uint32(region))
+	store = be.AppendUint32(store, rpmTypeBin)
+	store = be.AppendUint32(store, uint32(-16*(len(entries)+1)))
+	store = be.AppendUint32(store, 16)
+	regionIndex := index
+	index = nil
+	addIndex(region, rpmTypeBin, trailer, 16)
+	index = append(index, regionIndex...)
+
+	hdr := []byte{0x8e, 0xad, 0xe8, 0x01, 0, 0, 0, 0}
+	hdr = be.AppendUint32(hdr, uint32(len(entries)+1))
+	hdr = be.AppendUint32(hdr, uint32(len(store)))
+	hdr = append(hdr, index...)
+	return append(hdr, store...)
+}
+
+// writeCpio writes files as a cpio archive in the "newc" format
+// that RPM payloads use, with names relative to "." and files owned by root.
+// Each file's inode number is its index in files plus one.
+func writeCpio(w io.Writer, files []pkgFile) error {
+	bw := bufio.NewWriter(w)
+	// Each header, with its name, and each file's data are padded
+	// to a multiple of 4 bytes.
+	write := func(ino int, mode uint32, nlink int, mtime int64, name string, data []byte) {
+		hdr := fmt.Sprintf("070701%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%s\x00",
+			ino, mode, 0, 0, nlink, mtime, len(data), 0, 0, 0, 0, len(name)+1, 0, name)
+		bw.WriteString(hdr)
+		bw.Write(make([]byte, -len(hdr)&3))
+		bw.Write(data)
+		bw.Write(make([]byte, -len(data)&3))
+	}
+	for i, f := range files {
+		if len(f.data) > math.MaxUint32 {
+			return fmt.Errorf("%s is too large for a cpio archive", f.name)
+		}
+		nlink := 1
+		if f.isDir() {
+			nlink = 2
+		}
+		write(i+1, f.mode, nlink, max(f.mtime.Unix(), 0), "."+f.name, f.data)
+	}
+	write(0, 0, 1, 0, "TRAILER!!!", nil)
+	return bw.Flush()
+}
@@ -0,0 +1,159 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package windowsmsi
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// A minimal writer of Microsoft cabinet files, with all files in one
+// MSZIP-compressed folder, as embedded in MSI packages.
+// See https://learn.microsoft.com/en-us/previous-versions/bb417343(v=msdn.10).
+
+const cabBlockSize = 32768 // uncompressed bytes per data block
+
+// A cabFile is a file in a cabinet.
+type cabFile struct {
+	name  string
+	data  []byte
+	mtime time.Time
+}
+
+// writeCab writes a cabinet containing files, in order.
+func writeCab(w io.Writer, files []cabFile) error {
+	if len(files) > 0xFFFF {
+		return fmt.Errorf("too many files for a cabinet: %d", len(files))
+	}
+
+	// Compress the concatenated file data into blocks,
+	// each an independent deflate stream prefixed by "CK".
+	type cabBlock struct {
+		data []byte // "CK" and compressed data
+		n    int    // uncompressed size
+	}
+	var blocks []cabBlock
+	var block bytes.Buffer
+	var raw []byte
+	fw, err := flate.NewWriter(&block, flate.BestCompression)
+	if err != nil {
+		return err
+	}
+	flush := func() error {
+		block.Reset()
+		block.WriteString("CK")
+		fw.Reset(&block)
+		if _, err := fw.Write(raw); err != nil {
+			return err
+		}
+		if err := fw.Close(); err != nil {
+			return err
+		}
+		blocks = append(blocks, cabBlock{bytes.Clone(block.Bytes()), len(raw)})
+		raw = raw[:0]
+		return nil
+	}
+	var total int64
+	for _, f := range files {
+		total += int64(len(f.data))
+		for data := f.data; len(data) > 0; {
+			n := min(len(data), cabBlockSize-len(raw))
+			raw = append(raw, data[:n]...)
+			data = data[n:]
+			if len(raw) == cabBlockSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if len(raw) > 0 {
+		if err := flush(); err != nil {
+			return err
+		}
+	}
+	if len(blocks) > 0xFFFF || total > 0x7FFF8000 {
+		return fmt.Errorf("too much data for a cabinet: %d bytes", total)
+	}
+
+	// Compute the offsets of the sections.
+	const (
+		headerSize = 36
+		folderSize = 8
+		fileSize   = 16
+		dataSize   = 8
+	)
+	filesOffset := headerSize + folderSize
+	dataOffset := filesOffset
+	for _, f := range files {
+		dataOffset += fileSize + len(f.name) + 1
+	}
+	cabSize := dataOffset
+	for _, blk := range blocks {
+		cabSize += dataSize + len(blk.data)
+	}
+
+	le := binary.LittleEndian
+	bw := bufio.NewWriter(w)
+	var b []byte
+	b = append(b, "MSCF"...)
+	b = le.AppendUint32(b, 0) // reserved
+	b = le.AppendUint32(b, uint32(cabSize))
+	b = le.AppendUint32(b, 0) // reserved
+	b = le.AppendUint32(b, uint32(filesOffset))
+	b = le.AppendUint32(b, 0) // reserved
+	b = append(b, 3, 1)       // version 1.3
+	b = le.AppendUint16(b, 1) // folders
+	b = le.AppendUint16(b, uint16(len(files)))
+	b = le.AppendUint16(b, 0) // flags
+	b = le.AppendUint16(b, 0) // set ID
+	b = le.AppendUint16(b, 0) // cabinet number in set
+
+	// The one folder.
+	b = le.AppendUint32(b, uint32(dataOffset))
+	b = le.AppendUint16(b, uint16(len(blocks)))
+	b = le.AppendUint16(b, 1) // MSZIP compression
+
+	var offset uint32
+	for _, f := range files {
+		date, tm := dosTime(f.mtime)
+		b = le.AppendUint32(b, uint32(len(f.data)))
+		b = le.AppendUint32(b, offset) // offset in uncompressed folder data
+		b = le.AppendUint16(b, 0)      // folder index
+		b = le.AppendUint16(b, date)
+		b = le.AppendUint16(b, tm)
+		b = le.AppendUint16(b, 0x20) // archive attribute
+		b = append(b, f.name...)
+		b = append(b, 0)
+		offset += uint32(len(f.data))
+	}
+	bw.Write(b)
+
+	for _, blk := range blocks {
+		b = b[:0]
+		b = le.AppendUint32(b, 0) // no checksum
+		b = le.AppendUint16(b, uint16(len(blk.data)))
+		b = le.AppendUint16(b, uint16(blk.n))
+		bw.Write(b)
+		bw.Write(blk.data)
+	}
+	return bw.Flush()
+}
+
+// dosTime returns t as an MS-DOS date and time.
+func dosTime(t time.Time) (date, tm uint16) {
+	t = t.UTC()
+	if t.Year() < 1980 {
+		return 1<<5 | 1, 0 // 1980-01-01
+	}
+	date = uint16((t.Year()-1980)<<9 | int(t.Month())<<5 | t.Day())
+	tm = uint16(t.Hour()<<11 | t.Minute()<<5 | t.Second()/2)
+	return date, tm
+}
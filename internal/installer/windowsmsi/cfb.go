@@ -0,0 +1,320 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package windowsmsi
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"slices"
+	"unicode"
+	"unicode/utf16"
+)
+
+// A minimal writer of the OLE compound file format (version 3, with
+// 512-byte sectors), which MSI databases are stored in.
+// See https://learn.microsoft.com/en-us/openspecs/windows_protocols/ms-cfb.
+
+const (
+	cfbSectorSize     = 512
+	cfbMiniSectorSize = 64
+	cfbMiniCutoff     = 4096 // streams smaller than this go in the mini stream
+	cfbDirEntrySize   = 128
+
+	cfbFreeSect   = 0xFFFFFFFF
+	cfbEndOfChain = 0xFFFFFFFE
+	cfbFATSect    = 0xFFFFFFFD
+	cfbDIFSect    = 0xFFFFFFFC
+	cfbNoStream   = 0xFFFFFFFF
+
+	cfbHeaderDIFATEntries = 109
+	cfbIDsPerSector       = cfbSectorSize / 4
+)
+
+// A cfbStream is a stream in a compound file.
+type cfbStream struct {
+	name string
+	data []byte
+}
+
+// cfbDirEntry is a directory entry under construction.
+type cfbDirEntry struct {
+	name        []uint16
+	typ         byte // 2 for a stream, 5 for the root storage
+	color       byte // 0 for red, 1 for black
+	left, right uint32
+	child       uint32
+	clsid       [16]byte
+	start       uint32
+	size        uint64
+}
+
+// writeCFB writes a compound file containing streams, all in
+// its root storage, which has the class ID clsid.
+func writeCFB(w io.Writer, clsid [16]byte, streams []cfbStream) error {
+	for _, s := range streams {
+		if n := len(utf16.Encode([]rune(s.name))); n == 0 || n > 31 {
+			return fmt.Errorf("compound file stream name %q has bad length %d", s.name, n)
+		}
+		if uint64(len(s.data)) > 1<<32-1 {
+			return fmt.Errorf("compound file stream %q is too large", s.name)
+		}
+	}
+
+	dir := []*cfbDirEntry{{
+		name:  utf16.Encode([]rune("Root Entry")),
+		typ:   5,
+		color: 1,
+		left:  cfbNoStream, right: cfbNoStream,
+		clsid: clsid,
+		start: cfbEndOfChain,
+	}}
+
+	// Put small streams in the mini stream, which is itself stored
+	// like a regular stream, starting at the root entry's start sector.
+	var mini []byte
+	var miniFAT []uint32
+	for _, s := range streams {
+		e := &cfbDirEntry{
+			name: utf16.Encode([]rune(s.name)),
+			typ:  2,
+			left: cfbNoStream, right: cfbNoStream, child: cfbNoStream,
+			start: cfbEndOfChain,
+			size:  uint64(len(s.data)),
+		}
+		dir = append(dir, e)
+		if len(s.data) == 0 || len(s.data) >= cfbMiniCutoff {
+			continue
+		}
+		e.start = uint32(len(miniFAT))
+		n := sectors(len(s.data), cfbMiniSectorSize)
+		for i := range n {
+			next := uint32(len(miniFAT) + 1)
+			if i == n-1 {
+				next = cfbEndOfChain
+			}
+			miniFAT = append(miniFAT, next)
+		}
+		mini = append(mini, s.data...)
+		mini = append(mini, make([]byte, n*cfbMiniSectorSize-len(s.data))...)
+	}
+	dir[0].size = uint64(len(mini))
+
+	// Lay out the regular sectors: large streams, the mini stream,
+	// the mini FAT, the directory, the FAT, and the DIFAT, in that order.
+	var fat []uint32
+	chain := func(n int) uint32 {
+		if n == 0 {
+			return cfbEndOfChain
+		}
+		start := uint32(len(fat))
+		for i := range n {
+			next := uint32(len(fat) + 1)
+			if i == n-1 {
+				next = cfbEndOfChain
+			}
+			fat = append(fat, next)
+		}
+		return start
+	}
+	var data [][]byte // contents of each chain in fat, in order
+	for i, s := range streams {
+		if len(s.data) >= cfbMiniCutoff {
+			dir[i+1].start = chain(sectors(len(s.data), cfbSectorSize))
+			data = append(data, s.data)
+		}
+	}
+	if len(mini) > 0 {
+		dir[0].start = chain(sectors(len(mini), cfbSectorSize))
+		data = append(data, mini)
+	}
+	miniFATBytes := make([]byte, 4*len(miniFAT))
+	for i, id := range miniFAT {
+		binary.LittleEndian.PutUint32(miniFATBytes[4*i:], id)
+	}
+	miniFATStart := chain(sectors(len(miniFATBytes), cfbSectorSize))
+	data = append(data, miniFATBytes)
+
+	dirSectors := sectors(len(dir)*cfbDirEntrySize, cfbSectorSize)
+	dirStart := chain(dirSectors)
+
+	// The FAT and DIFAT sectors are counted in the FAT too,
+	// so find how many are needed by iteration.
+	nFAT, nDIFAT := 0, 0
+	for {
+		total := len(fat) + nFAT + nDIFAT
+		newFAT := sectors(total, cfbIDsPerSector)
+		newDIFAT := 0
+		if newFAT > cfbHeaderDIFATEntries {
+			newDIFAT = sectors(newFAT-cfbHeaderDIFATEntries, cfbIDsPerSector-1)
+		}
+		if newFAT == nFAT && newDIFAT == nDIFAT {
+			break
+		}
+		nFAT, nDIFAT = newFAT, newDIFAT
+	}
+	fatStart := uint32(len(fat))
+	for range nFAT {
+		fat = append(fat, cfbFATSect)
+	}
+	difatStart := uint32(len(fat))
+	for range nDIFAT {
+		fat = append(fat, cfbDIFSect)
+	}
+	for len(fat) < nFAT*cfbIDsPerSector {
+		fat = append(fat, cfbFreeSect)
+	}
+
+	dir[0].child = cfbTree(dir[1:], 1)
+
+	// Header.
+	bw := bufio.NewWriter(w)
+	le := binary.LittleEndian
+	hdr := make([]byte, cfbSectorSize)
+	copy(hdr, "\xD0\xCF\x11\xE0\xA1\xB1\x1A\xE1")
+	le.PutUint16(hdr[24:], 0x003E) // minor version
+	le.PutUint16(hdr[26:], 0x0003) // major version
+	le.PutUint16(hdr[28:], 0xFFFE) // byte order
+	le.PutUint16(hdr[30:], 9)      // sector shift
+	le.PutUint16(hdr[32:], 6)      // mini sector shift
+	le.PutUint32(hdr[44:], uint32(nFAT))
+	le.PutUint32(hdr[48:], dirStart)
+	le.PutUint32(hdr[56:], cfbMiniCutoff)
+	le.PutUint32(hdr[60:], miniFATStart)
+	le.PutUint32(hdr[64:], uint32(sectors(len(miniFATBytes), cfbSectorSize)))
+	if nDIFAT > 0 {
+		le.PutUint32(hdr[68:], difatStart)
+	} else {
+		le.PutUint32(hdr[68:], cfbEndOfChain)
+	}
+	le.PutUint32(hdr[72:], uint32(nDIFAT))
+	for i := range cfbHeaderDIFATEntries {
+		id := uint32(cfbFreeSect)
+		if i < nFAT {
+			id = fatStart + uint32(i)
+		}
+		le.PutUint32(hdr[76+4*i:], id)
+	}
+	bw.Write(hdr)
+
+	// Streams, mini stream, and mini FAT.
+	for _, d := range data {
+		bw.Write(d)
+		bw.Write(make([]byte, sectors(len(d), cfbSectorSize)*cfbSectorSize-len(d)))
+	}
+
+	// Directory.
+	buf := make([]byte, dirSectors*cfbSectorSize)
+	for i := range dirSectors * cfbSectorSize / cfbDirEntrySize {
+		b := buf[i*cfbDirEntrySize:][:cfbDirEntrySize]
+		if i >= len(dir) {
+			le.PutUint32(b[68:], cfbNoStream)
+			le.PutUint32(b[72:], cfbNoStream)
+			le.PutUint32(b[76:], cfbNoStream)
+			continue
+		}
+		e := dir[i]
+		for j, c := range e.name {
+			le.PutUint16(b[2*j:], c)
+		}
+		le.PutUint16(b[64:], uint16(2*(len(e.name)+1)))
+		b[66] = e.typ
+		b[67] = e.color
+		le.PutUint32(b[68:], e.left)
+		le.PutUint32(b[72:], e.right)
+		le.PutUint32(b[76:], e.child)
+		copy(b[80:96], e.clsid[:])
+		le.PutUint32(b[116:], e.start)
+		le.PutUint64(b[120:], e.size)
+	}
+	bw.Write(buf)
+
+	// FAT.
+	buf = make([]byte, 4*len(fat))
+	for i, id := range fat {
+		le.PutUint32(buf[4*i:], id)
+	}
+	bw.Write(buf)
+
+	// DIFAT, listing the FAT sectors not listed in the header.
+	for i := range nDIFAT {
+		buf := make([]byte, cfbSectorSize)
+		for j := range cfbIDsPerSector - 1 {
+			k := cfbHeaderDIFATEntries + i*(cfbIDsPerSector-1) + j
+			id := uint32(cfbFreeSect)
+			if k < nFAT {
+				id = fatStart + uint32(k)
+			}
+			le.PutUint32(buf[4*j:], id)
+		}
+		next := uint32(cfbEndOfChain)
+		if i < nDIFAT-1 {
+			next = difatStart + uint32(i) + 1
+		}
+		le.PutUint32(buf[cfbSectorSize-4:], next)
+		bw.Write(buf)
+	}
+	return bw.Flush()
+}
+
+// sectors returns the number of sectors of the given size needed for n bytes.
+func sectors(n, size int) int {
+	return (n + size - 1) / size
+}
+
+// cfbTree links the entries, whose IDs start at first, into a
+// red-black tree ordered as compound files require, and returns
+// the ID of its root.
+//
+// The tree is built balanced from the sorted entries, so that all
+// paths to a leaf have the same length, except for those through the
+// bottom level if it isn't full. Coloring that level red, and all
+// other entries black, satisfies the red-black tree invariants.
+func cfbTree(entries []*cfbDirEntry, first uint32) uint32 {
+	ids := make([]uint32, len(entries))
+	for i := range ids {
+		ids[i] = first + uint32(i)
+	}
+	slices.SortFunc(ids, func(a, b uint32) int {
+		return cfbCompare(entries[a-first].name, entries[b-first].name)
+	})
+	full := len(ids)&(len(ids)+1) == 0 // 2^k - 1 entries
+	depth := 0
+	for n := len(ids); n > 0; n >>= 1 {
+		depth++
+	}
+	var build func(ids []uint32, level int) uint32
+	build = func(ids []uint32, level int) uint32 {
+		if len(ids) == 0 {
+			return cfbNoStream
+		}
+		mid := len(ids) / 2
+		e := entries[ids[mid]-first]
+		e.color = 1
+		if !full && level == depth-1 {
+			e.color = 0
+		}
+		e.left = build(ids[:mid], level+1)
+		e.right = build(ids[mid+1:], level+1)
+		return ids[mid]
+	}
+	return build(ids, 0)
+}
+
+// cfbCompare compares directory entry names the way compound files
+// order them: shorter names first, then by their upper-case forms.
+func cfbCompare(a, b []uint16) int {
+	if len(a) != len(b) {
+		return len(a) - len(b)
+	}
+	for i := range a {
+		ca, cb := unicode.ToUpper(rune(a[i])), unicode.ToUpper(rune(b[i]))
+		if ca != cb {
+			return int(ca) - int(cb)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,381 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package windowsmsi
+
+import (
+	"cmp"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// A minimal writer of MSI databases. It isn't documented by Microsoft,
+// but is well understood from Wine's and msitools' implementations:
+// a database is a compound file with a stream for each non-empty table,
+// the _Tables and _Columns tables describing the others, a string pool
+// the tables refer to by index, and a summary information stream.
+
+// Column types, as stored in the _Columns table.
+const (
+	colValid       = 0x0100
+	colLocalizable = 0x0200
+	colString      = 0x0800 | 0x0400 | colValid
+	colNullable    = 0x1000
+	colKey         = 0x2000
+
+	colI2 = 0x0400 | colValid | 2 // 16-bit integer
+	colI4 = colValid | 4          // 32-bit integer
+)
+
+// strCol returns a string column type holding up to n characters,
+// or any number if n is 0.
+func strCol(n int) int { return colString | n }
+
+// locCol returns a localizable string column type, like strCol.
+func locCol(n int) int { return colString | colLocalizable | n }
+
+// A msiColumn is a column of a table.
+type msiColumn struct {
+	name string
+	typ  int // strCol(n), locCol(n), colI2, or colI4, plus colNullable and colKey bits
+}
+
+func (c msiColumn) isString() bool { return c.typ&colString == colString }
+
+// A msiTable is a database table.
+// Primary key columns must come first.
+type msiTable struct {
+	name string
+	cols []msiColumn
+	rows [][]any // each value is a string, an int, or nil for null
+}
+
+// add adds a row to t.
+func (t *msiTable) add(values ...any) {
+	if len(values) != len(t.cols) {
+		panic(fmt.Sprintf("table %s has %d columns, row has %d", t.name, len(t.cols), len(values)))
+	}
+	t.rows = append(t.rows, values)
+}
+
+// A msiDatabase is an MSI database under construction.
+type msiDatabase struct {
+	tables  []*msiTable
+	streams []cfbStream // binary streams such as embedded cabinets
+	summary msiSummary
+}
+
+// table adds a new empty table to db and returns it.
+func (db *msiDatabase) table(name string, cols ...msiColumn) *msiTable {
+	t := &msiTable{name: name, cols: cols}
+	db.tables = append(db.tables, t)
+	return t
+}
+
+// A stringPool assigns indexes to the strings in a database.
+// Index 0 is the null or empty string.
+type stringPool struct {
+	index   map[string]int
+	strings []string // strings[i-1] has index i
+	refs    []int    // reference counts
+}
+
+func (p *stringPool) id(s string) int {
+	if s == "" {
+		return 0
+	}
+	if p.index == nil {
+		p.index = make(map[string]int)
+	}
+	i, ok := p.index[s]
+	if !ok {
+		p.strings = append(p.strings, s)
+		p.refs = append(p.refs, 0)
+		i = len(p.strings)
+		p.index[s] = i
+	}
+	p.refs[i-1]++
+	return i
+}
+
+// streams encodes the string pool as the _StringPool and _StringData streams.
+func (p *stringPool) streams() (pool, data []byte, err error) {
+	le := binary.LittleEndian
+	header := uint32(1252) // Windows-1252 code page
+	if len(p.strings) > 0xFFFF {
+		header |= 0x80000000 // string indexes take 3 bytes
+	}
+	pool = le.AppendUint32(pool, header)
+	for i, s := range p.strings {
+		for _, r := range s {
+			if r >= 0x80 {
+				return nil, nil, fmt.Errorf("string %q is not ASCII", s)
+			}
+		}
+		refs := min(p.refs[i], 0xFFFF)
+		if len(s) > 0xFFFF {
+			pool = le.AppendUint16(pool, 0)
+			pool = le.AppendUint16(pool, uint16(refs))
+			pool = le.AppendUint16(pool, uint16(len(s)))
+			pool = le.AppendUint16(pool, uint16(len(s)>>16))
+		} else {
+			pool = le.AppendUint16(pool, uint16(len(s)))
+			pool = le.AppendUint16(pool, uint16(refs))
+		}
+		data = append(data, s...)
+	}
+	return pool, data, nil
+}
+
+// write writes db as an MSI file with the given class ID to w.
+func (db *msiDatabase) write(w io.Writer, clsid [16]byte) error {
+	tables := slices.Clone(db.tables)
+	slices.SortFunc(tables, func(a, b *msiTable) int { return strings.Compare(a.name, b.name) })
+
+	// Assign string indexes and convert each table to integer rows,
+	// which are then sorted by their primary key.
+	var pool stringPool
+	type encoded struct {
+		t    *msiTable
+		rows [][]uint32
+	}
+	var enc []encoded
+	for _, t := range tables {
+		pool.id(t.name)
+		for _, c := range t.cols {
+			pool.id(t.name)
+			pool.id(c.name)
+		}
+		var rows [][]uint32
+		for _, row := range t.rows {
+			r := make([]uint32, len(row))
+			for i, v := range row {
+				c := t.cols[i]
+				if v == nil || v == "" {
+					if c.typ&colNullable == 0 {
+						return fmt.Errorf("table %s: null value in column %s", t.name, c.name)
+					}
+					continue
+				}
+				switch v := v.(type) {
+				case string:
+					if !c.isString() {
+						return fmt.Errorf("table %s: string value %q in integer column %s", t.name, v, c.name)
+					}
+					if n := c.typ & 0xFF; n != 0 && len(v) > n {
+						return fmt.Errorf("table %s: value %q too long for column %s", t.name, v, c.name)
+					}
+					r[i] = uint32(pool.id(v))
+				case int:
+					switch {
+					case c.isString():
+						return fmt.Errorf("table %s: integer value %d in string column %s", t.name, v, c.name)
+					case c.typ&0xFF == 2:
+						if v < -0x7FFF || v > 0x7FFF {
+							return fmt.Errorf("table %s: value %d out of range for column %s", t.name, v, c.name)
+						}
+						r[i] = uint32(v + 0x8000)
+					default:
+						r[i] = uint32(int32(v)) ^ 0x80000000
+					}
+				default:
+					return fmt.Errorf("table %s: bad value type %T", t.name, v)
+				}
+			}
+			rows = append(rows, r)
+		}
+		nkeys := 0
+		for _, c := range t.cols {
+			if c.typ&colKey != 0 {
+				nkeys++
+			}
+		}
+		slices.SortFunc(rows, func(a, b []uint32) int { return slices.Compare(a[:nkeys], b[:nkeys]) })
+		for i := 1; i < len(rows); i++ {
+			if slices.Equal(rows[i-1][:nkeys], rows[i][:nkeys]) {
+				return fmt.Errorf("table %s: duplicate primary key", t.name)
+			}
+		}
+		enc = append(enc, encoded{t, rows})
+	}
+	strRef := 2
+	if len(pool.strings) > 0xFFFF {
+		strRef = 3
+	}
+
+	// encode stores rows column by column, as all tables are.
+	encode := func(cols []msiColumn, rows [][]uint32) []byte {
+		var b []byte
+		for i, c := range cols {
+			size := strRef
+			if !c.isString() {
+				size = c.typ & 0xFF
+			}
+			for _, r := range rows {
+				v := r[i]
+				for j := range size {
+					b = append(b, byte(v>>(8*j)))
+				}
+			}
+		}
+		return b
+	}
+
+	var streams []cfbStream
+	var tablesRows, columnsRows [][]uint32
+	for _, e := range enc {
+		tablesRows = append(tablesRows, []uint32{uint32(pool.index[e.t.name])})
+		for i, c := range e.t.cols {
+			columnsRows = append(columnsRows, []uint32{
+				uint32(pool.index[e.t.name]),
+				uint32(i + 1 + 0x8000),
+				uint32(pool.index[c.name]),
+				uint32(c.typ + 0x8000),
+			})
+		}
+		if len(e.rows) > 0 {
+			streams = append(streams, cfbStream{msiStreamName(e.t.name, true), encode(e.t.cols, e.rows)})
+		}
+	}
+	slices.SortFunc(tablesRows, slices.Compare)
+	slices.SortFunc(columnsRows, func(a, b []uint32) int { return slices.Compare(a[:2], b[:2]) })
+	streams = append(streams,
+		cfbStream{msiStreamName("_Tables", true), encode([]msiColumn{{"Name", strCol(64)}}, tablesRows)},
+		cfbStream{msiStreamName("_Columns", true), encode([]msiColumn{
+			{"Table", strCol(64)}, {"Number", colI2}, {"Name", strCol(64)}, {"Type", colI2},
+		}, columnsRows)},
+	)
+	poolData, strData, err := pool.streams()
+	if err != nil {
+		return err
+	}
+	streams = append(streams,
+		cfbStream{msiStreamName("_StringPool", true), poolData},
+		cfbStream{msiStreamName("_StringData", true), strData},
+	)
+	for _, st := range db.streams {
+		streams = append(streams, cfbStream{msiStreamName(st.name, false), st.data})
+	}
+	streams = append(streams, cfbStream{"\x05SummaryInformation", db.summary.encode()})
+	return writeCFB(w, clsid, streams)
+}
+
+// msiStreamName returns the compound file stream name for an MSI table
+// or stream name, which is compressed by packing pairs of characters
+// from a 64-character alphabet into one UTF-16 code unit each.
+func msiStreamName(name string, table bool) string {
+	const alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz._"
+	var out []rune
+	if table {
+		out = append(out, 0x4840)
+	}
+	for i := 0; i < len(name); i++ {
+		a := strings.IndexByte(alphabet, name[i])
+		if a < 0 {
+			out = append(out, rune(name[i]))
+			continue
+		}
+		if i+1 < len(name) {
+			if b := strings.IndexByte(alphabet, name[i+1]); b >= 0 {
+				out = append(out, rune(0x3800+a+b<<6))
+				i++
+				continue
+			}
+		}
+		out = append(out, rune(0x4800+a))
+	}
+	return string(out)
+}
+
+// msiSummary holds the properties of the summary information stream.
+// See https://learn.microsoft.com/en-us/windows/win32/msi/summary-information-stream-property-set.
+type msiSummary struct {
+	title, subject, author, keywords, comments string
+	template                                   string // platform and languages, such as "x64;1033"
+	revision                                   string // package code
+	created                                    time.Time
+	pageCount                                  int // minimum installer version
+	wordCount                                  int // source image flags
+	appName                                    string
+	security                                   int
+}
+
+// encode encodes the summary information as an OLE property set.
+// See https://learn.microsoft.com/en-us/openspecs/windows_protocols/ms-oleps.
+func (si msiSummary) encode() []byte {
+	const (
+		vtI2       = 2
+		vtI4       = 3
+		vtLPSTR    = 30
+		vtFILETIME = 64
+	)
+	le := binary.LittleEndian
+	type prop struct {
+		id    uint32
+		value []byte
+	}
+	var props []prop
+	add := func(id, typ uint32, value []byte) {
+		b := append(le.AppendUint32(nil, typ), value...)
+		for len(b)%4 != 0 {
+			b = append(b, 0)
+		}
+		props = append(props, prop{id, b})
+	}
+	addString := func(id uint32, s string) {
+		if s == "" {
+			return
+		}
+		b := le.AppendUint32(nil, uint32(len(s)+1))
+		b = append(b, s...)
+		add(id, vtLPSTR, append(b, 0))
+	}
+	add(1, vtI2, le.AppendUint16(nil, 1252)) // code page
+	addString(2, si.title)
+	addString(3, si.subject)
+	addString(4, si.author)
+	addString(5, si.keywords)
+	addString(6, si.comments)
+	addString(7, si.template)
+	addString(9, si.revision)
+	// FILETIME counts 100ns intervals since 1601.
+	ft := uint64(si.created.Sub(time.Date(1601, 1, 1, 0, 0, 0, 0, time.UTC)) / 100)
+	add(12, vtFILETIME, le.AppendUint32(le.AppendUint32(nil, uint32(ft)), uint32(ft>>32)))
+	add(13, vtFILETIME, le.AppendUint32(le.AppendUint32(nil, uint32(ft)), uint32(ft>>32)))
+	add(14, vtI4, le.AppendUint32(nil, uint32(si.pageCount)))
+	add(15, vtI4, le.AppendUint32(nil, uint32(si.wordCount)))
+	addString(18, si.appName)
+	add(19, vtI4, le.AppendUint32(nil, uint32(si.security)))
+	slices.SortFunc(props, func(a, b prop) int { return cmp.Compare(a.id, b.id) })
+
+	// The section: size, count, (id, offset) pairs, then the values.
+	offset := 8 + 8*len(props)
+	var index, values []byte
+	for _, p := range props {
+		index = le.AppendUint32(index, p.id)
+		index = le.AppendUint32(index, uint32(offset+len(values)))
+		values = append(values, p.value...)
+	}
+	var section []byte
+	section = le.AppendUint32(section, uint32(offset+len(values)))
+	section = le.AppendUint32(section, uint32(len(props)))
+	section = append(section, index...)
+	section = append(section, values...)
+
+	// The property set header, with the one section.
+	fmtid := guidBytes("{F29F85E0-4FF9-1068-AB91-08002B27B3D9}") // summary information
+	var b []byte
+	b = le.AppendUint16(b, 0xFFFE)     // byte order
+	b = le.AppendUint16(b, 0)          // version
+	b = le.AppendUint32(b, 0x00020006) // OS version (Windows NT 6.2)
+	b = append(b, make([]byte, 16)...) // class ID
+	b = le.AppendUint32(b, 1)          // sections
+	b = append(b, fmtid[:]...)
+	b = le.AppendUint32(b, uint32(len(b)+4))
+	return append(b, section...)
+}
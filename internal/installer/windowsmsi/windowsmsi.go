@@ -100,20 +100,9 @@ func ConstructInstaller(_ context.Context, workDir, tgzPath string, opt Installe
 	if err != nil {
 		return "", fmt.Errorf("failed to split version %q: %v", version, err)
 	}
-	var msArch string
-	switch opt.GOARCH {
-	case "386":
-		msArch = "x86"
-	case "amd64":
-		msArch = "x64"
-	case "arm":
-		// Historically the installer for the windows/arm port
-		// used the same value as for the windows/arm64 port.
-		fallthrough
-	case "arm64":
-		msArch = "arm64"
-	default:
-		panic("unknown arch for windows " + opt.GOARCH)
+	msArch, err := msiArch(opt.GOARCH)
+	if err != nil {
+		return "", err
 	}
 	if err := run(filepath.Join(wixDir, "candle"),
 		"-nologo",
@@ -0,0 +1,570 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package windowsmsi
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+// WriteInstaller writes an MSI installer for the Go toolchain .tar.gz
+// binary archive read from tgz to w.
+//
+// Unlike ConstructInstaller, it doesn't need the WiX tools, so it can
+// run on any system, and the installer depends only on its inputs.
+// The installer installs the same files, registry values, environment
+// variables, and uninstall shortcut, and upgrades previous installations
+// in the same way, but has no setup dialogs, license page, or product
+// icon, and doesn't notify running programs of the PATH change
+// (go.dev/issue/18680), as those need WiX's UI and custom action DLLs.
+// The _Validation table is omitted too.
+func WriteInstaller(w io.Writer, tgz io.Reader, opt InstallerOptions) error {
+	if opt.GOARCH == "" {
+		return fmt.Errorf("GOARCH is empty")
+	}
+	arch, err := msiArch(opt.GOARCH)
+	if err != nil {
+		return err
+	}
+	h := sha256.New()
+	files, err := readTgz(io.TeeReader(tgz, h))
+	if err != nil {
+		return err
+	}
+	tgzHash := hex.EncodeToString(h.Sum(nil))
+	var version string
+	var mtime time.Time
+	for _, f := range files {
+		if f.name == "VERSION" {
+			version, _, _ = strings.Cut(string(f.data), "\n")
+			mtime = f.mtime
+		}
+	}
+	if version == "" {
+		return fmt.Errorf("no VERSION file in Go toolchain archive")
+	}
+	verMajor, verMinor, err := splitVersion(version)
+	if err != nil {
+		return fmt.Errorf("failed to split version %q: %v", version, err)
+	}
+
+	// Settings from installer.wxs.
+	upgradeCode, installerVersion := "{22EA7650-4AC6-4001-BF29-F4B8775DB1C0}", 300
+	sysFolder, programFilesFolder := "System64Folder", "ProgramFiles64Folder"
+	switch opt.GOARCH {
+	case "386":
+		upgradeCode = "{1C3114EA-08C3-11E1-9095-7FCA4824019B}"
+		sysFolder, programFilesFolder = "SystemFolder", "ProgramFilesFolder"
+	case "arm64":
+		upgradeCode, installerVersion = "{21ADE9A3-3FDD-4BA6-BEA6-C85ABADC9488}", 500
+	}
+	componentAttrs := 0
+	platform := "Intel"
+	switch arch {
+	case "x64":
+		componentAttrs, platform = 256, "x64" // 64-bit components
+	case "arm64":
+		componentAttrs, platform = 256, "Arm64"
+	}
+	productName := fmt.Sprintf("Go Programming Language %s %s", opt.GOARCH, version)
+	productCode := guid("ProductCode", tgzHash, opt.GOARCH)
+	packageCode := guid("PackageCode", tgzHash, opt.GOARCH)
+
+	var db msiDatabase
+	db.summary = msiSummary{
+		title:     "Installation Database",
+		subject:   productName,
+		author:    "https://go.dev",
+		keywords:  "Installer",
+		comments:  "The Go programming language is an open source project to make programmers more productive.",
+		template:  platform + ";1033",
+		revision:  packageCode,
+		created:   mtime,
+		pageCount: installerVersion,
+		wordCount: 2, // compressed files with long file names
+		appName:   "golang.org/x/build/internal/installer/windowsmsi",
+		security:  2, // read-only recommended
+	}
+
+	property := db.table("Property",
+		msiColumn{"Property", strCol(72) | colKey},
+		msiColumn{"Value", locCol(0)},
+	)
+	for _, p := range [][2]string{
+		{"ALLUSERS", "1"},
+		{"ARPCOMMENTS", "The Go programming language is a fast, statically typed, compiled language that feels like a dynamically typed, interpreted language."},
+		{"ARPCONTACT", "golang-nuts@googlegroups.com"},
+		{"ARPHELPLINK", "https://go.dev/help"},
+		{"ARPNOMODIFY", "1"},
+		{"ARPREADME", "https://go.dev"},
+		{"ARPURLINFOABOUT", "https://go.dev"},
+		{"Manufacturer", "https://go.dev"},
+		{"ProductCode", productCode},
+		{"ProductLanguage", "1033"},
+		{"ProductName", productName},
+		{"ProductVersion", fmt.Sprintf("1.%d.%d", verMajor, verMinor)},
+		{"SecureCustomProperties", "WIX_UPGRADE_DETECTED"},
+		{"UpgradeCode", upgradeCode},
+	} {
+		property.add(p[0], p[1])
+	}
+
+	directory := db.table("Directory",
+		msiColumn{"Directory", strCol(72) | colKey},
+		msiColumn{"Directory_Parent", strCol(72) | colNullable},
+		msiColumn{"DefaultDir", locCol(255)},
+	)
+	directory.add("TARGETDIR", nil, "SourceDir")
+	directory.add(programFilesFolder, "TARGETDIR", "PFiles")
+	directory.add("INSTALLDIR", programFilesFolder, "Go")
+	directory.add("ProgramMenuFolder", "TARGETDIR", "PMenu")
+	directory.add("GoProgramShortcutsDir", "ProgramMenuFolder", "GOPROG~1|Go Programming Language")
+	directory.add("EnvironmentEntries", "TARGETDIR", "ENVIRO~1|EnvironmentEntries")
+	directory.add("GoEnvironmentEntries", "EnvironmentEntries", "GOPROG~1|Go Programming Language")
+
+	component := db.table("Component",
+		msiColumn{"Component", strCol(72) | colKey},
+		msiColumn{"ComponentId", strCol(38) | colNullable},
+		msiColumn{"Directory_", strCol(72)},
+		msiColumn{"Attributes", colI2},
+		msiColumn{"Condition", strCol(255) | colNullable},
+		msiColumn{"KeyPath", strCol(72) | colNullable},
+	)
+	const registryKeyPath = 4
+	component.add("Component_GoProgramShortCuts", "{F5FBFB5E-6C5C-423B-9298-21B0E3C98F4B}", "GoProgramShortcutsDir", componentAttrs|registryKeyPath, nil, "RegistryShortCuts")
+	component.add("Component_GoEnvironment", "{3EC7A4D5-EB08-4DE7-9312-2DF392C45993}", "GoEnvironmentEntries", componentAttrs|registryKeyPath, nil, "RegistryInstalled")
+
+	file := db.table("File",
+		msiColumn{"File", strCol(72) | colKey},
+		msiColumn{"Component_", strCol(72)},
+		msiColumn{"FileName", locCol(255)},
+		msiColumn{"FileSize", colI4},
+		msiColumn{"Version", strCol(72) | colNullable},
+		msiColumn{"Language", strCol(20) | colNullable},
+		msiColumn{"Attributes", colI2 | colNullable},
+		msiColumn{"Sequence", colI4},
+	)
+	feature := db.table("Feature",
+		msiColumn{"Feature", strCol(38) | colKey},
+		msiColumn{"Feature_Parent", strCol(38) | colNullable},
+		msiColumn{"Title", locCol(64) | colNullable},
+		msiColumn{"Description", locCol(255) | colNullable},
+		msiColumn{"Display", colI2 | colNullable},
+		msiColumn{"Level", colI2},
+		msiColumn{"Directory_", strCol(72) | colNullable},
+		msiColumn{"Attributes", colI2},
+	)
+	feature.add("GoTools", nil, "Go", nil, 2, 1, nil, 0)
+	featureComponents := db.table("FeatureComponents",
+		msiColumn{"Feature_", strCol(38) | colKey},
+		msiColumn{"Component_", strCol(72) | colKey},
+	)
+	featureComponents.add("GoTools", "Component_GoProgramShortCuts")
+	featureComponents.add("GoTools", "Component_GoEnvironment")
+
+	// The Go tree: a directory, component, and file per file,
+	// with the files stored in order in an embedded cabinet.
+	dirIDs := map[string]string{".": "INSTALLDIR"}
+	shortNames := make(map[string]*shortNamer)
+	namer := func(dir string) *shortNamer {
+		if shortNames[dir] == nil {
+			shortNames[dir] = new(shortNamer)
+		}
+		return shortNames[dir]
+	}
+	for _, f := range files {
+		for p := f.name; p != "."; p = path.Dir(p) {
+			namer(path.Dir(p)).reserve(path.Base(p))
+		}
+	}
+	var addDir func(dir string) string
+	addDir = func(dir string) string {
+		if id, ok := dirIDs[dir]; ok {
+			return id
+		}
+		parent := addDir(path.Dir(dir))
+		id := ident("dir", dir)
+		dirIDs[dir] = id
+		directory.add(id, parent, namer(path.Dir(dir)).name(path.Base(dir)))
+		return id
+	}
+	var cab []cabFile
+	for i, f := range files {
+		dir := addDir(path.Dir(f.name))
+		fileID, componentID := ident("fil", f.name), ident("cmp", f.name)
+		component.add(componentID, guid("Component", arch, f.name), dir, componentAttrs, nil, fileID)
+		featureComponents.add("GoTools", componentID)
+		if int64(len(f.data)) > 0x7FFFFFFF {
+			return fmt.Errorf("file %s is too large", f.name)
+		}
+		const vital = 512
+		file.add(fileID, componentID, namer(path.Dir(f.name)).name(path.Base(f.name)), len(f.data), nil, nil, vital, i+1)
+		cab = append(cab, cabFile{name: fileID, data: f.data, mtime: f.mtime})
+	}
+	var cabData bytes.Buffer
+	if err := writeCab(&cabData, cab); err != nil {
+		return err
+	}
+	db.streams = append(db.streams, cfbStream{"go.cab", cabData.Bytes()})
+	db.table("Media",
+		msiColumn{"DiskId", colI2 | colKey},
+		msiColumn{"LastSequence", colI4},
+		msiColumn{"DiskPrompt", locCol(64) | colNullable},
+		msiColumn{"Cabinet", strCol(255) | colNullable},
+		msiColumn{"VolumeLabel", strCol(32) | colNullable},
+		msiColumn{"Source", strCol(72) | colNullable},
+	).add(1, len(files), nil, "#go.cab", nil, nil)
+
+	// Registry values, environment variables, and the uninstall shortcut.
+	registry := db.table("Registry",
+		msiColumn{"Registry", strCol(72) | colKey},
+		msiColumn{"Root", colI2},
+		msiColumn{"Key", locCol(255)},
+		msiColumn{"Name", locCol(255) | colNullable},
+		msiColumn{"Value", locCol(0) | colNullable},
+		msiColumn{"Component_", strCol(72)},
+	)
+	const hkcu = 1
+	registry.add("RegistryShortCuts", hkcu, `Software\GoProgrammingLanguage`, "ShortCuts", "#1", "Component_GoProgramShortCuts")
+	registry.add("RegistryInstalled", hkcu, `Software\GoProgrammingLanguage`, "installed", "#1", "Component_GoEnvironment")
+	registry.add("RegistryInstallLocation", hkcu, `Software\GoProgrammingLanguage`, "installLocation", "[INSTALLDIR]", "Component_GoEnvironment")
+
+	// Environment names are prefixed with "=" to set the variable,
+	// "+" to create it if it doesn't exist, "-" to remove it on
+	// uninstall, and "*" for a system variable. "[~];" appends the value.
+	environment := db.table("Environment",
+		msiColumn{"Environment", strCol(72) | colKey},
+		msiColumn{"Name", locCol(255)},
+		msiColumn{"Value", locCol(255) | colNullable},
+		msiColumn{"Component_", strCol(72)},
+	)
+	environment.add("GoPathEntry", "=-*PATH", "[~];[INSTALLDIR]bin", "Component_GoEnvironment")
+	environment.add("UserGoPath", "+-GOPATH", `%USERPROFILE%\go`, "Component_GoEnvironment")
+	environment.add("UserGoPathEntry", "=-PATH", `[~];%USERPROFILE%\go\bin`, "Component_GoEnvironment")
+
+	db.table("Shortcut",
+		msiColumn{"Shortcut", strCol(72) | colKey},
+		msiColumn{"Directory_", strCol(72)},
+		msiColumn{"Name", locCol(128)},
+		msiColumn{"Component_", strCol(72)},
+		msiColumn{"Target", strCol(72)},
+		msiColumn{"Arguments", strCol(255) | colNullable},
+		msiColumn{"Description", locCol(255) | colNullable},
+		msiColumn{"Hotkey", colI2 | colNullable},
+		msiColumn{"Icon_", strCol(72) | colNullable},
+		msiColumn{"IconIndex", colI2 | colNullable},
+		msiColumn{"ShowCmd", colI2 | colNullable},
+		msiColumn{"WkDir", strCol(72) | colNullable},
+	).add("UninstallShortcut", "GoProgramShortcutsDir", "UNINST~1|Uninstall Go", "Component_GoProgramShortCuts",
+		"["+sysFolder+"]msiexec.exe", "/x [ProductCode]", "Uninstalls Go and all of its components",
+		nil, nil, nil, nil, nil)
+
+	removeFile := db.table("RemoveFile",
+		msiColumn{"FileKey", strCol(72) | colKey},
+		msiColumn{"Component_", strCol(72)},
+		msiColumn{"FileName", locCol(255) | colNullable},
+		msiColumn{"DirProperty", strCol(72)},
+		msiColumn{"InstallMode", colI2},
+	)
+	const onUninstall = 2
+	removeFile.add("GoProgramShortcutsDir", "Component_GoProgramShortCuts", nil, "GoProgramShortcutsDir", onUninstall)
+	removeFile.add("GoEnvironmentEntries", "Component_GoEnvironment", nil, "GoEnvironmentEntries", onUninstall)
+
+	// Upgrades, launch conditions, and custom actions.
+	const (
+		migrateFeatures     = 1
+		versionMinInclusive = 256
+	)
+	db.table("Upgrade",
+		msiColumn{"UpgradeCode", strCol(38) | colKey},
+		msiColumn{"VersionMin", strCol(20) | colKey | colNullable},
+		msiColumn{"VersionMax", strCol(20) | colKey | colNullable},
+		msiColumn{"Language", strCol(255) | colKey | colNullable},
+		msiColumn{"Attributes", colI4 | colKey},
+		msiColumn{"Remove", strCol(255) | colNullable},
+		msiColumn{"ActionProperty", strCol(72)},
+	).add(upgradeCode, "0", nil, nil, migrateFeatures|versionMinInclusive, nil, "WIX_UPGRADE_DETECTED")
+
+	launchCondition := db.table("LaunchCondition",
+		msiColumn{"Condition", strCol(255) | colKey},
+		msiColumn{"Description", locCol(255)},
+	)
+	if verMajor < 21 {
+		launchCondition.add("((VersionNT > 601) OR (VersionNT = 601 AND ServicePackLevel >= 1))", "Windows 7 (with Service Pack 1) or greater required.")
+	} else {
+		// Windows 10 reports itself as Windows 8.1; see installer.wxs.
+		launchCondition.add("(VersionNT >= 603)", "Windows 10 or greater required.")
+	}
+
+	const setProperty = 51
+	db.table("CustomAction",
+		msiColumn{"Action", strCol(72) | colKey},
+		msiColumn{"Type", colI2},
+		msiColumn{"Source", strCol(72) | colNullable},
+		msiColumn{"Target", strCol(255) | colNullable},
+	).add("SetApplicationRootDirectory", setProperty, "ARPINSTALLLOCATION", "[INSTALLDIR]")
+
+	// The sequence tables, with the standard actions that WiX
+	// schedules for this installer, and their standard sequence numbers.
+	for _, seq := range []struct {
+		table   string
+		actions []string
+	}{
+		{"InstallExecuteSequence", []string{
+			"FindRelatedProducts", "LaunchConditions", "ValidateProductID",
+			"CostInitialize", "FileCost", "CostFinalize", "MigrateFeatureStates",
+			"InstallValidate", "RemoveExistingProducts", "InstallInitialize", "ProcessComponents",
+			"UnpublishFeatures", "RemoveRegistryValues", "RemoveShortcuts", "RemoveEnvironmentStrings",
+			"RemoveFiles", "RemoveFolders", "CreateFolders", "InstallFiles", "CreateShortcuts",
+			"WriteRegistryValues", "WriteEnvironmentStrings", "RegisterUser", "RegisterProduct",
+			"PublishFeatures", "PublishProduct", "SetApplicationRootDirectory", "InstallFinalize",
+		}},
+		{"InstallUISequence", []string{
+			"FindRelatedProducts", "LaunchConditions", "ValidateProductID",
+			"CostInitialize", "FileCost", "CostFinalize", "MigrateFeatureStates", "ExecuteAction",
+		}},
+		{"AdminExecuteSequence", []string{
+			"CostInitialize", "FileCost", "CostFinalize", "InstallValidate",
+			"InstallInitialize", "InstallAdminPackage", "InstallFiles", "InstallFinalize",
+		}},
+		{"AdminUISequence", []string{
+			"CostInitialize", "FileCost", "CostFinalize", "ExecuteAction",
+		}},
+		{"AdvtExecuteSequence", []string{
+			"CostInitialize", "CostFinalize", "InstallValidate", "InstallInitialize",
+			"CreateShortcuts", "PublishFeatures", "PublishProduct", "InstallFinalize",
+		}},
+	} {
+		t := db.table(seq.table,
+			msiColumn{"Action", strCol(72) | colKey},
+			msiColumn{"Condition", strCol(255) | colNullable},
+			msiColumn{"Sequence", colI2 | colNullable},
+		)
+		for _, a := range seq.actions {
+			n, ok := actionSequence[a]
+			if !ok {
+				panic("no sequence number for " + a)
+			}
+			t.add(a, nil, n)
+		}
+	}
+
+	return db.write(w, guidBytes("{000C1084-0000-0000-C000-000000000046}"))
+}
+
+// actionSequence holds the sequence numbers of the actions the installer uses.
+var actionSequence = map[string]int{
+	"FindRelatedProducts":         25,
+	"LaunchConditions":            100,
+	"ValidateProductID":           700,
+	"CostInitialize":              800,
+	"FileCost":                    900,
+	"CostFinalize":                1000,
+	"MigrateFeatureStates":        1200,
+	"ExecuteAction":               1300,
+	"InstallValidate":             1400,
+	"RemoveExistingProducts":      1401, // WiX's MajorUpgrade default, afterInstallValidate
+	"InstallInitialize":           1500,
+	"ProcessComponents":           1600,
+	"UnpublishFeatures":           1800,
+	"RemoveRegistryValues":        2600,
+	"RemoveShortcuts":             3200,
+	"RemoveEnvironmentStrings":    3300,
+	"RemoveFiles":                 3500,
+	"RemoveFolders":               3600,
+	"CreateFolders":               3700,
+	"InstallAdminPackage":         3900,
+	"InstallFiles":                4000,
+	"CreateShortcuts":             4500,
+	"WriteRegistryValues":         5000,
+	"WriteEnvironmentStrings":     5200,
+	"RegisterUser":                6000,
+	"RegisterProduct":             6100,
+	"PublishFeatures":             6300,
+	"PublishProduct":              6400,
+	"SetApplicationRootDirectory": 6599, // before InstallFinalize
+	"InstallFinalize":             6600,
+}
+
+// msiArch returns the Windows Installer architecture name for goarch.
+func msiArch(goarch string) (string, error) {
+	switch goarch {
+	case "386":
+		return "x86", nil
+	case "amd64":
+		return "x64", nil
+	case "arm":
+		// Historically the installer for the windows/arm port
+		// used the same value as for the windows/arm64 port.
+		fallthrough
+	case "arm64":
+		return "arm64", nil
+	}
+	return "", fmt.Errorf("unknown arch for windows %q", goarch)
+}
+
+// A tgzFile is a regular file in a Go toolchain archive.
+type tgzFile struct {
+	name  string // slash-separated path relative to GOROOT
+	data  []byte
+	mtime time.Time
+}
+
+// readTgz reads the files in a Go toolchain .tar.gz binary archive,
+// and returns them sorted by name.
+func readTgz(r io.Reader) ([]tgzFile, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	var files []tgzFile
+	tr := tar.NewReader(zr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			continue
+		case tar.TypeReg:
+		default:
+			return nil, fmt.Errorf("unsupported file type %q for %s in Go toolchain archive", hdr.Typeflag, hdr.Name)
+		}
+		name, ok := strings.CutPrefix(hdr.Name, "go/")
+		if !ok || !fs.ValidPath(name) {
+			return nil, fmt.Errorf("unexpected path %q in Go toolchain archive", hdr.Name)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, tgzFile{name, data, hdr.ModTime})
+	}
+	// Read to the end, so that the whole input is hashed.
+	if _, err := io.Copy(io.Discard, zr); err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, errors.New("no files in Go toolchain archive")
+	}
+	slices.SortFunc(files, func(a, b tgzFile) int { return strings.Compare(a.name, b.name) })
+	return files, nil
+}
+
+// ident returns an MSI identifier for the file or directory at path.
+func ident(prefix, path string) string {
+	sum := sha256.Sum256([]byte(path))
+	return prefix + strings.ToUpper(hex.EncodeToString(sum[:16]))
+}
+
+// guid returns a name-based GUID (a version 5 UUID) for parts,
+// in the registry format the installer uses.
+func guid(parts ...string) string {
+	h := sha1.New()
+	io.WriteString(h, "golang.org/x/build/internal/installer/windowsmsi")
+	for _, p := range parts {
+		io.WriteString(h, "\x00"+p)
+	}
+	u := h.Sum(nil)[:16]
+	u[6] = u[6]&0x0F | 0x50
+	u[8] = u[8]&0x3F | 0x80
+	return strings.ToUpper(fmt.Sprintf("{%x-%x-%x-%x-%x}", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16]))
+}
+
+// guidBytes returns the binary form of a GUID in registry format,
+// with the first three groups little-endian.
+func guidBytes(g string) [16]byte {
+	b, err := hex.DecodeString(strings.NewReplacer("{", "", "}", "", "-", "").Replace(g))
+	if err != nil || len(b) != 16 {
+		panic("bad GUID " + g)
+	}
+	slices.Reverse(b[0:4])
+	slices.Reverse(b[4:6])
+	slices.Reverse(b[6:8])
+	return [16]byte(b)
+}
+
+// A shortNamer assigns unique 8.3 short names to the long names of
+// the files and subdirectories of one directory, which the installer
+// needs for file systems without long name support.
+type shortNamer struct {
+	used map[string]bool
+}
+
+// reserve records that name is in the directory, so that no
+// other file gets it as a short name if it is a valid one.
+func (s *shortNamer) reserve(name string) {
+	if s.used == nil {
+		s.used = make(map[string]bool)
+	}
+	if isShortName(name) {
+		s.used[strings.ToUpper(name)] = true
+	}
+}
+
+// name returns the MSI file name for long: long itself if it's a
+// valid short name, or otherwise "SHORT~N.EXT|long".
+func (s *shortNamer) name(long string) string {
+	if isShortName(long) {
+		return long
+	}
+	clean := func(s string, n int) string {
+		var b strings.Builder
+		for _, r := range strings.ToUpper(s) {
+			if b.Len() < n && (r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+				b.WriteRune(r)
+			}
+		}
+		return b.String()
+	}
+	base, ext := long, ""
+	if i := strings.LastIndexByte(long, '.'); i > 0 {
+		base, ext = long[:i], clean(long[i+1:], 3)
+	}
+	base = clean(base, 6)
+	if base == "" {
+		base = "_"
+	}
+	for n := 1; ; n++ {
+		suffix := fmt.Sprintf("~%d", n)
+		short := base[:min(len(base), 8-len(suffix))] + suffix
+		if ext != "" {
+			short += "." + ext
+		}
+		if !s.used[short] {
+			s.used[short] = true
+			return short + "|" + long
+		}
+	}
+}
+
+// isShortName reports whether name is a valid 8.3 file name.
+func isShortName(name string) bool {
+	base, ext, _ := strings.Cut(name, ".")
+	if base == "" || len(base) > 8 || len(ext) > 3 || strings.Contains(ext, ".") || strings.HasSuffix(name, ".") {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || strings.ContainsRune("._-!#$%&'()@^`{}~", r)) {
+			return false
+		}
+	}
+	return true
+}
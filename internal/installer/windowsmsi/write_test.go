@@ -0,0 +1,482 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package windowsmsi
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"maps"
+	"math/rand/v2"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"golang.org/x/build/internal/installer/installertest"
+)
+
+func testFiles() map[string][]byte {
+	r := rand.New(rand.NewPCG(1, 2))
+	random := make([]byte, 100000)
+	for i := range random {
+		random[i] = byte(r.Uint32())
+	}
+	files := map[string][]byte{
+		"LICENSE":                    []byte("Copyright (c) 2009 The Go Authors.\n"),
+		"bin/go.exe":                 random,
+		"bin/gofmt.exe":              bytes.Repeat([]byte("gofmt "), 3000),
+		"src/cmd/go/main.go":         []byte("package main\n"),
+		"src/cmd/go/testdata/empty":  nil,
+		"misc/wasm/wasm_exec.js":     []byte("// wasm\n"),
+		"lib/time/zoneinfo.zip":      []byte("zip"),
+		"pkg/tool/windows_amd64/vet": []byte("vet"),
+	}
+	for i := range 40 {
+		files[fmt.Sprintf("src/pkg%d/long_file_name_%d.go", i%3, i)] = fmt.Appendf(nil, "package pkg // %d\n", i)
+	}
+	return files
+}
+
+func TestWriteInstaller(t *testing.T) {
+	files := testFiles()
+	tgz := installertest.Tgz(t, "go1.27.1", files)
+	for _, goarch := range []string{"386", "amd64", "arm64"} {
+		t.Run(goarch, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteInstaller(&buf, bytes.NewReader(tgz), InstallerOptions{GOARCH: goarch}); err != nil {
+				t.Fatal(err)
+			}
+			var again bytes.Buffer
+			if err := WriteInstaller(&again, bytes.NewReader(tgz), InstallerOptions{GOARCH: goarch}); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(buf.Bytes(), again.Bytes()) {
+				t.Errorf("WriteInstaller output is not deterministic")
+			}
+
+			db := readTestMSI(t, buf.Bytes())
+			props := make(map[string]any)
+			for _, row := range db.tables["Property"] {
+				props[row["Property"].(string)] = row["Value"]
+			}
+			if got, want := props["ProductVersion"], "1.27.1"; got != want {
+				t.Errorf("ProductVersion = %v, want %v", got, want)
+			}
+			if got, want := props["ProductName"], "Go Programming Language "+goarch+" go1.27.1"; got != want {
+				t.Errorf("ProductName = %v, want %v", got, want)
+			}
+			if got := db.tables["InstallExecuteSequence"]; len(got) == 0 {
+				t.Errorf("no InstallExecuteSequence rows")
+			}
+
+			// Resolve the installed path of each file, as msiextract
+			// does, and check it against the cabinet contents.
+			dirs := make(map[string]map[string]any)
+			for _, row := range db.tables["Directory"] {
+				dirs[row["Directory"].(string)] = row
+			}
+			var dirPath func(id string) string
+			dirPath = func(id string) string {
+				row := dirs[id]
+				if row == nil {
+					t.Fatalf("unknown directory %q", id)
+				}
+				name := row["DefaultDir"].(string)
+				if i := strings.LastIndex(name, "|"); i >= 0 {
+					name = name[i+1:]
+				}
+				if row["Directory_Parent"] == nil {
+					return "."
+				}
+				return filepath.ToSlash(filepath.Join(dirPath(row["Directory_Parent"].(string)), name))
+			}
+			components := make(map[string]map[string]any)
+			for _, row := range db.tables["Component"] {
+				components[row["Component"].(string)] = row
+			}
+			cab := readTestCab(t, db.streams["go.cab"])
+			root := "PFiles/Go/"
+			got := make(map[string][]byte)
+			for _, row := range db.tables["File"] {
+				comp := components[row["Component_"].(string)]
+				name := row["FileName"].(string)
+				if i := strings.LastIndex(name, "|"); i >= 0 {
+					name = name[i+1:]
+				}
+				p := dirPath(comp["Directory_"].(string)) + "/" + name
+				rel, ok := strings.CutPrefix(p, root)
+				if !ok {
+					t.Fatalf("file %s not under %s", p, root)
+				}
+				data, ok := cab[row["File"].(string)]
+				if !ok {
+					t.Fatalf("file %s not in cabinet", row["File"])
+				}
+				if size := row["FileSize"].(int); size != len(data) {
+					t.Errorf("%s: FileSize is %d, cabinet has %d bytes", rel, size, len(data))
+				}
+				got[rel] = data
+			}
+			want := maps.Clone(files)
+			want["VERSION"] = []byte("go1.27.1\ntime 2026-02-03T04:05:06Z\n")
+			if !maps.EqualFunc(got, want, bytes.Equal) {
+				t.Errorf("installed files = %v, want %v", slices.Sorted(maps.Keys(got)), slices.Sorted(maps.Keys(want)))
+			}
+
+			if goarch == "386" && dirs["ProgramFilesFolder"] == nil || goarch != "386" && dirs["ProgramFiles64Folder"] == nil {
+				t.Errorf("installer for %s doesn't use the right Program Files folder", goarch)
+			}
+		})
+	}
+}
+
+func TestWriteInstallerBsdtar(t *testing.T) {
+	bsdtar, err := exec.LookPath("bsdtar")
+	if err != nil {
+		t.Skip("bsdtar not found")
+	}
+	var buf bytes.Buffer
+	if err := WriteInstaller(&buf, bytes.NewReader(installertest.Tgz(t, "go1.27.1", testFiles())), InstallerOptions{GOARCH: "amd64"}); err != nil {
+		t.Fatal(err)
+	}
+	db := readTestMSI(t, buf.Bytes())
+	cabFile := filepath.Join(t.TempDir(), "go.cab")
+	if err := os.WriteFile(cabFile, db.streams["go.cab"], 0666); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if out, err := exec.Command(bsdtar, "-x", "-f", cabFile, "-C", dir).CombinedOutput(); err != nil {
+		t.Fatalf("bsdtar: %v\n%s", err, out)
+	}
+	for name, want := range readTestCab(t, db.streams["go.cab"]) {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("bsdtar extracted %s with different contents", name)
+		}
+	}
+}
+
+func TestWriteCFBLarge(t *testing.T) {
+	// Enough data to need more FAT sectors than the header lists,
+	// so that DIFAT sectors are used.
+	big := make([]byte, 8<<20)
+	for i := range big {
+		big[i] = byte(i * 7)
+	}
+	var streams []cfbStream
+	want := make(map[string][]byte)
+	for i := range 50 {
+		name := fmt.Sprintf("stream%d", i)
+		data := big[:i*100]
+		if i == 0 {
+			data = big
+		}
+		streams = append(streams, cfbStream{name, data})
+		want[name] = data
+	}
+	var buf bytes.Buffer
+	if err := writeCFB(&buf, [16]byte{}, streams); err != nil {
+		t.Fatal(err)
+	}
+	got := readTestCFB(t, buf.Bytes())
+	if !maps.EqualFunc(got, want, bytes.Equal) {
+		t.Errorf("streams read back differ")
+	}
+}
+
+func TestShortNames(t *testing.T) {
+	var s shortNamer
+	for _, name := range []string{"go.exe", "README.md", "zoneinfo1.zip", "zoneinfo2.zip", "wasm_exec.js", "CONTRIBUTING.md", "a b", "GOROOT~1.GZ"} {
+		s.reserve(name)
+	}
+	for _, tt := range []struct{ long, want string }{
+		{"go.exe", "go.exe"},
+		{"README.md", "README.md"},
+		{"zoneinfo1.zip", "ZONEIN~1.ZIP|zoneinfo1.zip"},
+		{"zoneinfo2.zip", "ZONEIN~2.ZIP|zoneinfo2.zip"},
+		{"CONTRIBUTING.md", "CONTRI~1.MD|CONTRIBUTING.md"},
+		{"a b", "AB~1|a b"},
+		{"goroot.tar.gz", "GOROOT~2.GZ|goroot.tar.gz"},
+	} {
+		if got := s.name(tt.long); got != tt.want {
+			t.Errorf("name(%q) = %q, want %q", tt.long, got, tt.want)
+		}
+	}
+}
+
+type testMSI struct {
+	tables  map[string][]map[string]any
+	streams map[string][]byte
+}
+
+// readTestMSI reads the tables and streams of an MSI database.
+func readTestMSI(t *testing.T, data []byte) *testMSI {
+	t.Helper()
+	raw := readTestCFB(t, data)
+	db := &testMSI{tables: make(map[string][]map[string]any), streams: make(map[string][]byte)}
+	tableStreams := make(map[string][]byte)
+	for name, data := range raw {
+		if name == "\x05SummaryInformation" {
+			continue
+		}
+		if n, ok := decodeStreamName(name); ok {
+			tableStreams[n] = data
+		} else {
+			n, _ := decodeStreamName(string(rune(0x4840)) + name)
+			db.streams[n] = data
+		}
+	}
+
+	le := binary.LittleEndian
+	pool, strData := tableStreams["_StringPool"], tableStreams["_StringData"]
+	if le.Uint32(pool)&0x80000000 != 0 {
+		t.Fatal("long string references not supported")
+	}
+	strs := []string{""}
+	for p := pool[4:]; len(p) > 0; p = p[4:] {
+		n := int(le.Uint16(p))
+		strs = append(strs, string(strData[:n]))
+		strData = strData[n:]
+	}
+
+	type column struct {
+		name string
+		typ  int
+	}
+	decode := func(data []byte, cols []column) [][]any {
+		width := 0
+		for _, c := range cols {
+			if c.typ&colString == colString {
+				width += 2
+			} else {
+				width += c.typ & 0xFF
+			}
+		}
+		if len(data)%width != 0 {
+			t.Fatalf("table data size %d not a multiple of row width %d", len(data), width)
+		}
+		n := len(data) / width
+		rows := make([][]any, n)
+		for i := range rows {
+			rows[i] = make([]any, len(cols))
+		}
+		for j, c := range cols {
+			for i := range n {
+				switch {
+				case c.typ&colString == colString:
+					if id := le.Uint16(data); id != 0 {
+						rows[i][j] = strs[id]
+					}
+					data = data[2:]
+				case c.typ&0xFF == 2:
+					if v := le.Uint16(data); v != 0 {
+						rows[i][j] = int(v) - 0x8000
+					}
+					data = data[2:]
+				default:
+					if v := le.Uint32(data); v != 0 {
+						rows[i][j] = int(int32(v ^ 0x80000000))
+					}
+					data = data[4:]
+				}
+			}
+		}
+		return rows
+	}
+
+	cols := make(map[string][]column)
+	for _, row := range decode(tableStreams["_Columns"], []column{{"Table", colString}, {"Number", colI2}, {"Name", colString}, {"Type", colI2}}) {
+		table := row[0].(string)
+		cols[table] = append(cols[table], column{row[2].(string), row[3].(int)})
+	}
+	for _, row := range decode(tableStreams["_Tables"], []column{{"Name", colString}}) {
+		table := row[0].(string)
+		for _, r := range decode(tableStreams[table], cols[table]) {
+			m := make(map[string]any)
+			for i, c := range cols[table] {
+				m[c.name] = r[i]
+			}
+			db.tables[table] = append(db.tables[table], m)
+		}
+	}
+	return db
+}
+
+// decodeStreamName reverses msiStreamName, reporting whether name is a table.
+func decodeStreamName(name string) (string, bool) {
+	const alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz._"
+	var b strings.Builder
+	table := false
+	for i, r := range name {
+		switch {
+		case i == 0 && r == 0x4840:
+			table = true
+		case r >= 0x3800 && r < 0x4800:
+			b.WriteByte(alphabet[(r-0x3800)&0x3F])
+			b.WriteByte(alphabet[(r-0x3800)>>6])
+		case r >= 0x4800 && r < 0x4840:
+			b.WriteByte(alphabet[r-0x4800])
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String(), table
+}
+
+// readTestCFB returns the streams in the root storage of a compound file.
+func readTestCFB(t *testing.T, data []byte) map[string][]byte {
+	t.Helper()
+	le := binary.LittleEndian
+	if !bytes.HasPrefix(data, []byte("\xD0\xCF\x11\xE0\xA1\xB1\x1A\xE1")) {
+		t.Fatal("bad compound file signature")
+	}
+	sector := func(id uint32) []byte {
+		off := (int(id) + 1) * cfbSectorSize
+		if off+cfbSectorSize > len(data) {
+			t.Fatalf("sector %d out of range", id)
+		}
+		return data[off : off+cfbSectorSize]
+	}
+
+	// FAT sectors are listed in the header, and then in the DIFAT chain.
+	var fatSectors []uint32
+	for i := range cfbHeaderDIFATEntries {
+		if id := le.Uint32(data[76+4*i:]); id != cfbFreeSect {
+			fatSectors = append(fatSectors, id)
+		}
+	}
+	for id := le.Uint32(data[68:]); id != cfbEndOfChain; {
+		s := sector(id)
+		for j := range cfbIDsPerSector - 1 {
+			if id := le.Uint32(s[4*j:]); id != cfbFreeSect {
+				fatSectors = append(fatSectors, id)
+			}
+		}
+		id = le.Uint32(s[cfbSectorSize-4:])
+	}
+	if n := le.Uint32(data[44:]); int(n) != len(fatSectors) {
+		t.Fatalf("header says %d FAT sectors, found %d", n, len(fatSectors))
+	}
+	var fat []uint32
+	for _, id := range fatSectors {
+		s := sector(id)
+		for j := range cfbIDsPerSector {
+			fat = append(fat, le.Uint32(s[4*j:]))
+		}
+	}
+	readChain := func(start uint32) []byte {
+		var b []byte
+		for id := start; id != cfbEndOfChain; id = fat[id] {
+			b = append(b, sector(id)...)
+		}
+		return b
+	}
+
+	dir := readChain(le.Uint32(data[48:]))
+	miniFATData := readChain(le.Uint32(data[60:]))
+	var miniFAT []uint32
+	for i := 0; i+4 <= len(miniFATData); i += 4 {
+		miniFAT = append(miniFAT, le.Uint32(miniFATData[i:]))
+	}
+	root := dir[:cfbDirEntrySize]
+	if root[66] != 5 {
+		t.Fatal("first directory entry is not the root")
+	}
+	mini := readChain(le.Uint32(root[116:]))
+
+	// Walk the red-black tree of the root's children,
+	// checking that it is ordered and balanced.
+	streams := make(map[string][]byte)
+	var walk func(id uint32) int
+	var last []uint16
+	walk = func(id uint32) int {
+		if id == cfbNoStream {
+			return 1
+		}
+		e := dir[int(id)*cfbDirEntrySize:][:cfbDirEntrySize]
+		lh := walk(le.Uint32(e[68:]))
+		nameLen := int(le.Uint16(e[64:]))/2 - 1
+		name := make([]uint16, nameLen)
+		for i := range name {
+			name[i] = le.Uint16(e[2*i:])
+		}
+		if last != nil && cfbCompare(last, name) >= 0 {
+			t.Errorf("directory entries out of order: %q, %q", string(utf16.Decode(last)), string(utf16.Decode(name)))
+		}
+		last = name
+		size := le.Uint64(e[120:])
+		start := le.Uint32(e[116:])
+		var b []byte
+		if size < cfbMiniCutoff {
+			for id := start; id != cfbEndOfChain && size > 0; id = miniFAT[id] {
+				b = append(b, mini[int(id)*cfbMiniSectorSize:][:cfbMiniSectorSize]...)
+			}
+		} else {
+			b = readChain(start)
+		}
+		streams[string(utf16.Decode(name))] = b[:size]
+		rh := walk(le.Uint32(e[72:]))
+		if lh != rh {
+			t.Errorf("directory tree has unequal black heights %d and %d", lh, rh)
+		}
+		if e[67] == 1 {
+			lh++
+		}
+		return lh
+	}
+	walk(le.Uint32(root[76:]))
+	return streams
+}
+
+// readTestCab returns the contents of the files in an MSZIP cabinet.
+func readTestCab(t *testing.T, data []byte) map[string][]byte {
+	t.Helper()
+	le := binary.LittleEndian
+	if string(data[:4]) != "MSCF" || int(le.Uint32(data[8:])) != len(data) {
+		t.Fatal("bad cabinet header")
+	}
+	filesOffset := le.Uint32(data[16:])
+	nFiles := int(le.Uint16(data[28:]))
+	dataOffset := le.Uint32(data[36:])
+	nBlocks := int(le.Uint16(data[40:]))
+
+	var folder []byte
+	p := data[dataOffset:]
+	for range nBlocks {
+		n, raw := le.Uint16(p[4:]), le.Uint16(p[6:])
+		block := p[8 : 8+n]
+		if string(block[:2]) != "CK" {
+			t.Fatal("data block without MSZIP signature")
+		}
+		b, err := io.ReadAll(flate.NewReader(bytes.NewReader(block[2:])))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(b) != int(raw) {
+			t.Fatalf("data block has %d bytes, want %d", len(b), raw)
+		}
+		folder = append(folder, b...)
+		p = p[8+n:]
+	}
+
+	files := make(map[string][]byte)
+	p = data[filesOffset:]
+	for range nFiles {
+		size, off := le.Uint32(p), le.Uint32(p[4:])
+		name, _, _ := bytes.Cut(p[16:], []byte{0})
+		files[string(name)] = folder[off : off+size]
+		p = p[16+len(name)+1:]
+	}
+	return files
+}
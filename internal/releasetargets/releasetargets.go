@@ -220,3 +220,73 @@ func LatestFirstClassPorts() map[OSArch]bool {
 func IsFirstClass(os, arch string) bool {
 	return LatestFirstClassPorts()[OSArch{os, arch}]
 }
+
+// Releases returns the Go 1.x releases, as x, that release targets are
+// configured for, in increasing order.
+func Releases() []int {
+	return sortedReleases()
+}
+
+// A Change is a difference in a release target between two sets of
+// release targets, such as those of two Go releases.
+type Change struct {
+	Name string
+	Old  *Target // nil if the target was added
+	New  *Target // nil if the target was removed
+}
+
+// Kind returns "added", "removed", or "changed".
+func (c Change) Kind() string {
+	switch {
+	case c.Old == nil:
+		return "added"
+	case c.New == nil:
+		return "removed"
+	}
+	return "changed"
+}
+
+// Details returns descriptions of how a changed target differs,
+// such as "second class port → first class port".
+// It returns nil for an added or removed target.
+func (c Change) Details() []string {
+	if c.Old == nil || c.New == nil {
+		return nil
+	}
+	var details []string
+	detail := func(what, old, new string) {
+		if old != new {
+			details = append(details, fmt.Sprintf("%s %s → %s", what, old, new))
+		}
+	}
+	if c.Old.SecondClass != c.New.SecondClass {
+		class := map[bool]string{false: "first class port", true: "second class port"}
+		details = append(details, class[c.Old.SecondClass]+" → "+class[c.New.SecondClass])
+	}
+	detail("GOOS", c.Old.GOOS, c.New.GOOS)
+	detail("GOARCH", c.Old.GOARCH, c.New.GOARCH)
+	detail("extra env", fmt.Sprintf("%q", c.Old.ExtraEnv), fmt.Sprintf("%q", c.New.ExtraEnv))
+	detail("minimum macOS version", c.Old.MinMacOSVersion, c.New.MinMacOSVersion)
+	return details
+}
+
+// Diff returns the changes to release targets from old to new,
+// sorted by target name.
+func Diff(old, new ReleaseTargets) []Change {
+	var changes []Change
+	for name, o := range old {
+		n, ok := new[name]
+		if !ok {
+			changes = append(changes, Change{Name: name, Old: o})
+		} else if c := (Change{Name: name, Old: o, New: n}); len(c.Details()) != 0 {
+			changes = append(changes, c)
+		}
+	}
+	for name, n := range new {
+		if _, ok := old[name]; !ok {
+			changes = append(changes, Change{Name: name, New: n})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	return changes
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"sort"
 	"strings"
 	"testing"
@@ -71,3 +72,47 @@ func printRelease(w io.Writer, release int, targets ReleaseTargets) {
 	}
 	fmt.Fprintf(w, "\n\n")
 }
+
+func TestDiff(t *testing.T) {
+	for _, tt := range []struct {
+		old, new int
+		want     []string
+	}{
+		{25, 26, []string{"removed freebsd-riscv64"}},
+		{26, 27, []string{
+			"changed darwin-amd64: minimum macOS version 12 → 13",
+			"changed darwin-arm64: minimum macOS version 12 → 13",
+		}},
+		{27, 27, nil},
+		{27, 25, []string{
+			"changed darwin-amd64: minimum macOS version 13 → 12",
+			"changed darwin-arm64: minimum macOS version 13 → 12",
+			"added freebsd-riscv64",
+		}},
+	} {
+		var got []string
+		for _, c := range Diff(TargetsForGo1Point(tt.old), TargetsForGo1Point(tt.new)) {
+			s := c.Kind() + " " + c.Name
+			if details := c.Details(); len(details) != 0 {
+				s += ": " + strings.Join(details, ", ")
+			}
+			got = append(got, s)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Diff(1.%d, 1.%d) = %q, want %q", tt.old, tt.new, got, tt.want)
+		}
+	}
+}
+
+func TestDiffDetails(t *testing.T) {
+	old := ReleaseTargets{"linux-arm": {Name: "linux-arm", GOOS: "linux", GOARCH: "arm", SecondClass: true}}
+	new := ReleaseTargets{"linux-arm": {Name: "linux-arm", GOOS: "linux", GOARCH: "arm", ExtraEnv: []string{"GOARM=6"}}}
+	changes := Diff(old, new)
+	if len(changes) != 1 {
+		t.Fatalf("Diff returned %d changes, want 1", len(changes))
+	}
+	want := []string{`second class port → first class port`, `extra env [] → ["GOARM=6"]`}
+	if got := changes[0].Details(); !slices.Equal(got, want) {
+		t.Errorf("Details() = %q, want %q", got, want)
+	}
+}
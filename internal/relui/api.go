@@ -0,0 +1,617 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package relui
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"golang.org/x/build/internal/relui/db"
+	"golang.org/x/build/internal/workflow"
+)
+
+// The relui API is a JSON over HTTP interface for automation. It offers the
+// same operations as the HTML interface, under the same authorization rules.
+//
+//	GET    /api/v1/definitions                           list workflow definitions
+//	GET    /api/v1/definitions/{name}                    describe a definition
+//	GET    /api/v1/definitions/{name}/graph              a definition's task graph
+//	GET    /api/v1/workflows[?name=NAME]                 list workflows
+//	POST   /api/v1/workflows                             start a workflow
+//	GET    /api/v1/workflows/{id}                        a workflow and its tasks
+//	GET    /api/v1/workflows/{id}/logs[?after=LOGID]     task logs
+//	GET    /api/v1/workflows/{id}/watch                  stream of updates
+//	POST   /api/v1/workflows/{id}/stop                   stop a workflow
+//	POST   /api/v1/workflows/{id}/tasks/{name}/approve   approve a task
+//	POST   /api/v1/workflows/{id}/tasks/{name}/retry     retry a task
+//	GET    /api/v1/schedules                             list schedules
+//	POST   /api/v1/schedules                             create a schedule
+//	DELETE /api/v1/schedules/{id}                        delete a schedule
+//
+// Errors are reported with an appropriate status code and an apiError body.
+
+// registerAPI registers the API handlers on s.m.
+func (s *Server) registerAPI() {
+	s.m.HandleFunc("GET /api/v1/definitions", s.apiListDefinitions)
+	s.m.HandleFunc("GET /api/v1/definitions/{name}", s.apiGetDefinition)
+	s.m.HandleFunc("GET /api/v1/definitions/{name}/graph", s.apiDefinitionGraph)
+	s.m.HandleFunc("GET /api/v1/workflows", s.apiListWorkflows)
+	s.m.HandleFunc("POST /api/v1/workflows", s.apiStartWorkflow)
+	s.m.HandleFunc("GET /api/v1/workflows/{id}", s.apiGetWorkflow)
+	s.m.HandleFunc("GET /api/v1/workflows/{id}/logs", s.apiWorkflowLogs)
+	s.m.HandleFunc("GET /api/v1/workflows/{id}/watch", s.apiWatchWorkflow)
+	s.m.HandleFunc("POST /api/v1/workflows/{id}/stop", s.apiStopWorkflow)
+	s.m.HandleFunc("POST /api/v1/workflows/{id}/tasks/{name}/approve", s.apiApproveTask)
+	s.m.HandleFunc("POST /api/v1/workflows/{id}/tasks/{name}/retry", s.apiRetryTask)
+	s.m.HandleFunc("GET /api/v1/schedules", s.apiListSchedules)
+	s.m.HandleFunc("POST /api/v1/schedules", s.apiCreateSchedule)
+	s.m.HandleFunc("DELETE /api/v1/schedules/{id}", s.apiDeleteSchedule)
+}
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+type apiDefinition struct {
+	Name             string         `json:"name"`
+	Version          int            `json:"version"`
+	Fingerprint      string         `json:"fingerprint"`
+	AuthorizedGroups []string       `json:"authorized_groups,omitempty"`
+	Parameters       []apiParameter `json:"parameters"`
+}
+
+type apiParameter struct {
+	Name     string   `json:"name"`
+	Type     string   `json:"type"` // The parameter's Go type, such as "string" or "[]string".
+	Required bool     `json:"required"`
+	Doc      string   `json:"doc,omitempty"`
+	Example  string   `json:"example,omitempty"`
+	Options  []string `json:"options,omitempty"` // Allowed values, if restricted.
+}
+
+type apiWorkflow struct {
+	ID                    uuid.UUID       `json:"id"`
+	Name                  string          `json:"name"`
+	Params                json.RawMessage `json:"params,omitempty"`
+	Created               time.Time       `json:"created"`
+	Updated               time.Time       `json:"updated"`
+	Finished              bool            `json:"finished"`
+	Output                json.RawMessage `json:"output,omitempty"`
+	Error                 string          `json:"error,omitempty"`
+	ScheduleID            int32           `json:"schedule_id,omitempty"`
+	DefinitionVersion     int32           `json:"definition_version"`
+	DefinitionFingerprint string          `json:"definition_fingerprint,omitempty"`
+}
+
+type apiTask struct {
+	Name             string          `json:"name"`
+	Started          bool            `json:"started"`
+	Finished         bool            `json:"finished"`
+	Result           json.RawMessage `json:"result,omitempty"`
+	Error            string          `json:"error,omitempty"`
+	RetryCount       int32           `json:"retry_count,omitempty"`
+	ReadyForApproval bool            `json:"ready_for_approval,omitempty"`
+	Approved         *time.Time      `json:"approved,omitempty"`
+	Created          time.Time       `json:"created"`
+	Updated          time.Time       `json:"updated"`
+}
+
+type apiLog struct {
+	ID      int32     `json:"id"`
+	Task    string    `json:"task"`
+	Body    string    `json:"body"`
+	Created time.Time `json:"created"`
+}
+
+type apiWorkflowDetail struct {
+	Workflow apiWorkflow `json:"workflow"`
+	Tasks    []apiTask   `json:"tasks"`
+}
+
+// apiWatchEvent is one line of the stream served by apiWatchWorkflow.
+// Only one field is set.
+type apiWatchEvent struct {
+	Task     *apiTask     `json:"task,omitempty"`
+	Log      *apiLog      `json:"log,omitempty"`
+	Workflow *apiWorkflow `json:"workflow,omitempty"` // Sent once the workflow has finished.
+}
+
+type apiStartRequest struct {
+	Name   string          `json:"name"`
+	Params json.RawMessage `json:"params"`
+}
+
+type apiSchedule struct {
+	ID       int32           `json:"id"`
+	Workflow string          `json:"workflow"`
+	Params   json.RawMessage `json:"params,omitempty"`
+	Type     ScheduleType    `json:"type"`
+	Once     time.Time       `json:"once,omitzero"`
+	Cron     string          `json:"cron,omitempty"`
+	Next     time.Time       `json:"next,omitzero"`
+}
+
+func newAPIDefinition(name string, d *workflow.Definition) apiDefinition {
+	ad := apiDefinition{
+		Name:             name,
+		Version:          d.Version(),
+		Fingerprint:      d.Fingerprint(),
+		AuthorizedGroups: d.AuthorizedGroups(),
+		Parameters:       []apiParameter{},
+	}
+	for _, p := range d.Parameters() {
+		ap := apiParameter{
+			Name:     p.Name(),
+			Type:     p.Type().String(),
+			Required: p.RequireNonZero(),
+			Doc:      p.Doc(),
+			Example:  p.Example(),
+		}
+		if p.HTMLElement() == "select" {
+			ap.Options = p.HTMLSelectOptions()
+		}
+		ad.Parameters = append(ad.Parameters, ap)
+	}
+	return ad
+}
+
+func newAPIWorkflow(w db.Workflow) apiWorkflow {
+	aw := apiWorkflow{
+		ID:                    w.ID,
+		Name:                  w.Name.String,
+		Created:               w.CreatedAt,
+		Updated:               w.UpdatedAt,
+		Finished:              w.Finished,
+		Error:                 w.Error,
+		ScheduleID:            w.ScheduleID.Int32,
+		DefinitionVersion:     w.DefinitionVersion,
+		DefinitionFingerprint: w.DefinitionFingerprint,
+	}
+	if w.Params.Valid {
+		aw.Params = json.RawMessage(w.Params.String)
+	}
+	if w.Output != "" {
+		aw.Output = json.RawMessage(w.Output)
+	}
+	return aw
+}
+
+func newAPITask(t db.Task) apiTask {
+	at := apiTask{
+		Name:             t.Name,
+		Started:          t.Started,
+		Finished:         t.Finished,
+		Error:            t.Error.String,
+		RetryCount:       t.RetryCount,
+		ReadyForApproval: t.ReadyForApproval,
+		Created:          t.CreatedAt,
+		Updated:          t.UpdatedAt,
+	}
+	if t.Result.Valid {
+		at.Result = json.RawMessage(t.Result.String)
+	}
+	if t.ApprovedAt.Valid {
+		at.Approved = &t.ApprovedAt.Time
+	}
+	return at
+}
+
+func newAPILog(l db.TaskLog) apiLog {
+	return apiLog{ID: l.ID, Task: l.TaskName, Body: l.Body, Created: l.CreatedAt}
+}
+
+// writeJSON writes v to w as JSON with the given status code.
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("writeJSON: %v", err)
+	}
+}
+
+// apiErrorf writes an apiError response.
+func apiErrorf(w http.ResponseWriter, code int, format string, args ...any) {
+	writeJSON(w, code, apiError{Error: fmt.Sprintf(format, args...)})
+}
+
+// apiCheckAuthorized reports whether the user is authorized to access d.
+// It writes an error response if and only if it returns false.
+func (s *Server) apiCheckAuthorized(w http.ResponseWriter, r *http.Request, d *workflow.Definition) bool {
+	err := s.authorize(r.Context(), d)
+	if errors.Is(err, errForbidden) {
+		apiErrorf(w, http.StatusForbidden, "%v", err)
+		return false
+	} else if err != nil {
+		log.Printf("authorize: %v", err)
+		apiErrorf(w, http.StatusInternalServerError, "authorization failed")
+		return false
+	}
+	return true
+}
+
+// apiLookupWorkflow looks up the workflow identified by the request's
+// id path value and its definition, and checks that the user may access
+// it. It writes an error response if and only if it returns ok == false.
+func (s *Server) apiLookupWorkflow(w http.ResponseWriter, r *http.Request) (_ db.Workflow, _ *workflow.Definition, ok bool) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		apiErrorf(w, http.StatusBadRequest, "invalid workflow ID %q", r.PathValue("id"))
+		return db.Workflow{}, nil, false
+	}
+	wf, err := db.New(s.db).Workflow(r.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+		apiErrorf(w, http.StatusNotFound, "no workflow with ID %v", id)
+		return db.Workflow{}, nil, false
+	} else if err != nil {
+		log.Printf("apiLookupWorkflow: Workflow(%v): %v", id, err)
+		apiErrorf(w, http.StatusInternalServerError, "looking up workflow failed")
+		return db.Workflow{}, nil, false
+	}
+	d := s.w.dh.Definition(wf.Name.String)
+	if d == nil {
+		apiErrorf(w, http.StatusBadRequest, "workflow %v has an unknown definition %q", id, wf.Name.String)
+		return db.Workflow{}, nil, false
+	}
+	if !s.apiCheckAuthorized(w, r, d) {
+		return db.Workflow{}, nil, false
+	}
+	return wf, d, true
+}
+
+func (s *Server) apiListDefinitions(w http.ResponseWriter, r *http.Request) {
+	defs := s.w.dh.Definitions()
+	resp := []apiDefinition{}
+	for _, name := range slices.Sorted(maps.Keys(defs)) {
+		resp = append(resp, newAPIDefinition(name, defs[name]))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) apiGetDefinition(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	d := s.w.dh.Definition(name)
+	if d == nil {
+		apiErrorf(w, http.StatusNotFound, "no workflow definition named %q", name)
+		return
+	}
+	writeJSON(w, http.StatusOK, newAPIDefinition(name, d))
+}
+
+func (s *Server) apiDefinitionGraph(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	d := s.w.dh.Definition(name)
+	if d == nil {
+		apiErrorf(w, http.StatusNotFound, "no workflow definition named %q", name)
+		return
+	}
+	writeJSON(w, http.StatusOK, d.Graph())
+}
+
+func (s *Server) apiListWorkflows(w http.ResponseWriter, r *http.Request) {
+	q := db.New(s.db)
+	var (
+		rows []db.Workflow
+		err  error
+	)
+	if name := r.FormValue("name"); name != "" {
+		rows, err = q.WorkflowsByName(r.Context(), sql.NullString{String: name, Valid: true})
+	} else {
+		rows, err = q.Workflows(r.Context())
+	}
+	if err != nil {
+		log.Printf("apiListWorkflows: %v", err)
+		apiErrorf(w, http.StatusInternalServerError, "listing workflows failed")
+		return
+	}
+	resp := []apiWorkflow{}
+	for _, row := range rows {
+		resp = append(resp, newAPIWorkflow(row))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// apiParseParams parses raw as the JSON-encoded parameters of d and
+// validates them.
+func apiParseParams(d *workflow.Definition, raw json.RawMessage) (map[string]any, error) {
+	if len(raw) == 0 {
+		raw = json.RawMessage("{}")
+	}
+	var rawParams map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &rawParams); err != nil {
+		return nil, fmt.Errorf("params must be a JSON object: %v", err)
+	}
+	for name := range rawParams {
+		if !slices.ContainsFunc(d.Parameters(), func(p workflow.MetaParameter) bool { return p.Name() == name }) {
+			return nil, fmt.Errorf("unknown parameter %q", name)
+		}
+	}
+	params, err := UnmarshalWorkflow(string(raw), d)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range d.Parameters() {
+		if err := p.Valid(params[p.Name()]); err != nil {
+			return nil, err
+		}
+	}
+	return params, nil
+}
+
+func (s *Server) apiStartWorkflow(w http.ResponseWriter, r *http.Request) {
+	var req apiStartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apiErrorf(w, http.StatusBadRequest, "invalid request: %v", err)
+		return
+	}
+	d := s.w.dh.Definition(req.Name)
+	if d == nil {
+		apiErrorf(w, http.StatusBadRequest, "no workflow definition named %q", req.Name)
+		return
+	}
+	if !s.apiCheckAuthorized(w, r, d) {
+		return
+	}
+	params, err := apiParseParams(d, req.Params)
+	if err != nil {
+		apiErrorf(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	id, err := s.w.StartWorkflow(r.Context(), req.Name, params, 0)
+	if err != nil {
+		log.Printf("apiStartWorkflow: s.w.StartWorkflow(_, %q, %v, 0): %v", req.Name, params, err)
+		apiErrorf(w, http.StatusInternalServerError, "starting workflow failed")
+		return
+	}
+	s.audit(r.Context(), id, "", "start", "")
+	wf, err := db.New(s.db).Workflow(r.Context(), id)
+	if err != nil {
+		log.Printf("apiStartWorkflow: Workflow(%v): %v", id, err)
+		apiErrorf(w, http.StatusInternalServerError, "looking up workflow failed")
+		return
+	}
+	writeJSON(w, http.StatusCreated, newAPIWorkflow(wf))
+}
+
+func (s *Server) apiGetWorkflow(w http.ResponseWriter, r *http.Request) {
+	wf, _, ok := s.apiLookupWorkflow(w, r)
+	if !ok {
+		return
+	}
+	tasks, err := db.New(s.db).TasksForWorkflow(r.Context(), wf.ID)
+	if err != nil {
+		log.Printf("apiGetWorkflow: TasksForWorkflow(%v): %v", wf.ID, err)
+		apiErrorf(w, http.StatusInternalServerError, "listing tasks failed")
+		return
+	}
+	resp := apiWorkflowDetail{Workflow: newAPIWorkflow(wf), Tasks: []apiTask{}}
+	for _, t := range tasks {
+		resp.Tasks = append(resp.Tasks, newAPITask(t))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) apiWorkflowLogs(w http.ResponseWriter, r *http.Request) {
+	wf, _, ok := s.apiLookupWorkflow(w, r)
+	if !ok {
+		return
+	}
+	var after int
+	if v := r.FormValue("after"); v != "" {
+		var err error
+		if after, err = strconv.Atoi(v); err != nil {
+			apiErrorf(w, http.StatusBadRequest, "invalid log ID %q", v)
+			return
+		}
+	}
+	logs, err := db.New(s.db).TaskLogsForWorkflow(r.Context(), wf.ID)
+	if err != nil {
+		log.Printf("apiWorkflowLogs: TaskLogsForWorkflow(%v): %v", wf.ID, err)
+		apiErrorf(w, http.StatusInternalServerError, "listing logs failed")
+		return
+	}
+	resp := []apiLog{}
+	for _, l := range logs {
+		if int(l.ID) > after {
+			resp = append(resp, newAPILog(l))
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// apiWatchWorkflow streams updates to a workflow's tasks and logs as
+// newline-delimited JSON apiWatchEvents, starting with the current state
+// of every task and all existing logs. The stream ends once the workflow
+// has finished.
+func (s *Server) apiWatchWorkflow(w http.ResponseWriter, r *http.Request) {
+	wf, _, ok := s.apiLookupWorkflow(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	enc := json.NewEncoder(w)
+	rc := http.NewResponseController(w)
+	err := s.watchWorkflow(r.Context(), wf.ID, 0, func(ev apiWatchEvent) error {
+		if err := enc.Encode(ev); err != nil {
+			return err
+		}
+		rc.Flush()
+		return nil
+	})
+	if err != nil && r.Context().Err() == nil {
+		log.Printf("apiWatchWorkflow(%v): %v", wf.ID, err)
+	}
+}
+
+func (s *Server) apiStopWorkflow(w http.ResponseWriter, r *http.Request) {
+	wf, _, ok := s.apiLookupWorkflow(w, r)
+	if !ok {
+		return
+	}
+	if !s.w.cancelWorkflow(wf.ID) {
+		apiErrorf(w, http.StatusNotFound, "workflow %v is not running", wf.ID)
+		return
+	}
+	s.audit(r.Context(), wf.ID, "", "stop", "")
+	writeJSON(w, http.StatusOK, newAPIWorkflow(wf))
+}
+
+func (s *Server) apiApproveTask(w http.ResponseWriter, r *http.Request) {
+	wf, _, ok := s.apiLookupWorkflow(w, r)
+	if !ok {
+		return
+	}
+	t, err := s.approveTask(r.Context(), wf.ID, r.PathValue("name"))
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+		apiErrorf(w, http.StatusNotFound, "workflow %v has no task %q", wf.ID, r.PathValue("name"))
+		return
+	} else if errors.Is(err, errApprovalDenied) {
+		apiErrorf(w, http.StatusForbidden, "%v", err)
+		return
+	} else if err != nil {
+		log.Printf("apiApproveTask: approveTask(_, %v, %q): %v", wf.ID, r.PathValue("name"), err)
+		apiErrorf(w, http.StatusInternalServerError, "approving task failed")
+		return
+	}
+	writeJSON(w, http.StatusOK, newAPITask(t))
+}
+
+func (s *Server) apiRetryTask(w http.ResponseWriter, r *http.Request) {
+	wf, _, ok := s.apiLookupWorkflow(w, r)
+	if !ok {
+		return
+	}
+	err := s.w.RetryTask(r.Context(), wf.ID, r.PathValue("name"))
+	if errors.Is(err, workflow.ErrUnknownTask) {
+		apiErrorf(w, http.StatusNotFound, "workflow %v has no task %q", wf.ID, r.PathValue("name"))
+		return
+	} else if errors.Is(err, workflow.ErrTaskNotRetryable) || errors.Is(err, errWorkflowNotRunning) {
+		apiErrorf(w, http.StatusConflict, "%v", err)
+		return
+	} else if err != nil {
+		log.Printf("apiRetryTask: RetryTask(_, %v, %q): %v", wf.ID, r.PathValue("name"), err)
+		apiErrorf(w, http.StatusInternalServerError, "retrying task failed")
+		return
+	}
+	s.audit(r.Context(), wf.ID, r.PathValue("name"), "retry", "")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) apiListSchedules(w http.ResponseWriter, r *http.Request) {
+	entries, failed := s.scheduler.Entries(r.Context())
+	resp := []apiSchedule{}
+	for _, e := range entries {
+		resp = append(resp, newAPISchedule(e.WorkflowJob().Schedule, e.Next))
+	}
+	for _, e := range failed {
+		resp = append(resp, newAPISchedule(e.Schedule, e.Next()))
+	}
+	slices.SortFunc(resp, func(a, b apiSchedule) int { return int(a.ID - b.ID) })
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func newAPISchedule(row db.Schedule, next time.Time) apiSchedule {
+	sched := Schedule{Once: row.Once, Cron: row.Spec}
+	sched.setType()
+	as := apiSchedule{
+		ID:       row.ID,
+		Workflow: row.WorkflowName,
+		Type:     sched.Type,
+		Once:     row.Once,
+		Cron:     row.Spec,
+		Next:     next,
+	}
+	if row.WorkflowParams.Valid {
+		as.Params = json.RawMessage(row.WorkflowParams.String)
+	}
+	return as
+}
+
+func (s *Server) apiCreateSchedule(w http.ResponseWriter, r *http.Request) {
+	var req apiSchedule
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apiErrorf(w, http.StatusBadRequest, "invalid request: %v", err)
+		return
+	}
+	d := s.w.dh.Definition(req.Workflow)
+	if d == nil {
+		apiErrorf(w, http.StatusBadRequest, "no workflow definition named %q", req.Workflow)
+		return
+	}
+	if !s.apiCheckAuthorized(w, r, d) {
+		return
+	}
+	params, err := apiParseParams(d, req.Params)
+	if err != nil {
+		apiErrorf(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	sched := Schedule{Type: req.Type, Once: req.Once, Cron: req.Cron}
+	if sched.Type == ScheduleImmediate {
+		apiErrorf(w, http.StatusBadRequest, "to run a workflow immediately, start it instead")
+		return
+	} else if err := sched.Valid(); err != nil {
+		apiErrorf(w, http.StatusBadRequest, "invalid schedule: %v", err)
+		return
+	} else if sched.Type == ScheduleOnce && sched.Once.Before(time.Now()) {
+		apiErrorf(w, http.StatusBadRequest, "schedule time %v is in the past", sched.Once)
+		return
+	}
+	row, err := s.scheduler.Create(r.Context(), sched, req.Workflow, params)
+	if err != nil {
+		log.Printf("apiCreateSchedule: s.scheduler.Create(_, %v, %q, %v): %v", sched, req.Workflow, params, err)
+		apiErrorf(w, http.StatusInternalServerError, "creating schedule failed")
+		return
+	}
+	writeJSON(w, http.StatusCreated, newAPISchedule(row, time.Time{}))
+}
+
+func (s *Server) apiDeleteSchedule(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		apiErrorf(w, http.StatusBadRequest, "invalid schedule ID %q", r.PathValue("id"))
+		return
+	}
+	rows, err := db.New(s.db).Schedules(r.Context())
+	if err != nil {
+		log.Printf("apiDeleteSchedule: Schedules: %v", err)
+		apiErrorf(w, http.StatusInternalServerError, "listing schedules failed")
+		return
+	}
+	i := slices.IndexFunc(rows, func(row db.Schedule) bool { return row.ID == int32(id) })
+	if i == -1 {
+		apiErrorf(w, http.StatusNotFound, "no schedule with ID %d", id)
+		return
+	}
+	d := s.w.dh.Definition(rows[i].WorkflowName)
+	if d == nil {
+		apiErrorf(w, http.StatusBadRequest, "schedule %d has an unknown workflow %q", id, rows[i].WorkflowName)
+		return
+	}
+	if !s.apiCheckAuthorized(w, r, d) {
+		return
+	}
+	if err := s.scheduler.Delete(r.Context(), id); err == ErrScheduleNotFound {
+		apiErrorf(w, http.StatusNotFound, "no schedule with ID %d", id)
+		return
+	} else if err != nil {
+		log.Printf("apiDeleteSchedule: s.scheduler.Delete(_, %v): %v", id, err)
+		apiErrorf(w, http.StatusInternalServerError, "deleting schedule failed")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func ptr[T any](v T) *T { return &v }
@@ -0,0 +1,171 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package relui
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"golang.org/x/build/internal/relui/db"
+	"golang.org/x/build/internal/workflow"
+)
+
+func TestAPIParseParams(t *testing.T) {
+	wd := workflow.New(workflow.ACL{})
+	workflow.Param(wd, workflow.ParamDef[string]{Name: "version", Check: func(v string) error {
+		if !strings.HasPrefix(v, "go") {
+			return errors.New("version must start with go")
+		}
+		return nil
+	}})
+	workflow.Param(wd, workflow.ParamDef[[]string]{Name: "reviewers", ParamType: workflow.SliceShort})
+
+	cases := []struct {
+		desc    string
+		raw     string
+		want    map[string]any
+		wantErr bool
+	}{
+		{
+			desc: "valid",
+			raw:  `{"version": "go1.30", "reviewers": ["gopher"]}`,
+			want: map[string]any{"version": "go1.30", "reviewers": []string{"gopher"}},
+		},
+		{desc: "not an object", raw: `["go1.30"]`, wantErr: true},
+		{desc: "unknown parameter", raw: `{"version": "go1.30", "reviewers": [], "extra": 1}`, wantErr: true},
+		{desc: "wrong type", raw: `{"version": 1, "reviewers": []}`, wantErr: true},
+		{desc: "invalid value", raw: `{"version": "1.30", "reviewers": []}`, wantErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			got, err := apiParseParams(wd, json.RawMessage(c.raw))
+			if (err != nil) != c.wantErr {
+				t.Fatalf("apiParseParams(_, %s) = _, %v, want error: %t", c.raw, err, c.wantErr)
+			}
+			if c.wantErr {
+				return
+			}
+			if diff := cmp.Diff(c.want, got); diff != "" {
+				t.Errorf("apiParseParams(_, %s) mismatch (-want +got):\n%s", c.raw, diff)
+			}
+		})
+	}
+}
+
+func TestAPIDefinitions(t *testing.T) {
+	ctx := t.Context()
+	p := testDB(ctx, t)
+	s := NewServer(p, NewWorker(NewDefinitionHolder(), p, &PGListener{DB: p}), nil, SiteHeader{}, nil, nil, nil)
+
+	rec := httptest.NewRecorder()
+	s.m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/definitions/echo", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /api/v1/definitions/echo: status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var got apiDefinition
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	want := apiDefinition{Name: "echo", Parameters: []apiParameter{
+		{Name: "greeting", Type: "string"},
+		{Name: "farewell", Type: "string"},
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GET /api/v1/definitions/echo mismatch (-want +got):\n%s", diff)
+	}
+
+	rec = httptest.NewRecorder()
+	s.m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/definitions/nonexistent", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("GET /api/v1/definitions/nonexistent: status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestAPIWorkflow(t *testing.T) {
+	ctx := t.Context()
+	p := testDB(ctx, t)
+	q := db.New(p)
+	s := NewServer(p, NewWorker(NewDefinitionHolder(), p, &PGListener{DB: p}), nil, SiteHeader{}, nil, nil, nil)
+
+	hourAgo := time.Now().Add(-1 * time.Hour)
+	wfID := uuid.New()
+	if _, err := q.CreateWorkflow(ctx, db.CreateWorkflowParams{
+		ID:        wfID,
+		Params:    nullString(`{"farewell": "bye", "greeting": "hello"}`),
+		Name:      nullString("echo"),
+		CreatedAt: hourAgo,
+		UpdatedAt: hourAgo,
+	}); err != nil {
+		t.Fatalf("CreateWorkflow: %v", err)
+	}
+	if _, err := q.CreateTask(ctx, db.CreateTaskParams{
+		WorkflowID: wfID,
+		Name:       "approve please",
+		CreatedAt:  hourAgo,
+		UpdatedAt:  hourAgo,
+	}); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+
+	serve := func(method, target string, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		s.m.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return rec
+	}
+
+	rec := serve(http.MethodGet, "/api/v1/workflows/"+wfID.String(), "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET workflow: status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var detail apiWorkflowDetail
+	if err := json.NewDecoder(rec.Body).Decode(&detail); err != nil {
+		t.Fatal(err)
+	}
+	if detail.Workflow.ID != wfID || len(detail.Tasks) != 1 || detail.Tasks[0].Name != "approve please" {
+		t.Errorf("GET workflow = %+v, want workflow %v with one task", detail, wfID)
+	}
+
+	rec = serve(http.MethodPost, "/api/v1/workflows/"+wfID.String()+"/tasks/approve%20please/approve", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("approve: status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	var task apiTask
+	if err := json.NewDecoder(rec.Body).Decode(&task); err != nil {
+		t.Fatal(err)
+	}
+	if task.Approved == nil {
+		t.Errorf("approve: task.Approved = nil, want a time")
+	}
+
+	for _, c := range []struct {
+		method, target, body string
+		wantCode             int
+	}{
+		{http.MethodGet, "/api/v1/workflows/invalid", "", http.StatusBadRequest},
+		{http.MethodGet, "/api/v1/workflows/" + uuid.New().String(), "", http.StatusNotFound},
+		{http.MethodPost, "/api/v1/workflows/" + wfID.String() + "/tasks/missing/approve", "", http.StatusNotFound},
+		{http.MethodPost, "/api/v1/workflows/" + wfID.String() + "/stop", "", http.StatusNotFound},
+		{http.MethodPost, "/api/v1/workflows/" + wfID.String() + "/tasks/approve%20please/retry", "", http.StatusConflict},
+		{http.MethodPost, "/api/v1/workflows", `{"name": "nonexistent"}`, http.StatusBadRequest},
+		{http.MethodPost, "/api/v1/workflows", `{"name": "echo", "params": {"greeting": 1}}`, http.StatusBadRequest},
+		{http.MethodPost, "/api/v1/schedules", `{"workflow": "echo", "type": "Cron", "cron": "bogus"}`, http.StatusBadRequest},
+	} {
+		rec := serve(c.method, c.target, c.body)
+		if rec.Code != c.wantCode {
+			t.Errorf("%s %s: status = %d, want %d", c.method, c.target, rec.Code, c.wantCode)
+		}
+		var apiErr apiError
+		if err := json.NewDecoder(rec.Body).Decode(&apiErr); err != nil || apiErr.Error == "" {
+			t.Errorf("%s %s: body isn't an apiError: %v", c.method, c.target, err)
+		}
+	}
+}
@@ -0,0 +1,271 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package relui
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"golang.org/x/build/internal/access"
+	"golang.org/x/build/internal/relui/db"
+	"golang.org/x/build/internal/relui/groups"
+	wf "golang.org/x/build/internal/workflow"
+)
+
+// An ApprovalRule describes who must approve a task created with
+// ApproveActionDepWithRule before the workflow can proceed.
+type ApprovalRule struct {
+	// Groups, if non-empty, are the groups of which each approver
+	// must be a member of at least one.
+	Groups []string `json:"groups,omitempty"`
+	// Required is the number of distinct approvers needed.
+	// Zero means one.
+	Required int `json:"required,omitempty"`
+	// DisallowCreator prevents the user who started the workflow,
+	// or created the schedule that started it, from approving its
+	// tasks. Workflows whose creator isn't known, such as those
+	// started before creators were recorded, have no one to exclude.
+	DisallowCreator bool `json:"disallow_creator,omitempty"`
+	// Expiry, if non-zero, is how long an approval counts toward
+	// Required. Approvals older than that are ignored.
+	Expiry time.Duration `json:"expiry,omitempty"`
+}
+
+func (r ApprovalRule) required() int {
+	return max(r.Required, 1)
+}
+
+// PublishApprovalRule is the approval rule for the tasks that gate
+// publishing a release: two members of the release team, neither
+// of whom started the workflow.
+var PublishApprovalRule = ApprovalRule{
+	Groups:          []string{groups.ReleaseTeam},
+	Required:        2,
+	DisallowCreator: true,
+}
+
+// ApproveActionDepWithRule is like ApproveActionDep, but the task is only
+// approved once the approvals recorded for it satisfy rule.
+func ApproveActionDepWithRule(p db.PGDBTX, rule ApprovalRule) func(*wf.TaskContext) error {
+	wait := ApproveActionDep(p)
+	return func(ctx *wf.TaskContext) error {
+		b, err := json.Marshal(rule)
+		if err != nil {
+			return err
+		}
+		if _, err := db.New(p).UpsertTaskApprovalRule(ctx, db.UpsertTaskApprovalRuleParams{
+			WorkflowID: ctx.WorkflowID,
+			TaskName:   ctx.TaskName,
+			Rule:       string(b),
+		}); err != nil {
+			return err
+		}
+		ctx.Printf("Waiting for %d approval(s).", rule.required())
+		return wait(ctx)
+	}
+}
+
+// errApprovalDenied is returned by approveTask when the user
+// may not approve the task.
+var errApprovalDenied = errors.New("approval denied")
+
+// userFromContext returns the email address of the authenticated user
+// of ctx, or the empty string if there is none.
+func userFromContext(ctx context.Context) string {
+	iap, err := access.IAPFromContext(ctx)
+	if err != nil {
+		return ""
+	}
+	return iap.Email
+}
+
+// approveTask records the current user's approval of the named task.
+// The task is marked approved once its approvals satisfy its
+// ApprovalRule, or immediately if it doesn't have one.
+func (s *Server) approveTask(ctx context.Context, id uuid.UUID, name string) (db.Task, error) {
+	q := db.New(s.db)
+	row, err := q.TaskApprovalRule(ctx, db.TaskApprovalRuleParams{WorkflowID: id, TaskName: name})
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+		t, err := q.ApproveTask(ctx, db.ApproveTaskParams{
+			WorkflowID: id,
+			Name:       name,
+			ApprovedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		})
+		if err != nil {
+			return t, err
+		}
+		s.audit(ctx, id, name, "approve", "")
+		s.w.l.Logger(id, t.Name).Printf("USER-APPROVED")
+		return t, nil
+	} else if err != nil {
+		return db.Task{}, err
+	}
+	var rule ApprovalRule
+	if err := json.Unmarshal([]byte(row.Rule), &rule); err != nil {
+		return db.Task{}, fmt.Errorf("task %q has an invalid approval rule: %v", name, err)
+	}
+
+	user := userFromContext(ctx)
+	if user == "" {
+		return db.Task{}, fmt.Errorf("%w: task %q requires an authenticated approver", errApprovalDenied, name)
+	}
+	if rule.DisallowCreator {
+		wf, err := q.Workflow(ctx, id)
+		if err != nil {
+			return db.Task{}, err
+		}
+		if wf.CreatedBy == user {
+			return db.Task{}, fmt.Errorf("%w: the workflow's creator can't approve task %q", errApprovalDenied, name)
+		}
+	}
+	if len(rule.Groups) != 0 && s.cria != nil {
+		isMember, err := s.cria.IsMemberOfAny(ctx, "user:"+user, rule.Groups)
+		if err != nil {
+			return db.Task{}, fmt.Errorf("cria.IsMemberOfAny(user:%s) failed: %w", user, err)
+		}
+		if !isMember {
+			return db.Task{}, fmt.Errorf("%w: task %q must be approved by a member of %v", errApprovalDenied, name, rule.Groups)
+		}
+	}
+
+	var (
+		t       db.Task
+		details string
+	)
+	err = s.db.BeginFunc(ctx, func(tx pgx.Tx) error {
+		q := db.New(tx)
+		// Lock the task, so that concurrent approvals are counted
+		// one after another and the last of them sees all the others.
+		if _, err := q.TaskForUpdate(ctx, db.TaskForUpdateParams{WorkflowID: id, Name: name}); err != nil {
+			return err
+		}
+		now := time.Now()
+		approvals, err := q.TaskApprovals(ctx, db.TaskApprovalsParams{WorkflowID: id, TaskName: name})
+		if err != nil {
+			return err
+		}
+		if slices.ContainsFunc(validApprovals(rule, approvals, now), func(a db.TaskApproval) bool { return a.Approver == user }) {
+			return fmt.Errorf("%w: %s already approved task %q", errApprovalDenied, user, name)
+		}
+		// Approving again after an earlier approval expired renews it.
+		if _, err := q.UpsertTaskApproval(ctx, db.UpsertTaskApprovalParams{
+			WorkflowID: id,
+			TaskName:   name,
+			Approver:   user,
+			CreatedAt:  now,
+		}); err != nil {
+			return err
+		}
+		approvals, err = q.TaskApprovals(ctx, db.TaskApprovalsParams{WorkflowID: id, TaskName: name})
+		if err != nil {
+			return err
+		}
+		valid := validApprovals(rule, approvals, now)
+		details = fmt.Sprintf("%d of %d approvals", len(valid), rule.required())
+		if _, err := q.CreateAuditEvent(ctx, db.CreateAuditEventParams{
+			WorkflowID: id,
+			TaskName:   name,
+			Action:     "approve",
+			Actor:      user,
+			Details:    details,
+			CreatedAt:  now,
+		}); err != nil {
+			return err
+		}
+		if len(valid) < rule.required() {
+			t, err = q.Task(ctx, db.TaskParams{WorkflowID: id, Name: name})
+			return err
+		}
+		t, err = q.ApproveTask(ctx, db.ApproveTaskParams{
+			WorkflowID: id,
+			Name:       name,
+			ApprovedAt: sql.NullTime{Time: now, Valid: true},
+		})
+		return err
+	})
+	if err != nil {
+		return t, err
+	}
+	s.w.l.Logger(id, name).Printf("USER-APPROVED by %s (%s)", user, details)
+	return t, nil
+}
+
+// validApprovals returns the approvals that count toward rule at time now.
+func validApprovals(rule ApprovalRule, approvals []db.TaskApproval, now time.Time) []db.TaskApproval {
+	if rule.Expiry == 0 {
+		return approvals
+	}
+	return slices.DeleteFunc(slices.Clone(approvals), func(a db.TaskApproval) bool {
+		return now.Sub(a.CreatedAt) > rule.Expiry
+	})
+}
+
+// audit records that the current user performed action on workflow id,
+// or one of its tasks if taskName is non-empty. Failures are logged.
+func (s *Server) audit(ctx context.Context, id uuid.UUID, taskName, action, details string) {
+	_, err := db.New(s.db).CreateAuditEvent(ctx, db.CreateAuditEventParams{
+		WorkflowID: id,
+		TaskName:   taskName,
+		Action:     action,
+		Actor:      userFromContext(ctx),
+		Details:    details,
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		log.Printf("audit(%v, %q, %q): %v", id, taskName, action, err)
+	}
+}
+
+// taskApprovalState describes the progress of a task's approval rule,
+// for display.
+type taskApprovalState struct {
+	Rule      ApprovalRule
+	Approvers []string // Approvers whose approvals currently count.
+}
+
+// Required returns the number of approvals the task needs.
+func (st *taskApprovalState) Required() int {
+	return st.Rule.required()
+}
+
+// taskApprovalStates returns the approval state of each task of
+// workflow id that has an ApprovalRule.
+func taskApprovalStates(ctx context.Context, q *db.Queries, id uuid.UUID) (map[string]*taskApprovalState, error) {
+	rules, err := q.TaskApprovalRulesForWorkflow(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	approvals, err := q.TaskApprovalsForWorkflow(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	states := make(map[string]*taskApprovalState)
+	for _, r := range rules {
+		st := &taskApprovalState{}
+		if err := json.Unmarshal([]byte(r.Rule), &st.Rule); err != nil {
+			return nil, fmt.Errorf("task %q has an invalid approval rule: %v", r.TaskName, err)
+		}
+		var mine []db.TaskApproval
+		for _, a := range approvals {
+			if a.TaskName == r.TaskName {
+				mine = append(mine, a)
+			}
+		}
+		for _, a := range validApprovals(st.Rule, mine, now) {
+			st.Approvers = append(st.Approvers, a.Approver)
+		}
+		states[r.TaskName] = st
+	}
+	return states, nil
+}
@@ -0,0 +1,233 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package relui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"golang.org/x/build/internal/access"
+	"golang.org/x/build/internal/criadb"
+	"golang.org/x/build/internal/relui/db"
+	wf "golang.org/x/build/internal/workflow"
+)
+
+func TestValidApprovals(t *testing.T) {
+	now := time.Now()
+	approvals := []db.TaskApproval{
+		{Approver: "old@golang.org", CreatedAt: now.Add(-2 * time.Hour)},
+		{Approver: "new@golang.org", CreatedAt: now.Add(-time.Minute)},
+	}
+	var got []string
+	for _, a := range validApprovals(ApprovalRule{Expiry: time.Hour}, approvals, now) {
+		got = append(got, a.Approver)
+	}
+	if diff := cmp.Diff([]string{"new@golang.org"}, got); diff != "" {
+		t.Errorf("validApprovals with expiry mismatch (-want +got):\n%s", diff)
+	}
+	if got := validApprovals(ApprovalRule{}, approvals, now); len(got) != 2 {
+		t.Errorf("validApprovals without expiry = %v, want all approvals", got)
+	}
+}
+
+func TestApproveTaskWithRule(t *testing.T) {
+	ctx := t.Context()
+	p := testDB(ctx, t)
+	q := db.New(p)
+	memberships := [][2]string{
+		{"user:creator@golang.org", "mdb/release"},
+		{"user:alice@golang.org", "mdb/release"},
+		{"user:bob@golang.org", "mdb/release"},
+	}
+	s := NewServer(p, NewWorker(NewDefinitionHolder(), p, &PGListener{DB: p}), nil, SiteHeader{}, nil, criadb.NewTestDatabase(memberships), nil)
+
+	wfID := createApprovalTestWorkflow(t, ctx, q, "creator@golang.org", `{"groups": ["mdb/release"], "required": 2, "disallow_creator": true}`)
+	as := func(email string) context.Context {
+		return access.ContextWithIAP(ctx, access.IAPFields{Email: email})
+	}
+	s.audit(as("creator@golang.org"), wfID, "", "start", "")
+
+	for _, c := range []struct {
+		user         string
+		wantDenied   bool
+		wantApproved bool
+	}{
+		{user: "creator@golang.org", wantDenied: true},
+		{user: "mallory@golang.org", wantDenied: true},
+		{user: "alice@golang.org"},
+		{user: "alice@golang.org", wantDenied: true},
+		{user: "bob@golang.org", wantApproved: true},
+	} {
+		task, err := s.approveTask(as(c.user), wfID, "publish")
+		if c.wantDenied {
+			if !errors.Is(err, errApprovalDenied) {
+				t.Errorf("approveTask as %s = %v, want errApprovalDenied", c.user, err)
+			}
+			continue
+		} else if err != nil {
+			t.Fatalf("approveTask as %s: %v", c.user, err)
+		}
+		if task.ApprovedAt.Valid != c.wantApproved {
+			t.Errorf("approveTask as %s: task approved = %t, want %t", c.user, task.ApprovedAt.Valid, c.wantApproved)
+		}
+	}
+
+	events, err := q.AuditEventsForWorkflow(ctx, wfID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got [][3]string
+	for _, e := range events {
+		got = append(got, [3]string{e.Actor, e.Action, e.Details})
+	}
+	want := [][3]string{
+		{"creator@golang.org", "start", ""},
+		{"alice@golang.org", "approve", "1 of 2 approvals"},
+		{"bob@golang.org", "approve", "2 of 2 approvals"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("audit events mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestApproveTaskUnknownCreator(t *testing.T) {
+	ctx := t.Context()
+	p := testDB(ctx, t)
+	s := NewServer(p, NewWorker(NewDefinitionHolder(), p, &PGListener{DB: p}), nil, SiteHeader{}, nil, nil, nil)
+
+	// Workflows started before creators were recorded have none.
+	wfID := createApprovalTestWorkflow(t, ctx, db.New(p), "", `{"disallow_creator": true}`)
+	task, err := s.approveTask(access.ContextWithIAP(ctx, access.IAPFields{Email: "alice@golang.org"}), wfID, "publish")
+	if err != nil {
+		t.Fatalf("approveTask of a workflow with an unknown creator: %v", err)
+	}
+	if !task.ApprovedAt.Valid {
+		t.Errorf("approveTask of a workflow with an unknown creator didn't approve the task")
+	}
+}
+
+func TestApproveTaskScheduledWorkflow(t *testing.T) {
+	ctx := t.Context()
+	p := testDB(ctx, t)
+	q := db.New(p)
+	l := &PGListener{DB: p}
+	s := NewServer(p, NewWorker(NewDefinitionHolder(), p, l), nil, SiteHeader{}, nil, nil, nil)
+
+	sched, err := q.CreateSchedule(ctx, db.CreateScheduleParams{WorkflowName: "echo", CreatedBy: "creator@golang.org"})
+	if err != nil {
+		t.Fatalf("CreateSchedule: %v", err)
+	}
+	// Scheduled workflows are started without an authenticated user.
+	wfID := uuid.New()
+	if err := l.WorkflowStarted(ctx, wfID, "echo", wf.New(wf.ACL{}), nil, int(sched.ID)); err != nil {
+		t.Fatalf("WorkflowStarted: %v", err)
+	}
+	w, err := q.Workflow(ctx, wfID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.CreatedBy != "creator@golang.org" {
+		t.Errorf("scheduled workflow CreatedBy = %q, want the creator of its schedule", w.CreatedBy)
+	}
+	if _, err := q.CreateTask(ctx, db.CreateTaskParams{WorkflowID: wfID, Name: "publish", CreatedAt: time.Now(), UpdatedAt: time.Now()}); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	if _, err := q.UpsertTaskApprovalRule(ctx, db.UpsertTaskApprovalRuleParams{WorkflowID: wfID, TaskName: "publish", Rule: `{"disallow_creator": true}`}); err != nil {
+		t.Fatalf("UpsertTaskApprovalRule: %v", err)
+	}
+
+	as := func(email string) context.Context {
+		return access.ContextWithIAP(ctx, access.IAPFields{Email: email})
+	}
+	if _, err := s.approveTask(as("creator@golang.org"), wfID, "publish"); !errors.Is(err, errApprovalDenied) {
+		t.Errorf("approveTask as the schedule's creator = %v, want errApprovalDenied", err)
+	}
+	task, err := s.approveTask(as("alice@golang.org"), wfID, "publish")
+	if err != nil {
+		t.Fatalf("approveTask as alice@golang.org: %v", err)
+	}
+	if !task.ApprovedAt.Valid {
+		t.Errorf("approveTask as alice@golang.org didn't approve the task")
+	}
+}
+
+func TestApproveTaskConcurrently(t *testing.T) {
+	ctx := t.Context()
+	p := testDB(ctx, t)
+	q := db.New(p)
+	s := NewServer(p, NewWorker(NewDefinitionHolder(), p, &PGListener{DB: p}), nil, SiteHeader{}, nil, nil, nil)
+
+	wfID := createApprovalTestWorkflow(t, ctx, q, "creator@golang.org", `{"required": 2}`)
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i, user := range []string{"alice@golang.org", "bob@golang.org"} {
+		wg.Go(func() {
+			_, errs[i] = s.approveTask(access.ContextWithIAP(ctx, access.IAPFields{Email: user}), wfID, "publish")
+		})
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("approveTask: %v", err)
+		}
+	}
+	task, err := q.Task(ctx, db.TaskParams{WorkflowID: wfID, Name: "publish"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !task.ApprovedAt.Valid {
+		t.Errorf("task wasn't approved after two concurrent approvals")
+	}
+}
+
+func TestApproveTaskRenewsExpiredApproval(t *testing.T) {
+	ctx := t.Context()
+	p := testDB(ctx, t)
+	q := db.New(p)
+	s := NewServer(p, NewWorker(NewDefinitionHolder(), p, &PGListener{DB: p}), nil, SiteHeader{}, nil, nil, nil)
+
+	wfID := createApprovalTestWorkflow(t, ctx, q, "creator@golang.org", fmt.Sprintf(`{"required": 2, "expiry": %d}`, time.Hour))
+	if _, err := q.UpsertTaskApproval(ctx, db.UpsertTaskApprovalParams{
+		WorkflowID: wfID,
+		TaskName:   "publish",
+		Approver:   "alice@golang.org",
+		CreatedAt:  time.Now().Add(-2 * time.Hour),
+	}); err != nil {
+		t.Fatalf("UpsertTaskApproval: %v", err)
+	}
+	if _, err := s.approveTask(access.ContextWithIAP(ctx, access.IAPFields{Email: "alice@golang.org"}), wfID, "publish"); err != nil {
+		t.Fatalf("approveTask after the approval expired: %v", err)
+	}
+	approvals, err := q.TaskApprovals(ctx, db.TaskApprovalsParams{WorkflowID: wfID, TaskName: "publish"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(approvals) != 1 || time.Since(approvals[0].CreatedAt) > time.Minute {
+		t.Errorf("approvals = %v, want a renewed approval by alice@golang.org", approvals)
+	}
+}
+
+// createApprovalTestWorkflow creates a workflow started by creator,
+// with a task "publish" that has the approval rule rule, in JSON.
+func createApprovalTestWorkflow(t *testing.T, ctx context.Context, q *db.Queries, creator, rule string) uuid.UUID {
+	t.Helper()
+	wfID := uuid.New()
+	if _, err := q.CreateWorkflow(ctx, db.CreateWorkflowParams{ID: wfID, Name: nullString("echo"), CreatedAt: time.Now(), UpdatedAt: time.Now(), CreatedBy: creator}); err != nil {
+		t.Fatalf("CreateWorkflow: %v", err)
+	}
+	if _, err := q.CreateTask(ctx, db.CreateTaskParams{WorkflowID: wfID, Name: "publish", CreatedAt: time.Now(), UpdatedAt: time.Now()}); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	if _, err := q.UpsertTaskApprovalRule(ctx, db.UpsertTaskApprovalRuleParams{WorkflowID: wfID, TaskName: "publish", Rule: rule}); err != nil {
+		t.Fatalf("UpsertTaskApprovalRule: %v", err)
+	}
+	return wfID
+}
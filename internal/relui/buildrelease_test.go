@@ -217,6 +217,7 @@ func newReleaseTestDeps(t *testing.T, previousTag string, major int, wantVersion
 		BuildBucketClient:        buildBucket,
 		CloudBuildClient:         task.NewFakeCloudBuild(t, fakeGerrit, dockerProject, map[string]map[string]string{dockerTrigger: {"_GO_VERSION": wantVersion[2:]}}),
 		SwarmingClient:           task.NewFakeSwarmingClient(t, fakeGo),
+		LinuxPackages:            true,
 		ApproveAction: func(ctx *workflow.TaskContext) error {
 			switch ctx.TaskName {
 			case "Confirm PRIVATE-track security CLs",
@@ -280,6 +281,10 @@ func testRelease(t *testing.T, prevTag string, major int, wantVersion string, ki
 		case "windows":
 			wantPublishedFiles[wantVersion+"."+t.Name+".zip"] = "archive"
 			wantPublishedFiles[wantVersion+"."+t.Name+".msi"] = "installer"
+		case "linux":
+			wantPublishedFiles[wantVersion+"."+t.Name+".tar.gz"] = "archive"
+			wantPublishedFiles[wantVersion+"."+t.Name+".deb"] = "installer"
+			wantPublishedFiles[wantVersion+"."+t.Name+".rpm"] = "installer"
 		default:
 			wantPublishedFiles[wantVersion+"."+t.Name+".tar.gz"] = "archive"
 		}
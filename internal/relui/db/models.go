@@ -11,6 +11,16 @@ import (
 	"github.com/google/uuid"
 )
 
+type AuditEvent struct {
+	ID         int32
+	WorkflowID uuid.UUID
+	TaskName   string
+	Action     string
+	Actor      string
+	Details    string
+	CreatedAt  time.Time
+}
+
 type Schedule struct {
 	ID              int32
 	WorkflowName    string
@@ -20,6 +30,7 @@ type Schedule struct {
 	IntervalMinutes int32
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
+	CreatedBy       string
 }
 
 type Task struct {
@@ -34,6 +45,20 @@ type Task struct {
 	ReadyForApproval bool
 	Started          bool
 	RetryCount       int32
+	FirstStartedAt   sql.NullTime
+}
+
+type TaskApproval struct {
+	WorkflowID uuid.UUID
+	TaskName   string
+	Approver   string
+	CreatedAt  time.Time
+}
+
+type TaskApprovalRule struct {
+	WorkflowID uuid.UUID
+	TaskName   string
+	Rule       string
 }
 
 type TaskLog struct {
@@ -46,13 +71,16 @@ type TaskLog struct {
 }
 
 type Workflow struct {
-	ID         uuid.UUID
-	Params     sql.NullString
-	Name       sql.NullString
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
-	Finished   bool
-	Output     string
-	Error      string
-	ScheduleID sql.NullInt32
+	ID                    uuid.UUID
+	Params                sql.NullString
+	Name                  sql.NullString
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
+	Finished              bool
+	Output                string
+	Error                 string
+	ScheduleID            sql.NullInt32
+	DefinitionVersion     int32
+	DefinitionFingerprint string
+	CreatedBy             string
 }
@@ -19,7 +19,7 @@ SET approved_at = $3,
     updated_at  = $3
 WHERE workflow_id = $1
   AND name = $2
-RETURNING workflow_id, name, finished, result, error, created_at, updated_at, approved_at, ready_for_approval, started, retry_count
+RETURNING workflow_id, name, finished, result, error, created_at, updated_at, approved_at, ready_for_approval, started, retry_count, first_started_at
 `
 
 type ApproveTaskParams struct {
@@ -43,6 +43,7 @@ func (q *Queries) ApproveTask(ctx context.Context, arg ApproveTaskParams) (Task,
 		&i.ReadyForApproval,
 		&i.Started,
 		&i.RetryCount,
+		&i.FirstStartedAt,
 	)
 	return i, err
 }
@@ -75,9 +76,10 @@ func (q *Queries) ClearWorkflowSchedule(ctx context.Context, dollar_1 int32) ([]
 }
 
 const createSchedule = `-- name: CreateSchedule :one
-INSERT INTO schedules (workflow_name, workflow_params, spec, once, interval_minutes, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id, workflow_name, workflow_params, spec, once, interval_minutes, created_at, updated_at
+INSERT INTO schedules (workflow_name, workflow_params, spec, once, interval_minutes, created_at, updated_at,
+                       created_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, workflow_name, workflow_params, spec, once, interval_minutes, created_at, updated_at, created_by
 `
 
 type CreateScheduleParams struct {
@@ -88,6 +90,7 @@ type CreateScheduleParams struct {
 	IntervalMinutes int32
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
+	CreatedBy       string
 }
 
 func (q *Queries) CreateSchedule(ctx context.Context, arg CreateScheduleParams) (Schedule, error) {
@@ -99,6 +102,7 @@ func (q *Queries) CreateSchedule(ctx context.Context, arg CreateScheduleParams)
 		arg.IntervalMinutes,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.CreatedBy,
 	)
 	var i Schedule
 	err := row.Scan(
@@ -110,6 +114,7 @@ func (q *Queries) CreateSchedule(ctx context.Context, arg CreateScheduleParams)
 		&i.IntervalMinutes,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CreatedBy,
 	)
 	return i, err
 }
@@ -118,7 +123,7 @@ const createTask = `-- name: CreateTask :one
 INSERT INTO tasks (workflow_id, name, finished, result, error, created_at, updated_at, approved_at,
                    ready_for_approval)
 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-RETURNING workflow_id, name, finished, result, error, created_at, updated_at, approved_at, ready_for_approval, started, retry_count
+RETURNING workflow_id, name, finished, result, error, created_at, updated_at, approved_at, ready_for_approval, started, retry_count, first_started_at
 `
 
 type CreateTaskParams struct {
@@ -158,6 +163,7 @@ func (q *Queries) CreateTask(ctx context.Context, arg CreateTaskParams) (Task, e
 		&i.ReadyForApproval,
 		&i.Started,
 		&i.RetryCount,
+		&i.FirstStartedAt,
 	)
 	return i, err
 }
@@ -189,18 +195,22 @@ func (q *Queries) CreateTaskLog(ctx context.Context, arg CreateTaskLogParams) (T
 }
 
 const createWorkflow = `-- name: CreateWorkflow :one
-INSERT INTO workflows (id, params, name, schedule_id, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6)
-RETURNING id, params, name, created_at, updated_at, finished, output, error, schedule_id
+INSERT INTO workflows (id, params, name, schedule_id, created_at, updated_at, definition_version,
+                       definition_fingerprint, created_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id, params, name, created_at, updated_at, finished, output, error, schedule_id, definition_version, definition_fingerprint, created_by
 `
 
 type CreateWorkflowParams struct {
-	ID         uuid.UUID
-	Params     sql.NullString
-	Name       sql.NullString
-	ScheduleID sql.NullInt32
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
+	ID                    uuid.UUID
+	Params                sql.NullString
+	Name                  sql.NullString
+	ScheduleID            sql.NullInt32
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
+	DefinitionVersion     int32
+	DefinitionFingerprint string
+	CreatedBy             string
 }
 
 func (q *Queries) CreateWorkflow(ctx context.Context, arg CreateWorkflowParams) (Workflow, error) {
@@ -211,6 +221,9 @@ func (q *Queries) CreateWorkflow(ctx context.Context, arg CreateWorkflowParams)
 		arg.ScheduleID,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.DefinitionVersion,
+		arg.DefinitionFingerprint,
+		arg.CreatedBy,
 	)
 	var i Workflow
 	err := row.Scan(
@@ -223,6 +236,9 @@ func (q *Queries) CreateWorkflow(ctx context.Context, arg CreateWorkflowParams)
 		&i.Output,
 		&i.Error,
 		&i.ScheduleID,
+		&i.DefinitionVersion,
+		&i.DefinitionFingerprint,
+		&i.CreatedBy,
 	)
 	return i, err
 }
@@ -231,7 +247,7 @@ const deleteSchedule = `-- name: DeleteSchedule :one
 DELETE
 FROM schedules
 WHERE id = $1
-RETURNING id, workflow_name, workflow_params, spec, once, interval_minutes, created_at, updated_at
+RETURNING id, workflow_name, workflow_params, spec, once, interval_minutes, created_at, updated_at, created_by
 `
 
 func (q *Queries) DeleteSchedule(ctx context.Context, id int32) (Schedule, error) {
@@ -246,10 +262,28 @@ func (q *Queries) DeleteSchedule(ctx context.Context, id int32) (Schedule, error
 		&i.IntervalMinutes,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CreatedBy,
 	)
 	return i, err
 }
 
+const deleteTask = `-- name: DeleteTask :exec
+DELETE
+FROM tasks
+WHERE workflow_id = $1
+  AND name = $2
+`
+
+type DeleteTaskParams struct {
+	WorkflowID uuid.UUID
+	Name       string
+}
+
+func (q *Queries) DeleteTask(ctx context.Context, arg DeleteTaskParams) error {
+	_, err := q.db.Exec(ctx, deleteTask, arg.WorkflowID, arg.Name)
+	return err
+}
+
 const failUnfinishedTasks = `-- name: FailUnfinishedTasks :exec
 UPDATE tasks
     SET finished = TRUE,
@@ -269,8 +303,65 @@ func (q *Queries) FailUnfinishedTasks(ctx context.Context, arg FailUnfinishedTas
 	return err
 }
 
+const notifyWorkflowEvent = `-- name: NotifyWorkflowEvent :exec
+SELECT pg_notify('relui_workflow_events', $1::text)
+`
+
+func (q *Queries) NotifyWorkflowEvent(ctx context.Context, payload string) error {
+	_, err := q.db.Exec(ctx, notifyWorkflowEvent, payload)
+	return err
+}
+
+const renameTask = `-- name: RenameTask :exec
+UPDATE tasks
+SET name       = $1::text,
+    updated_at = $2
+WHERE workflow_id = $3
+  AND name = $4
+`
+
+type RenameTaskParams struct {
+	NewName    string
+	UpdatedAt  time.Time
+	WorkflowID uuid.UUID
+	Name       string
+}
+
+func (q *Queries) RenameTask(ctx context.Context, arg RenameTaskParams) error {
+	_, err := q.db.Exec(ctx, renameTask,
+		arg.NewName,
+		arg.UpdatedAt,
+		arg.WorkflowID,
+		arg.Name,
+	)
+	return err
+}
+
+const schedule = `-- name: Schedule :one
+SELECT id, workflow_name, workflow_params, spec, once, interval_minutes, created_at, updated_at, created_by
+FROM schedules
+WHERE id = $1
+`
+
+func (q *Queries) Schedule(ctx context.Context, id int32) (Schedule, error) {
+	row := q.db.QueryRow(ctx, schedule, id)
+	var i Schedule
+	err := row.Scan(
+		&i.ID,
+		&i.WorkflowName,
+		&i.WorkflowParams,
+		&i.Spec,
+		&i.Once,
+		&i.IntervalMinutes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CreatedBy,
+	)
+	return i, err
+}
+
 const schedules = `-- name: Schedules :many
-SELECT id, workflow_name, workflow_params, spec, once, interval_minutes, created_at, updated_at
+SELECT id, workflow_name, workflow_params, spec, once, interval_minutes, created_at, updated_at, created_by
 FROM schedules
 ORDER BY id
 `
@@ -293,6 +384,7 @@ func (q *Queries) Schedules(ctx context.Context) ([]Schedule, error) {
 			&i.IntervalMinutes,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.CreatedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -354,7 +446,7 @@ func (q *Queries) SchedulesLastRun(ctx context.Context) ([]SchedulesLastRunRow,
 }
 
 const task = `-- name: Task :one
-SELECT tasks.workflow_id, tasks.name, tasks.finished, tasks.result, tasks.error, tasks.created_at, tasks.updated_at, tasks.approved_at, tasks.ready_for_approval, tasks.started, tasks.retry_count
+SELECT tasks.workflow_id, tasks.name, tasks.finished, tasks.result, tasks.error, tasks.created_at, tasks.updated_at, tasks.approved_at, tasks.ready_for_approval, tasks.started, tasks.retry_count, tasks.first_started_at
 FROM tasks
 WHERE workflow_id = $1
   AND name = $2
@@ -381,6 +473,7 @@ func (q *Queries) Task(ctx context.Context, arg TaskParams) (Task, error) {
 		&i.ReadyForApproval,
 		&i.Started,
 		&i.RetryCount,
+		&i.FirstStartedAt,
 	)
 	return i, err
 }
@@ -492,13 +585,53 @@ func (q *Queries) TaskLogsForWorkflow(ctx context.Context, workflowID uuid.UUID)
 	return items, nil
 }
 
+const taskLogsForWorkflowAfter = `-- name: TaskLogsForWorkflowAfter :many
+SELECT task_logs.id, task_logs.workflow_id, task_logs.task_name, task_logs.body, task_logs.created_at, task_logs.updated_at
+FROM task_logs
+WHERE workflow_id = $1
+  AND id > $2
+ORDER BY id
+`
+
+type TaskLogsForWorkflowAfterParams struct {
+	WorkflowID uuid.UUID
+	ID         int32
+}
+
+func (q *Queries) TaskLogsForWorkflowAfter(ctx context.Context, arg TaskLogsForWorkflowAfterParams) ([]TaskLog, error) {
+	rows, err := q.db.Query(ctx, taskLogsForWorkflowAfter, arg.WorkflowID, arg.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TaskLog
+	for rows.Next() {
+		var i TaskLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkflowID,
+			&i.TaskName,
+			&i.Body,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const tasks = `-- name: Tasks :many
 WITH most_recent_logs AS (
     SELECT workflow_id, task_name, MAX(updated_at) AS updated_at
     FROM task_logs
     GROUP BY workflow_id, task_name
 )
-SELECT tasks.workflow_id, tasks.name, tasks.finished, tasks.result, tasks.error, tasks.created_at, tasks.updated_at, tasks.approved_at, tasks.ready_for_approval, tasks.started, tasks.retry_count,
+SELECT tasks.workflow_id, tasks.name, tasks.finished, tasks.result, tasks.error, tasks.created_at, tasks.updated_at, tasks.approved_at, tasks.ready_for_approval, tasks.started, tasks.retry_count, tasks.first_started_at,
        GREATEST(most_recent_logs.updated_at, tasks.updated_at)::timestamptz AS most_recent_update
 FROM tasks
 LEFT JOIN most_recent_logs ON tasks.workflow_id = most_recent_logs.workflow_id AND
@@ -518,6 +651,7 @@ type TasksRow struct {
 	ReadyForApproval bool
 	Started          bool
 	RetryCount       int32
+	FirstStartedAt   sql.NullTime
 	MostRecentUpdate time.Time
 }
 
@@ -542,6 +676,7 @@ func (q *Queries) Tasks(ctx context.Context) ([]TasksRow, error) {
 			&i.ReadyForApproval,
 			&i.Started,
 			&i.RetryCount,
+			&i.FirstStartedAt,
 			&i.MostRecentUpdate,
 		); err != nil {
 			return nil, err
@@ -555,7 +690,7 @@ func (q *Queries) Tasks(ctx context.Context) ([]TasksRow, error) {
 }
 
 const tasksForWorkflow = `-- name: TasksForWorkflow :many
-SELECT tasks.workflow_id, tasks.name, tasks.finished, tasks.result, tasks.error, tasks.created_at, tasks.updated_at, tasks.approved_at, tasks.ready_for_approval, tasks.started, tasks.retry_count
+SELECT tasks.workflow_id, tasks.name, tasks.finished, tasks.result, tasks.error, tasks.created_at, tasks.updated_at, tasks.approved_at, tasks.ready_for_approval, tasks.started, tasks.retry_count, tasks.first_started_at
 FROM tasks
 WHERE workflow_id = $1
 ORDER BY created_at
@@ -582,6 +717,7 @@ func (q *Queries) TasksForWorkflow(ctx context.Context, workflowID uuid.UUID) ([
 			&i.ReadyForApproval,
 			&i.Started,
 			&i.RetryCount,
+			&i.FirstStartedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -599,7 +735,7 @@ WITH most_recent_logs AS (
     FROM task_logs
     GROUP BY workflow_id, task_name
 )
-SELECT tasks.workflow_id, tasks.name, tasks.finished, tasks.result, tasks.error, tasks.created_at, tasks.updated_at, tasks.approved_at, tasks.ready_for_approval, tasks.started, tasks.retry_count,
+SELECT tasks.workflow_id, tasks.name, tasks.finished, tasks.result, tasks.error, tasks.created_at, tasks.updated_at, tasks.approved_at, tasks.ready_for_approval, tasks.started, tasks.retry_count, tasks.first_started_at,
        GREATEST(most_recent_logs.updated_at, tasks.updated_at)::timestamptz AS most_recent_update
 FROM tasks
 LEFT JOIN most_recent_logs ON tasks.workflow_id = most_recent_logs.workflow_id AND
@@ -620,6 +756,7 @@ type TasksForWorkflowSortedRow struct {
 	ReadyForApproval bool
 	Started          bool
 	RetryCount       int32
+	FirstStartedAt   sql.NullTime
 	MostRecentUpdate time.Time
 }
 
@@ -644,6 +781,7 @@ func (q *Queries) TasksForWorkflowSorted(ctx context.Context, workflowID uuid.UU
 			&i.ReadyForApproval,
 			&i.Started,
 			&i.RetryCount,
+			&i.FirstStartedAt,
 			&i.MostRecentUpdate,
 		); err != nil {
 			return nil, err
@@ -657,7 +795,7 @@ func (q *Queries) TasksForWorkflowSorted(ctx context.Context, workflowID uuid.UU
 }
 
 const unfinishedWorkflows = `-- name: UnfinishedWorkflows :many
-SELECT workflows.id, workflows.params, workflows.name, workflows.created_at, workflows.updated_at, workflows.finished, workflows.output, workflows.error, workflows.schedule_id
+SELECT workflows.id, workflows.params, workflows.name, workflows.created_at, workflows.updated_at, workflows.finished, workflows.output, workflows.error, workflows.schedule_id, workflows.definition_version, workflows.definition_fingerprint, workflows.created_by
 FROM workflows
 WHERE workflows.finished = FALSE
 `
@@ -681,6 +819,9 @@ func (q *Queries) UnfinishedWorkflows(ctx context.Context) ([]Workflow, error) {
 			&i.Output,
 			&i.Error,
 			&i.ScheduleID,
+			&i.DefinitionVersion,
+			&i.DefinitionFingerprint,
+			&i.CreatedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -697,7 +838,7 @@ UPDATE tasks
 SET ready_for_approval = $3
 WHERE workflow_id = $1
   AND name = $2
-RETURNING workflow_id, name, finished, result, error, created_at, updated_at, approved_at, ready_for_approval, started, retry_count
+RETURNING workflow_id, name, finished, result, error, created_at, updated_at, approved_at, ready_for_approval, started, retry_count, first_started_at
 `
 
 type UpdateTaskReadyForApprovalParams struct {
@@ -721,36 +862,64 @@ func (q *Queries) UpdateTaskReadyForApproval(ctx context.Context, arg UpdateTask
 		&i.ReadyForApproval,
 		&i.Started,
 		&i.RetryCount,
+		&i.FirstStartedAt,
 	)
 	return i, err
 }
 
+const updateWorkflowDefinition = `-- name: UpdateWorkflowDefinition :exec
+UPDATE workflows
+SET definition_version     = $2,
+    definition_fingerprint = $3,
+    updated_at             = $4
+WHERE workflows.id = $1
+`
+
+type UpdateWorkflowDefinitionParams struct {
+	ID                    uuid.UUID
+	DefinitionVersion     int32
+	DefinitionFingerprint string
+	UpdatedAt             time.Time
+}
+
+func (q *Queries) UpdateWorkflowDefinition(ctx context.Context, arg UpdateWorkflowDefinitionParams) error {
+	_, err := q.db.Exec(ctx, updateWorkflowDefinition,
+		arg.ID,
+		arg.DefinitionVersion,
+		arg.DefinitionFingerprint,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
 const upsertTask = `-- name: UpsertTask :one
 INSERT INTO tasks (workflow_id, name, started, finished, result, error, created_at, updated_at,
-                   retry_count)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+                   retry_count, first_started_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 ON CONFLICT (workflow_id, name) DO UPDATE
-    SET workflow_id = excluded.workflow_id,
-        name        = excluded.name,
-        started     = excluded.started,
-        finished    = excluded.finished,
-        result      = excluded.result,
-        error       = excluded.error,
-        updated_at  = excluded.updated_at,
-        retry_count = excluded.retry_count
-RETURNING workflow_id, name, finished, result, error, created_at, updated_at, approved_at, ready_for_approval, started, retry_count
+    SET workflow_id      = excluded.workflow_id,
+        name             = excluded.name,
+        started          = excluded.started,
+        finished         = excluded.finished,
+        result           = excluded.result,
+        error            = excluded.error,
+        updated_at       = excluded.updated_at,
+        retry_count      = excluded.retry_count,
+        first_started_at = excluded.first_started_at
+RETURNING workflow_id, name, finished, result, error, created_at, updated_at, approved_at, ready_for_approval, started, retry_count, first_started_at
 `
 
 type UpsertTaskParams struct {
-	WorkflowID uuid.UUID
-	Name       string
-	Started    bool
-	Finished   bool
-	Result     sql.NullString
-	Error      sql.NullString
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
-	RetryCount int32
+	WorkflowID     uuid.UUID
+	Name           string
+	Started        bool
+	Finished       bool
+	Result         sql.NullString
+	Error          sql.NullString
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	RetryCount     int32
+	FirstStartedAt sql.NullTime
 }
 
 func (q *Queries) UpsertTask(ctx context.Context, arg UpsertTaskParams) (Task, error) {
@@ -764,6 +933,7 @@ func (q *Queries) UpsertTask(ctx context.Context, arg UpsertTaskParams) (Task, e
 		arg.CreatedAt,
 		arg.UpdatedAt,
 		arg.RetryCount,
+		arg.FirstStartedAt,
 	)
 	var i Task
 	err := row.Scan(
@@ -778,12 +948,13 @@ func (q *Queries) UpsertTask(ctx context.Context, arg UpsertTaskParams) (Task, e
 		&i.ReadyForApproval,
 		&i.Started,
 		&i.RetryCount,
+		&i.FirstStartedAt,
 	)
 	return i, err
 }
 
 const workflow = `-- name: Workflow :one
-SELECT id, params, name, created_at, updated_at, finished, output, error, schedule_id
+SELECT id, params, name, created_at, updated_at, finished, output, error, schedule_id, definition_version, definition_fingerprint, created_by
 FROM workflows
 WHERE id = $1
 `
@@ -801,6 +972,9 @@ func (q *Queries) Workflow(ctx context.Context, id uuid.UUID) (Workflow, error)
 		&i.Output,
 		&i.Error,
 		&i.ScheduleID,
+		&i.DefinitionVersion,
+		&i.DefinitionFingerprint,
+		&i.CreatedBy,
 	)
 	return i, err
 }
@@ -824,7 +998,7 @@ SET finished   = $2,
     error      = $4,
     updated_at = $5
 WHERE workflows.id = $1
-RETURNING id, params, name, created_at, updated_at, finished, output, error, schedule_id
+RETURNING id, params, name, created_at, updated_at, finished, output, error, schedule_id, definition_version, definition_fingerprint, created_by
 `
 
 type WorkflowFinishedParams struct {
@@ -854,6 +1028,9 @@ func (q *Queries) WorkflowFinished(ctx context.Context, arg WorkflowFinishedPara
 		&i.Output,
 		&i.Error,
 		&i.ScheduleID,
+		&i.DefinitionVersion,
+		&i.DefinitionFingerprint,
+		&i.CreatedBy,
 	)
 	return i, err
 }
@@ -917,7 +1094,7 @@ func (q *Queries) WorkflowSidebar(ctx context.Context) ([]WorkflowSidebarRow, er
 
 const workflows = `-- name: Workflows :many
 
-SELECT id, params, name, created_at, updated_at, finished, output, error, schedule_id
+SELECT id, params, name, created_at, updated_at, finished, output, error, schedule_id, definition_version, definition_fingerprint, created_by
 FROM workflows
 ORDER BY created_at DESC
 `
@@ -944,6 +1121,9 @@ func (q *Queries) Workflows(ctx context.Context) ([]Workflow, error) {
 			&i.Output,
 			&i.Error,
 			&i.ScheduleID,
+			&i.DefinitionVersion,
+			&i.DefinitionFingerprint,
+			&i.CreatedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -956,7 +1136,7 @@ func (q *Queries) Workflows(ctx context.Context) ([]Workflow, error) {
 }
 
 const workflowsByName = `-- name: WorkflowsByName :many
-SELECT id, params, name, created_at, updated_at, finished, output, error, schedule_id
+SELECT id, params, name, created_at, updated_at, finished, output, error, schedule_id, definition_version, definition_fingerprint, created_by
 FROM workflows
 WHERE name = $1
 ORDER BY created_at DESC
@@ -981,6 +1161,9 @@ func (q *Queries) WorkflowsByName(ctx context.Context, name sql.NullString) ([]W
 			&i.Output,
 			&i.Error,
 			&i.ScheduleID,
+			&i.DefinitionVersion,
+			&i.DefinitionFingerprint,
+			&i.CreatedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -993,7 +1176,7 @@ func (q *Queries) WorkflowsByName(ctx context.Context, name sql.NullString) ([]W
 }
 
 const workflowsByNames = `-- name: WorkflowsByNames :many
-SELECT id, params, name, created_at, updated_at, finished, output, error, schedule_id
+SELECT id, params, name, created_at, updated_at, finished, output, error, schedule_id, definition_version, definition_fingerprint, created_by
 FROM workflows
 WHERE name = ANY($1::text[])
 ORDER BY created_at DESC
@@ -1018,6 +1201,9 @@ func (q *Queries) WorkflowsByNames(ctx context.Context, names []string) ([]Workf
 			&i.Output,
 			&i.Error,
 			&i.ScheduleID,
+			&i.DefinitionVersion,
+			&i.DefinitionFingerprint,
+			&i.CreatedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,324 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package relui
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"golang.org/x/build/internal/relui/db"
+)
+
+// eventsChannel is the Postgres notification channel on which relui
+// instances announce changes to workflows, such as a task changing state
+// or logging a line. It must match the NotifyWorkflowEvent query.
+const eventsChannel = "relui_workflow_events"
+
+// A workflowEvent is the payload of a notification on eventsChannel.
+// It only identifies what changed; subscribers read the details
+// from the database.
+type workflowEvent struct {
+	WorkflowID uuid.UUID `json:"workflow_id"`
+	Task       string    `json:"task,omitempty"`
+}
+
+// notifyWorkflowEvent announces a change to workflow id on eventsChannel.
+// It's called once the change has been written, outside of the transaction
+// that wrote it. Notifying is best-effort: a failure is logged, and doesn't
+// fail the change, since subscribers only miss a live update of the page.
+func notifyWorkflowEvent(ctx context.Context, q *db.Queries, id uuid.UUID, taskName string) {
+	payload, err := json.Marshal(workflowEvent{WorkflowID: id, Task: taskName})
+	if err == nil {
+		err = q.NotifyWorkflowEvent(ctx, string(payload))
+	}
+	if err != nil {
+		log.Printf("notifyWorkflowEvent(%v, %q) = %v", id, taskName, err)
+	}
+}
+
+// An EventBroker relays notifications of changes to workflows, made by
+// this or any other relui instance sharing the database, to subscribers
+// such as browsers watching a workflow's page.
+type EventBroker struct {
+	mu   sync.Mutex
+	subs map[uuid.UUID]map[*Subscription]bool
+}
+
+// NewEventBroker returns an EventBroker with no subscribers.
+// Call Run to start receiving notifications.
+func NewEventBroker() *EventBroker {
+	return &EventBroker{subs: make(map[uuid.UUID]map[*Subscription]bool)}
+}
+
+// A Subscription receives notifications of changes to a workflow.
+type Subscription struct {
+	// C receives a value when the workflow changes. Notifications
+	// that arrive while a previous one is still unread are coalesced.
+	C <-chan struct{}
+
+	c     chan struct{}
+	mu    sync.Mutex
+	tasks map[string]bool // tasks changed since the last call to Tasks
+}
+
+// Tasks returns the names of the tasks of the workflow that changed
+// since it was last called.
+func (sub *Subscription) Tasks() []string {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	names := slices.Collect(maps.Keys(sub.tasks))
+	clear(sub.tasks)
+	return names
+}
+
+// Subscribe returns a subscription to the changes to workflow id,
+// and a function to cancel it.
+func (b *EventBroker) Subscribe(id uuid.UUID) (*Subscription, func()) {
+	c := make(chan struct{}, 1)
+	sub := &Subscription{C: c, c: c, tasks: make(map[string]bool)}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subs[id] == nil {
+		b.subs[id] = make(map[*Subscription]bool)
+	}
+	b.subs[id][sub] = true
+	return sub, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subs[id], sub)
+		if len(b.subs[id]) == 0 {
+			delete(b.subs, id)
+		}
+	}
+}
+
+// publish notifies the subscribers to the workflow of ev.
+func (b *EventBroker) publish(ev workflowEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subs[ev.WorkflowID] {
+		if ev.Task != "" {
+			sub.mu.Lock()
+			sub.tasks[ev.Task] = true
+			sub.mu.Unlock()
+		}
+		select {
+		case sub.c <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Run listens for notifications on eventsChannel and relays them to
+// subscribers until ctx is done. If the connection is lost, it reconnects.
+func (b *EventBroker) Run(ctx context.Context, p *pgxpool.Pool) {
+	for ctx.Err() == nil {
+		if err := b.listen(ctx, p); err != nil && ctx.Err() == nil {
+			log.Printf("EventBroker: %v", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(5 * time.Second):
+			}
+		}
+	}
+}
+
+func (b *EventBroker) listen(ctx context.Context, p *pgxpool.Pool) error {
+	conn, err := p.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+	if _, err := conn.Exec(ctx, "LISTEN "+eventsChannel); err != nil {
+		return err
+	}
+	// A connection with an active LISTEN mustn't go back to the pool.
+	defer conn.Conn().Close(context.Background())
+	for {
+		n, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("WaitForNotification: %w", err)
+		}
+		var ev workflowEvent
+		if err := json.Unmarshal([]byte(n.Payload), &ev); err != nil {
+			log.Printf("EventBroker: bad notification %q: %v", n.Payload, err)
+			continue
+		}
+		b.publish(ev)
+	}
+}
+
+// watchPollInterval is how often watchWorkflow checks for changes
+// when no EventBroker is configured, and watchFallbackInterval is how often
+// it checks when one is, in case a notification was missed.
+var (
+	watchPollInterval     = 2 * time.Second
+	watchFallbackInterval = 30 * time.Second
+)
+
+// watchWorkflow calls emit with the current state of each task of
+// workflow id and each log after afterLog, then again for each change,
+// until the workflow finishes or ctx is done. Its final event describes
+// the finished workflow.
+//
+// After the first read, only the logs after the last one emitted are
+// read. When notified of changes by an EventBroker, only the tasks
+// named by the notifications are read; otherwise all of them are.
+func (s *Server) watchWorkflow(ctx context.Context, id uuid.UUID, afterLog int32, emit func(apiWatchEvent) error) error {
+	var (
+		sub     *Subscription
+		changed <-chan struct{}
+	)
+	interval := watchPollInterval
+	if s.events != nil {
+		// Subscribe before the first read, so no change is missed.
+		var unsubscribe func()
+		sub, unsubscribe = s.events.Subscribe(id)
+		defer unsubscribe()
+		changed = sub.C
+		interval = watchFallbackInterval
+	}
+	q := db.New(s.db)
+	sent := make(map[string][]byte) // Task name → last sent state.
+	emitTask := func(t db.Task) error {
+		at := newAPITask(t)
+		state, err := json.Marshal(at)
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(state, sent[t.Name]) {
+			return nil
+		}
+		sent[t.Name] = state
+		return emit(apiWatchEvent{Task: &at})
+	}
+	readAll := true
+	var notified []string // Tasks named by notifications, if not reading all.
+	for {
+		wf, err := q.Workflow(ctx, id)
+		if err != nil {
+			return err
+		}
+		if readAll {
+			tasks, err := q.TasksForWorkflow(ctx, id)
+			if err != nil {
+				return err
+			}
+			for _, t := range tasks {
+				if err := emitTask(t); err != nil {
+					return err
+				}
+			}
+		}
+		for _, name := range notified {
+			t, err := q.Task(ctx, db.TaskParams{WorkflowID: id, Name: name})
+			if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+				continue // Renamed by a migration, say.
+			} else if err != nil {
+				return err
+			}
+			if err := emitTask(t); err != nil {
+				return err
+			}
+		}
+		logs, err := q.TaskLogsForWorkflowAfter(ctx, db.TaskLogsForWorkflowAfterParams{WorkflowID: id, ID: afterLog})
+		if err != nil {
+			return err
+		}
+		for _, l := range logs {
+			afterLog = l.ID
+			if err := emit(apiWatchEvent{Log: ptr(newAPILog(l))}); err != nil {
+				return err
+			}
+		}
+		if wf.Finished || wf.Error != "" {
+			return emit(apiWatchEvent{Workflow: ptr(newAPIWorkflow(wf))})
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+			readAll, notified = false, sub.Tasks()
+		case <-time.After(interval):
+			// Some changes, such as approvals, aren't notified.
+			readAll, notified = true, nil
+		}
+	}
+}
+
+// workflowEventsHandler streams changes to a workflow as server-sent
+// events named "task", "log" and "workflow", whose data is the JSON
+// encoding of an apiTask, apiLog or apiWorkflow respectively. Log events
+// carry their log ID as the event ID, so that reconnecting clients only
+// receive newer logs.
+func (s *Server) workflowEventsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+	if _, err := db.New(s.db).Workflow(r.Context(), id); errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	} else if err != nil {
+		log.Printf("workflowEventsHandler: Workflow(%v): %v", id, err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	var afterLog int32
+	if v := r.Header.Get("Last-Event-ID"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		afterLog = int32(n)
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	rc := http.NewResponseController(w)
+	err = s.watchWorkflow(r.Context(), id, afterLog, func(ev apiWatchEvent) error {
+		var name, eventID string
+		var data any
+		switch {
+		case ev.Task != nil:
+			name, data = "task", ev.Task
+		case ev.Log != nil:
+			name, data, eventID = "log", ev.Log, strconv.Itoa(int(ev.Log.ID))
+		case ev.Workflow != nil:
+			name, data = "workflow", ev.Workflow
+		}
+		b, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		if eventID != "" {
+			fmt.Fprintf(w, "id: %s\n", eventID)
+		}
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, b); err != nil {
+			return err
+		}
+		rc.Flush()
+		return nil
+	})
+	if err != nil && r.Context().Err() == nil {
+		log.Printf("workflowEventsHandler(%v): %v", id, err)
+	}
+}
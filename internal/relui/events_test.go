@@ -0,0 +1,156 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package relui
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"golang.org/x/build/internal/relui/db"
+)
+
+func TestEventBroker(t *testing.T) {
+	b := NewEventBroker()
+	id, other := uuid.New(), uuid.New()
+	sub, unsubscribe := b.Subscribe(id)
+
+	b.publish(workflowEvent{WorkflowID: other, Task: "a"})
+	select {
+	case <-sub.C:
+		t.Fatalf("received notification for another workflow")
+	default:
+	}
+
+	// Unread notifications are coalesced, and publish never blocks.
+	b.publish(workflowEvent{WorkflowID: id, Task: "a"})
+	b.publish(workflowEvent{WorkflowID: id, Task: "b"})
+	b.publish(workflowEvent{WorkflowID: id})
+	select {
+	case <-sub.C:
+	default:
+		t.Fatalf("no notification after publish")
+	}
+	select {
+	case <-sub.C:
+		t.Fatalf("received a second notification, want them coalesced")
+	default:
+	}
+	if diff := cmp.Diff([]string{"a", "b"}, slices.Sorted(slices.Values(sub.Tasks()))); diff != "" {
+		t.Errorf("Tasks mismatch (-want +got):\n%s", diff)
+	}
+	if got := sub.Tasks(); len(got) != 0 {
+		t.Errorf("Tasks = %q after they were read, want none", got)
+	}
+
+	unsubscribe()
+	b.publish(workflowEvent{WorkflowID: id})
+	select {
+	case <-sub.C:
+		t.Fatalf("received notification after unsubscribing")
+	default:
+	}
+	if len(b.subs) != 0 {
+		t.Errorf("b.subs = %v after unsubscribing, want empty", b.subs)
+	}
+}
+
+func TestEventBrokerNotify(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	p := testDB(ctx, t)
+	b := NewEventBroker()
+	go b.Run(ctx, p)
+
+	id := uuid.New()
+	sub, unsubscribe := b.Subscribe(id)
+	defer unsubscribe()
+	// Notifications sent before the broker is listening are lost,
+	// so keep sending until one arrives.
+	for {
+		notifyWorkflowEvent(ctx, db.New(p), id, "task")
+		select {
+		case <-sub.C:
+			return
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+func TestWorkflowEventsHandler(t *testing.T) {
+	ctx := t.Context()
+	p := testDB(ctx, t)
+	q := db.New(p)
+	hourAgo := time.Now().Add(-1 * time.Hour).UTC().Truncate(time.Second)
+	wfID := uuid.New()
+	if _, err := q.CreateWorkflow(ctx, db.CreateWorkflowParams{
+		ID:        wfID,
+		Params:    nullString(`{"farewell": "bye", "greeting": "hello"}`),
+		Name:      nullString("echo"),
+		CreatedAt: hourAgo,
+		UpdatedAt: hourAgo,
+	}); err != nil {
+		t.Fatalf("CreateWorkflow: %v", err)
+	}
+	if _, err := q.CreateTask(ctx, db.CreateTaskParams{
+		WorkflowID: wfID,
+		Name:       "greeting",
+		Finished:   true,
+		Result:     nullString(`"hello"`),
+		CreatedAt:  hourAgo,
+		UpdatedAt:  hourAgo,
+	}); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	var logIDs []int32
+	for _, body := range []string{"first", "second"} {
+		l, err := q.CreateTaskLog(ctx, db.CreateTaskLogParams{WorkflowID: wfID, TaskName: "greeting", Body: body})
+		if err != nil {
+			t.Fatalf("CreateTaskLog: %v", err)
+		}
+		logIDs = append(logIDs, l.ID)
+	}
+	if _, err := q.WorkflowFinished(ctx, db.WorkflowFinishedParams{ID: wfID, Finished: true, Output: "{}", UpdatedAt: hourAgo}); err != nil {
+		t.Fatalf("WorkflowFinished: %v", err)
+	}
+
+	s := NewServer(p, NewWorker(NewDefinitionHolder(), p, &PGListener{DB: p}), nil, SiteHeader{}, nil, nil, nil)
+	events := func(lastEventID string) []string {
+		req := httptest.NewRequest(http.MethodGet, "/workflows/"+wfID.String()+"/events", nil)
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		rec := httptest.NewRecorder()
+		s.m.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+		}
+		if got := rec.Header().Get("Content-Type"); got != "text/event-stream" {
+			t.Errorf("Content-Type = %q, want text/event-stream", got)
+		}
+		var names []string
+		for _, line := range strings.Split(rec.Body.String(), "\n") {
+			if name, ok := strings.CutPrefix(line, "event: "); ok {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+
+	// The workflow has finished, so each stream ends after catching up.
+	if diff := cmp.Diff([]string{"task", "log", "log", "workflow"}, events("")); diff != "" {
+		t.Errorf("events mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"task", "log", "workflow"}, events(strconv.Itoa(int(logIDs[0])))); diff != "" {
+		t.Errorf("events after reconnecting mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -60,7 +60,8 @@ func (l *PGListener) WorkflowStalled(workflowID uuid.UUID) error {
 
 // TaskStateChanged is called whenever a task is updated by the
 // workflow. The workflow.TaskState is persisted as a db.Task,
-// creating or updating a row as necessary.
+// creating or updating a row as necessary, and the change is
+// announced on eventsChannel.
 func (l *PGListener) TaskStateChanged(workflowID uuid.UUID, taskName string, state *workflow.TaskState) error {
 	log.Printf("TaskStateChanged(%q, %q, %#v)", workflowID, taskName, state)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -82,30 +83,50 @@ func (l *PGListener) TaskStateChanged(workflowID uuid.UUID, taskName string, sta
 			CreatedAt:  updated,
 			UpdatedAt:  updated,
 			RetryCount: int32(state.RetryCount),
+			FirstStartedAt: sql.NullTime{
+				Time:  state.FirstStarted,
+				Valid: !state.FirstStarted.IsZero(),
+			},
 		})
 		return err
 	})
 	if err != nil {
 		log.Printf("TaskStateChanged(%q, %q, %#v) = %v", workflowID, taskName, state, err)
+		return err
 	}
-	return err
+	notifyWorkflowEvent(ctx, db.New(l.DB), workflowID, taskName)
+	return nil
 }
 
-// WorkflowStarted persists a new workflow execution in the database.
-func (l *PGListener) WorkflowStarted(ctx context.Context, workflowID uuid.UUID, name string, params map[string]any, scheduleID int) error {
+// WorkflowStarted persists a new workflow execution in the database,
+// along with the version and fingerprint of its definition d, and its
+// creator: the authenticated user of ctx, or for a scheduled workflow,
+// the creator of the schedule.
+func (l *PGListener) WorkflowStarted(ctx context.Context, workflowID uuid.UUID, name string, d *workflow.Definition, params map[string]any, scheduleID int) error {
 	q := db.New(l.DB)
 	m, err := json.Marshal(params)
 	if err != nil {
 		return err
 	}
+	createdBy := userFromContext(ctx)
+	if createdBy == "" && scheduleID != 0 {
+		sched, err := q.Schedule(ctx, int32(scheduleID))
+		if err != nil {
+			return err
+		}
+		createdBy = sched.CreatedBy
+	}
 	updated := time.Now()
 	wfp := db.CreateWorkflowParams{
-		ID:         workflowID,
-		Name:       sql.NullString{String: name, Valid: true},
-		Params:     sql.NullString{String: string(m), Valid: len(m) > 0},
-		ScheduleID: sql.NullInt32{Int32: int32(scheduleID), Valid: scheduleID != 0},
-		CreatedAt:  updated,
-		UpdatedAt:  updated,
+		ID:                    workflowID,
+		Name:                  sql.NullString{String: name, Valid: true},
+		Params:                sql.NullString{String: string(m), Valid: len(m) > 0},
+		ScheduleID:            sql.NullInt32{Int32: int32(scheduleID), Valid: scheduleID != 0},
+		CreatedAt:             updated,
+		UpdatedAt:             updated,
+		DefinitionVersion:     int32(d.Version()),
+		DefinitionFingerprint: d.Fingerprint(),
+		CreatedBy:             createdBy,
 	}
 	_, err = q.CreateWorkflow(ctx, wfp)
 	return err
@@ -134,8 +155,11 @@ func (l *PGListener) WorkflowFinished(ctx context.Context, workflowID uuid.UUID,
 	if workflowErr != nil {
 		wp.Error = workflowErr.Error()
 	}
-	_, err = q.WorkflowFinished(ctx, wp)
-	return err
+	if _, err := q.WorkflowFinished(ctx, wp); err != nil {
+		return err
+	}
+	notifyWorkflowEvent(ctx, q, workflowID, "")
+	return nil
 }
 
 func (l *PGListener) template(name string) *template.Template {
@@ -158,7 +182,8 @@ func (l *PGListener) Logger(workflowID uuid.UUID, taskName string) workflow.Logg
 	}
 }
 
-// postgresLogger logs task output to the database. It implements workflow.Logger.
+// postgresLogger logs task output to the database and announces each
+// line on eventsChannel. It implements workflow.Logger.
 type postgresLogger struct {
 	db         db.PGDBTX
 	workflowID uuid.UUID
@@ -182,7 +207,9 @@ func (l *postgresLogger) Printf(format string, v ...any) {
 	})
 	if err != nil {
 		log.Printf("l.Printf(%q, %v) = %v", format, v, err)
+		return
 	}
+	notifyWorkflowEvent(ctx, db.New(l.db), l.workflowID, l.taskName)
 }
 
 func LogOnlyMailer(header task.MailHeader, content task.MailContent) error {
@@ -0,0 +1,7 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+DROP TABLE task_approvals;
+DROP TABLE task_approval_rules;
+DROP TABLE audit_events;
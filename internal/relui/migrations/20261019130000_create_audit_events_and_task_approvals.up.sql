@@ -0,0 +1,32 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+CREATE TABLE audit_events (
+  id SERIAL PRIMARY KEY,
+  workflow_id uuid NOT NULL REFERENCES workflows (id),
+  task_name text NOT NULL DEFAULT '',
+  action text NOT NULL,
+  actor text NOT NULL,
+  details text NOT NULL DEFAULT '',
+  created_at timestamp with time zone NOT NULL default current_timestamp
+);
+
+CREATE INDEX audit_events_workflow_id_idx ON audit_events (workflow_id);
+
+CREATE TABLE task_approval_rules (
+  workflow_id uuid NOT NULL,
+  task_name text NOT NULL,
+  rule jsonb NOT NULL,
+  PRIMARY KEY (workflow_id, task_name),
+  FOREIGN KEY (workflow_id, task_name) REFERENCES tasks (workflow_id, name)
+);
+
+CREATE TABLE task_approvals (
+  workflow_id uuid NOT NULL,
+  task_name text NOT NULL,
+  approver text NOT NULL,
+  created_at timestamp with time zone NOT NULL default current_timestamp,
+  PRIMARY KEY (workflow_id, task_name, approver),
+  FOREIGN KEY (workflow_id, task_name) REFERENCES tasks (workflow_id, name)
+);
@@ -0,0 +1,7 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+ALTER TABLE workflows
+    ADD COLUMN definition_version     integer NOT NULL DEFAULT 0,
+    ADD COLUMN definition_fingerprint text    NOT NULL DEFAULT '';
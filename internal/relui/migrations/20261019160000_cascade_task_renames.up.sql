@@ -0,0 +1,24 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+-- Migrating a workflow to a new version of its definition renames and
+-- removes tasks. Carry their logs and approvals along.
+
+ALTER TABLE task_logs
+    DROP CONSTRAINT task_logs_workflow_id_task_name_fkey,
+    ADD CONSTRAINT task_logs_workflow_id_task_name_fkey
+        FOREIGN KEY (workflow_id, task_name) REFERENCES tasks (workflow_id, name)
+            ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TABLE task_approval_rules
+    DROP CONSTRAINT task_approval_rules_workflow_id_task_name_fkey,
+    ADD CONSTRAINT task_approval_rules_workflow_id_task_name_fkey
+        FOREIGN KEY (workflow_id, task_name) REFERENCES tasks (workflow_id, name)
+            ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TABLE task_approvals
+    DROP CONSTRAINT task_approvals_workflow_id_task_name_fkey,
+    ADD CONSTRAINT task_approvals_workflow_id_task_name_fkey
+        FOREIGN KEY (workflow_id, task_name) REFERENCES tasks (workflow_id, name)
+            ON UPDATE CASCADE ON DELETE CASCADE;
@@ -0,0 +1,57 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+-- name: CreateAuditEvent :one
+INSERT INTO audit_events (workflow_id, task_name, action, actor, details, created_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: AuditEventsForWorkflow :many
+SELECT *
+FROM audit_events
+WHERE workflow_id = $1
+ORDER BY created_at, id;
+
+-- name: UpsertTaskApprovalRule :one
+INSERT INTO task_approval_rules (workflow_id, task_name, rule)
+VALUES ($1, $2, $3)
+ON CONFLICT (workflow_id, task_name) DO UPDATE
+    SET rule = excluded.rule
+RETURNING *;
+
+-- name: TaskApprovalRule :one
+SELECT *
+FROM task_approval_rules
+WHERE workflow_id = $1 AND task_name = $2;
+
+-- name: TaskApprovalRulesForWorkflow :many
+SELECT *
+FROM task_approval_rules
+WHERE workflow_id = $1;
+
+-- name: UpsertTaskApproval :one
+INSERT INTO task_approvals (workflow_id, task_name, approver, created_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (workflow_id, task_name, approver) DO UPDATE
+    SET created_at = excluded.created_at
+RETURNING *;
+
+-- name: TaskForUpdate :one
+SELECT tasks.*
+FROM tasks
+WHERE workflow_id = $1
+  AND name = $2
+FOR UPDATE;
+
+-- name: TaskApprovals :many
+SELECT *
+FROM task_approvals
+WHERE workflow_id = $1 AND task_name = $2
+ORDER BY created_at;
+
+-- name: TaskApprovalsForWorkflow :many
+SELECT *
+FROM task_approvals
+WHERE workflow_id = $1
+ORDER BY created_at;
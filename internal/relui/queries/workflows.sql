@@ -39,8 +39,9 @@ GROUP BY name
 ORDER BY name;
 
 -- name: CreateWorkflow :one
-INSERT INTO workflows (id, params, name, schedule_id, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6)
+INSERT INTO workflows (id, params, name, schedule_id, created_at, updated_at, definition_version,
+                       definition_fingerprint, created_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 RETURNING *;
 
 -- name: CreateTask :one
@@ -51,17 +52,18 @@ RETURNING *;
 
 -- name: UpsertTask :one
 INSERT INTO tasks (workflow_id, name, started, finished, result, error, created_at, updated_at,
-                   retry_count)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+                   retry_count, first_started_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 ON CONFLICT (workflow_id, name) DO UPDATE
-    SET workflow_id = excluded.workflow_id,
-        name        = excluded.name,
-        started     = excluded.started,
-        finished    = excluded.finished,
-        result      = excluded.result,
-        error       = excluded.error,
-        updated_at  = excluded.updated_at,
-        retry_count = excluded.retry_count
+    SET workflow_id      = excluded.workflow_id,
+        name             = excluded.name,
+        started          = excluded.started,
+        finished         = excluded.finished,
+        result           = excluded.result,
+        error            = excluded.error,
+        updated_at       = excluded.updated_at,
+        retry_count      = excluded.retry_count,
+        first_started_at = excluded.first_started_at
 RETURNING *;
 
 -- name: Tasks :many
@@ -122,6 +124,13 @@ FROM task_logs
 WHERE workflow_id = $1
 ORDER BY created_at;
 
+-- name: TaskLogsForWorkflowAfter :many
+SELECT task_logs.*
+FROM task_logs
+WHERE workflow_id = $1
+  AND id > $2
+ORDER BY id;
+
 -- name: TaskLogs :many
 SELECT task_logs.*
 FROM task_logs
@@ -140,6 +149,26 @@ UPDATE tasks
     updated_at   = $2
 WHERE workflow_id = $1 and started and not finished;
 
+-- name: RenameTask :exec
+UPDATE tasks
+SET name       = @new_name::text,
+    updated_at = @updated_at
+WHERE workflow_id = @workflow_id
+  AND name = @name;
+
+-- name: DeleteTask :exec
+DELETE
+FROM tasks
+WHERE workflow_id = $1
+  AND name = $2;
+
+-- name: UpdateWorkflowDefinition :exec
+UPDATE workflows
+SET definition_version     = $2,
+    definition_fingerprint = $3,
+    updated_at             = $4
+WHERE workflows.id = $1;
+
 -- name: WorkflowFinished :one
 UPDATE workflows
 SET finished   = $2,
@@ -169,9 +198,15 @@ SELECT *
 FROM schedules
 ORDER BY id;
 
+-- name: Schedule :one
+SELECT *
+FROM schedules
+WHERE id = $1;
+
 -- name: CreateSchedule :one
-INSERT INTO schedules (workflow_name, workflow_params, spec, once, interval_minutes, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+INSERT INTO schedules (workflow_name, workflow_params, spec, once, interval_minutes, created_at, updated_at,
+                       created_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 RETURNING *;
 
 -- name: DeleteSchedule :one
@@ -199,3 +234,6 @@ SELECT schedules.id,
        last_scheduled_run.finished AS workflow_finished
 FROM schedules
 LEFT OUTER JOIN last_scheduled_run ON last_scheduled_run.schedule_id = schedules.id;
+
+-- name: NotifyWorkflowEvent :exec
+SELECT pg_notify('relui_workflow_events', sqlc.arg(payload)::text);
@@ -152,6 +152,7 @@ func (s *Scheduler) Create(ctx context.Context, sched Schedule, workflowName str
 			Spec:           sched.Cron,
 			CreatedAt:      now,
 			UpdatedAt:      now,
+			CreatedBy:      userFromContext(ctx),
 		})
 		if err != nil {
 			return err
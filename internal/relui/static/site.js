@@ -84,9 +84,113 @@
     });
   };
 
+  const days = ["Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"];
+  const months = ["Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"];
+  const pad = (n, width = 2, fill = "0") => String(n).padStart(width, fill);
+  const formatClock = (d) => `${pad(d.getUTCHours())}:${pad(d.getUTCMinutes())}:${pad(d.getUTCSeconds())}`;
+
+  /**
+   * formatUpdated formats a time like the Updated column of the task list.
+   *
+   * @param {string} time - an RFC 3339 time
+   */
+  const formatUpdated = (time) => {
+    const d = new Date(time);
+    return `${days[d.getUTCDay()]} ${months[d.getUTCMonth()]} ${pad(d.getUTCDate(), 2, " ")} ${d.getUTCFullYear()} ${formatClock(d)}`;
+  };
+
+  /**
+   * formatLogTime formats a time like the prefix of a task log line.
+   *
+   * @param {string} time - an RFC 3339 time
+   */
+  const formatLogTime = (time) => {
+    const d = new Date(time);
+    return `${d.getUTCFullYear()}/${pad(d.getUTCMonth() + 1)}/${pad(d.getUTCDate())} ${formatClock(d)}`;
+  };
+
+  /**
+   * taskStateIcon returns the icon file name and alt text for a task,
+   * matching those chosen by the task list template.
+   *
+   * @param {Object} task - an apiTask
+   */
+  const taskStateIcon = (task) => {
+    if (task.error) {
+      return ["error_red_24dp.svg", "error"];
+    } else if (task.finished) {
+      return ["check_circle_green_24dp.svg", "finished"];
+    } else if (task.started) {
+      return ["pending_yellow_24dp.svg", "started"];
+    }
+    return ["pending_grey_24dp.svg", "pending"];
+  };
+
+  /**
+   * registerWorkflowEvents subscribes to server-sent events for the
+   * workflow shown on the page. It updates task states and appends new
+   * log lines as they arrive, and asks the user to reload the page for
+   * changes it can't apply, such as new tasks or actions.
+   */
+  const registerWorkflowEvents = () => {
+    const section = document.querySelector(".WorkflowShow[data-events-url]");
+    if (!section || !window.EventSource) {
+      return;
+    }
+    const notice = section.querySelector(".WorkflowShow-reloadNotice");
+    const showNotice = () => {
+      notice.hidden = false;
+    };
+    const taskRow = (name) =>
+      Array.from(section.querySelectorAll(".TaskList-itemSummary[data-task-name]")).find(
+        (row) => row.dataset.taskName === name
+      );
+    const source = new EventSource(section.dataset.eventsUrl);
+    source.addEventListener("task", (e) => {
+      const task = JSON.parse(e.data);
+      const row = taskRow(task.name);
+      if (!row) {
+        // The task was added by an expansion after the page loaded.
+        showNotice();
+        return;
+      }
+      const [icon, alt] = taskStateIcon(task);
+      const img = row.querySelector(".TaskList-itemStateIcon");
+      if (img.alt !== alt) {
+        img.src = img.src.replace(/[^/]*$/, icon);
+        img.alt = alt;
+      }
+      row.querySelector(".TaskList-itemUpdated").textContent = formatUpdated(task.updated);
+      const hasAction = row.querySelector(".TaskList-retryTask, .TaskList-approveTask") !== null;
+      const wantAction = Boolean(task.error) || (task.ready_for_approval && !task.approved);
+      if (hasAction !== wantAction) {
+        showNotice();
+      }
+    });
+    source.addEventListener("log", (e) => {
+      const log = JSON.parse(e.data);
+      const row = taskRow(log.task);
+      if (!row) {
+        showNotice();
+        return;
+      }
+      const logs = row.nextElementSibling.querySelector(".TaskList-itemLogs");
+      const line = document.createElement("div");
+      line.className = "TaskList-itemLogLine";
+      line.textContent = `${formatLogTime(log.created)} ${log.body}`;
+      logs.insertBefore(line, logs.querySelector(".TaskList-itemLogLineResult"));
+      row.querySelector(".TaskList-itemUpdated").textContent = formatUpdated(log.created);
+    });
+    source.addEventListener("workflow", () => {
+      source.close();
+      showNotice();
+    });
+  };
+
   const registerListeners = () => {
     registerTaskListExpandListeners(".TaskList-expandableItem");
     addSliceRowListener(".NewWorkflow-addSliceRowButton");
+    registerWorkflowEvents();
   };
   if (document.readyState === "loading") {
     document.addEventListener("DOMContentLoaded", registerListeners);
@@ -52,8 +52,9 @@ type Server struct {
 	baseURL   *url.URL // nil means "/".
 	header    SiteHeader
 	// mux used if baseURL is set
-	bm   *http.ServeMux
-	cria *criadb.AuthDatabase // nil means all workflows are unrestricted.
+	bm     *http.ServeMux
+	cria   *criadb.AuthDatabase // nil means all workflows are unrestricted.
+	events *EventBroker         // nil means watchers poll for changes.
 
 	templates       *template.Template
 	homeTmpl        *template.Template
@@ -67,7 +68,10 @@ type Server struct {
 //
 // cria may be nil, in which case workflows are unrestricted, this is
 // mainly intended to ease development.
-func NewServer(p db.PGDBTX, w *Worker, baseURL *url.URL, header SiteHeader, ms *metrics.Service, cria *criadb.AuthDatabase) *Server {
+//
+// events may be nil, in which case pages watching a workflow for
+// changes poll the database instead.
+func NewServer(p db.PGDBTX, w *Worker, baseURL *url.URL, header SiteHeader, ms *metrics.Service, cria *criadb.AuthDatabase, events *EventBroker) *Server {
 	s := &Server{
 		db:        p,
 		m:         &metricsRouter{mux: http.NewServeMux()},
@@ -76,6 +80,7 @@ func NewServer(p db.PGDBTX, w *Worker, baseURL *url.URL, header SiteHeader, ms *
 		baseURL:   baseURL,
 		header:    header,
 		cria:      cria,
+		events:    events,
 	}
 	if err := s.scheduler.Resume(context.Background()); err != nil {
 		log.Fatalf("s.scheduler.Resume() = %v", err)
@@ -94,10 +99,12 @@ func NewServer(p db.PGDBTX, w *Worker, baseURL *url.URL, header SiteHeader, ms *
 	s.homeTmpl = s.mustLookup("home.html")
 	s.newWorkflowTmpl = s.mustLookup("new_workflow.html")
 	s.m.HandleFunc("GET /workflows/{id}", s.showWorkflowHandler)
+	s.m.HandleFunc("GET /workflows/{id}/events", s.workflowEventsHandler)
 	s.m.HandleFunc("POST /workflows/{id}/stop", s.stopWorkflowHandler)
 	s.m.HandleFunc("POST /workflows/{id}/tasks/{name}/retry", s.retryTaskHandler)
 	s.m.HandleFunc("POST /workflows/{id}/tasks/{name}/approve", s.approveTaskHandler)
 	s.m.HandleFunc("POST /schedules/{id}/delete", s.deleteScheduleHandler)
+	s.registerAPI()
 	s.m.Handle("GET /metrics", ms)
 	s.m.HandleFunc("GET /new_workflow", s.newWorkflowHandler)
 	s.m.HandleFunc("POST /workflows", s.createWorkflowHandler)
@@ -255,9 +262,56 @@ type showWorkflowResponse struct {
 	SiteHeader SiteHeader
 	Workflow   db.Workflow
 	Tasks      []db.TasksForWorkflowSortedRow
+	// TaskGroups contains Tasks, grouped by the sub-workflow
+	// they belong to.
+	TaskGroups []*taskGroup
 	// TaskLogs is a map of all logs for a db.Task, keyed on
 	// (db.Task).Name
 	TaskLogs map[string][]db.TaskLog
+	// TaskApprovals is the approval state of tasks created with
+	// ApproveActionDepWithRule, keyed on (db.Task).Name.
+	TaskApprovals map[string]*taskApprovalState
+	// AuditEvents records who started, stopped, or acted on
+	// tasks of the workflow, oldest first.
+	AuditEvents []db.AuditEvent
+}
+
+// A taskGroup is a set of tasks that are displayed together.
+type taskGroup struct {
+	// Name is the name of the sub-workflow the tasks belong to,
+	// or empty for tasks that don't belong to one.
+	Name     string
+	Tasks    []db.TasksForWorkflowSortedRow
+	Finished int // Number of tasks that finished successfully.
+	Errors   int // Number of tasks that finished in an error.
+}
+
+// groupTasks groups tasks by the innermost sub-workflow of d that they
+// belong to. Groups are ordered by their first task. If d is nil, all
+// tasks are placed in a single group.
+func groupTasks(d *workflow.Definition, tasks []db.TasksForWorkflowSortedRow) []*taskGroup {
+	var groups []*taskGroup
+	byName := make(map[string]*taskGroup)
+	for _, t := range tasks {
+		var name string
+		if d != nil {
+			name = d.TaskGroup(t.Name)
+		}
+		g, ok := byName[name]
+		if !ok {
+			g = &taskGroup{Name: name}
+			byName[name] = g
+			groups = append(groups, g)
+		}
+		g.Tasks = append(g.Tasks, t)
+		switch {
+		case t.Error.Valid:
+			g.Errors++
+		case t.Finished:
+			g.Finished++
+		}
+	}
+	return groups
 }
 
 func (s *Server) showWorkflowHandler(w http.ResponseWriter, r *http.Request) {
@@ -299,11 +353,22 @@ func (s *Server) buildShowWorkflowResponse(ctx context.Context, id uuid.UUID) (*
 	if err != nil {
 		return nil, err
 	}
+	approvals, err := taskApprovalStates(ctx, q, id)
+	if err != nil {
+		return nil, err
+	}
+	events, err := q.AuditEventsForWorkflow(ctx, id)
+	if err != nil {
+		return nil, err
+	}
 	sr := &showWorkflowResponse{
-		SiteHeader: s.header,
-		TaskLogs:   make(map[string][]db.TaskLog),
-		Tasks:      tasks,
-		Workflow:   w,
+		SiteHeader:    s.header,
+		TaskLogs:      make(map[string][]db.TaskLog),
+		Tasks:         tasks,
+		TaskGroups:    groupTasks(s.w.dh.Definition(w.Name.String), tasks),
+		TaskApprovals: approvals,
+		AuditEvents:   events,
+		Workflow:      w,
 	}
 	sr.SiteHeader.Subtitle = w.Name.String
 	sr.SiteHeader.NameParam = w.Name.String
@@ -447,6 +512,7 @@ func (s *Server) createWorkflowHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
+	s.audit(r.Context(), id, "", "start", "")
 	http.Redirect(w, r, s.BaseLink("/workflows", id.String()), http.StatusSeeOther)
 }
 
@@ -478,6 +544,8 @@ func (s *Server) retryTaskHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	if err := s.w.RetryTask(r.Context(), id, r.PathValue("name")); err != nil {
 		log.Printf("s.w.RetryTask(_, %q): %v", id, err)
+	} else {
+		s.audit(r.Context(), id, r.PathValue("name"), "retry", "")
 	}
 	http.Redirect(w, r, s.BaseLink("/workflows", id.String()), http.StatusSeeOther)
 }
@@ -508,20 +576,18 @@ func (s *Server) approveTaskHandler(w http.ResponseWriter, r *http.Request) {
 		// authorizedForWorkflow writes errors to w itself.
 		return
 	}
-	t, err := q.ApproveTask(r.Context(), db.ApproveTaskParams{
-		WorkflowID: id,
-		Name:       r.PathValue("name"),
-		ApprovedAt: sql.NullTime{Time: time.Now(), Valid: true},
-	})
+	t, err := s.approveTask(r.Context(), id, r.PathValue("name"))
 	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
 		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
 		return
+	} else if errors.Is(err, errApprovalDenied) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
 	} else if err != nil {
 		log.Printf("q.ApproveTask(_, %q) = %v, %v", id, t, err)
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
-	s.w.l.Logger(id, t.Name).Printf("USER-APPROVED")
 	http.Redirect(w, r, s.BaseLink("/workflows", id.String()), http.StatusSeeOther)
 }
 
@@ -555,6 +621,7 @@ func (s *Server) stopWorkflowHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
 		return
 	}
+	s.audit(r.Context(), id, "", "stop", "")
 	http.Redirect(w, r, s.BaseLink("/"), http.StatusSeeOther)
 }
 
@@ -669,38 +736,51 @@ func prettySize(size int) string {
 }
 
 // authorizedForWorkflow checks if the authenticated user who sent request r is
-// authorized to access workflow d, by checking if they are a member of any of
-// the configured groups in d.acl. Group membership is determined by querying
-// the CrIA authorization database. It writes a response to w if and only if
-// it returns false, in which case the caller doesn't need to.
+// authorized to access workflow d. See authorize. It writes a response to w
+// if and only if it returns false, in which case the caller doesn't need to.
 func (s *Server) authorizedForWorkflow(ctx context.Context, d *workflow.Definition, w http.ResponseWriter, r *http.Request) bool {
+	err := s.authorize(ctx, d)
+	if errors.Is(err, errForbidden) {
+		// TODO(roland): At some point we way want to provide a better UX for
+		// this case. Currently it will just blast the user with the browser
+		// default 403 status page.
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return false
+	} else if err != nil {
+		log.Printf("authorize: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return false
+	}
+	return true
+}
+
+// errForbidden is returned by authorize when the user isn't authorized.
+var errForbidden = errors.New("user is not authorized for this workflow")
+
+// authorize checks if the authenticated user of ctx is authorized to access
+// workflow d, by checking if they are a member of any of the configured
+// groups in d.acl. Group membership is determined by querying the CrIA
+// authorization database.
+func (s *Server) authorize(ctx context.Context, d *workflow.Definition) error {
 	if s.cria == nil {
-		return true
+		return nil
 	}
 	authorizedGroups := d.AuthorizedGroups()
 	if authorizedGroups == nil {
-		return true
+		return nil
 	}
 
 	iap, err := access.IAPFromContext(ctx)
 	if err != nil {
-		log.Printf("Error getting IAP fields from context: %v", err)
-		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
-		return false
+		return fmt.Errorf("getting IAP fields from context: %w", err)
 	}
 
 	isMember, err := s.cria.IsMemberOfAny(ctx, fmt.Sprintf("user:%s", iap.Email), authorizedGroups)
 	if err != nil {
-		log.Printf("cria.IsMemberOfAny(user:%s) failed: %s", iap.Email, err)
-		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
-		return false
+		return fmt.Errorf("cria.IsMemberOfAny(user:%s) failed: %w", iap.Email, err)
 	}
 	if !isMember {
-		// TODO(roland): At some point we way want to provide a better UX for
-		// this case. Currently it will just blast the user with the browser
-		// default 403 status page.
-		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
-		return false
+		return errForbidden
 	}
-	return true
+	return nil
 }
@@ -121,7 +121,7 @@ func TestServerHomeHandler(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/", nil)
 	w := httptest.NewRecorder()
 
-	s := NewServer(p, NewWorker(NewDefinitionHolder(), p, &PGListener{DB: p}), nil, SiteHeader{}, nil, nil)
+	s := NewServer(p, NewWorker(NewDefinitionHolder(), p, &PGListener{DB: p}), nil, SiteHeader{}, nil, nil, nil)
 
 	s.homeHandler(w, req)
 	resp := w.Result()
@@ -160,7 +160,7 @@ func TestServerNewWorkflowHandler(t *testing.T) {
 			req := httptest.NewRequest(http.MethodGet, u.String(), nil)
 			w := httptest.NewRecorder()
 
-			s := NewServer(testDB(ctx, t), NewWorker(NewDefinitionHolder(), nil, nil), nil, SiteHeader{}, nil, nil)
+			s := NewServer(testDB(ctx, t), NewWorker(NewDefinitionHolder(), nil, nil), nil, SiteHeader{}, nil, nil, nil)
 			s.newWorkflowHandler(w, req)
 			resp := w.Result()
 
@@ -291,7 +291,7 @@ func TestServerCreateWorkflowHandler(t *testing.T) {
 			rec := httptest.NewRecorder()
 			q := db.New(p)
 
-			s := NewServer(p, NewWorker(NewDefinitionHolder(), p, &PGListener{DB: p}), nil, SiteHeader{}, nil, nil)
+			s := NewServer(p, NewWorker(NewDefinitionHolder(), p, &PGListener{DB: p}), nil, SiteHeader{}, nil, nil, nil)
 			s.createWorkflowHandler(rec, req)
 			resp := rec.Result()
 
@@ -643,7 +643,7 @@ func TestServerApproveTaskHandler(t *testing.T) {
 			req := httptest.NewRequest(http.MethodPost, path.Join("/workflows/", c.params["id"], "tasks", url.PathEscape(c.params["name"]), "approve"), nil)
 			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 			rec := httptest.NewRecorder()
-			s := NewServer(p, NewWorker(NewDefinitionHolder(), p, &PGListener{DB: p}), nil, SiteHeader{}, nil, nil)
+			s := NewServer(p, NewWorker(NewDefinitionHolder(), p, &PGListener{DB: p}), nil, SiteHeader{}, nil, nil, nil)
 
 			s.m.ServeHTTP(rec, req)
 			resp := rec.Result()
@@ -733,7 +733,7 @@ func TestServerStopWorkflow(t *testing.T) {
 				t.Fatalf("worker.markRunning(%q) = %v, wanted no error", wfID, err)
 			}
 
-			s := NewServer(p, worker, nil, SiteHeader{}, nil, nil)
+			s := NewServer(p, worker, nil, SiteHeader{}, nil, nil, nil)
 			s.m.ServeHTTP(rec, req)
 			resp := rec.Result()
 
@@ -857,7 +857,7 @@ func testWorkflowACL(t *testing.T, acld bool, authorized bool, wantSucceed bool)
 	} else {
 		memberships = [][2]string{{"user:test@google.com", "mdb/other"}}
 	}
-	s := NewServer(p, worker, nil, SiteHeader{}, nil, criadb.NewTestDatabase(memberships))
+	s := NewServer(p, worker, nil, SiteHeader{}, nil, criadb.NewTestDatabase(memberships), nil)
 
 	iap := access.IAPFields{
 		Email: "test@google.com",
@@ -1074,3 +1074,28 @@ func (l localRoundTripper) RoundTrip(req *http.Request) (*http.Response, error)
 	l.handler.ServeHTTP(w, req)
 	return w.Result(), nil
 }
+
+func TestGroupTasks(t *testing.T) {
+	child := workflow.New(workflow.ACL{})
+	v := workflow.Param(child, workflow.ParamDef[string]{Name: "v"})
+	workflow.Output(child, "out", workflow.Task1(child, "echo", func(_ context.Context, s string) (string, error) { return s, nil }, v))
+	d := workflow.New(workflow.ACL{})
+	sub := workflow.SubWorkflow(d, "sub", child, map[string]workflow.Dependency{"v": workflow.Const("hi")})
+	workflow.Output(d, "out", workflow.Task1(d, "echo", func(_ context.Context, s string) (string, error) { return s, nil }, workflow.SubOutput[string](sub, "out")))
+
+	tasks := []db.TasksForWorkflowSortedRow{
+		{Name: "echo"},
+		{Name: "sub: echo", Finished: true},
+	}
+	want := []*taskGroup{
+		{Name: "", Tasks: tasks[:1]},
+		{Name: "sub", Tasks: tasks[1:], Finished: 1},
+	}
+	if diff := cmp.Diff(want, groupTasks(d, tasks)); diff != "" {
+		t.Errorf("groupTasks() mismatch (-want +got):\n%s", diff)
+	}
+	want = []*taskGroup{{Name: "", Tasks: tasks, Finished: 1}}
+	if diff := cmp.Diff(want, groupTasks(nil, tasks)); diff != "" {
+		t.Errorf("groupTasks(nil, _) mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -6,11 +6,14 @@ package relui
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"maps"
 	"reflect"
+	"slices"
 	"sync"
 	"time"
 
@@ -24,7 +27,7 @@ import (
 type Listener interface {
 	workflow.Listener
 
-	WorkflowStarted(ctx context.Context, workflowID uuid.UUID, name string, params map[string]any, scheduleID int) error
+	WorkflowStarted(ctx context.Context, workflowID uuid.UUID, name string, d *workflow.Definition, params map[string]any, scheduleID int) error
 	WorkflowFinished(ctx context.Context, workflowID uuid.UUID, outputs map[string]any, err error) error
 }
 
@@ -149,7 +152,7 @@ func (w *Worker) StartWorkflow(ctx context.Context, name string, params map[stri
 	if err != nil {
 		return uuid.UUID{}, err
 	}
-	if err := w.l.WorkflowStarted(ctx, wf.ID, name, params, scheduleID); err != nil {
+	if err := w.l.WorkflowStarted(ctx, wf.ID, name, d, params, scheduleID); err != nil {
 		return wf.ID, err
 	}
 	if err := w.run(wf); err != nil {
@@ -158,22 +161,34 @@ func (w *Worker) StartWorkflow(ctx context.Context, name string, params map[stri
 	return wf.ID, err
 }
 
-// ResumeAll resumes all workflows with unfinished tasks.
+// ResumeAll resumes all workflows with unfinished tasks. Workflows
+// that are incompatible with their current definitions are reported
+// and left unfinished.
 func (w *Worker) ResumeAll(ctx context.Context) error {
 	q := db.New(w.db)
 	wfs, err := q.UnfinishedWorkflows(ctx)
 	if err != nil {
 		return fmt.Errorf("q.UnfinishedWorkflows() = _, %w", err)
 	}
+	var incompatible []uuid.UUID
 	for _, wf := range wfs {
 		if err := w.Resume(ctx, wf.ID); err != nil {
 			log.Printf("w.Resume(_, %q) = %v", wf.ID, err)
+			if errors.Is(err, workflow.ErrIncompatibleDefinition) {
+				incompatible = append(incompatible, wf.ID)
+			}
 		}
 	}
+	if len(incompatible) != 0 {
+		log.Printf("ResumeAll: %d workflow(s) are incompatible with their current definitions and were left unfinished: %v", len(incompatible), incompatible)
+	}
 	return nil
 }
 
-// Resume resumes a workflow.
+// Resume resumes a workflow. If the workflow's stored state is
+// incompatible with the current version of its definition, Resume
+// returns an error wrapping workflow.ErrIncompatibleDefinition and
+// leaves the workflow unfinished.
 func (w *Worker) Resume(ctx context.Context, id uuid.UUID) error {
 	var err error
 	var wf db.Workflow
@@ -212,7 +227,10 @@ func (w *Worker) Resume(ctx context.Context, id uuid.UUID) error {
 		w.l.WorkflowFinished(ctx, wf.ID, nil, err)
 		return err
 	}
-	state := &workflow.WorkflowState{ID: wf.ID, Params: params}
+	state := &workflow.WorkflowState{ID: wf.ID, Params: params, Version: int(wf.DefinitionVersion)}
+	if fp := d.Fingerprint(); wf.DefinitionVersion == int32(d.Version()) && wf.DefinitionFingerprint != "" && wf.DefinitionFingerprint != fp {
+		log.Printf("Resume(%v): definition %q changed (fingerprint %s, was %s) without a version change", wf.ID, wf.Name.String, fp, wf.DefinitionFingerprint)
+	}
 
 	taskStates := make(map[string]*workflow.TaskState)
 	for _, t := range tasks {
@@ -222,19 +240,99 @@ func (w *Worker) Resume(ctx context.Context, id uuid.UUID) error {
 			Error:      t.Error.String,
 			RetryCount: int(t.RetryCount),
 		}
+		if t.FirstStartedAt.Valid {
+			ts.FirstStarted = t.FirstStartedAt.Time
+		}
 		if t.Result.Valid {
 			ts.SerializedResult = []byte(t.Result.String)
 		}
 		taskStates[t.Name] = ts
 	}
 	res, err := workflow.Resume(d, state, taskStates)
-	if err != nil {
+	if errors.Is(err, workflow.ErrIncompatibleDefinition) {
+		// Leave the workflow unfinished, so that it can be resumed
+		// if the deployment is rolled back or a migration is added.
+		return fmt.Errorf("workflow %v (%q version %d) can't be resumed by version %d: %w", wf.ID, wf.Name.String, wf.DefinitionVersion, d.Version(), err)
+	} else if err != nil {
 		w.l.WorkflowFinished(ctx, wf.ID, nil, err)
 		return err
 	}
+	if wf.DefinitionVersion != int32(d.Version()) || wf.DefinitionFingerprint != d.Fingerprint() {
+		if err := w.saveMigration(ctx, wf, d, tasks); err != nil {
+			return fmt.Errorf("saving the migration of workflow %v to version %d: %w", wf.ID, d.Version(), err)
+		}
+	}
 	return w.run(res)
 }
 
+// saveMigration persists the migration of workflow wf, whose tasks are
+// tasks, to the current version of its definition d. The tasks are
+// removed, renamed and added as d's migrations describe, and the
+// workflow is marked as started with d's version and fingerprint, in
+// one transaction, so that it isn't migrated again when it's next resumed.
+func (w *Worker) saveMigration(ctx context.Context, wf db.Workflow, d *workflow.Definition, tasks []db.Task) error {
+	names := make(map[string]bool)
+	for _, t := range tasks {
+		names[t.Name] = true
+	}
+	return w.db.BeginFunc(ctx, func(tx pgx.Tx) error {
+		q := db.New(tx)
+		now := time.Now()
+		for _, m := range d.Migrations(int(wf.DefinitionVersion)) {
+			for _, name := range m.RemovedTasks {
+				if !names[name] {
+					continue
+				}
+				if err := q.DeleteTask(ctx, db.DeleteTaskParams{WorkflowID: wf.ID, Name: name}); err != nil {
+					return fmt.Errorf("q.DeleteTask(_, %v, %q) = %w", wf.ID, name, err)
+				}
+				delete(names, name)
+			}
+			for _, oldName := range slices.Sorted(maps.Keys(m.RenamedTasks)) {
+				if !names[oldName] {
+					continue
+				}
+				newName := m.RenamedTasks[oldName]
+				if err := q.RenameTask(ctx, db.RenameTaskParams{WorkflowID: wf.ID, Name: oldName, NewName: newName, UpdatedAt: now}); err != nil {
+					return fmt.Errorf("q.RenameTask(_, %v, %q, %q) = %w", wf.ID, oldName, newName, err)
+				}
+				delete(names, oldName)
+				names[newName] = true
+			}
+			for name, result := range m.DefaultResults {
+				if names[name] {
+					continue
+				}
+				var serialized []byte
+				if result != nil {
+					var err error
+					if serialized, err = json.Marshal(result); err != nil {
+						return err
+					}
+				}
+				if _, err := q.UpsertTask(ctx, db.UpsertTaskParams{
+					WorkflowID: wf.ID,
+					Name:       name,
+					Started:    true,
+					Finished:   true,
+					Result:     sql.NullString{String: string(serialized), Valid: serialized != nil},
+					CreatedAt:  now,
+					UpdatedAt:  now,
+				}); err != nil {
+					return fmt.Errorf("q.UpsertTask(_, %v, %q) = %w", wf.ID, name, err)
+				}
+				names[name] = true
+			}
+		}
+		return q.UpdateWorkflowDefinition(ctx, db.UpdateWorkflowDefinitionParams{
+			ID:                    wf.ID,
+			DefinitionVersion:     int32(d.Version()),
+			DefinitionFingerprint: d.Fingerprint(),
+			UpdatedAt:             now,
+		})
+	})
+}
+
 func UnmarshalWorkflow(marshalled string, d *workflow.Definition) (map[string]any, error) {
 	params := map[string]any{}
 	rawParams := map[string]json.RawMessage{}
@@ -251,13 +349,17 @@ func UnmarshalWorkflow(marshalled string, d *workflow.Definition) (map[string]an
 	return params, nil
 }
 
+// errWorkflowNotRunning is returned by RetryTask for a workflow that
+// isn't running on the worker.
+var errWorkflowNotRunning = errors.New("workflow is not running")
+
 // RetryTask retries a task in a running workflow.
 func (w *Worker) RetryTask(ctx context.Context, id uuid.UUID, name string) error {
 	w.mu.Lock()
 	rwf, ok := w.running[id.String()]
 	w.mu.Unlock()
 	if !ok {
-		return fmt.Errorf("no workflow with id %q", id)
+		return fmt.Errorf("workflow %v: %w", id, errWorkflowNotRunning)
 	}
 	return rwf.w.RetryTask(ctx, name)
 }
@@ -122,6 +122,75 @@ func TestWorkerResume(t *testing.T) {
 	}
 }
 
+func TestWorkerResumeMigrated(t *testing.T) {
+	ctx := t.Context()
+	dbp := testDB(ctx, t)
+	q := db.New(dbp)
+
+	// Version 2 of the definition renamed task "old-echo" to "echo".
+	wd := newTestEchoWorkflow()
+	wd.SetVersion(2, workflow.Migration{From: 1, RenamedTasks: map[string]string{"old-echo": "echo"}})
+	cwp := db.CreateWorkflowParams{
+		ID:                uuid.New(),
+		Name:              nullString(t.Name()),
+		Params:            nullString(`{"greeting": "hello", "names": ["alice", "bob"]}`),
+		DefinitionVersion: 1,
+	}
+	if wf, err := q.CreateWorkflow(ctx, cwp); err != nil {
+		t.Fatalf("q.CreateWorkflow(_, %v) = %v, %v, wanted no error", cwp, wf, err)
+	}
+	cwt := db.CreateTaskParams{WorkflowID: cwp.ID, Name: "old-echo", Finished: true, Result: nullString(`"hello alice bob"`), CreatedAt: time.Now()}
+	if wt, err := q.CreateTask(ctx, cwt); err != nil {
+		t.Fatalf("q.CreateTask(_, %v) = %v, %v, wanted no error", cwt, wt, err)
+	}
+	ctlp := db.CreateTaskLogParams{WorkflowID: cwp.ID, TaskName: "old-echo", Body: "hello alice bob"}
+	if l, err := q.CreateTaskLog(ctx, ctlp); err != nil {
+		t.Fatalf("q.CreateTaskLog(_, %v) = %v, %v, wanted no error", ctlp, l, err)
+	}
+
+	// Resume the workflow as two successive deployments would.
+	// The second must not try to migrate it again.
+	for i := range 2 {
+		runCtx, cancel := context.WithCancel(ctx)
+		wg := sync.WaitGroup{}
+		dh := NewDefinitionHolder()
+		dh.RegisterDefinition(t.Name(), wd)
+		w := NewWorker(dh, dbp, &testWorkflowListener{
+			Listener:   &PGListener{DB: dbp},
+			onFinished: wg.Done,
+		})
+		wg.Add(1)
+		go w.Run(runCtx)
+		if err := w.Resume(ctx, cwp.ID); err != nil {
+			t.Fatalf("w.Resume(_, %v) #%d = %v, wanted no error", cwp.ID, i+1, err)
+		}
+		wg.Wait()
+		cancel()
+	}
+
+	wf, err := q.Workflow(ctx, cwp.ID)
+	if err != nil {
+		t.Fatalf("q.Workflow(_, %v) = %v, %v, wanted no error", cwp.ID, wf, err)
+	}
+	if wf.DefinitionVersion != 2 || wf.DefinitionFingerprint != wd.Fingerprint() {
+		t.Errorf("workflow definition = version %d, fingerprint %q; want version 2, fingerprint %q", wf.DefinitionVersion, wf.DefinitionFingerprint, wd.Fingerprint())
+	}
+	if wf.Error != "" || wf.Output != `{"echo": "hello alice bob"}` {
+		t.Errorf("workflow finished with output %q, error %q; want output of echo", wf.Output, wf.Error)
+	}
+	tasks, err := q.TasksForWorkflow(ctx, cwp.ID)
+	if err != nil {
+		t.Fatalf("q.TasksForWorkflow(_, %v) = %v, %v, wanted no error", cwp.ID, tasks, err)
+	}
+	if len(tasks) != 1 || tasks[0].Name != "echo" {
+		t.Errorf("q.TasksForWorkflow(_, %v) = %v, want only task echo", cwp.ID, tasks)
+	}
+	logs, err := q.TaskLogsForTask(ctx, db.TaskLogsForTaskParams{WorkflowID: cwp.ID, TaskName: "echo"})
+	if err != nil || len(logs) != 1 {
+		t.Errorf("q.TaskLogsForTask(_, %v, %q) = %v, %v, want the log of old-echo", cwp.ID, "echo", logs, err)
+	}
+}
+
 func TestWorkerResumeMissingDefinition(t *testing.T) {
 	ctx := t.Context()
 	dbp := testDB(ctx, t)
@@ -36,6 +36,7 @@ import (
 	"golang.org/x/build/gerrit"
 	"golang.org/x/build/internal/gcsfs"
 	"golang.org/x/build/internal/installer/darwinpkg"
+	"golang.org/x/build/internal/installer/linuxpkg"
 	"golang.org/x/build/internal/installer/windowsmsi"
 	"golang.org/x/build/internal/releasetargets"
 	"golang.org/x/build/internal/relui/db"
@@ -227,6 +228,7 @@ func checkTaskApproved(ctx *wf.TaskContext, p db.PGDBTX) (bool, error) {
 		if err != nil {
 			return false, err
 		}
+		notifyWorkflowEvent(ctx, q, ctx.WorkflowID, ctx.TaskName)
 	}
 	return t.ApprovedAt.Valid, err
 }
@@ -518,7 +520,7 @@ func addCommTasks(
 	wd *wf.Definition, build *BuildReleaseTasks, comm task.CommunicationTasks,
 	kind task.ReleaseKind, published wf.Value[[]task.Published], securitySummary wf.Value[string], securityFixes, coordinators wf.Value[[]string],
 ) {
-	okayToAnnounce := wf.Action0(wd, "Wait to Announce", build.ApproveAction, wf.After(published))
+	okayToAnnounce := wf.Action0(wd, "Wait to Announce", build.approvePublish, wf.After(published))
 
 	// Announce that a new Go release has been published.
 	sentMail := wf.Task4(wd, "mail-announcement", comm.AnnounceRelease, wf.Const(kind), published, securityFixes, coordinators, wf.After(okayToAnnounce))
@@ -610,7 +612,7 @@ func addSingleReleaseWorkflow(
 	// Wait for planned release day,
 	// then re-check release-blocking issues
 	// and upstream PRIVATE-track security fixes, if any.
-	waitReleaseApproval := wf.Action0(wd, "Wait for Release Coordinator Approval", build.ApproveAction, wf.After(signedAndTestedArtifacts))
+	waitReleaseApproval := wf.Action0(wd, "Wait for Release Coordinator Approval", build.approvePublish, wf.After(signedAndTestedArtifacts))
 	recheckedBlockingIssues := wf.Action3(wd, "Re-check blocking issues", milestone.CheckBlockers, milestones, nextVersion, kindVal, wf.After(waitReleaseApproval))
 	upstreamedPrivateSecurityCLs := wf.Task5(wd, "Publicize PRIVATE-track security fixes (if any)", func(ctx *wf.TaskContext,
 		version, targetBranch, startingHead, securityCommit string, reviewers []string,
@@ -865,6 +867,13 @@ func (tasks *BuildReleaseTasks) addBuildTasks(wd *wf.Definition, major int, kind
 			msi := wf.Task1(wd, "Build MSI installer", tasks.buildWindowsMSI, tar)
 			signedMSI := wf.Task2(wd, "Sign MSI installer", tasks.signArtifact, msi, wf.Const(sign.BuildWindows))
 			artifacts = append(artifacts, signedMSI, zip)
+		case "linux":
+			artifacts = append(artifacts, tar)
+			if tasks.LinuxPackages {
+				deb := wf.Task2(wd, "Build .deb package", tasks.buildLinuxPackage, tar, wf.Const("deb"))
+				rpm := wf.Task2(wd, "Build .rpm package", tasks.buildLinuxPackage, tar, wf.Const("rpm"))
+				artifacts = append(artifacts, deb, rpm)
+			}
 		default:
 			artifacts = append(artifacts, tar)
 		}
@@ -926,6 +935,16 @@ type BuildReleaseTasks struct {
 	BuildBucketClient        task.BuildBucketClient
 	SwarmingClient           task.SwarmingClient
 	ApproveAction            func(*wf.TaskContext) error
+	ApprovePublishAction     func(*wf.TaskContext) error // ApprovePublishAction, if set, is used instead of ApproveAction to gate publishing and announcing a release.
+	LinuxPackages            bool                        // LinuxPackages is whether to build .deb and .rpm packages for Linux targets.
+}
+
+// approvePublish waits for approval to publish or announce a release.
+func (b *BuildReleaseTasks) approvePublish(ctx *wf.TaskContext) error {
+	if b.ApprovePublishAction != nil {
+		return b.ApprovePublishAction(ctx)
+	}
+	return b.ApproveAction(ctx)
 }
 
 // readSecurityRef reads the head of the internal release branch that corresponds
@@ -1487,6 +1506,22 @@ func (b *BuildReleaseTasks) buildWindowsMSI(ctx *wf.TaskContext, binary artifact
 	})
 }
 
+// buildLinuxPackage builds a Debian or RPM package, as selected by format,
+// for the given binary artifact. The packages aren't signed.
+func (b *BuildReleaseTasks) buildLinuxPackage(ctx *wf.TaskContext, binary artifact, format string) (artifact, error) {
+	return b.runBuildStep(ctx, binary.Target, binary, format, func(r io.Reader, w io.Writer) error {
+		opt := linuxpkg.InstallerOptions{GOARCH: binary.Target.GOARCH}
+		switch format {
+		case "deb":
+			return linuxpkg.WriteDeb(w, r, opt)
+		case "rpm":
+			return linuxpkg.WriteRPM(w, r, opt)
+		default:
+			return fmt.Errorf("unknown Linux package format %q", format)
+		}
+	})
+}
+
 func (b *BuildReleaseTasks) convertZipToTGZ(ctx *wf.TaskContext, binary artifact) (artifact, error) {
 	return b.runBuildStep(ctx, binary.Target, binary, "tar.gz", func(r io.Reader, w io.Writer) error {
 		// Reading the whole file isn't ideal, but we need a ReaderAt, and
@@ -1969,7 +2004,7 @@ func (tasks *BuildReleaseTasks) publishArtifacts(ctx *wf.TaskContext, version st
 			f.Kind = "source"
 		case "tar.gz", "zip":
 			f.Kind = "archive"
-		case "msi", "pkg":
+		case "msi", "pkg", "deb", "rpm":
 			f.Kind = "installer"
 		}
 
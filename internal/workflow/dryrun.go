@@ -0,0 +1,91 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// A DryRun describes how to run a workflow definition without running
+// its tasks, so that changes to a workflow can be exercised before they
+// are deployed. Each task or action is replaced by a stub or a recorded
+// result. Expansions are pure functions of their inputs, and run as usual.
+type DryRun struct {
+	// Stubs maps task names to functions that run instead of the task.
+	// A stub must have the same type as the function it replaces.
+	Stubs map[string]any
+	// Results maps task names to results, encoded as JSON, that a task
+	// returns instead of running. For example, they may be recorded
+	// from TaskState.SerializedResult of an earlier workflow. An action
+	// only needs an entry to succeed; its value is ignored.
+	Results map[string]json.RawMessage
+}
+
+// Start instantiates a workflow with the given parameters, like Start,
+// but with task functions replaced according to r. A task that has
+// neither a stub nor a recorded result fails when it runs.
+func (r *DryRun) Start(def *Definition, params map[string]any) (*Workflow, error) {
+	w, err := Start(def, params)
+	if err != nil {
+		return nil, err
+	}
+	w.dryRun = r
+	return w, nil
+}
+
+// taskFunc returns the function to run for td.
+func (w *Workflow) taskFunc(td *taskDefinition) any {
+	if w.dryRun == nil {
+		return td.f
+	}
+	return w.dryRun.taskFunc(td)
+}
+
+func (r *DryRun) taskFunc(td *taskDefinition) any {
+	ft := reflect.TypeOf(td.f)
+	if stub, ok := r.Stubs[td.name]; ok {
+		if st := reflect.TypeOf(stub); st != ft {
+			return failingFunc(ft, fmt.Errorf("dry run: stub for task %q has type %v, want %v", td.name, st, ft))
+		}
+		return stub
+	}
+	result, ok := r.Results[td.name]
+	if !ok {
+		return failingFunc(ft, fmt.Errorf("dry run: no stub or recorded result for task %q", td.name))
+	}
+	return reflect.MakeFunc(ft, func([]reflect.Value) []reflect.Value {
+		if ft.NumOut() == 1 {
+			return results(ft, nil, nil)
+		}
+		v, err := unmarshalNew(ft.Out(0), result)
+		if err != nil {
+			return results(ft, nil, fmt.Errorf("dry run: unmarshaling recorded result for task %q: %v", td.name, err))
+		}
+		return results(ft, v, nil)
+	}).Interface()
+}
+
+// failingFunc returns a function of type ft that returns err.
+func failingFunc(ft reflect.Type, err error) any {
+	return reflect.MakeFunc(ft, func([]reflect.Value) []reflect.Value {
+		return results(ft, nil, err)
+	}).Interface()
+}
+
+// results returns the return values of a task or action function of
+// type ft. v is ignored for actions.
+func results(ft reflect.Type, v any, err error) []reflect.Value {
+	var out []reflect.Value
+	if ft.NumOut() == 2 {
+		rv := reflect.Zero(ft.Out(0))
+		if v != nil {
+			rv = reflect.ValueOf(v)
+		}
+		out = append(out, rv)
+	}
+	return append(out, reflect.ValueOf(&err).Elem())
+}
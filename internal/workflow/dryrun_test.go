@@ -0,0 +1,75 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package workflow_test
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	wf "golang.org/x/build/internal/workflow"
+)
+
+func TestDryRun(t *testing.T) {
+	dr := &wf.DryRun{
+		Stubs: map[string]any{
+			"build": func(_ context.Context, v string) (string, error) { return "stub " + v, nil },
+		},
+		Results: map[string]json.RawMessage{
+			"test":        nil,
+			"write notes": json.RawMessage(`"recorded notes"`),
+			"publish":     json.RawMessage(`"recorded publish"`),
+		},
+	}
+	w, err := dr.Start(graphTestDefinition(), map[string]any{"version": "go1.99"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputs := runWorkflow(t, w, nil)
+	if got, want := outputs["published"], "recorded publish"; got != want {
+		t.Errorf("published = %q, want %q", got, want)
+	}
+}
+
+func TestDryRunStubsOnly(t *testing.T) {
+	var published []string
+	dr := &wf.DryRun{
+		Stubs: map[string]any{
+			"build":       func(_ context.Context, v string) (string, error) { return "built " + v, nil },
+			"test":        func(_ context.Context, s string) error { return nil },
+			"write notes": func(context.Context) (string, error) { return "notes", nil },
+			"publish": func(_ context.Context, s []string, n string) (string, error) {
+				published = append(s, n)
+				return "ok", nil
+			},
+		},
+	}
+	w, err := dr.Start(graphTestDefinition(), map[string]any{"version": "go1.99"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	runWorkflow(t, w, nil)
+	if got, want := published, []string{"built go1.99", "extra", "notes"}; !slices.Equal(got, want) {
+		t.Errorf("publish arguments = %q, want %q", got, want)
+	}
+}
+
+func TestDryRunBadStub(t *testing.T) {
+	dr := &wf.DryRun{
+		Stubs: map[string]any{
+			// Wrong type: the real task takes a string argument.
+			"build": func(context.Context) (string, error) { return "", nil },
+		},
+	}
+	w, err := dr.Start(graphTestDefinition(), map[string]any{"version": "go1.99"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "dry run: stub for task \"build\" has type func(context.Context) (string, error), want func(context.Context, string) (string, error)"
+	if got := runToFailure(t, w, nil, "build"); got != want {
+		t.Errorf("got error %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package workflow
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// A Graph is a static view of a workflow Definition, suitable for
+// review and display before the workflow is started.
+//
+// Expansions appear as single nodes of kind NodeExpansion. The tasks
+// they add to the workflow are unknown until the expansion runs.
+type Graph struct {
+	Nodes []*Node `json:"nodes"`
+	Edges []Edge  `json:"edges"`
+}
+
+// NodeKind is the kind of a Graph node.
+type NodeKind string
+
+const (
+	NodeParam     NodeKind = "param"
+	NodeTask      NodeKind = "task"
+	NodeAction    NodeKind = "action"
+	NodeExpansion NodeKind = "expansion"
+	NodeOutput    NodeKind = "output"
+)
+
+// A Node is a parameter, task, action, expansion or output of a workflow.
+type Node struct {
+	ID   string   `json:"id"` // Unique within the graph.
+	Name string   `json:"name"`
+	Kind NodeKind `json:"kind"`
+	// Type is the Go type of the value the node produces.
+	// It's empty for actions.
+	Type string `json:"type,omitempty"`
+	// Group is the innermost sub-workflow that contains the node.
+	// See Definition.TaskGroup.
+	Group string `json:"group,omitempty"`
+}
+
+// An Edge connects a node to a node that depends on it.
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// After reports whether the dependency is an ordering dependency
+	// declared with After, rather than a function argument.
+	After bool `json:"after,omitempty"`
+}
+
+// Graph returns the static graph of the workflow definition.
+// Nodes are sorted by kind and name, so that the result is stable.
+func (d *Definition) Graph() *Graph {
+	g := &Graph{}
+	for _, p := range d.parameters {
+		g.Nodes = append(g.Nodes, &Node{ID: nodeID(NodeParam, p.Name()), Name: p.Name(), Kind: NodeParam, Type: p.Type().String()})
+	}
+	for _, td := range d.tasks {
+		n := &Node{ID: taskNodeID(td), Name: td.name, Kind: taskKind(td), Group: d.TaskGroup(td.name)}
+		if n.Kind != NodeAction {
+			n.Type = reflect.TypeOf(td.f).Out(0).String()
+			if td.isExpansion {
+				// The expansion function returns a Value[T]; show T.
+				n.Type = strings.TrimSuffix(strings.TrimPrefix(n.Type, "workflow.Value["), "]")
+			}
+		}
+		g.Nodes = append(g.Nodes, n)
+		for i, dep := range td.deps {
+			for _, from := range dep.graphSources() {
+				g.Edges = append(g.Edges, Edge{From: from, To: n.ID, After: i >= len(td.args)})
+			}
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(d.outputs)) {
+		v := d.outputs[name]
+		n := &Node{ID: nodeID(NodeOutput, name), Name: name, Kind: NodeOutput, Type: v.typ().String()}
+		g.Nodes = append(g.Nodes, n)
+		for _, from := range v.graphSources() {
+			g.Edges = append(g.Edges, Edge{From: from, To: n.ID})
+		}
+	}
+	kindOrder := map[NodeKind]int{NodeParam: 0, NodeTask: 1, NodeAction: 1, NodeExpansion: 1, NodeOutput: 2}
+	slices.SortStableFunc(g.Nodes, func(a, b *Node) int {
+		return cmp.Or(cmp.Compare(kindOrder[a.Kind], kindOrder[b.Kind]), cmp.Compare(a.Name, b.Name))
+	})
+	slices.SortFunc(g.Edges, func(a, b Edge) int {
+		return cmp.Or(cmp.Compare(a.To, b.To), cmp.Compare(a.From, b.From))
+	})
+	g.Edges = slices.Compact(g.Edges)
+	return g
+}
+
+func nodeID(kind NodeKind, name string) string {
+	return string(kind) + ":" + name
+}
+
+func taskKind(td *taskDefinition) NodeKind {
+	switch {
+	case td.isExpansion:
+		return NodeExpansion
+	case reflect.TypeOf(td.f).NumOut() == 1:
+		return NodeAction
+	default:
+		return NodeTask
+	}
+}
+
+func taskNodeID(td *taskDefinition) string {
+	return nodeID(taskKind(td), td.name)
+}
+
+// graphSources implementations return the IDs of the graph nodes
+// that a Dependency is derived from.
+
+func (p parameter[T]) graphSources() []string { return []string{nodeID(NodeParam, p.d.Name)} }
+func (c *constant[T]) graphSources() []string { return nil }
+func (s *slice[T]) graphSources() []string {
+	var ids []string
+	for _, v := range s.vals {
+		ids = append(ids, v.graphSources()...)
+	}
+	return ids
+}
+func (tr *taskResult[T]) graphSources() []string      { return []string{taskNodeID(tr.task)} }
+func (er *expansionResult[T]) graphSources() []string { return []string{taskNodeID(er.td)} }
+func (d *dependency) graphSources() []string          { return []string{taskNodeID(d.task)} }
+
+// WriteDOT writes the graph in the Graphviz DOT language.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph workflow {\n")
+	for _, n := range g.Nodes {
+		var shape string
+		switch n.Kind {
+		case NodeParam, NodeOutput:
+			shape = "ellipse"
+		case NodeExpansion:
+			shape = "box, style=dashed"
+		default:
+			shape = "box"
+		}
+		fmt.Fprintf(&b, "\t%q [label=%q, shape=%s];\n", n.ID, n.Name, shape)
+	}
+	for _, e := range g.Edges {
+		if e.After {
+			fmt.Fprintf(&b, "\t%q -> %q [style=dotted];\n", e.From, e.To)
+		} else {
+			fmt.Fprintf(&b, "\t%q -> %q;\n", e.From, e.To)
+		}
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMermaid writes the graph as a Mermaid flowchart.
+func (g *Graph) WriteMermaid(w io.Writer) error {
+	// Mermaid IDs can't contain most punctuation, so number the nodes.
+	ids := make(map[string]string)
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+	for i, n := range g.Nodes {
+		id := fmt.Sprintf("n%d", i)
+		ids[n.ID] = id
+		label := strings.ReplaceAll(n.Name, `"`, "#quot;")
+		switch n.Kind {
+		case NodeParam, NodeOutput:
+			fmt.Fprintf(&b, "\t%s([\"%s\"])\n", id, label)
+		case NodeExpansion:
+			fmt.Fprintf(&b, "\t%s{{\"%s\"}}\n", id, label)
+		default:
+			fmt.Fprintf(&b, "\t%s[\"%s\"]\n", id, label)
+		}
+	}
+	for _, e := range g.Edges {
+		arrow := "-->"
+		if e.After {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&b, "\t%s %s %s\n", ids[e.From], arrow, ids[e.To])
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
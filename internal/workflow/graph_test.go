@@ -0,0 +1,120 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package workflow_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	wf "golang.org/x/build/internal/workflow"
+)
+
+func graphTestDefinition() *wf.Definition {
+	wd := wf.New(wf.ACL{})
+	version := wf.Param(wd, wf.ParamDef[string]{Name: "version"})
+	built := wf.Task1(wd, "build", func(_ context.Context, v string) (string, error) { return "built " + v, nil }, version)
+	tested := wf.Action1(wd, "test", func(_ context.Context, s string) error { return nil }, built)
+	notes := wf.Expand1(wd, "notes", func(wd *wf.Definition, v string) (wf.Value[string], error) {
+		return wf.Task0(wd, "write notes", func(context.Context) (string, error) { return "notes for " + v, nil }), nil
+	}, version)
+	published := wf.Task2(wd, "publish", func(_ context.Context, s []string, n string) (string, error) {
+		return strings.Join(append(s, n), ", "), nil
+	}, wf.Slice(built, wf.Const("extra")), notes, wf.After(tested))
+	wf.Output(wd, "published", published)
+	return wd
+}
+
+func TestGraph(t *testing.T) {
+	got := graphTestDefinition().Graph()
+	want := &wf.Graph{
+		Nodes: []*wf.Node{
+			{ID: "param:version", Name: "version", Kind: wf.NodeParam, Type: "string"},
+			{ID: "task:build", Name: "build", Kind: wf.NodeTask, Type: "string"},
+			{ID: "expansion:notes", Name: "notes", Kind: wf.NodeExpansion, Type: "string"},
+			{ID: "task:publish", Name: "publish", Kind: wf.NodeTask, Type: "string"},
+			{ID: "action:test", Name: "test", Kind: wf.NodeAction},
+			{ID: "output:published", Name: "published", Kind: wf.NodeOutput, Type: "string"},
+		},
+		Edges: []wf.Edge{
+			{From: "task:build", To: "action:test"},
+			{From: "param:version", To: "expansion:notes"},
+			{From: "task:publish", To: "output:published"},
+			{From: "param:version", To: "task:build"},
+			{From: "action:test", To: "task:publish", After: true},
+			{From: "expansion:notes", To: "task:publish"},
+			{From: "task:build", To: "task:publish"},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Graph() mismatch (-want +got):\n%s", diff)
+	}
+
+	// The JSON form must round-trip.
+	data, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded *wf.Graph
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, decoded); diff != "" {
+		t.Errorf("JSON round trip mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGraphDOT(t *testing.T) {
+	var b strings.Builder
+	if err := graphTestDefinition().Graph().WriteDOT(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `digraph workflow {
+	"param:version" [label="version", shape=ellipse];
+	"task:build" [label="build", shape=box];
+	"expansion:notes" [label="notes", shape=box, style=dashed];
+	"task:publish" [label="publish", shape=box];
+	"action:test" [label="test", shape=box];
+	"output:published" [label="published", shape=ellipse];
+	"task:build" -> "action:test";
+	"param:version" -> "expansion:notes";
+	"task:publish" -> "output:published";
+	"param:version" -> "task:build";
+	"action:test" -> "task:publish" [style=dotted];
+	"expansion:notes" -> "task:publish";
+	"task:build" -> "task:publish";
+}
+`
+	if diff := cmp.Diff(want, b.String()); diff != "" {
+		t.Errorf("WriteDOT mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGraphMermaid(t *testing.T) {
+	var b strings.Builder
+	if err := graphTestDefinition().Graph().WriteMermaid(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `flowchart TD
+	n0(["version"])
+	n1["build"]
+	n2{{"notes"}}
+	n3["publish"]
+	n4["test"]
+	n5(["published"])
+	n1 --> n4
+	n0 --> n2
+	n3 --> n5
+	n0 --> n1
+	n4 -.-> n3
+	n2 --> n3
+	n1 --> n3
+`
+	if diff := cmp.Diff(want, b.String()); diff != "" {
+		t.Errorf("WriteMermaid mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package workflow
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SubWorkflow embeds a copy of the child workflow definition into d as a
+// group of tasks named name. Unlike Sub, which only namespaces tasks
+// defined directly in d, it composes a complete, separately built
+// Definition, such as one registered to run on its own.
+//
+// The child's parameters are not parameters of d. Instead, params maps
+// the name of each of the child's parameters to a Value of d that provides
+// it; the Value's type must match the parameter's. The child's outputs
+// can be read with SubOutput. The child's ACL is ignored.
+//
+// Child expansions run as part of d, and tasks they add are placed in the
+// same group.
+func SubWorkflow(d *Definition, name string, child *Definition, params map[string]Dependency) *SubWorkflowResult {
+	r := &rebinder{
+		prefix: d.name(name) + ": ",
+		params: make(map[string]metaValue),
+		tasks:  make(map[*taskDefinition]*taskDefinition),
+	}
+	if slices.Contains(d.subWorkflows, strings.TrimSuffix(r.prefix, ": ")) {
+		panic(fmt.Errorf("sub-workflow %q was already added to this workflow definition", name))
+	}
+	for _, p := range child.parameters {
+		v, ok := params[p.Name()]
+		if !ok {
+			panic(fmt.Errorf("sub-workflow %q: no value for parameter %q", name, p.Name()))
+		}
+		mv, ok := v.(metaValue)
+		if !ok || mv.typ() != p.Type() {
+			panic(fmt.Errorf("sub-workflow %q: parameter %q needs a Value of type %v, got %T", name, p.Name(), p.Type(), v))
+		}
+		r.params[p.Name()] = mv
+	}
+	for pname := range params {
+		if !slices.ContainsFunc(child.parameters, func(p MetaParameter) bool { return p.Name() == pname }) {
+			panic(fmt.Errorf("sub-workflow %q has no parameter %q", name, pname))
+		}
+	}
+
+	// Copy all tasks before rebinding their dependencies,
+	// which may refer to any other task.
+	for _, td := range child.tasks {
+		copied := *td
+		copied.name = r.prefix + td.name
+		if td.isExpansion {
+			copied.namePrefix = r.prefix + td.namePrefix
+			copied.rebinders = append(slices.Clone(td.rebinders), r)
+		}
+		if _, ok := d.tasks[copied.name]; ok {
+			panic(fmt.Errorf("sub-workflow %q: task %q was already added to this workflow definition", name, copied.name))
+		}
+		r.tasks[td] = &copied
+	}
+	for _, td := range r.tasks {
+		td.rebindDeps(r)
+		d.tasks[td.name] = td
+	}
+	d.subWorkflows = append(d.subWorkflows, strings.TrimSuffix(r.prefix, ": "))
+	for _, sub := range child.subWorkflows {
+		d.subWorkflows = append(d.subWorkflows, r.prefix+sub)
+	}
+	return &SubWorkflowResult{name: name, child: child, r: r}
+}
+
+// SubWorkflowResult is the result of embedding a workflow definition
+// with SubWorkflow.
+type SubWorkflowResult struct {
+	name  string
+	child *Definition
+	r     *rebinder
+}
+
+// SubOutput returns a Value for the output of the embedded workflow
+// registered with Output under the given name.
+func SubOutput[T any](s *SubWorkflowResult, name string) Value[T] {
+	out, ok := s.child.outputs[name]
+	if !ok {
+		panic(fmt.Errorf("sub-workflow %q has no output %q", s.name, name))
+	}
+	v, ok := out.rebind(s.r).(Value[T])
+	if !ok {
+		var zero T
+		panic(fmt.Errorf("sub-workflow %q output %q has type %v, not %T", s.name, name, out.typ(), zero))
+	}
+	return v
+}
+
+// Done returns a Dependency that is ready once every task
+// of the embedded workflow has completed successfully.
+func (s *SubWorkflowResult) Done() Dependency {
+	return &groupDone{prefix: s.r.prefix}
+}
+
+type groupDone struct {
+	prefix string
+}
+
+func (g *groupDone) ready(w *Workflow) bool {
+	for td := range w.tasks {
+		if strings.HasPrefix(td.name, g.prefix) && !w.taskReady(td) {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *groupDone) graphSources() []string { return nil }
+
+func (g *groupDone) rebind(r *rebinder) Dependency {
+	return &groupDone{prefix: r.prefix + g.prefix}
+}
+
+// SubWorkflows returns the names of the sub-workflows embedded in d with
+// SubWorkflow, including nested ones, in the order they were added.
+func (d *Definition) SubWorkflows() []string {
+	return d.subWorkflows
+}
+
+// TaskGroup returns the name of the innermost sub-workflow that contains
+// the named task, or the empty string if the task isn't part of one.
+func (d *Definition) TaskGroup(taskName string) string {
+	var group string
+	for _, sub := range d.subWorkflows {
+		if strings.HasPrefix(taskName, sub+": ") && len(sub) > len(group) {
+			group = sub
+		}
+	}
+	return group
+}
+
+// A rebinder maps the Values and tasks of a child workflow definition
+// to their copies in a parent definition.
+type rebinder struct {
+	prefix string // Prepended to names of child tasks.
+	params map[string]metaValue
+	tasks  map[*taskDefinition]*taskDefinition
+}
+
+func (r *rebinder) task(td *taskDefinition) *taskDefinition {
+	if copied, ok := r.tasks[td]; ok {
+		return copied
+	}
+	// Tasks added by expansions are created in the parent.
+	return td
+}
+
+// rebindDeps replaces td's arguments and dependencies
+// with their counterparts in the parent.
+func (td *taskDefinition) rebindDeps(r *rebinder) {
+	args := make([]metaValue, len(td.args))
+	for i, arg := range td.args {
+		args[i] = arg.rebind(r).(metaValue)
+	}
+	deps := make([]Dependency, len(td.deps))
+	for i, dep := range td.deps {
+		deps[i] = dep.rebind(r)
+	}
+	td.args, td.deps = args, deps
+}
+
+// rebind implementations return the counterpart of a Dependency
+// in the parent definition.
+
+func (p parameter[T]) rebind(r *rebinder) Dependency {
+	if v, ok := r.params[p.d.Name]; ok {
+		return v
+	}
+	return p
+}
+func (c *constant[T]) rebind(*rebinder) Dependency { return c }
+func (s *slice[T]) rebind(r *rebinder) Dependency {
+	vals := make([]Value[T], len(s.vals))
+	for i, v := range s.vals {
+		vals[i] = v.rebind(r).(Value[T])
+	}
+	return &slice[T]{vals: vals}
+}
+func (tr *taskResult[T]) rebind(r *rebinder) Dependency { return &taskResult[T]{r.task(tr.task)} }
+func (er *expansionResult[T]) rebind(r *rebinder) Dependency {
+	return &expansionResult[T]{r.task(er.td)}
+}
+func (d *dependency) rebind(r *rebinder) Dependency { return &dependency{r.task(d.task)} }
@@ -0,0 +1,135 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package workflow_test
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	wf "golang.org/x/build/internal/workflow"
+)
+
+// newTagDefinition returns a definition that tags a version
+// and writes release notes for it using an expansion.
+func newTagDefinition() *wf.Definition {
+	wd := wf.New(wf.ACL{})
+	version := wf.Param(wd, wf.ParamDef[string]{Name: "version"})
+	tag := wf.Task1(wd, "tag", func(_ context.Context, v string) (string, error) {
+		return "tagged " + v, nil
+	}, version)
+	notes := wf.Expand1(wd, "plan notes", func(wd *wf.Definition, v string) (wf.Value[string], error) {
+		// Refers to tag, a Value of the enclosing definition.
+		return wf.Task1(wd, "write notes", func(_ context.Context, t string) (string, error) {
+			return "notes for " + t, nil
+		}, tag), nil
+	}, version)
+	wf.Output(wd, "tag", tag)
+	wf.Output(wd, "notes", notes)
+	return wd
+}
+
+func TestSubWorkflow(t *testing.T) {
+	wd := wf.New(wf.ACL{})
+	version := wf.Param(wd, wf.ParamDef[string]{Name: "version"})
+	next := wf.Task1(wd, "next version", func(_ context.Context, v string) (string, error) {
+		return v + ".1", nil
+	}, version)
+	first := wf.SubWorkflow(wd, "first", newTagDefinition(), map[string]wf.Dependency{"version": version})
+	second := wf.SubWorkflow(wd, "second", newTagDefinition(), map[string]wf.Dependency{"version": next})
+	concat := func(_ context.Context, s []string) (string, error) { return strings.Join(s, "; "), nil }
+	wf.Output(wd, "result", wf.Task1(wd, "concat", concat, wf.Slice(
+		wf.SubOutput[string](first, "tag"),
+		wf.SubOutput[string](first, "notes"),
+		wf.SubOutput[string](second, "tag"),
+		wf.SubOutput[string](second, "notes"),
+	)))
+
+	storage := &mapListener{Listener: &verboseListener{t}}
+	w := startWorkflow(t, wd, map[string]any{"version": "go1.30"})
+	outputs := runWorkflow(t, w, storage)
+	want := "tagged go1.30; notes for tagged go1.30; tagged go1.30.1; notes for tagged go1.30.1"
+	if got := outputs["result"]; got != want {
+		t.Errorf("result = %q, want %q", got, want)
+	}
+	var names []string
+	for name := range storage.states[w.ID] {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	wantNames := []string{
+		"concat",
+		"first: plan notes", "first: tag", "first: write notes",
+		"next version",
+		"second: plan notes", "second: tag", "second: write notes",
+	}
+	if diff := cmp.Diff(wantNames, names); diff != "" {
+		t.Errorf("task names mismatch (-want +got):\n%s", diff)
+	}
+	if got, want := wd.TaskGroup("second: write notes"), "second"; got != want {
+		t.Errorf("TaskGroup(%q) = %q, want %q", "second: write notes", got, want)
+	}
+}
+
+func TestSubWorkflowNested(t *testing.T) {
+	middle := wf.New(wf.ACL{})
+	v := wf.Param(middle, wf.ParamDef[string]{Name: "v"})
+	inner := wf.SubWorkflow(middle, "inner", newTagDefinition(), map[string]wf.Dependency{"version": v})
+	wf.Output(middle, "tag", wf.SubOutput[string](inner, "tag"))
+
+	wd := wf.New(wf.ACL{})
+	outer := wf.SubWorkflow(wd, "outer", middle, map[string]wf.Dependency{"v": wf.Const("go1.31")})
+	var mu sync.Mutex
+	var ranAfter bool
+	wf.Output(wd, "tag", wf.SubOutput[string](outer, "tag"))
+	wf.Output(wd, "after", wf.Task0(wd, "after", func(context.Context) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		ranAfter = true
+		return "", nil
+	}, wf.After(outer.Done())))
+
+	if diff := cmp.Diff([]string{"outer", "outer: inner"}, wd.SubWorkflows()); diff != "" {
+		t.Errorf("SubWorkflows() mismatch (-want +got):\n%s", diff)
+	}
+	if got, want := wd.TaskGroup("outer: inner: tag"), "outer: inner"; got != want {
+		t.Errorf("TaskGroup = %q, want %q", got, want)
+	}
+
+	w := startWorkflow(t, wd, nil)
+	outputs := runWorkflow(t, w, nil)
+	if got, want := outputs["tag"], "tagged go1.31"; got != want {
+		t.Errorf("tag = %q, want %q", got, want)
+	}
+	if !ranAfter {
+		t.Errorf("task after sub-workflow didn't run")
+	}
+}
+
+func TestSubWorkflowBadParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		params map[string]wf.Dependency
+		want   string
+	}{
+		{"missing", nil, `sub-workflow "child": no value for parameter "version"`},
+		{"extra", map[string]wf.Dependency{"version": wf.Const(""), "other": wf.Const("")}, `sub-workflow "child" has no parameter "other"`},
+		{"wrong type", map[string]wf.Dependency{"version": wf.Const(1)}, `sub-workflow "child": parameter "version" needs a Value of type string`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				err, _ := recover().(error)
+				if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+					t.Errorf("SubWorkflow panicked with %v, want prefix %q", err, tt.want)
+				}
+			}()
+			wf.SubWorkflow(wf.New(wf.ACL{}), "child", newTagDefinition(), tt.params)
+		})
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package workflow
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// ErrIncompatibleDefinition is returned by Resume when the stored state of
+// a workflow can't be resumed with its current definition, for example
+// because a task was renamed without a corresponding Migration.
+var ErrIncompatibleDefinition = errors.New("workflow state is incompatible with its definition")
+
+// A Migration describes how to resume workflows that were started with
+// an earlier version of a definition. See Definition.SetVersion.
+type Migration struct {
+	// From is the version migrated from. The migration is applied
+	// to workflows started with version From or earlier.
+	From int
+	// RenamedTasks maps the old names of renamed tasks to their new names.
+	RenamedTasks map[string]string
+	// RemovedTasks are tasks that are no longer part of the definition.
+	// Their stored state is discarded.
+	RemovedTasks []string
+	// DefaultResults maps the names of tasks added to the definition
+	// to results. Workflows that haven't run such a task treat it as
+	// having finished with the given result. The result must have the
+	// type the task returns, and must be nil for an Action.
+	DefaultResults map[string]any
+}
+
+// SetVersion sets d's version, which should be increased whenever the
+// definition changes in a way that affects workflows already in progress,
+// along with the migrations needed to resume such workflows.
+// The version of a definition is zero until it is set.
+func (d *Definition) SetVersion(version int, migrations ...Migration) {
+	for _, m := range migrations {
+		if m.From >= version {
+			panic(fmt.Errorf("migration from version %d is not older than version %d", m.From, version))
+		}
+		for name := range m.DefaultResults {
+			td, ok := d.tasks[name]
+			if !ok {
+				panic(fmt.Errorf("migration from version %d: default result for unknown task %q", m.From, name))
+			}
+			if err := checkDefaultResult(td, m.DefaultResults[name]); err != nil {
+				panic(fmt.Errorf("migration from version %d: %v", m.From, err))
+			}
+		}
+	}
+	d.version = version
+	d.migrations = slices.SortedFunc(slices.Values(migrations), func(a, b Migration) int { return a.From - b.From })
+}
+
+// Version returns d's version, as set by SetVersion.
+func (d *Definition) Version() int {
+	return d.version
+}
+
+// Migrations returns the migrations of d that apply to a workflow
+// started with the given version, in the order they are applied.
+// Within a migration, removed tasks are discarded first, then tasks
+// are renamed in the order of their old names, and then default
+// results are added for the tasks that still have no state.
+func (d *Definition) Migrations(version int) []Migration {
+	var ms []Migration
+	for _, m := range d.migrations {
+		if m.From >= version {
+			ms = append(ms, m)
+		}
+	}
+	return ms
+}
+
+// Fingerprint returns a string that identifies the structure of d: its
+// parameters, tasks, outputs and the types and connections between them.
+// Two definitions with the same fingerprint can resume each other's
+// workflows. It doesn't cover the behavior of tasks, or tasks added by
+// expansions.
+func (d *Definition) Fingerprint() string {
+	b, err := json.Marshal(d.Graph())
+	if err != nil {
+		panic(err) // Graphs only contain strings and bools.
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:8])
+}
+
+func checkDefaultResult(td *taskDefinition, result any) error {
+	ft := reflect.TypeOf(td.f)
+	if td.isExpansion {
+		return fmt.Errorf("task %q is an expansion, which can't have a default result", td.name)
+	}
+	if ft.NumOut() == 1 {
+		if result != nil {
+			return fmt.Errorf("task %q is an action, so its default result must be nil", td.name)
+		}
+		return nil
+	}
+	if got, want := reflect.TypeOf(result), ft.Out(0); got != want {
+		return fmt.Errorf("default result for task %q has type %v, want %v", td.name, got, want)
+	}
+	return nil
+}
+
+// migrate applies the migrations of def that are needed for a workflow
+// started with the given version to taskStates, returning a new map.
+func (def *Definition) migrate(version int, taskStates map[string]*TaskState) (map[string]*TaskState, error) {
+	states := maps.Clone(taskStates)
+	if version > def.version {
+		return nil, fmt.Errorf("%w: workflow was started with version %d, newer than version %d", ErrIncompatibleDefinition, version, def.version)
+	}
+	for _, m := range def.Migrations(version) {
+		for _, name := range m.RemovedTasks {
+			delete(states, name)
+		}
+		for _, oldName := range slices.Sorted(maps.Keys(m.RenamedTasks)) {
+			newName := m.RenamedTasks[oldName]
+			st, ok := states[oldName]
+			if !ok {
+				continue
+			}
+			if _, ok := states[newName]; ok {
+				return nil, fmt.Errorf("%w: can't rename task %q to %q, which already has state", ErrIncompatibleDefinition, oldName, newName)
+			}
+			delete(states, oldName)
+			renamed := *st
+			renamed.Name = newName
+			states[newName] = &renamed
+		}
+		for name, result := range m.DefaultResults {
+			if _, ok := states[name]; ok {
+				continue
+			}
+			st := &TaskState{Name: name, Started: true, Finished: true}
+			if reflect.TypeOf(def.tasks[name].f).NumOut() != 1 {
+				serialized, err := json.Marshal(result)
+				if err != nil {
+					return nil, fmt.Errorf("marshaling default result for task %q: %v", name, err)
+				}
+				st.SerializedResult = serialized
+				st.Result = result
+			}
+			states[name] = st
+		}
+	}
+	return states, nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package workflow_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	wf "golang.org/x/build/internal/workflow"
+)
+
+func TestFingerprint(t *testing.T) {
+	hi := func(_ context.Context, s string) (string, error) { return s, nil }
+	define := func(extra bool) *wf.Definition {
+		wd := wf.New(wf.ACL{})
+		greeting := wf.Param(wd, wf.ParamDef[string]{Name: "greeting"})
+		out := wf.Task1(wd, "hi", hi, greeting)
+		if extra {
+			out = wf.Task1(wd, "hi again", hi, out)
+		}
+		wf.Output(wd, "out", out)
+		return wd
+	}
+	if a, b := define(false).Fingerprint(), define(false).Fingerprint(); a != b {
+		t.Errorf("identical definitions have different fingerprints %q and %q", a, b)
+	}
+	if a, b := define(false).Fingerprint(), define(true).Fingerprint(); a == b {
+		t.Errorf("different definitions have the same fingerprint %q", a)
+	}
+}
+
+func TestResumeMigration(t *testing.T) {
+	runs := map[string]int{}
+	task := func(name string) func(context.Context) (string, error) {
+		return func(context.Context) (string, error) {
+			runs[name]++
+			return name, nil
+		}
+	}
+	join := func(_ context.Context, a, b string) (string, error) { return a + "+" + b, nil }
+
+	// Version 1 of the definition has tasks "a", "b" and "old".
+	// Version 2 renames "a" to "first", removes "old", and adds "second".
+	wd := wf.New(wf.ACL{})
+	wf.Output(wd, "out", wf.Task2(wd, "join", join, wf.Task0(wd, "first", task("first")), wf.Task0(wd, "second", task("second"))))
+	wd.SetVersion(2, wf.Migration{
+		From:           1,
+		RenamedTasks:   map[string]string{"a": "first"},
+		RemovedTasks:   []string{"old"},
+		DefaultResults: map[string]any{"second": "default"},
+	})
+	if got, want := wd.Version(), 2; got != want {
+		t.Errorf("Version() = %v, want %v", got, want)
+	}
+
+	states := map[string]*wf.TaskState{
+		"a":    {Name: "a", Started: true, Finished: true, Result: "a", SerializedResult: []byte(`"a"`)},
+		"old":  {Name: "old", Started: true, Finished: true, Result: "old", SerializedResult: []byte(`"old"`)},
+		"join": {Name: "join"},
+	}
+	w, err := wf.Resume(wd, &wf.WorkflowState{ID: uuid.New(), Version: 1}, states)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := runWorkflow(t, w, nil)
+	if got, want := out["out"], "a+default"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+	if len(runs) != 0 {
+		t.Errorf("tasks ran %v, want none", runs)
+	}
+	if _, ok := states["a"]; !ok {
+		t.Errorf("Resume modified the caller's task states")
+	}
+
+	// A workflow started with version 2 isn't migrated.
+	_, err = wf.Resume(wd, &wf.WorkflowState{ID: uuid.New(), Version: 2}, states)
+	if !errors.Is(err, wf.ErrIncompatibleDefinition) {
+		t.Errorf("Resume of unmigrated state = %v, want ErrIncompatibleDefinition", err)
+	}
+	// Nor can a workflow from a newer version be resumed.
+	_, err = wf.Resume(wd, &wf.WorkflowState{ID: uuid.New(), Version: 3}, states)
+	if !errors.Is(err, wf.ErrIncompatibleDefinition) {
+		t.Errorf("Resume of newer version = %v, want ErrIncompatibleDefinition", err)
+	}
+}
+
+func TestSetVersionBadDefaultResult(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("SetVersion with a mistyped default result didn't panic")
+		}
+	}()
+	wd := wf.New(wf.ACL{})
+	wf.Output(wd, "out", wf.Task0(wd, "task", func(context.Context) (string, error) { return "", nil }))
+	wd.SetVersion(1, wf.Migration{From: 0, DefaultResults: map[string]any{"task": 42}})
+}
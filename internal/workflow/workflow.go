@@ -31,6 +31,11 @@
 // definitions to create an ordering dependency that doesn't correspond to a
 // function argument.
 //
+// A task that returns an error is automatically retried up to MaxRetries
+// times, each attempt limited by WatchdogDelay between log messages. The
+// MaxAttempts, RetryBackoff, RetryIf, Deadline and Watchdog TaskOptions
+// adjust that policy for individual tasks.
+//
 // Expansions are a third type of function that adds to a running workflow
 // definition rather than producing an output. Unlike Actions and Tasks, they
 // execute multiple times and must produce exactly the same workflow
@@ -40,14 +45,17 @@
 //
 // Once a Definition is complete, call Start to set its parameters and
 // instantiate it into a Workflow. Call Run to execute the workflow until
-// completion.
+// completion. To review a Definition without running its tasks, use Graph
+// to export its structure, or DryRun to run it with stubbed tasks.
 package workflow
 
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"maps"
+	"math/rand/v2"
 	"reflect"
 	"runtime/debug"
 	"strings"
@@ -94,6 +102,8 @@ func (d *Definition) shallowClone() *Definition {
 	clone := New(d.acl)
 	clone.namePrefix = d.namePrefix
 	clone.parameters = append([]MetaParameter(nil), d.parameters...)
+	clone.subWorkflows = append([]string(nil), d.subWorkflows...)
+	clone.version, clone.migrations = d.version, d.migrations
 	maps.Copy(clone.tasks, d.tasks)
 	maps.Copy(clone.outputs, d.outputs)
 	return clone
@@ -110,6 +120,11 @@ type definitionState struct {
 	parameters []MetaParameter // Ordered according to registration, unique parameter names.
 	tasks      map[string]*taskDefinition
 	outputs    map[string]metaValue
+	// Names of embedded sub-workflows, in the order they were added.
+	subWorkflows []string
+	// Set by SetVersion.
+	version    int
+	migrations []Migration
 	// list of groups that are allowed to interact with the associated
 	// definition.
 	acl ACL
@@ -346,6 +361,8 @@ func Output[T any](d *Definition, name string, v Value[T]) {
 // A Dependency represents a dependency on a prior task.
 type Dependency interface {
 	ready(*Workflow) bool
+	graphSources() []string
+	rebind(*rebinder) Dependency
 }
 
 // After represents an ordering dependency on another Task or Action. It can be
@@ -360,6 +377,93 @@ type after struct {
 
 func (a *after) taskOption() {}
 
+// MaxAttempts limits the number of times a task is automatically attempted
+// before its error is reported, overriding the package-wide MaxRetries.
+// A value of 1 disables automatic retries for the task.
+func MaxAttempts(n int) TaskOption {
+	if n < 1 {
+		panic(fmt.Errorf("MaxAttempts must be at least 1, got %d", n))
+	}
+	return maxAttempts(n)
+}
+
+type maxAttempts int
+
+func (maxAttempts) taskOption() {}
+
+// RetryBackoff delays automatic retries of a task. The first retry waits
+// initial, and each subsequent retry doubles the previous delay up to max.
+// Each delay is then randomly adjusted by up to ±jitter of its value,
+// where jitter is a fraction in the range [0, 1].
+func RetryBackoff(initial, max time.Duration, jitter float64) TaskOption {
+	if initial <= 0 || max < initial {
+		panic(fmt.Errorf("invalid backoff range [%v, %v]", initial, max))
+	}
+	if jitter < 0 || jitter > 1 {
+		panic(fmt.Errorf("backoff jitter must be in [0, 1], got %v", jitter))
+	}
+	return &backoff{initial: initial, max: max, jitter: jitter}
+}
+
+type backoff struct {
+	initial, max time.Duration
+	jitter       float64
+}
+
+func (*backoff) taskOption() {}
+
+// delay returns the delay before the given retry, counting from 1.
+func (b *backoff) delay(retry int) time.Duration {
+	d := b.initial
+	for i := 1; i < retry && d < b.max; i++ {
+		d *= 2
+	}
+	d = min(d, b.max)
+	if b.jitter > 0 {
+		d += time.Duration(float64(d) * b.jitter * (2*rand.Float64() - 1))
+	}
+	return d
+}
+
+// RetryIf classifies task errors. Automatic retries only happen for
+// errors for which retryable reports true.
+func RetryIf(retryable func(error) bool) TaskOption {
+	return retryIf(retryable)
+}
+
+type retryIf func(error) bool
+
+func (retryIf) taskOption() {}
+
+// Deadline sets a hard limit on the total time spent running a task,
+// measured from the start of its first attempt and including all automatic
+// retries and the delays between them. Once the deadline passes, the task's
+// context is canceled and the task fails without further automatic retries.
+// A manual retry starts a new deadline. d must be positive.
+func Deadline(d time.Duration) TaskOption {
+	if d <= 0 {
+		panic(fmt.Errorf("Deadline must be positive, got %v", d))
+	}
+	return deadline(d)
+}
+
+type deadline time.Duration
+
+func (deadline) taskOption() {}
+
+// Watchdog overrides WatchdogDelay for a task. The delay is still
+// scaled by TaskContext.SetWatchdogScale. d must be positive.
+func Watchdog(d time.Duration) TaskOption {
+	if d <= 0 {
+		panic(fmt.Errorf("Watchdog must be positive, got %v", d))
+	}
+	return watchdog(d)
+}
+
+type watchdog time.Duration
+
+func (watchdog) taskOption() {}
+
 // TaskN adds a task to the workflow definition. It takes N inputs, and returns
 // one output. name must uniquely identify the task in the workflow.
 // f must be a function that takes a context.Context or *TaskContext argument,
@@ -402,7 +506,20 @@ func addFunc(d *Definition, name string, f any, inputs []metaValue, opts []TaskO
 		td.deps = append(td.deps, input)
 	}
 	for _, opt := range opts {
-		td.deps = append(td.deps, opt.(*after).deps...)
+		switch opt := opt.(type) {
+		case *after:
+			td.deps = append(td.deps, opt.deps...)
+		case maxAttempts:
+			td.maxAttempts = int(opt)
+		case *backoff:
+			td.backoff = opt
+		case retryIf:
+			td.retryIf = opt
+		case deadline:
+			td.deadline = time.Duration(opt)
+		case watchdog:
+			td.watchdog = time.Duration(opt)
+		}
 	}
 	d.tasks[name] = td
 	return td
@@ -529,6 +646,7 @@ type TaskContext struct {
 	WorkflowID uuid.UUID
 
 	watchdogTimer *time.Timer
+	watchdogDelay time.Duration
 	watchdogScale int
 }
 
@@ -553,7 +671,7 @@ func (c *TaskContext) DisableRetries() {
 }
 
 func (c *TaskContext) ResetWatchdog() {
-	c.resetWatchdog(WatchdogDelay * time.Duration(c.watchdogScale))
+	c.resetWatchdog(c.watchdogDelay * time.Duration(c.watchdogScale))
 }
 
 // SetWatchdogScale sets the watchdog delay scale factor to max(v, 1),
@@ -601,12 +719,18 @@ type TaskState struct {
 	SerializedResult []byte
 	Error            string
 	RetryCount       int
+	// FirstStarted is when the first automatic attempt of the task
+	// started. It's used to enforce the task's Deadline across resumes.
+	FirstStarted time.Time
 }
 
 // WorkflowState contains the shallow state of a running workflow.
 type WorkflowState struct {
 	ID     uuid.UUID
 	Params map[string]any
+	// Version is the version of the definition the workflow was
+	// started with. See Definition.SetVersion.
+	Version int
 }
 
 // A Logger is a debug logger passed to a task implementation.
@@ -621,6 +745,25 @@ type taskDefinition struct {
 	args        []metaValue
 	deps        []Dependency
 	f           any
+
+	// Retry and timeout policy, set by TaskOptions.
+	maxAttempts int // If zero, MaxRetries applies.
+	backoff     *backoff
+	retryIf     func(error) bool
+	deadline    time.Duration
+	watchdog    time.Duration
+
+	// For expansions embedded with SubWorkflow, rebinders map references
+	// to the child definitions in the tasks the expansion adds, innermost
+	// sub-workflow first.
+	rebinders []*rebinder
+}
+
+func (td *taskDefinition) attempts() int {
+	if td.maxAttempts != 0 {
+		return td.maxAttempts
+	}
+	return MaxRetries
 }
 
 type taskResult[T any] struct {
@@ -654,6 +797,8 @@ type Workflow struct {
 
 	def   *Definition
 	tasks map[*taskDefinition]*taskState
+	// dryRun, if non-nil, replaces task functions. See DryRun.
+	dryRun *DryRun
 	// pendingStates stores states that haven't been loaded because their
 	// tasks didn't exist at Resume time.
 	pendingStates map[string]*TaskState
@@ -675,6 +820,7 @@ type taskState struct {
 	result           any
 	serializedResult []byte
 	retryCount       int
+	firstStarted     time.Time
 
 	// workflow expansion
 	expanded    *Definition
@@ -689,6 +835,7 @@ func (t *taskState) toExported() *TaskState {
 		SerializedResult: append([]byte(nil), t.serializedResult...),
 		Started:          t.started,
 		RetryCount:       t.retryCount,
+		FirstStarted:     t.firstStarted,
 	}
 	if t.err != nil {
 		state.Error = t.err.Error()
@@ -741,7 +888,16 @@ func (w *Workflow) validate() error {
 // The host must create the WorkflowState. TaskStates should be saved from
 // listener callbacks, but for ease of storage, their Result field does not
 // need to be populated.
+//
+// If the workflow was started with an earlier version of def, the
+// definition's migrations are applied to taskStates first. If the states
+// still don't match def, Resume returns an error wrapping
+// ErrIncompatibleDefinition.
 func Resume(def *Definition, state *WorkflowState, taskStates map[string]*TaskState) (*Workflow, error) {
+	taskStates, err := def.migrate(state.Version, taskStates)
+	if err != nil {
+		return nil, err
+	}
 	w := &Workflow{
 		ID:            state.ID,
 		params:        state.Params,
@@ -757,7 +913,7 @@ func Resume(def *Definition, state *WorkflowState, taskStates map[string]*TaskSt
 		var err error
 		w.tasks[taskDef], err = loadTaskState(w.pendingStates, taskDef, false)
 		if err != nil {
-			return nil, fmt.Errorf("loading state for %v: %v", taskDef.name, err)
+			return nil, fmt.Errorf("%w: loading state for %v: %v", ErrIncompatibleDefinition, taskDef.name, err)
 		}
 	}
 	return w, nil
@@ -781,6 +937,7 @@ func loadTaskState(states map[string]*TaskState, def *taskDefinition, allowMissi
 		finished:         finished,
 		serializedResult: tState.SerializedResult,
 		retryCount:       tState.RetryCount,
+		firstStarted:     tState.FirstStarted,
 	}
 	if state.serializedResult != nil {
 		result, err := unmarshalNew(reflect.ValueOf(def.f).Type().Out(0), tState.SerializedResult)
@@ -865,7 +1022,8 @@ func (w *Workflow) Run(ctx context.Context, listener Listener) (map[string]any,
 					defCopy.namePrefix = task.def.namePrefix
 					go func() { stateChan <- runExpansion(defCopy, taskCopy, args) }()
 				} else {
-					go func() { stateChan <- runTask(ctx, w.ID, listener, taskCopy, args) }()
+					f := w.taskFunc(task.def)
+					go func() { stateChan <- runTask(ctx, w.ID, listener, taskCopy, f, args) }()
 				}
 			}
 		}
@@ -891,12 +1049,12 @@ func (w *Workflow) Run(ctx context.Context, listener Listener) (map[string]any,
 		case retry := <-w.retryCommands:
 			def, ok := w.def.tasks[retry.name]
 			if !ok {
-				retry.reply <- fmt.Errorf("unknown task %q", retry.name)
+				retry.reply <- fmt.Errorf("%w %q", ErrUnknownTask, retry.name)
 				break
 			}
 			state := w.tasks[def]
 			if !state.finished || state.err == nil {
-				retry.reply <- fmt.Errorf("cannot retry task that did not finish in error")
+				retry.reply <- fmt.Errorf("%w: %q", ErrTaskNotRetryable, retry.name)
 				break
 			}
 			listener.Logger(w.ID, def.name).Printf("Manual retry requested")
@@ -934,21 +1092,56 @@ var MaxRetries = 3
 
 var WatchdogDelay = 11 * time.Minute // A little over go test -timeout's default value of 10 minutes.
 
-func runTask(ctx context.Context, workflowID uuid.UUID, listener Listener, state taskState, args []reflect.Value) taskState {
-	ctx, cancel := context.WithCancel(ctx)
+func runTask(ctx context.Context, workflowID uuid.UUID, listener Listener, state taskState, f any, args []reflect.Value) taskState {
+	logger := listener.Logger(workflowID, state.def.name)
+	if state.firstStarted.IsZero() {
+		state.firstStarted = time.Now()
+	}
+	var deadlineCtx context.Context = ctx
+	if state.def.deadline != 0 {
+		var cancel context.CancelFunc
+		deadlineCtx, cancel = context.WithDeadline(ctx, state.firstStarted.Add(state.def.deadline))
+		defer cancel()
+	}
+	if state.retryCount > 0 && state.def.backoff != nil {
+		d := state.def.backoff.delay(state.retryCount)
+		logger.Printf("waiting %v before retrying", d)
+		t := time.NewTimer(d)
+		select {
+		case <-t.C:
+		case <-deadlineCtx.Done():
+			t.Stop()
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		state.finished = true
+		state.err = err
+		return state
+	} else if deadlineCtx.Err() != nil {
+		state.finished = true
+		state.err = fmt.Errorf("task exceeded its deadline of %v", state.def.deadline)
+		return state
+	}
+
+	taskCtx, cancel := context.WithCancel(deadlineCtx)
 	defer cancel()
 
+	watchdogDelay := WatchdogDelay
+	if state.def.watchdog != 0 {
+		watchdogDelay = state.def.watchdog
+	}
 	tctx := &TaskContext{
-		Context:       ctx,
-		Logger:        listener.Logger(workflowID, state.def.name),
+		Context:       taskCtx,
+		Logger:        logger,
 		TaskName:      state.def.name,
 		WorkflowID:    workflowID,
-		watchdogTimer: time.AfterFunc(WatchdogDelay, cancel),
+		watchdogTimer: time.AfterFunc(watchdogDelay, cancel),
+		watchdogDelay: watchdogDelay,
 		watchdogScale: 1,
 	}
 
 	in := append([]reflect.Value{reflect.ValueOf(tctx)}, args...)
-	fv := reflect.ValueOf(state.def.f)
+	fv := reflect.ValueOf(f)
 	var (
 		out       []reflect.Value
 		taskPanic error // whether the task panicked
@@ -970,9 +1163,16 @@ func runTask(ctx context.Context, workflowID uuid.UUID, listener Listener, state
 		// to retry, do so manually. So, always disable any remaining automatic retries.
 		tctx.disableRetries = true
 	} else if watchdogTimerAlreadyExpired {
-		state.err = fmt.Errorf("task did not log for %v, assumed hung", WatchdogDelay*time.Duration(tctx.watchdogScale))
+		state.err = fmt.Errorf("task did not log for %v, assumed hung", watchdogDelay*time.Duration(tctx.watchdogScale))
+	} else if deadlineCtx.Err() != nil && ctx.Err() == nil {
+		// The deadline has passed, but the workflow wasn't stopped.
+		state.err = fmt.Errorf("task exceeded its deadline of %v", state.def.deadline)
+		tctx.disableRetries = true
 	} else if errIdx := len(out) - 1; !out[errIdx].IsNil() {
 		state.err = out[errIdx].Interface().(error)
+		if state.def.retryIf != nil && !state.def.retryIf(state.err) {
+			tctx.disableRetries = true
+		}
 	}
 	state.finished = true
 	if len(out) == 2 && state.err == nil {
@@ -985,18 +1185,23 @@ func runTask(ctx context.Context, workflowID uuid.UUID, listener Listener, state
 		}
 	}
 
-	if state.err != nil && !tctx.disableRetries && state.retryCount+1 < MaxRetries {
-		tctx.Printf("task failed, will retry (%v of %v): %v", state.retryCount+1, MaxRetries, state.err)
+	if attempts := state.def.attempts(); state.err != nil && !tctx.disableRetries && state.retryCount+1 < attempts {
+		tctx.Printf("task failed, will retry (%v of %v): %v", state.retryCount+1, attempts, state.err)
 		state = taskState{
-			def:        state.def,
-			created:    true,
-			retryCount: state.retryCount + 1,
+			def:          state.def,
+			created:      true,
+			retryCount:   state.retryCount + 1,
+			firstStarted: state.firstStarted,
 		}
 	}
 	return state
 }
 
 func runExpansion(d *Definition, state taskState, args []reflect.Value) taskState {
+	existing := make(map[*taskDefinition]bool)
+	for _, td := range d.tasks {
+		existing[td] = true
+	}
 	in := append([]reflect.Value{reflect.ValueOf(d)}, args...)
 	fv := reflect.ValueOf(state.def.f)
 	var (
@@ -1019,6 +1224,19 @@ func runExpansion(d *Definition, state taskState, args []reflect.Value) taskStat
 	} else {
 		state.expanded = d
 		state.resultValue = out[0].Interface().(metaValue)
+		for _, r := range state.def.rebinders {
+			for _, td := range d.tasks {
+				if !existing[td] {
+					td.rebindDeps(r)
+				}
+			}
+			state.resultValue = state.resultValue.rebind(r).(metaValue)
+		}
+		for _, td := range d.tasks {
+			if !existing[td] && td.isExpansion {
+				td.rebinders = state.def.rebinders
+			}
+		}
 	}
 	return state
 }
@@ -1062,6 +1280,12 @@ type defaultLogger struct{}
 
 func (l *defaultLogger) Printf(format string, v ...any) {}
 
+// Errors returned by RetryTask.
+var (
+	ErrUnknownTask      = errors.New("unknown task")
+	ErrTaskNotRetryable = errors.New("cannot retry task that did not finish in error")
+)
+
 type retryCommand struct {
 	name  string
 	reply chan error
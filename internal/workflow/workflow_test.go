@@ -364,6 +364,41 @@ func TestManualRetry(t *testing.T) {
 	}
 }
 
+func TestManualRetryErrors(t *testing.T) {
+	counter := 0
+	wd := wf.New(wf.ACL{})
+	wf.Output(wd, "ok", wf.Task0(wd, "ok", func(_ context.Context) (string, error) {
+		return "", nil
+	}))
+	wf.Output(wd, "fail", wf.Task0(wd, "fail", func(ctx *wf.TaskContext) (string, error) {
+		ctx.DisableRetries()
+		counter++
+		if counter == 1 {
+			return "", fmt.Errorf("first try fail")
+		}
+		return "", nil
+	}))
+	w := startWorkflow(t, wd, nil)
+	listener := &errorListener{
+		taskName: "fail",
+		callback: func(string) {
+			go func() {
+				if err := w.RetryTask(context.Background(), "missing"); !errors.Is(err, wf.ErrUnknownTask) {
+					t.Errorf("RetryTask(missing) = %v, want %v", err, wf.ErrUnknownTask)
+				}
+				if err := w.RetryTask(context.Background(), "ok"); !errors.Is(err, wf.ErrTaskNotRetryable) {
+					t.Errorf("RetryTask(ok) = %v, want %v", err, wf.ErrTaskNotRetryable)
+				}
+				if err := w.RetryTask(context.Background(), "fail"); err != nil {
+					t.Errorf("RetryTask(fail) = %v, want nil", err)
+				}
+			}()
+		},
+		Listener: &verboseListener{t},
+	}
+	runWorkflow(t, w, listener)
+}
+
 // Test that manual retry works on tasks that come from different expansions.
 //
 // This is similar to how the Go minor release workflow plans builders for
@@ -475,6 +510,174 @@ func TestAutomaticRetryDisabled(t *testing.T) {
 	}
 }
 
+func TestMaxAttempts(t *testing.T) {
+	counter := 0
+	alwaysFail := func(ctx context.Context) (string, error) {
+		counter++
+		return "", fmt.Errorf("failure %v", counter)
+	}
+
+	wd := wf.New(wf.ACL{})
+	wf.Output(wd, "result", wf.Task0(wd, "always fail", alwaysFail, wf.MaxAttempts(5)))
+
+	w := startWorkflow(t, wd, nil)
+	if got, want := runToFailure(t, w, nil, "always fail"), "failure 5"; got != want {
+		t.Errorf("got error %q, want %q", got, want)
+	}
+	if counter != 5 {
+		t.Errorf("task ran %v times, wanted 5", counter)
+	}
+}
+
+func TestRetryIf(t *testing.T) {
+	errPermanent := errors.New("permanent failure")
+	counter := 0
+	task := func(ctx context.Context) (string, error) {
+		counter++
+		if counter == 1 {
+			return "", fmt.Errorf("transient failure")
+		}
+		return "", fmt.Errorf("wrapped: %w", errPermanent)
+	}
+
+	wd := wf.New(wf.ACL{})
+	retryable := func(err error) bool { return !errors.Is(err, errPermanent) }
+	wf.Output(wd, "result", wf.Task0(wd, "task", task, wf.MaxAttempts(5), wf.RetryIf(retryable)))
+
+	w := startWorkflow(t, wd, nil)
+	if got, want := runToFailure(t, w, nil, "task"), "wrapped: permanent failure"; got != want {
+		t.Errorf("got error %q, want %q", got, want)
+	}
+	if counter != 2 {
+		t.Errorf("task ran %v times, wanted 2", counter)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	var attempts []time.Time
+	needsRetry := func(ctx context.Context) (string, error) {
+		attempts = append(attempts, time.Now())
+		if len(attempts) < 3 {
+			return "", fmt.Errorf("attempt %v failed", len(attempts))
+		}
+		return "hi", nil
+	}
+
+	wd := wf.New(wf.ACL{})
+	wf.Output(wd, "result", wf.Task0(wd, "needs retry", needsRetry, wf.RetryBackoff(100*time.Millisecond, 150*time.Millisecond, 0)))
+
+	w := startWorkflow(t, wd, nil)
+	outputs := runWorkflow(t, w, nil)
+	if got, want := outputs["result"], "hi"; got != want {
+		t.Errorf("result = %q, want %q", got, want)
+	}
+	if len(attempts) != 3 {
+		t.Fatalf("task ran %v times, wanted 3", len(attempts))
+	}
+	for i, want := range []time.Duration{100 * time.Millisecond, 150 * time.Millisecond} {
+		if got := attempts[i+1].Sub(attempts[i]); got < want {
+			t.Errorf("delay before retry %v = %v, want at least %v", i+1, got, want)
+		}
+	}
+}
+
+func TestInvalidTaskOptions(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		opt  func() wf.TaskOption
+	}{
+		{"MaxAttempts(0)", func() wf.TaskOption { return wf.MaxAttempts(0) }},
+		{"Deadline(0)", func() wf.TaskOption { return wf.Deadline(0) }},
+		{"Deadline(-1s)", func() wf.TaskOption { return wf.Deadline(-time.Second) }},
+		{"Watchdog(0)", func() wf.TaskOption { return wf.Watchdog(0) }},
+		{"Watchdog(-1s)", func() wf.TaskOption { return wf.Watchdog(-time.Second) }},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s didn't panic", c.name)
+				}
+			}()
+			c.opt()
+		}()
+	}
+}
+
+func TestDeadline(t *testing.T) {
+	counter := 0
+	slow := func(ctx context.Context) (string, error) {
+		counter++
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
+
+	wd := wf.New(wf.ACL{})
+	wf.Output(wd, "result", wf.Task0(wd, "slow", slow, wf.Deadline(200*time.Millisecond), wf.MaxAttempts(3)))
+
+	w := startWorkflow(t, wd, nil)
+	storage := &mapListener{Listener: &verboseListener{t}}
+	if got, want := runToFailure(t, w, storage, "slow"), "task exceeded its deadline of 200ms"; got != want {
+		t.Errorf("got error %q, want %q", got, want)
+	}
+	if counter != 1 {
+		t.Errorf("task ran %v times, wanted 1", counter)
+	}
+	// Hitting the deadline must not use up a retry.
+	if got := storage.states[w.ID]["slow"].RetryCount; got != 0 {
+		t.Errorf("RetryCount = %v, want 0", got)
+	}
+}
+
+func TestResumeRetryBudget(t *testing.T) {
+	counter := 0
+	task := func(ctx context.Context) (string, error) {
+		counter++
+		return "", fmt.Errorf("failure %v", counter)
+	}
+
+	wd := wf.New(wf.ACL{})
+	wf.Output(wd, "result", wf.Task0(wd, "task", task, wf.MaxAttempts(3), wf.Deadline(time.Hour)))
+
+	// Resume a workflow in which the task already used up two attempts
+	// and most of its deadline.
+	taskStates := map[string]*wf.TaskState{
+		"task": {Name: "task", RetryCount: 2, FirstStarted: time.Now().Add(-59 * time.Minute)},
+	}
+	w, err := wf.Resume(wd, &wf.WorkflowState{ID: uuid.New()}, taskStates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storage := &mapListener{Listener: &verboseListener{t}}
+	if got, want := runToFailure(t, w, storage, "task"), "failure 1"; got != want {
+		t.Errorf("got error %q, want %q", got, want)
+	}
+	if counter != 1 {
+		t.Errorf("task ran %v times, wanted 1", counter)
+	}
+	if got, want := storage.states[w.ID]["task"].FirstStarted, taskStates["task"].FirstStarted; !got.Equal(want) {
+		t.Errorf("FirstStarted = %v, want %v", got, want)
+	}
+}
+
+func TestTaskWatchdog(t *testing.T) {
+	sleepy := func(ctx *wf.TaskContext) (string, error) {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(time.Second):
+		}
+		return "huh? what?", nil
+	}
+
+	wd := wf.New(wf.ACL{})
+	wf.Output(wd, "result", wf.Task0(wd, "sleepy", sleepy, wf.MaxAttempts(1), wf.Watchdog(200*time.Millisecond)))
+
+	w := startWorkflow(t, wd, nil)
+	if got, want := runToFailure(t, w, nil, "sleepy"), "did not log for 200ms, assumed hung"; !strings.Contains(got, want) {
+		t.Errorf("got error %q, want %q", got, want)
+	}
+}
+
 func TestWatchdog(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		testWatchdog(t, true)
@@ -662,7 +865,7 @@ func (l *mapListener) TaskStateChanged(workflowID uuid.UUID, taskID string, stat
 
 func (l *mapListener) assertState(t *testing.T, w *wf.Workflow, want map[string]*wf.TaskState) {
 	t.Helper()
-	if diff := cmp.Diff(l.states[w.ID], want, cmpopts.IgnoreFields(wf.TaskState{}, "SerializedResult")); diff != "" {
+	if diff := cmp.Diff(l.states[w.ID], want, cmpopts.IgnoreFields(wf.TaskState{}, "SerializedResult", "FirstStarted")); diff != "" {
 		t.Errorf("task state didn't match expectations: %v", diff)
 	}
 }
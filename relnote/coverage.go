@@ -0,0 +1,88 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package relnote
+
+import (
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+)
+
+// A CoverageStatus describes how well a change is covered by the
+// release notes.
+type CoverageStatus string
+
+const (
+	// Documented means the change has a release-note fragment
+	// with a complete sentence.
+	Documented CoverageStatus = "documented"
+	// ToDo means the change's fragment exists but still contains a TODO.
+	ToDo CoverageStatus = "todo"
+	// Missing means the change has no fragment, or its fragment
+	// doesn't pass [CheckFragment].
+	Missing CoverageStatus = "missing"
+)
+
+// An APICoverage describes the release-note coverage of the API features
+// that share a doc fragment. See [GroupAPIFeaturesByFile].
+type APICoverage struct {
+	Fragment string       // path of the fragment in the docFS passed to CheckAPICoverage
+	Features []APIFeature // features documented by Fragment
+	Status   CoverageStatus
+	Problem  string // why Status is Missing, if it is
+}
+
+// CheckAPICoverage is like [CheckAPIFile], but instead of reporting
+// problems as an error it returns the coverage of each group of features
+// in the api file at filename in apiFS, sorted by fragment path.
+func CheckAPICoverage(apiFS fs.FS, filename string, docFS fs.FS) ([]APICoverage, error) {
+	features, err := parseAPIFile(apiFS, filename)
+	if err != nil {
+		return nil, err
+	}
+	byFile, err := GroupAPIFeaturesByFile(features)
+	if err != nil {
+		return nil, err
+	}
+	mcDir, err := minorChangesDir(docFS)
+	if err != nil {
+		return nil, err
+	}
+	var cov []APICoverage
+	for _, fn := range slices.Sorted(maps.Keys(byFile)) {
+		c := APICoverage{
+			Fragment: path.Join(mcDir, fn),
+			Features: byFile[fn],
+		}
+		c.Status, c.Problem = fragmentStatus(docFS, c.Fragment)
+		cov = append(cov, c)
+	}
+	return cov, nil
+}
+
+// FragmentStatus reports the coverage status of the fragment at filename
+// in fsys.
+func FragmentStatus(fsys fs.FS, filename string) CoverageStatus {
+	status, _ := fragmentStatus(fsys, filename)
+	return status
+}
+
+// fragmentStatus returns the coverage status of a fragment and,
+// if it is Missing, the reason why.
+func fragmentStatus(fsys fs.FS, filename string) (CoverageStatus, string) {
+	if err := checkFragmentFile(fsys, filename); err != nil {
+		return Missing, err.Error()
+	}
+	data, err := fs.ReadFile(fsys, filename)
+	if err != nil {
+		return Missing, err.Error()
+	}
+	if strings.Contains(string(data), "TODO") {
+		return ToDo, ""
+	}
+	return Documented, ""
+}
@@ -0,0 +1,51 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package relnote
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestCheckAPICoverage(t *testing.T) {
+	fsys := fstest.MapFS{
+		"api.txt": {Data: []byte(`pkg foo, type T #1
+pkg foo, func F() #1
+pkg bar, type T #2
+pkg baz, type T #3
+pkg qux, type T #4
+`)},
+		"7-stdlib/99-minor/foo/1.md": {Data: []byte("The new [T] type does things.\n")},
+		"7-stdlib/99-minor/bar/2.md": {Data: []byte("TODO: document bar.T\n")},
+		"7-stdlib/99-minor/baz/3.md": {Data: []byte("Not a sentence\n")},
+	}
+	cov, err := CheckAPICoverage(fsys, "api.txt", fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]CoverageStatus{
+		"7-stdlib/99-minor/bar/2.md": ToDo,
+		"7-stdlib/99-minor/baz/3.md": Missing,
+		"7-stdlib/99-minor/foo/1.md": Documented,
+		"7-stdlib/99-minor/qux/4.md": Missing,
+	}
+	if len(cov) != len(want) {
+		t.Fatalf("got %d results, want %d: %+v", len(cov), len(want), cov)
+	}
+	for i, c := range cov {
+		if i > 0 && cov[i-1].Fragment >= c.Fragment {
+			t.Errorf("results not sorted: %q before %q", cov[i-1].Fragment, c.Fragment)
+		}
+		if c.Status != want[c.Fragment] {
+			t.Errorf("%s: status %q, want %q", c.Fragment, c.Status, want[c.Fragment])
+		}
+		if (c.Status == Missing) != (c.Problem != "") {
+			t.Errorf("%s: status %q with problem %q", c.Fragment, c.Status, c.Problem)
+		}
+	}
+	if got := len(cov[2].Features); got != 2 {
+		t.Errorf("%s has %d features, want 2", cov[2].Fragment, got)
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package relnote
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+
+	md "rsc.io/markdown"
+)
+
+// A TOCEntry is a heading in a table of contents.
+type TOCEntry struct {
+	Level int    // heading level, from 1 to 6
+	Title string // text of the heading
+	ID    string // HTML id of the heading
+}
+
+// AddHeadingIDs gives each heading in doc without an ID one derived from its
+// text, like "minor-changes-to-the-library", and returns the headings of doc
+// as a table of contents. IDs are made unique by adding a numeric suffix.
+func AddHeadingIDs(doc *md.Document) []TOCEntry {
+	used := map[string]bool{}
+	for _, b := range doc.Blocks {
+		if h, ok := b.(*md.Heading); ok && h.ID != "" {
+			used[h.ID] = true
+		}
+	}
+	var toc []TOCEntry
+	for _, b := range doc.Blocks {
+		h, ok := b.(*md.Heading)
+		if !ok {
+			continue
+		}
+		title := strings.TrimSpace(html.UnescapeString(text(h)))
+		if h.ID == "" {
+			id := headingID(title)
+			for i := 2; used[id]; i++ {
+				id = fmt.Sprintf("%s-%d", headingID(title), i)
+			}
+			used[id] = true
+			h.ID = id
+		}
+		toc = append(toc, TOCEntry{Level: h.Level, Title: title, ID: h.ID})
+	}
+	return toc
+}
+
+// headingID returns an HTML id for a heading with the given text:
+// its letters and digits in lower case, with runs of other characters
+// replaced by hyphens.
+func headingID(title string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			hyphen = false
+			b.WriteRune(r)
+		} else {
+			hyphen = true
+		}
+	}
+	if b.Len() == 0 {
+		return "section"
+	}
+	return b.String()
+}
+
+// ToHTML renders doc, typically the result of [Merge], as HTML.
+// Headings are given IDs as by [AddHeadingIDs], and the document is
+// preceded by a table of contents linking to the headings of level
+// 2 and 3.
+func ToHTML(doc *md.Document) string {
+	toc := AddHeadingIDs(doc)
+	var buf bytes.Buffer
+	buf.WriteString("<nav class=\"toc\">\n")
+	depth := 0
+	for _, e := range toc {
+		if e.Level < 2 || e.Level > 3 {
+			continue
+		}
+		for ; depth < e.Level-1; depth++ {
+			buf.WriteString("<ul>\n")
+		}
+		for ; depth > e.Level-1; depth-- {
+			buf.WriteString("</ul>\n")
+		}
+		fmt.Fprintf(&buf, "<li><a href=\"#%s\">%s</a></li>\n", html.EscapeString(e.ID), html.EscapeString(e.Title))
+	}
+	for ; depth > 0; depth-- {
+		buf.WriteString("</ul>\n")
+	}
+	buf.WriteString("</nav>\n")
+	buf.WriteString(md.ToHTML(doc))
+	return buf.String()
+}
+
+// ToText renders doc, typically the result of [Merge], as plain text,
+// preceded by a table of contents of its headings of level 2 and 3.
+// Formatting and link targets are dropped, and HTML blocks are omitted.
+func ToText(doc *md.Document) string {
+	var buf bytes.Buffer
+	var toc []string
+	for _, e := range AddHeadingIDs(doc) {
+		if e.Level == 2 || e.Level == 3 {
+			toc = append(toc, strings.Repeat("  ", e.Level-2)+e.Title)
+		}
+	}
+	if len(toc) > 0 {
+		buf.WriteString("Contents\n\n")
+		for _, line := range toc {
+			fmt.Fprintf(&buf, "  %s\n", line)
+		}
+		buf.WriteString("\n")
+	}
+	writeTextBlocks(&buf, doc.Blocks, "")
+	return strings.TrimRight(buf.String(), "\n") + "\n"
+}
+
+// writeTextBlocks writes each block of bs as plain text, followed by a
+// blank line, prefixing each line with indent.
+func writeTextBlocks(buf *bytes.Buffer, bs []md.Block, indent string) {
+	for _, b := range bs {
+		writeTextBlock(buf, b, indent)
+	}
+}
+
+func writeTextBlock(buf *bytes.Buffer, b md.Block, indent string) {
+	writeLines := func(s string) {
+		for _, line := range strings.Split(strings.TrimRight(s, "\n"), "\n") {
+			fmt.Fprintf(buf, "%s%s\n", indent, line)
+		}
+		buf.WriteString("\n")
+	}
+	switch b := b.(type) {
+	case *md.Heading:
+		title := strings.TrimSpace(html.UnescapeString(text(b)))
+		underline := "-"
+		if b.Level == 1 {
+			underline = "="
+		}
+		writeLines(title + "\n" + strings.Repeat(underline, len([]rune(title))))
+	case *md.Paragraph, *md.Text:
+		writeLines(html.UnescapeString(text(b)))
+	case *md.CodeBlock:
+		var lines []string
+		for _, line := range b.Text {
+			lines = append(lines, "    "+line)
+		}
+		writeLines(strings.Join(lines, "\n"))
+	case *md.List:
+		for i, item := range b.Items {
+			marker := "- "
+			if b.Bullet == '.' || b.Bullet == ')' {
+				marker = fmt.Sprintf("%d%c ", b.Start+i, b.Bullet)
+			}
+			var ibuf bytes.Buffer
+			writeTextBlocks(&ibuf, item.(*md.Item).Blocks, "")
+			lines := strings.Split(strings.TrimRight(ibuf.String(), "\n"), "\n")
+			for j, line := range lines {
+				prefix := marker
+				if j > 0 {
+					prefix = strings.Repeat(" ", len(marker))
+				}
+				if line == "" {
+					prefix = ""
+				}
+				fmt.Fprintf(buf, "%s%s%s\n", indent, prefix, line)
+			}
+		}
+		buf.WriteString("\n")
+	case *md.Quote:
+		writeTextBlocks(buf, b.Blocks, indent+"> ")
+	case *md.ThematicBreak:
+		writeLines("---")
+	// Nothing to show for these blocks.
+	case *md.HTMLBlock:
+	case *md.Empty:
+	default:
+		panic(fmt.Sprintf("unknown block type %T", b))
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package relnote
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestAddHeadingIDs(t *testing.T) {
+	doc := NewParser().Parse(`# Go 1.99
+
+## Tools {#tools}
+
+### Go command
+
+## Standard library
+
+### Go command
+
+#### [bytes]
+`)
+	addSymbolLinks(doc, "")
+	got := AddHeadingIDs(doc)
+	want := []TOCEntry{
+		{1, "Go 1.99", "go-1-99"},
+		{2, "Tools", "tools"},
+		{3, "Go command", "go-command"},
+		{2, "Standard library", "standard-library"},
+		{3, "Go command", "go-command-2"},
+		{4, "bytes", "bytes"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("\ngot  %v\nwant %v", got, want)
+	}
+}
+
+func TestToHTML(t *testing.T) {
+	doc := NewParser().Parse("## Tools\n\n### Vet\n\nVet checks [fmt.Printf].\n\n## Ports\n")
+	addSymbolLinks(doc, "")
+	got := ToHTML(doc)
+	for _, want := range []string{
+		"<nav class=\"toc\">\n<ul>\n<li><a href=\"#tools\">Tools</a></li>\n<ul>\n<li><a href=\"#vet\">Vet</a></li>\n</ul>\n<li><a href=\"#ports\">Ports</a></li>\n</ul>\n</nav>\n",
+		`<h2 id="tools">Tools</h2>`,
+		`<a href="/pkg/fmt#Printf"><code>fmt.Printf</code></a>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ToHTML output missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestToText(t *testing.T) {
+	doc := NewParser().Parse(`## Tools
+
+The [go/ast.File] type & *more*.
+
+- one
+- two
+
+<!-- hidden -->
+
+    code
+`)
+	addSymbolLinks(doc, "")
+	want := `Contents
+
+  Tools
+
+Tools
+-----
+
+The go/ast.File type & more.
+
+- one
+- two
+
+    code
+`
+	if got := ToText(doc); got != want {
+		t.Errorf("\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package relnote
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io/fs"
+	"path"
+	"strings"
+
+	md "rsc.io/markdown"
+)
+
+// An API is the exported API of the standard library, as described by
+// the files in the api directory of the main go repo.
+type API struct {
+	pkgs map[string]map[string]bool // package → exported symbols, like "Buffer" and "Buffer.String"
+}
+
+// LoadAPI reads the files matching "*.txt" and "next/*.txt" in fsys,
+// which is usually the api directory of a GOROOT.
+func LoadAPI(fsys fs.FS) (*API, error) {
+	a := &API{pkgs: map[string]map[string]bool{}}
+	for _, pattern := range []string{"*.txt", "next/*.txt"} {
+		files, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			if path.Base(f) == "except.txt" {
+				// Lists removed features, in a different format.
+				continue
+			}
+			features, err := parseAPIFile(fsys, f)
+			if err != nil {
+				return nil, err
+			}
+			for _, feat := range features {
+				a.add(feat)
+			}
+		}
+	}
+	return a, nil
+}
+
+// add records the symbols declared by feat.
+func (a *API) add(feat APIFeature) {
+	syms := a.pkgs[feat.Package]
+	if syms == nil {
+		syms = map[string]bool{}
+		a.pkgs[feat.Package] = syms
+	}
+	for _, s := range featureSymbols(feat.Feature) {
+		syms[s] = true
+	}
+}
+
+// featureSymbols returns the symbols declared by an API feature, such as
+// "Buffer" and "Buffer.Len" for "type Buffer struct" and
+// "method (*Buffer) Len() int" respectively. A struct field or interface
+// method also declares its type.
+func featureSymbols(feature string) []string {
+	kind, rest, _ := strings.Cut(feature, " ")
+	// name returns the identifier at the start of s.
+	name := func(s string) string {
+		if i := strings.IndexAny(s, " ([,"); i >= 0 {
+			return s[:i]
+		}
+		return s
+	}
+	switch kind {
+	case "const", "func", "var":
+		return []string{name(rest)}
+	case "method":
+		// method (*T[$0]) M(...) ...
+		recv, m, ok := strings.Cut(strings.TrimPrefix(rest, "("), ") ")
+		if !ok {
+			return nil
+		}
+		recv = name(strings.TrimPrefix(recv, "*"))
+		return []string{recv, recv + "." + name(m)}
+	case "type":
+		// type T struct
+		// type T struct, F int
+		// type T interface, M() error
+		t := name(rest)
+		if _, member, ok := strings.Cut(rest, ", "); ok {
+			member = strings.TrimPrefix(member, "embedded ")
+			return []string{t, t + "." + name(strings.TrimPrefix(member, "*"))}
+		}
+		return []string{t}
+	}
+	return nil
+}
+
+// HasPackage reports whether a has a package with the given import path.
+func (a *API) HasPackage(pkg string) bool {
+	_, ok := a.pkgs[pkg]
+	return ok
+}
+
+// Has reports whether package pkg of a has an exported symbol sym,
+// like "Buffer" or "Buffer.String".
+func (a *API) Has(pkg, sym string) bool {
+	return a.pkgs[pkg][sym]
+}
+
+// CheckSymbolLinks reports links in doc, typically the result of [Merge],
+// to standard library packages or symbols that don't exist in api.
+// Each problem is described by the fragment file it comes from, if known.
+func CheckSymbolLinks(doc *md.Document, api *API) error {
+	c := &linkChecker{api: api}
+	c.blocks(doc.Blocks)
+	return errors.Join(c.errs...)
+}
+
+type linkChecker struct {
+	api  *API
+	file string // current fragment, from the comments inserted by Merge
+	line int    // current line in the merged document
+	errs []error
+}
+
+func (c *linkChecker) blocks(bs []md.Block) {
+	for _, b := range bs {
+		c.block(b)
+	}
+}
+
+func (c *linkChecker) block(b md.Block) {
+	c.line = b.Pos().StartLine
+	switch b := b.(type) {
+	case *md.Heading:
+		// Headings, including the package headings added by Merge,
+		// aren't part of the previous fragment.
+		c.file = ""
+		c.block(b.Text)
+	case *md.Text:
+		c.inlines(b.Inline)
+	case *md.List:
+		c.blocks(b.Items)
+	case *md.Item:
+		c.blocks(b.Blocks)
+	case *md.Paragraph:
+		c.block(b.Text)
+	case *md.Quote:
+		c.blocks(b.Blocks)
+	case *md.HTMLBlock:
+		if len(b.Text) == 1 {
+			if f, ok := strings.CutPrefix(b.Text[0], "<!-- "); ok {
+				c.file = strings.TrimSuffix(f, " -->")
+			}
+		}
+	// no links in these blocks
+	case *md.CodeBlock:
+	case *md.Empty:
+	case *md.ThematicBreak:
+	default:
+		panic(fmt.Sprintf("unknown block type %T", b))
+	}
+}
+
+func (c *linkChecker) inlines(ins []md.Inline) {
+	for _, in := range ins {
+		switch in := in.(type) {
+		case *md.Link:
+			c.link(in)
+		case *md.Strong:
+			c.inlines(in.Inner)
+		case *md.Emph:
+			c.inlines(in.Inner)
+		case *md.Del:
+			c.inlines(in.Inner)
+		}
+	}
+}
+
+// link checks a link to the standard library documentation, like those
+// added by addSymbolLinks, whose URL has the form /pkg/PKG or /pkg/PKG#SYM.
+func (c *linkChecker) link(l *md.Link) {
+	target, ok := strings.CutPrefix(l.URL, "/pkg/")
+	if !ok {
+		return
+	}
+	pkg, sym, _ := strings.Cut(target, "#")
+	pkg = strings.TrimSuffix(pkg, "/")
+	var err error
+	if !c.api.HasPackage(pkg) {
+		err = fmt.Errorf("no package %s", pkg)
+	} else if sym != "" && !c.api.Has(pkg, sym) {
+		err = fmt.Errorf("package %s has no exported symbol %s", pkg, sym)
+	}
+	if err == nil {
+		return
+	}
+	where := c.file
+	if where == "" {
+		where = fmt.Sprintf("line %d", c.line)
+	}
+	c.errs = append(c.errs, fmt.Errorf("%s: link [%s]: %v", where, html.UnescapeString(inlineText(l.Inner)), err))
+}
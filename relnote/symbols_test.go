@@ -0,0 +1,84 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package relnote
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFeatureSymbols(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want []string
+	}{
+		{"func Clone([]uint8) []uint8", []string{"Clone"}},
+		{"func All[$0 interface{ ~[]$1 }, $1 interface{}]($0) iter.Seq2[int, $1]", []string{"All"}},
+		{"method (*Buffer) Available() int", []string{"Buffer", "Buffer.Available"}},
+		{"method (Handle[$0]) Value() $0", []string{"Handle", "Handle.Value"}},
+		{"type Buffer struct", []string{"Buffer"}},
+		{"type Seq[$0 interface{}] func(func($0) bool)", []string{"Seq"}},
+		{"type File struct, GoVersion string", []string{"File", "File.GoVersion"}},
+		{"type Type interface, OverflowInt(int64) bool", []string{"Type", "Type.OverflowInt"}},
+		{"const AF_ALG = 38", []string{"AF_ALG"}},
+		{"const AF_ALG ideal-int", []string{"AF_ALG"}},
+		{"var ErrProcessDone error", []string{"ErrProcessDone"}},
+	} {
+		if got := featureSymbols(test.in); !slices.Equal(got, test.want) {
+			t.Errorf("featureSymbols(%q) = %q, want %q", test.in, got, test.want)
+		}
+	}
+}
+
+func TestCheckSymbolLinks(t *testing.T) {
+	apiFS := fstest.MapFS{
+		"go1.txt":       {Data: []byte("pkg bytes, type Buffer struct\npkg bytes, method (*Buffer) Len() int\n")},
+		"except.txt":    {Data: []byte("pkg bytes, func Removed() ???\n")},
+		"next/1234.txt": {Data: []byte("pkg bytes, func Clone([]uint8) []uint8 #1234\n")},
+	}
+	api, err := LoadAPI(apiFS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	docFS := fstest.MapFS{
+		"1-intro.md":                     {Data: []byte("## Introduction\n\nSee [math.Max].\n")},
+		"6-stdlib/0-heading.md":          {Data: []byte("## Standard library\n")},
+		"6-stdlib/99-minor/0-heading.md": {Data: []byte("### Minor changes\n")},
+		"6-stdlib/99-minor/bytes/1234.md": {Data: []byte(
+			"The new [Clone] function and [Buffer.Len] and [Buffer.Cap] methods.\n")},
+	}
+	doc, err := Merge(docFS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "line 3: link [math.Max]: no package math\n" +
+		"6-stdlib/99-minor/bytes/1234.md: link [Buffer.Cap]: package bytes has no exported symbol Buffer.Cap"
+	if err := CheckSymbolLinks(doc, api); err == nil || err.Error() != want {
+		t.Errorf("CheckSymbolLinks =\n%v\nwant\n%s", err, want)
+	}
+}
+
+func TestLoadAPIFromGOROOT(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in short mode")
+	}
+	dir := filepath.Join(runtime.GOROOT(), "api")
+	if _, err := os.Stat(dir); err != nil {
+		t.Skip(err)
+	}
+	api, err := LoadAPI(os.DirFS(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sym := range []string{"Buffer", "Buffer.String", "NewBuffer", "MinRead"} {
+		if !api.Has("bytes", sym) {
+			t.Errorf("api.Has(bytes, %s) = false, want true", sym)
+		}
+	}
+}